@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	transferCfg "github.com/artie-labs/transfer/lib/config"
 	"gopkg.in/yaml.v3"
@@ -21,9 +22,77 @@ type Kafka struct {
 	PublishSize    uint   `yaml:"publishSize,omitempty"`
 	MaxRequestSize uint64 `yaml:"maxRequestSize,omitempty"`
 	// If username and password are passed in, we'll use SCRAM w/ SHA512.
-	Username   string `yaml:"username,omitempty"`
-	Password   string `yaml:"password,omitempty"`
-	DisableTLS bool   `yaml:"disableTLS,omitempty"`
+	Username     string             `yaml:"username,omitempty"`
+	Password     string             `yaml:"password,omitempty"`
+	DisableTLS   bool               `yaml:"disableTLS,omitempty"`
+	Partitioning *KafkaPartitioning `yaml:"partitioning,omitempty"`
+	// Serialization, both default to JSON. [SchemaRegistry] is required if either of them is Avro.
+	KeySerializer   Serializer      `yaml:"keySerializer,omitempty"`
+	ValueSerializer Serializer      `yaml:"valueSerializer,omitempty"`
+	SchemaRegistry  *SchemaRegistry `yaml:"schemaRegistry,omitempty"`
+}
+
+type PartitionStrategy string
+
+const (
+	// PartitionStrategyKeyHash - Hashes the message key with murmur2, this matches the default partitioner of the Java client.
+	PartitionStrategyKeyHash    PartitionStrategy = "key_hash"
+	PartitionStrategyRoundRobin PartitionStrategy = "round_robin"
+	// PartitionStrategyFixed - Publishes every message for a table to the partition set in [KafkaPartitioning.TablePartitions].
+	PartitionStrategyFixed PartitionStrategy = "fixed"
+	// PartitionStrategyKeyColumns - Hashes the values of [KafkaPartitioning.KeyColumns] instead of the whole key.
+	PartitionStrategyKeyColumns PartitionStrategy = "key_columns"
+)
+
+type KafkaPartitioning struct {
+	Strategy PartitionStrategy `yaml:"strategy,omitempty"`
+	// TablePartitions - Partition for each table keyed by the topic suffix (e.g. `public.users`), tables that are not
+	// listed will fall back to hashing the message key.
+	TablePartitions map[string]int `yaml:"tablePartitions,omitempty"`
+	// KeyColumns - Subset of the partition key columns to hash, in order.
+	KeyColumns []string `yaml:"keyColumns,omitempty"`
+}
+
+func (k *KafkaPartitioning) GetStrategy() PartitionStrategy {
+	if k == nil {
+		return PartitionStrategyKeyHash
+	}
+
+	return cmp.Or(k.Strategy, PartitionStrategyKeyHash)
+}
+
+// GetKeyColumns returns [KafkaPartitioning.KeyColumns] if messages are partitioned by them.
+func (k *KafkaPartitioning) GetKeyColumns() []string {
+	if k.GetStrategy() != PartitionStrategyKeyColumns {
+		return nil
+	}
+
+	return k.KeyColumns
+}
+
+func (k *KafkaPartitioning) Validate() error {
+	switch k.GetStrategy() {
+	case PartitionStrategyKeyHash, PartitionStrategyRoundRobin:
+		return nil
+	case PartitionStrategyFixed:
+		if len(k.TablePartitions) == 0 {
+			return fmt.Errorf("table partitions are required for the %q strategy", PartitionStrategyFixed)
+		}
+
+		for table, partition := range k.TablePartitions {
+			if partition < 0 {
+				return fmt.Errorf("partition for table %q must be >= 0", table)
+			}
+		}
+		return nil
+	case PartitionStrategyKeyColumns:
+		if len(k.KeyColumns) == 0 {
+			return fmt.Errorf("key columns are required for the %q strategy", PartitionStrategyKeyColumns)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid partition strategy: %q", k.Strategy)
+	}
 }
 
 type Mechanism string
@@ -54,6 +123,18 @@ func (k *Kafka) GetPublishSize() uint {
 	return cmp.Or(k.PublishSize, constants.DefaultPublishSize)
 }
 
+func (k *Kafka) GetKeySerializer() Serializer {
+	return cmp.Or(k.KeySerializer, SerializerJSON)
+}
+
+func (k *Kafka) GetValueSerializer() Serializer {
+	return cmp.Or(k.ValueSerializer, SerializerJSON)
+}
+
+func (k *Kafka) UsesAvro() bool {
+	return k.GetKeySerializer() == SerializerAvro || k.GetValueSerializer() == SerializerAvro
+}
+
 func (k *Kafka) Validate() error {
 	if k == nil {
 		return fmt.Errorf("kafka config is nil")
@@ -67,6 +148,24 @@ func (k *Kafka) Validate() error {
 		return fmt.Errorf("topic prefix not passed in")
 	}
 
+	if err := k.Partitioning.Validate(); err != nil {
+		return fmt.Errorf("invalid partitioning: %w", err)
+	}
+
+	if err := k.KeySerializer.Validate(); err != nil {
+		return fmt.Errorf("invalid key serializer: %w", err)
+	}
+
+	if err := k.ValueSerializer.Validate(); err != nil {
+		return fmt.Errorf("invalid value serializer: %w", err)
+	}
+
+	if k.UsesAvro() {
+		if err := k.SchemaRegistry.Validate(); err != nil {
+			return fmt.Errorf("invalid schema registry: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -120,10 +219,27 @@ type Settings struct {
 	Kafka       *Kafka              `yaml:"kafka,omitempty"`
 	Transfer    *transferCfg.Config `yaml:"transfer,omitempty"`
 
-	Reporting *Reporting `yaml:"reporting"`
-	Metrics   *Metrics   `yaml:"metrics"`
+	Reporting     *Reporting     `yaml:"reporting"`
+	Metrics       *Metrics       `yaml:"metrics"`
+	OffsetStorage *OffsetStorage `yaml:"offsetStorage,omitempty"`
 
 	BeforeBackfill BeforeBackfill `yaml:"beforeBackfill,omitempty"`
+
+	// ShutdownTimeoutSeconds - How long to wait for in-flight writes to finish after receiving SIGINT or SIGTERM.
+	ShutdownTimeoutSeconds int `yaml:"shutdownTimeoutSeconds,omitempty"`
+}
+
+func (s *Settings) GetShutdownTimeout() time.Duration {
+	return time.Duration(cmp.Or(s.ShutdownTimeoutSeconds, constants.DefaultShutdownTimeoutSeconds)) * time.Second
+}
+
+// GetUnavailableValuePlaceholder returns the value of the columns that were left out of an update by the source.
+func (s *Settings) GetUnavailableValuePlaceholder() string {
+	if s.Source == SourceMySQL && s.MySQL != nil {
+		return s.MySQL.GetUnavailableValuePlaceholder()
+	}
+
+	return constants.DefaultUnavailableValuePlaceholder
 }
 
 func (s *Settings) Validate() error {
@@ -131,6 +247,14 @@ func (s *Settings) Validate() error {
 		return fmt.Errorf("config is nil")
 	}
 
+	if s.ShutdownTimeoutSeconds < 0 {
+		return fmt.Errorf("shutdown timeout seconds must be >= 0")
+	}
+
+	if err := s.OffsetStorage.Validate(); err != nil {
+		return fmt.Errorf("offset storage validation failed: %w", err)
+	}
+
 	switch s.Source {
 	case SourceDynamo:
 		if s.DynamoDB == nil {
@@ -185,6 +309,10 @@ func (s *Settings) Validate() error {
 		if err := s.Kafka.Validate(); err != nil {
 			return fmt.Errorf("kafka validation failed: %w", err)
 		}
+
+		if s.Kafka.UsesAvro() && (s.Source == SourceDynamo || s.Source == SourceMongoDB) {
+			return fmt.Errorf("avro serialization is not supported for source: %q", s.Source)
+		}
 	case DestinationTransfer:
 		if s.Transfer == nil {
 			return fmt.Errorf("transfer config is nil")
@@ -194,8 +322,19 @@ func (s *Settings) Validate() error {
 		if err != nil {
 			return fmt.Errorf("transfer topic configs are invalid: %w", err)
 		}
-		if len(topicConfigs) != 1 {
-			return fmt.Errorf("expected exactly one transfer config, got %d", len(topicConfigs))
+		if len(topicConfigs) == 0 {
+			return fmt.Errorf("expected at least one transfer config")
+		}
+
+		if len(topicConfigs) > 1 {
+			// With multiple topic configs, each table is mapped to a topic config by its topic (e.g. `public.users`).
+			seenTopics := make(map[string]bool)
+			for _, topicConfig := range topicConfigs {
+				if seenTopics[topicConfig.Topic] {
+					return fmt.Errorf("duplicate transfer config for topic %q", topicConfig.Topic)
+				}
+				seenTopics[topicConfig.Topic] = true
+			}
 		}
 
 		for _, topicConfig := range topicConfigs {
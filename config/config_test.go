@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	transferCfg "github.com/artie-labs/transfer/lib/config"
 	transferConstants "github.com/artie-labs/transfer/lib/config/constants"
@@ -44,6 +45,11 @@ func TestSettings_Validate(t *testing.T) {
 			settings:    &Settings{Source: "foo"},
 			expectedErr: `invalid source: "foo"`,
 		},
+		{
+			name:        "negative shutdown timeout",
+			settings:    &Settings{Source: SourceDynamo, DynamoDB: dynamoDBCfg(), ShutdownTimeoutSeconds: -1},
+			expectedErr: "shutdown timeout seconds must be >= 0",
+		},
 		{
 			name: "nil dynamodb",
 			settings: &Settings{
@@ -67,6 +73,21 @@ func TestSettings_Validate(t *testing.T) {
 			settings:    &Settings{Source: SourceDynamo, DynamoDB: dynamoDBCfg(), Destination: DestinationKafka},
 			expectedErr: "kafka config is nil",
 		},
+		{
+			name: "avro with dynamodb",
+			settings: &Settings{
+				Source:      SourceDynamo,
+				DynamoDB:    dynamoDBCfg(),
+				Destination: DestinationKafka,
+				Kafka: &Kafka{
+					BootstrapServers: "localhost:9092",
+					TopicPrefix:      "prefix",
+					ValueSerializer:  SerializerAvro,
+					SchemaRegistry:   &SchemaRegistry{URL: "http://localhost:8081"},
+				},
+			},
+			expectedErr: `avro serialization is not supported for source: "dynamodb"`,
+		},
 		{
 			name: "valid kafka destination",
 			settings: &Settings{
@@ -123,6 +144,79 @@ func TestSettings_Validate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "duplicate transfer topics",
+			settings: &Settings{
+				Source:      SourceDynamo,
+				DynamoDB:    dynamoDBCfg(),
+				Destination: DestinationTransfer,
+				Transfer: &transferCfg.Config{
+					Mode:                 transferCfg.Replication,
+					Queue:                transferConstants.Kafka,
+					FlushIntervalSeconds: 10,
+					FlushSizeKb:          1,
+					BufferRows:           25_000,
+					Kafka: &transferCfg.Kafka{
+						BootstrapServer: "not-used",
+						GroupID:         "group-id",
+						TopicConfigs: []*kafkalib.TopicConfig{
+							{
+								Database:     "db",
+								Schema:       "schema",
+								Topic:        "schema.table",
+								CDCFormat:    "unused",
+								CDCKeyFormat: kafkalib.JSONKeyFmt,
+							},
+							{
+								Database:     "db",
+								Schema:       "schema",
+								Topic:        "schema.table",
+								CDCFormat:    "unused",
+								CDCKeyFormat: kafkalib.JSONKeyFmt,
+							},
+						},
+					},
+					Output: transferConstants.Snowflake,
+				},
+			},
+			expectedErr: `duplicate transfer config for topic "schema.table"`,
+		},
+		{
+			name: "valid transfer destination with multiple tables",
+			settings: &Settings{
+				Source:      SourceDynamo,
+				DynamoDB:    dynamoDBCfg(),
+				Destination: DestinationTransfer,
+				Transfer: &transferCfg.Config{
+					Mode:                 transferCfg.Replication,
+					Queue:                transferConstants.Kafka,
+					FlushIntervalSeconds: 10,
+					FlushSizeKb:          1,
+					BufferRows:           25_000,
+					Kafka: &transferCfg.Kafka{
+						BootstrapServer: "not-used",
+						GroupID:         "group-id",
+						TopicConfigs: []*kafkalib.TopicConfig{
+							{
+								Database:     "db",
+								Schema:       "schema",
+								Topic:        "schema.table_a",
+								CDCFormat:    "unused",
+								CDCKeyFormat: kafkalib.JSONKeyFmt,
+							},
+							{
+								Database:     "db",
+								Schema:       "schema",
+								Topic:        "schema.table_b",
+								CDCFormat:    "unused",
+								CDCKeyFormat: kafkalib.JSONKeyFmt,
+							},
+						},
+					},
+					Output: transferConstants.Snowflake,
+				},
+			},
+		},
 	}
 
 	for _, tc := range tcs {
@@ -136,6 +230,19 @@ func TestSettings_Validate(t *testing.T) {
 	}
 }
 
+func TestSettings_GetShutdownTimeout(t *testing.T) {
+	assert.Equal(t, 30*time.Second, (&Settings{}).GetShutdownTimeout())
+	assert.Equal(t, 5*time.Second, (&Settings{ShutdownTimeoutSeconds: 5}).GetShutdownTimeout())
+}
+
+func TestSettings_GetUnavailableValuePlaceholder(t *testing.T) {
+	assert.Equal(t, "__debezium_unavailable_value", (&Settings{Source: SourcePostgreSQL}).GetUnavailableValuePlaceholder())
+	assert.Equal(t, "__debezium_unavailable_value", (&Settings{Source: SourceMySQL, MySQL: &MySQL{}}).GetUnavailableValuePlaceholder())
+
+	settings := &Settings{Source: SourceMySQL, MySQL: &MySQL{StreamingSettings: MySQLStreamingSettings{UnavailableValuePlaceholder: "__unavailable"}}}
+	assert.Equal(t, "__unavailable", settings.GetUnavailableValuePlaceholder())
+}
+
 func TestReadConfig(t *testing.T) {
 	{
 		// Missing file
@@ -204,3 +311,53 @@ func TestReadConfig(t *testing.T) {
 		assert.Equal(t, settingsOut.Destination, DestinationKafka)
 	}
 }
+
+func TestKafkaPartitioning_Validate(t *testing.T) {
+	{
+		// Not set
+		var p *KafkaPartitioning
+		assert.NoError(t, p.Validate())
+		assert.Equal(t, PartitionStrategyKeyHash, p.GetStrategy())
+		assert.Empty(t, p.GetKeyColumns())
+	}
+	{
+		// Invalid strategy
+		p := &KafkaPartitioning{Strategy: "foo"}
+		assert.ErrorContains(t, p.Validate(), `invalid partition strategy: "foo"`)
+	}
+	{
+		// Round robin
+		p := &KafkaPartitioning{Strategy: PartitionStrategyRoundRobin}
+		assert.NoError(t, p.Validate())
+	}
+	{
+		// Fixed without partitions
+		p := &KafkaPartitioning{Strategy: PartitionStrategyFixed}
+		assert.ErrorContains(t, p.Validate(), `table partitions are required for the "fixed" strategy`)
+	}
+	{
+		// Fixed with a negative partition
+		p := &KafkaPartitioning{Strategy: PartitionStrategyFixed, TablePartitions: map[string]int{"public.users": -1}}
+		assert.ErrorContains(t, p.Validate(), `partition for table "public.users" must be >= 0`)
+	}
+	{
+		// Fixed
+		p := &KafkaPartitioning{Strategy: PartitionStrategyFixed, TablePartitions: map[string]int{"public.users": 1}}
+		assert.NoError(t, p.Validate())
+	}
+	{
+		// Key columns without columns
+		p := &KafkaPartitioning{Strategy: PartitionStrategyKeyColumns}
+		assert.ErrorContains(t, p.Validate(), `key columns are required for the "key_columns" strategy`)
+	}
+	{
+		// Key columns
+		p := &KafkaPartitioning{Strategy: PartitionStrategyKeyColumns, KeyColumns: []string{"tenant_id"}}
+		assert.NoError(t, p.Validate())
+		assert.Equal(t, []string{"tenant_id"}, p.GetKeyColumns())
+
+		// Key columns are only used by the "key_columns" strategy
+		p.Strategy = PartitionStrategyRoundRobin
+		assert.Empty(t, p.GetKeyColumns())
+	}
+}
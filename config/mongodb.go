@@ -9,6 +9,10 @@ import (
 
 func (s StreamingSettings) Validate() error {
 	if !s.Enabled {
+		if s.InitialSnapshot {
+			return fmt.Errorf("initial snapshot requires streaming to be enabled")
+		}
+
 		return nil
 	}
 
@@ -23,6 +27,9 @@ type StreamingSettings struct {
 	Enabled    bool   `yaml:"enabled,omitempty"`
 	OffsetFile string `yaml:"offsetFile,omitempty"`
 	BatchSize  int32  `yaml:"batchSize,omitempty"`
+	// InitialSnapshot - If there is no offset yet, snapshot all the collections first and then stream from a point that was
+	// captured before the snapshot started.
+	InitialSnapshot bool `yaml:"initialSnapshot,omitempty"`
 }
 
 type MongoDB struct {
@@ -31,6 +38,8 @@ type MongoDB struct {
 	Collections       []Collection      `yaml:"collections"`
 	StreamingSettings StreamingSettings `yaml:"streamingSettings,omitempty"`
 	DisableTLS        bool              `yaml:"disableTLS,omitempty"`
+	// SnapshotCheckpointFile - If set, snapshot progress is persisted here so that an interrupted snapshot can be resumed.
+	SnapshotCheckpointFile string `yaml:"snapshotCheckpointFile,omitempty"`
 
 	// DisableFullDocumentBeforeChange - This is relevant if you're connecting to Document DB.
 	// BSON field '$changeStream.fullDocumentBeforeChange' is an unknown field.
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamingSettings_Validate(t *testing.T) {
+	{
+		// Streaming is disabled
+		assert.NoError(t, StreamingSettings{}.Validate())
+	}
+	{
+		// Initial snapshot without streaming
+		assert.ErrorContains(t, StreamingSettings{InitialSnapshot: true}.Validate(), "initial snapshot requires streaming to be enabled")
+	}
+	{
+		// Streaming is enabled without an offset file
+		assert.ErrorContains(t, StreamingSettings{Enabled: true, InitialSnapshot: true}.Validate(), "offset file must be passed in when streaming is enabled")
+	}
+	{
+		// Streaming with an initial snapshot
+		assert.NoError(t, StreamingSettings{Enabled: true, OffsetFile: "offsets.yaml", InitialSnapshot: true}.Validate())
+	}
+}
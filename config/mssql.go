@@ -11,13 +11,42 @@ import (
 	"strings"
 )
 
+type MSSQLStreamingSettings struct {
+	Enabled    bool   `yaml:"enabled,omitempty"`
+	OffsetFile string `yaml:"offsetFile,omitempty"`
+	BatchSize  int32  `yaml:"batchSize,omitempty"`
+}
+
+func (m MSSQLStreamingSettings) Validate() error {
+	if !m.Enabled {
+		return nil
+	}
+
+	if m.OffsetFile == "" {
+		return fmt.Errorf("offset file is required")
+	}
+
+	return nil
+}
+
 type MSSQL struct {
-	Host     string        `yaml:"host"`
-	Port     int           `yaml:"port"`
-	Username string        `yaml:"username"`
-	Password string        `yaml:"password"`
-	Database string        `yaml:"database"`
-	Tables   []*MSSQLTable `yaml:"tables"`
+	Host              string                 `yaml:"host"`
+	Port              int                    `yaml:"port"`
+	Username          string                 `yaml:"username"`
+	Password          string                 `yaml:"password"`
+	Database          string                 `yaml:"database"`
+	Tables            []*MSSQLTable          `yaml:"tables"`
+	StreamingSettings MSSQLStreamingSettings `yaml:"streamingSettings,omitempty"`
+	// MaxConcurrentTables - Number of tables that are snapshotted at the same time, defaults to one.
+	MaxConcurrentTables int `yaml:"maxConcurrentTables,omitempty"`
+	// SnapshotCheckpointFile - If set, snapshot progress is persisted here so that an interrupted snapshot can be resumed.
+	SnapshotCheckpointFile string `yaml:"snapshotCheckpointFile,omitempty"`
+	// PollingSettings - Captures changes by polling each table with its [CursorColumn] instead of streaming.
+	PollingSettings PollingSettings `yaml:"pollingSettings,omitempty"`
+}
+
+func (m MSSQL) GetStreamingBatchSize() int32 {
+	return cmp.Or(m.StreamingSettings.BatchSize, constants.DefaultBatchSize)
 }
 
 type MSSQLTable struct {
@@ -26,11 +55,19 @@ type MSSQLTable struct {
 
 	// Optional settings
 	BatchSize                  uint     `yaml:"batchSize,omitempty"`
+	PrimaryKeysOverride        []string `yaml:"primaryKeysOverride,omitempty"`
 	OptionalPrimaryKeyValStart string   `yaml:"optionalPrimaryKeyValStart,omitempty"`
 	OptionalPrimaryKeyValEnd   string   `yaml:"optionalPrimaryKeyValEnd,omitempty"`
 	ExcludeColumns             []string `yaml:"excludeColumns,omitempty"`
 	// IncludeColumns - List of columns that should be included in the change event record.
 	IncludeColumns []string `yaml:"includeColumns,omitempty"`
+	// SnapshotParallelism - Number of chunks that the primary key range is split into and scanned concurrently.
+	SnapshotParallelism uint `yaml:"snapshotParallelism,omitempty"`
+	// CursorColumn - Column that is used to find changed rows when polling (e.g. `updated_at`), it should be set
+	// whenever a row is inserted or updated.
+	CursorColumn string `yaml:"cursorColumn,omitempty"`
+	// CaptureInstance - Name of the CDC capture instance used for streaming, defaults to `<schema>_<table>`.
+	CaptureInstance string `yaml:"captureInstance,omitempty"`
 }
 
 func (m *MSSQL) ToDSN() string {
@@ -47,6 +84,14 @@ func (m *MSSQL) ToDSN() string {
 	return u.String()
 }
 
+func (m *MSSQLTable) GetCaptureInstance() string {
+	if m.CaptureInstance != "" {
+		return m.CaptureInstance
+	}
+
+	return fmt.Sprintf("%s_%s", m.Schema, m.Name)
+}
+
 func (m *MSSQLTable) GetBatchSize() uint {
 	return cmp.Or(m.BatchSize, constants.DefaultBatchSize)
 }
@@ -71,6 +116,7 @@ func (m *MSSQLTable) ToScannerConfig(errorRetries int) scan.ScannerConfig {
 		OptionalStartingValues: m.GetOptionalPrimaryKeyValStart(),
 		OptionalEndingValues:   m.GetOptionalPrimaryKeyValEnd(),
 		ErrorRetries:           errorRetries,
+		Parallelism:            m.SnapshotParallelism,
 	}
 }
 
@@ -93,6 +139,10 @@ func (m *MSSQL) Validate() error {
 		return fmt.Errorf("no tables passed in")
 	}
 
+	if m.MaxConcurrentTables < 0 {
+		return fmt.Errorf("maxConcurrentTables cannot be negative")
+	}
+
 	for _, table := range m.Tables {
 		if stringutil.Empty(table.Name, table.Schema) {
 			return fmt.Errorf("table name and schema must be passed in")
@@ -102,7 +152,19 @@ func (m *MSSQL) Validate() error {
 		if len(table.ExcludeColumns) > 0 && len(table.IncludeColumns) > 0 {
 			return fmt.Errorf("cannot exclude and include columns at the same time")
 		}
+
+		if m.PollingSettings.Enabled && table.CursorColumn == "" {
+			return fmt.Errorf("cursor column is required for table %q when polling is enabled", table.Name)
+		}
 	}
 
-	return nil
+	if m.PollingSettings.Enabled && m.StreamingSettings.Enabled {
+		return fmt.Errorf("polling and streaming cannot be enabled at the same time")
+	}
+
+	if err := m.PollingSettings.Validate(); err != nil {
+		return fmt.Errorf("invalid polling settings: %w", err)
+	}
+
+	return m.StreamingSettings.Validate()
 }
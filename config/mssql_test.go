@@ -165,6 +165,27 @@ func TestMSSQL_Validate(t *testing.T) {
 			},
 		}
 		assert.NoError(t, m.Validate())
+
+		// Streaming without an offset file
+		m.StreamingSettings.Enabled = true
+		assert.ErrorContains(t, m.Validate(), "offset file is required")
+
+		// Streaming with an offset file
+		m.StreamingSettings.OffsetFile = "/tmp/offset"
+		assert.NoError(t, m.Validate())
+	}
+}
+
+func TestMSSQLTable_GetCaptureInstance(t *testing.T) {
+	{
+		// Default
+		m := &MSSQLTable{Name: "orders", Schema: "dbo"}
+		assert.Equal(t, "dbo_orders", m.GetCaptureInstance())
+	}
+	{
+		// Override
+		m := &MSSQLTable{Name: "orders", Schema: "dbo", CaptureInstance: "dbo_orders_v2"}
+		assert.Equal(t, "dbo_orders_v2", m.GetCaptureInstance())
 	}
 }
 
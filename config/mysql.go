@@ -4,6 +4,7 @@ import (
 	"cmp"
 	"fmt"
 	"math"
+	"regexp"
 	"strings"
 
 	"github.com/artie-labs/transfer/lib/stringutil"
@@ -20,10 +21,78 @@ type MySQLStreamingSettings struct {
 	// ServerID - Unique ID in the cluster.
 	ServerID  uint32 `yaml:"serverID,omitempty"`
 	BatchSize int32  `yaml:"batchSize,omitempty"`
+	// InitialSnapshot - If there is no offset yet, snapshot all the tables at a consistent binlog position first and then
+	// start streaming from that position.
+	InitialSnapshot bool `yaml:"initialSnapshot,omitempty"`
+	// IncrementalSnapshot - If set, tables that are added to [MySQL.Tables] after streaming has started are snapshotted
+	// without pausing the stream.
+	IncrementalSnapshot *MySQLIncrementalSnapshot `yaml:"incrementalSnapshot,omitempty"`
+	// TableSelection - If set, tables that match these patterns are streamed along with [MySQL.Tables], from any database
+	// on the server. Tables that are created while streaming are picked up once their CREATE TABLE is seen. Only
+	// [MySQL.Tables] are snapshotted.
+	TableSelection *MySQLTableSelection `yaml:"tableSelection,omitempty"`
+	// UnavailableValuePlaceholder - Value of the columns that did not change and were left out of an update because
+	// `binlog_row_image` is set to MINIMAL or NOBLOB.
+	UnavailableValuePlaceholder string `yaml:"unavailableValuePlaceholder,omitempty"`
+}
+
+// MySQLTableSelection - Patterns are regular expressions that have to match the whole name, tables are matched by
+// `database.table`. Exclude patterns take precedence over include patterns.
+type MySQLTableSelection struct {
+	// IncludeDatabases - Defaults to every database besides the system databases.
+	IncludeDatabases []string `yaml:"includeDatabases,omitempty"`
+	ExcludeDatabases []string `yaml:"excludeDatabases,omitempty"`
+	// IncludeTables - Defaults to every table of the included databases.
+	IncludeTables []string `yaml:"includeTables,omitempty"`
+	ExcludeTables []string `yaml:"excludeTables,omitempty"`
+}
+
+func (m MySQLTableSelection) Validate() error {
+	for _, patterns := range [][]string{m.IncludeDatabases, m.ExcludeDatabases, m.IncludeTables, m.ExcludeTables} {
+		for _, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+type MySQLIncrementalSnapshot struct {
+	// SignalTable - Table in the configured database that watermarks are written to. It needs a `id` VARCHAR primary key
+	// along with `type` and `data` VARCHAR columns.
+	SignalTable string `yaml:"signalTable"`
+	// ProgressFile - Which tables have been snapshotted and how far along the current table is.
+	ProgressFile string `yaml:"progressFile"`
+	// SignalsFile - Signals that have been received, so that they are not processed again after a restart.
+	SignalsFile string `yaml:"signalsFile"`
+	// SignalDirectory - If set, signals can also be sent by writing JSON files to this directory.
+	SignalDirectory string `yaml:"signalDirectory,omitempty"`
+}
+
+func (m MySQLIncrementalSnapshot) Validate() error {
+	if m.SignalTable == "" {
+		return fmt.Errorf("signal table is required")
+	}
+
+	if m.ProgressFile == "" {
+		return fmt.Errorf("progress file is required")
+	}
+
+	if m.SignalsFile == "" {
+		return fmt.Errorf("signals file is required")
+	}
+
+	return nil
 }
 
 func (m MySQLStreamingSettings) Validate() error {
 	if !m.Enabled {
+		if m.InitialSnapshot {
+			return fmt.Errorf("initial snapshot requires streaming to be enabled")
+		}
+
 		return nil
 	}
 
@@ -39,6 +108,18 @@ func (m MySQLStreamingSettings) Validate() error {
 		return fmt.Errorf("server ID is required")
 	}
 
+	if m.IncrementalSnapshot != nil {
+		if err := m.IncrementalSnapshot.Validate(); err != nil {
+			return fmt.Errorf("invalid incremental snapshot settings: %w", err)
+		}
+	}
+
+	if m.TableSelection != nil {
+		if err := m.TableSelection.Validate(); err != nil {
+			return fmt.Errorf("invalid table selection: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -50,12 +131,22 @@ type MySQL struct {
 	Database          string                 `yaml:"database"`
 	Tables            []*MySQLTable          `yaml:"tables"`
 	StreamingSettings MySQLStreamingSettings `yaml:"streamingSettings,omitempty"`
+	// MaxConcurrentTables - Number of tables that are snapshotted at the same time, defaults to one.
+	MaxConcurrentTables int `yaml:"maxConcurrentTables,omitempty"`
+	// SnapshotCheckpointFile - If set, snapshot progress is persisted here so that an interrupted snapshot can be resumed.
+	SnapshotCheckpointFile string `yaml:"snapshotCheckpointFile,omitempty"`
+	// PollingSettings - Captures changes by polling each table with its [CursorColumn] instead of streaming.
+	PollingSettings PollingSettings `yaml:"pollingSettings,omitempty"`
 }
 
 func (m MySQL) GetStreamingBatchSize() int32 {
 	return cmp.Or(m.StreamingSettings.BatchSize, constants.DefaultBatchSize)
 }
 
+func (m MySQL) GetUnavailableValuePlaceholder() string {
+	return cmp.Or(m.StreamingSettings.UnavailableValuePlaceholder, constants.DefaultUnavailableValuePlaceholder)
+}
+
 func (m *MySQL) ToDSN() string {
 	config := mysql.NewConfig()
 	config.User = m.Username
@@ -70,11 +161,17 @@ type MySQLTable struct {
 	Name string `yaml:"name"`
 	// Optional settings
 	BatchSize                  uint     `yaml:"batchSize,omitempty"`
+	PrimaryKeysOverride        []string `yaml:"primaryKeysOverride,omitempty"`
 	OptionalPrimaryKeyValStart string   `yaml:"optionalPrimaryKeyValStart,omitempty"`
 	OptionalPrimaryKeyValEnd   string   `yaml:"optionalPrimaryKeyValEnd,omitempty"`
 	ExcludeColumns             []string `yaml:"excludeColumns,omitempty"`
 	// IncludeColumns - List of columns that should be included in the change event record.
 	IncludeColumns []string `yaml:"includeColumns,omitempty"`
+	// SnapshotParallelism - Number of chunks that the primary key range is split into and scanned concurrently.
+	SnapshotParallelism uint `yaml:"snapshotParallelism,omitempty"`
+	// CursorColumn - Column that is used to find changed rows when polling (e.g. `updated_at`), it should be set
+	// whenever a row is inserted or updated.
+	CursorColumn string `yaml:"cursorColumn,omitempty"`
 }
 
 func (m *MySQLTable) GetBatchSize() uint {
@@ -101,6 +198,7 @@ func (m *MySQLTable) ToScannerConfig(errorRetries int) scan.ScannerConfig {
 		OptionalStartingValues: m.GetOptionalPrimaryKeyValStart(),
 		OptionalEndingValues:   m.GetOptionalPrimaryKeyValEnd(),
 		ErrorRetries:           errorRetries,
+		Parallelism:            m.SnapshotParallelism,
 	}
 }
 
@@ -119,10 +217,14 @@ func (m *MySQL) Validate() error {
 		return fmt.Errorf("port is > %d", math.MaxUint16)
 	}
 
-	if len(m.Tables) == 0 {
+	if len(m.Tables) == 0 && (!m.StreamingSettings.Enabled || m.StreamingSettings.TableSelection == nil) {
 		return fmt.Errorf("no tables passed in")
 	}
 
+	if m.MaxConcurrentTables < 0 {
+		return fmt.Errorf("maxConcurrentTables cannot be negative")
+	}
+
 	for _, table := range m.Tables {
 		if table.Name == "" {
 			return fmt.Errorf("table name must be passed in")
@@ -132,6 +234,18 @@ func (m *MySQL) Validate() error {
 		if len(table.ExcludeColumns) > 0 && len(table.IncludeColumns) > 0 {
 			return fmt.Errorf("cannot exclude and include columns at the same time")
 		}
+
+		if m.PollingSettings.Enabled && table.CursorColumn == "" {
+			return fmt.Errorf("cursor column is required for table %q when polling is enabled", table.Name)
+		}
+	}
+
+	if m.PollingSettings.Enabled && m.StreamingSettings.Enabled {
+		return fmt.Errorf("polling and streaming cannot be enabled at the same time")
+	}
+
+	if err := m.PollingSettings.Validate(); err != nil {
+		return fmt.Errorf("invalid polling settings: %w", err)
 	}
 
 	return m.StreamingSettings.Validate()
@@ -90,6 +90,12 @@ func TestMySQL_Validate(t *testing.T) {
 		c.Tables = []*MySQLTable{}
 		assert.ErrorContains(t, c.Validate(), "no tables passed in")
 	}
+	{
+		// negative maxConcurrentTables
+		c := createValidConfig()
+		c.MaxConcurrentTables = -1
+		assert.ErrorContains(t, c.Validate(), "maxConcurrentTables cannot be negative")
+	}
 	{
 		// missing table name
 		c := createValidConfig()
@@ -115,6 +121,12 @@ func TestMySQL_Validate(t *testing.T) {
 			c.StreamingSettings.Enabled = false
 			assert.NoError(t, c.Validate())
 		}
+		{
+			// Initial snapshot without streaming
+			c := createValidConfig()
+			c.StreamingSettings.InitialSnapshot = true
+			assert.ErrorContains(t, c.Validate(), "initial snapshot requires streaming to be enabled")
+		}
 		{
 			// Enabled
 			c := createValidConfig()
@@ -144,6 +156,67 @@ func TestMySQL_Validate(t *testing.T) {
 				c.StreamingSettings.ServerID = 1
 				assert.NoError(t, c.Validate())
 			}
+			{
+				// Incremental snapshot without a signal table
+				c.StreamingSettings.IncrementalSnapshot = &MySQLIncrementalSnapshot{ProgressFile: "/tmp/progress"}
+				assert.ErrorContains(t, c.Validate(), "invalid incremental snapshot settings: signal table is required")
+			}
+			{
+				// Incremental snapshot without a progress file
+				c.StreamingSettings.IncrementalSnapshot = &MySQLIncrementalSnapshot{SignalTable: "signals"}
+				assert.ErrorContains(t, c.Validate(), "invalid incremental snapshot settings: progress file is required")
+			}
+			{
+				// Incremental snapshot without a signals file
+				c.StreamingSettings.IncrementalSnapshot = &MySQLIncrementalSnapshot{SignalTable: "signals", ProgressFile: "/tmp/progress"}
+				assert.ErrorContains(t, c.Validate(), "invalid incremental snapshot settings: signals file is required")
+			}
+			{
+				// Valid incremental snapshot
+				c.StreamingSettings.IncrementalSnapshot = &MySQLIncrementalSnapshot{SignalTable: "signals", ProgressFile: "/tmp/progress", SignalsFile: "/tmp/signals"}
+				assert.NoError(t, c.Validate())
+			}
+			{
+				// Invalid table selection pattern
+				c.StreamingSettings.TableSelection = &MySQLTableSelection{IncludeTables: []string{`tenant_(\d+\.orders`}}
+				assert.ErrorContains(t, c.Validate(), "invalid table selection: invalid pattern")
+			}
+			{
+				// Tables can be selected by pattern only
+				c.StreamingSettings.TableSelection = &MySQLTableSelection{IncludeTables: []string{`tenant_\d+\.orders`}}
+				c.Tables = nil
+				assert.NoError(t, c.Validate())
+
+				c.StreamingSettings.TableSelection = nil
+				assert.ErrorContains(t, c.Validate(), "no tables passed in")
+			}
+		}
+	}
+	{
+		// Polling
+		{
+			// Table without a cursor column
+			c := createValidConfig()
+			c.PollingSettings = PollingSettings{Enabled: true, OffsetFile: "/tmp/offset"}
+			c.Tables[0].CursorColumn = "updated_at"
+			assert.ErrorContains(t, c.Validate(), `cursor column is required for table "table2" when polling is enabled`)
+		}
+		{
+			// Offset file not set
+			c := createValidConfig()
+			c.PollingSettings = PollingSettings{Enabled: true}
+			for _, table := range c.Tables {
+				table.CursorColumn = "updated_at"
+			}
+			assert.ErrorContains(t, c.Validate(), "invalid polling settings: offset file is required")
+
+			// Valid
+			c.PollingSettings.OffsetFile = "/tmp/offset"
+			assert.NoError(t, c.Validate())
+
+			// Streaming at the same time
+			c.StreamingSettings = MySQLStreamingSettings{Enabled: true, OffsetFile: "/tmp/offset", SchemaHistoryFile: "/tmp/schema", ServerID: 1}
+			assert.ErrorContains(t, c.Validate(), "polling and streaming cannot be enabled at the same time")
 		}
 	}
 }
@@ -153,6 +226,14 @@ func TestMySQL_ToDSN(t *testing.T) {
 	assert.Equal(t, "username:password@tcp(example.com:3306)/database", c.ToDSN())
 }
 
+func TestMySQL_GetUnavailableValuePlaceholder(t *testing.T) {
+	c := createValidConfig()
+	assert.Equal(t, "__debezium_unavailable_value", c.GetUnavailableValuePlaceholder())
+
+	c.StreamingSettings.UnavailableValuePlaceholder = "__unavailable"
+	assert.Equal(t, "__unavailable", c.GetUnavailableValuePlaceholder())
+}
+
 func TestMySQLTable_GetBatchSize(t *testing.T) {
 	{
 		// Batch size is not set
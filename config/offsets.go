@@ -0,0 +1,67 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+)
+
+type OffsetStorageKind string
+
+const (
+	// OffsetStorageFile - Offsets and schema history are stored in local files, this is the default.
+	OffsetStorageFile OffsetStorageKind = "file"
+	// OffsetStorageDatabase - Offsets and schema history are stored in a table on a database/sql database.
+	OffsetStorageDatabase OffsetStorageKind = "database"
+)
+
+const defaultOffsetStorageTableName = "artie_reader_offsets"
+
+var tableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// OffsetStorage - Where offsets and schema history are stored. The offset and schema history files that are configured
+// for each source are used as keys when the offsets are stored in a database.
+type OffsetStorage struct {
+	Kind OffsetStorageKind `yaml:"kind,omitempty"`
+
+	// Database settings
+	// Driver - database/sql driver name, e.g. `pgx`, `mysql` or `sqlite`.
+	Driver    string `yaml:"driver,omitempty"`
+	DSN       string `yaml:"dsn,omitempty"`
+	TableName string `yaml:"tableName,omitempty"`
+}
+
+func (o *OffsetStorage) GetKind() OffsetStorageKind {
+	if o == nil {
+		return OffsetStorageFile
+	}
+
+	return cmp.Or(o.Kind, OffsetStorageFile)
+}
+
+func (o *OffsetStorage) GetTableName() string {
+	return cmp.Or(o.TableName, defaultOffsetStorageTableName)
+}
+
+func (o *OffsetStorage) Validate() error {
+	switch o.GetKind() {
+	case OffsetStorageFile:
+		return nil
+	case OffsetStorageDatabase:
+		if o.Driver == "" {
+			return fmt.Errorf("driver is required")
+		}
+
+		if o.DSN == "" {
+			return fmt.Errorf("dsn is required")
+		}
+
+		if !tableNameRegex.MatchString(o.GetTableName()) {
+			return fmt.Errorf("invalid table name: %q", o.GetTableName())
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("invalid offset storage kind: %q", o.Kind)
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffsetStorage_Validate(t *testing.T) {
+	{
+		// Not set, defaults to file
+		var offsetStorage *OffsetStorage
+		assert.Equal(t, OffsetStorageFile, offsetStorage.GetKind())
+		assert.NoError(t, offsetStorage.Validate())
+	}
+	{
+		// Invalid kind
+		assert.ErrorContains(t, (&OffsetStorage{Kind: "foo"}).Validate(), `invalid offset storage kind: "foo"`)
+	}
+	{
+		// Database without a driver
+		assert.ErrorContains(t, (&OffsetStorage{Kind: OffsetStorageDatabase, DSN: "dsn"}).Validate(), "driver is required")
+	}
+	{
+		// Database without a DSN
+		assert.ErrorContains(t, (&OffsetStorage{Kind: OffsetStorageDatabase, Driver: "pgx"}).Validate(), "dsn is required")
+	}
+	{
+		// Database with an invalid table name
+		offsetStorage := &OffsetStorage{Kind: OffsetStorageDatabase, Driver: "pgx", DSN: "dsn", TableName: "offsets; DROP TABLE foo"}
+		assert.ErrorContains(t, offsetStorage.Validate(), "invalid table name")
+	}
+	{
+		// Valid database
+		offsetStorage := &OffsetStorage{Kind: OffsetStorageDatabase, Driver: "pgx", DSN: "dsn"}
+		assert.NoError(t, offsetStorage.Validate())
+		assert.Equal(t, "artie_reader_offsets", offsetStorage.GetTableName())
+
+		offsetStorage.TableName = "reader.offsets"
+		assert.NoError(t, offsetStorage.Validate())
+	}
+}
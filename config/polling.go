@@ -0,0 +1,56 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultPollingIntervalSeconds = 10
+	defaultPollingLagSeconds      = 5
+)
+
+// PollingSettings - Captures changes by repeatedly querying for rows with a newer cursor value (e.g. `updated_at`), this
+// is meant for databases where the replication log cannot be read.
+type PollingSettings struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// OffsetFile - Last cursor value that has been read for each table.
+	OffsetFile string `yaml:"offsetFile,omitempty"`
+	// IntervalSeconds - How long to wait before polling again once every table has caught up, defaults to 10 seconds.
+	IntervalSeconds int `yaml:"intervalSeconds,omitempty"`
+	// LagSeconds - Rows are only read once their cursor value is this far behind the database's clock, so that rows
+	// written by transactions that commit late are not skipped. Defaults to 5 seconds.
+	LagSeconds int `yaml:"lagSeconds,omitempty"`
+	// DeleteDetectionIntervalSeconds - If set, the primary keys of each table are diffed at this interval to detect
+	// deletes.
+	DeleteDetectionIntervalSeconds int `yaml:"deleteDetectionIntervalSeconds,omitempty"`
+}
+
+func (p PollingSettings) GetInterval() time.Duration {
+	return time.Duration(cmp.Or(p.IntervalSeconds, defaultPollingIntervalSeconds)) * time.Second
+}
+
+func (p PollingSettings) GetLag() time.Duration {
+	return time.Duration(cmp.Or(p.LagSeconds, defaultPollingLagSeconds)) * time.Second
+}
+
+func (p PollingSettings) GetDeleteDetectionInterval() time.Duration {
+	return time.Duration(p.DeleteDetectionIntervalSeconds) * time.Second
+}
+
+func (p PollingSettings) Validate() error {
+	if !p.Enabled {
+		return nil
+	}
+
+	if p.OffsetFile == "" {
+		return fmt.Errorf("offset file is required")
+	}
+
+	if p.IntervalSeconds < 0 || p.LagSeconds < 0 || p.DeleteDetectionIntervalSeconds < 0 {
+		return fmt.Errorf("intervalSeconds, lagSeconds and deleteDetectionIntervalSeconds cannot be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollingSettings_Validate(t *testing.T) {
+	{
+		// Disabled
+		assert.NoError(t, PollingSettings{}.Validate())
+	}
+	{
+		// Offset file not set
+		assert.ErrorContains(t, PollingSettings{Enabled: true}.Validate(), "offset file is required")
+	}
+	{
+		// Negative values
+		settings := PollingSettings{Enabled: true, OffsetFile: "/tmp/offset", LagSeconds: -1}
+		assert.ErrorContains(t, settings.Validate(), "cannot be negative")
+	}
+	{
+		// Valid
+		assert.NoError(t, PollingSettings{Enabled: true, OffsetFile: "/tmp/offset"}.Validate())
+	}
+}
+
+func TestPollingSettings_Getters(t *testing.T) {
+	{
+		// Defaults
+		settings := PollingSettings{}
+		assert.Equal(t, 10*time.Second, settings.GetInterval())
+		assert.Equal(t, 5*time.Second, settings.GetLag())
+		assert.Zero(t, settings.GetDeleteDetectionInterval())
+	}
+	{
+		// Overridden
+		settings := PollingSettings{IntervalSeconds: 60, LagSeconds: 30, DeleteDetectionIntervalSeconds: 3600}
+		assert.Equal(t, time.Minute, settings.GetInterval())
+		assert.Equal(t, 30*time.Second, settings.GetLag())
+		assert.Equal(t, time.Hour, settings.GetDeleteDetectionInterval())
+	}
+}
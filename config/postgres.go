@@ -12,14 +12,55 @@ import (
 	"github.com/artie-labs/reader/lib/rdbms/scan"
 )
 
+type PostgreSQLStreamingSettings struct {
+	Enabled    bool   `yaml:"enabled,omitempty"`
+	OffsetFile string `yaml:"offsetFile,omitempty"`
+	// SlotName - Name of the logical replication slot, it will be created with the pgoutput plugin if it does not exist.
+	SlotName string `yaml:"slotName,omitempty"`
+	// PublicationName - Name of the publication, it will be created for the configured tables if it does not exist.
+	PublicationName string `yaml:"publicationName,omitempty"`
+	BatchSize       int32  `yaml:"batchSize,omitempty"`
+}
+
+func (p PostgreSQLStreamingSettings) Validate() error {
+	if !p.Enabled {
+		return nil
+	}
+
+	if p.OffsetFile == "" {
+		return fmt.Errorf("offset file is required")
+	}
+
+	if p.SlotName == "" {
+		return fmt.Errorf("slot name is required")
+	}
+
+	if p.PublicationName == "" {
+		return fmt.Errorf("publication name is required")
+	}
+
+	return nil
+}
+
 type PostgreSQL struct {
-	Host       string             `yaml:"host"`
-	Port       int                `yaml:"port"`
-	Username   string             `yaml:"username"`
-	Password   string             `yaml:"password"`
-	Database   string             `yaml:"database"`
-	Tables     []*PostgreSQLTable `yaml:"tables"`
-	DisableSSL bool               `yaml:"disableSSL"`
+	Host              string                      `yaml:"host"`
+	Port              int                         `yaml:"port"`
+	Username          string                      `yaml:"username"`
+	Password          string                      `yaml:"password"`
+	Database          string                      `yaml:"database"`
+	Tables            []*PostgreSQLTable          `yaml:"tables"`
+	DisableSSL        bool                        `yaml:"disableSSL"`
+	StreamingSettings PostgreSQLStreamingSettings `yaml:"streamingSettings,omitempty"`
+	// MaxConcurrentTables - Number of tables that are snapshotted at the same time, defaults to one.
+	MaxConcurrentTables int `yaml:"maxConcurrentTables,omitempty"`
+	// SnapshotCheckpointFile - If set, snapshot progress is persisted here so that an interrupted snapshot can be resumed.
+	SnapshotCheckpointFile string `yaml:"snapshotCheckpointFile,omitempty"`
+	// PollingSettings - Captures changes by polling each table with its [CursorColumn] instead of streaming.
+	PollingSettings PollingSettings `yaml:"pollingSettings,omitempty"`
+}
+
+func (p PostgreSQL) GetStreamingBatchSize() int32 {
+	return cmp.Or(p.StreamingSettings.BatchSize, constants.DefaultBatchSize)
 }
 
 func (p *PostgreSQL) ToDSN() string {
@@ -33,6 +74,11 @@ func (p *PostgreSQL) ToDSN() string {
 	return connString
 }
 
+// ToReplicationDSN returns a connection string that opens a logical replication connection.
+func (p *PostgreSQL) ToReplicationDSN() string {
+	return fmt.Sprintf("%s replication=database", p.ToDSN())
+}
+
 type PostgreSQLTable struct {
 	Name   string `yaml:"name"`
 	Schema string `yaml:"schema"`
@@ -45,6 +91,11 @@ type PostgreSQLTable struct {
 	ExcludeColumns             []string `yaml:"excludeColumns,omitempty"`
 	// IncludeColumns - List of columns that should be included in the change event record.
 	IncludeColumns []string `yaml:"includeColumns,omitempty"`
+	// SnapshotParallelism - Number of chunks that the primary key range is split into and scanned concurrently.
+	SnapshotParallelism uint `yaml:"snapshotParallelism,omitempty"`
+	// CursorColumn - Column that is used to find changed rows when polling (e.g. `updated_at`), it should be set
+	// whenever a row is inserted or updated.
+	CursorColumn string `yaml:"cursorColumn,omitempty"`
 }
 
 func (p *PostgreSQLTable) GetBatchSize() uint {
@@ -71,6 +122,7 @@ func (p *PostgreSQLTable) ToScannerConfig(errorRetries int) scan.ScannerConfig {
 		OptionalStartingValues: p.GetOptionalPrimaryKeyValStart(),
 		OptionalEndingValues:   p.GetOptionalPrimaryKeyValEnd(),
 		ErrorRetries:           errorRetries,
+		Parallelism:            p.SnapshotParallelism,
 	}
 }
 
@@ -93,6 +145,10 @@ func (p *PostgreSQL) Validate() error {
 		return fmt.Errorf("no tables passed in")
 	}
 
+	if p.MaxConcurrentTables < 0 {
+		return fmt.Errorf("maxConcurrentTables cannot be negative")
+	}
+
 	for _, table := range p.Tables {
 		if table.Name == "" {
 			return fmt.Errorf("table name must be passed in")
@@ -106,7 +162,19 @@ func (p *PostgreSQL) Validate() error {
 		if len(table.ExcludeColumns) > 0 && len(table.IncludeColumns) > 0 {
 			return fmt.Errorf("cannot exclude and include columns at the same time")
 		}
+
+		if p.PollingSettings.Enabled && table.CursorColumn == "" {
+			return fmt.Errorf("cursor column is required for table %q when polling is enabled", table.Name)
+		}
 	}
 
-	return nil
+	if p.PollingSettings.Enabled && p.StreamingSettings.Enabled {
+		return fmt.Errorf("polling and streaming cannot be enabled at the same time")
+	}
+
+	if err := p.PollingSettings.Validate(); err != nil {
+		return fmt.Errorf("invalid polling settings: %w", err)
+	}
+
+	return p.StreamingSettings.Validate()
 }
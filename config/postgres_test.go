@@ -154,6 +154,47 @@ func TestPostgreSQL_Validate(t *testing.T) {
 		}
 		assert.NoError(t, p.Validate())
 	}
+	{
+		// Streaming
+		p := &PostgreSQL{
+			Host:     "host",
+			Port:     1,
+			Username: "username",
+			Password: "password",
+			Database: "database",
+			Tables: []*PostgreSQLTable{
+				{
+					Name:   "name",
+					Schema: "schema",
+				},
+			},
+		}
+		{
+			// Offset file not set
+			p.StreamingSettings.Enabled = true
+			assert.ErrorContains(t, p.Validate(), "offset file is required")
+		}
+		{
+			// Slot name not set
+			p.StreamingSettings.OffsetFile = "/tmp/offset"
+			assert.ErrorContains(t, p.Validate(), "slot name is required")
+		}
+		{
+			// Publication name not set
+			p.StreamingSettings.SlotName = "artie"
+			assert.ErrorContains(t, p.Validate(), "publication name is required")
+		}
+		{
+			// Valid
+			p.StreamingSettings.PublicationName = "dbz_publication"
+			assert.NoError(t, p.Validate())
+		}
+	}
+}
+
+func TestPostgreSQL_ToReplicationDSN(t *testing.T) {
+	p := &PostgreSQL{Host: "host", Port: 5432, Username: "user", Password: "pass", Database: "db"}
+	assert.Equal(t, "user=user dbname=db password=pass port=5432 host=host replication=database", p.ToReplicationDSN())
 }
 
 func TestPostgreSQLTable_GetBatchSize(t *testing.T) {
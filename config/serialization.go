@@ -0,0 +1,82 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+type Serializer string
+
+const (
+	// SerializerJSON - Debezium envelope encoded as JSON with the schema inlined, this is the default.
+	SerializerJSON Serializer = "json"
+	// SerializerAvro - Avro encoded using the Confluent wire format, schemas are registered with [SchemaRegistry].
+	SerializerAvro Serializer = "avro"
+)
+
+func (s Serializer) Validate() error {
+	switch s {
+	case "", SerializerJSON, SerializerAvro:
+		return nil
+	default:
+		return fmt.Errorf("invalid serializer: %q", s)
+	}
+}
+
+type SubjectNameStrategy string
+
+const (
+	// SubjectNameStrategyTopicName - `<topic>-key` and `<topic>-value`, this is the default.
+	SubjectNameStrategyTopicName SubjectNameStrategy = "topic_name"
+	// SubjectNameStrategyRecordName - Fully-qualified name of the record.
+	SubjectNameStrategyRecordName SubjectNameStrategy = "record_name"
+	// SubjectNameStrategyTopicRecordName - `<topic>-<fully-qualified name of the record>`.
+	SubjectNameStrategyTopicRecordName SubjectNameStrategy = "topic_record_name"
+)
+
+var compatibilityLevels = []string{
+	"BACKWARD",
+	"BACKWARD_TRANSITIVE",
+	"FORWARD",
+	"FORWARD_TRANSITIVE",
+	"FULL",
+	"FULL_TRANSITIVE",
+	"NONE",
+}
+
+type SchemaRegistry struct {
+	URL string `yaml:"url"`
+	// Optional
+	Username            string              `yaml:"username,omitempty"`
+	Password            string              `yaml:"password,omitempty"`
+	SubjectNameStrategy SubjectNameStrategy `yaml:"subjectNameStrategy,omitempty"`
+	// Compatibility - If set, this compatibility level will be set on each subject before we register schemas for it.
+	Compatibility string `yaml:"compatibility,omitempty"`
+}
+
+func (s *SchemaRegistry) GetSubjectNameStrategy() SubjectNameStrategy {
+	return cmp.Or(s.SubjectNameStrategy, SubjectNameStrategyTopicName)
+}
+
+func (s *SchemaRegistry) Validate() error {
+	if s == nil {
+		return fmt.Errorf("schema registry config is nil")
+	}
+
+	if s.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+
+	switch s.GetSubjectNameStrategy() {
+	case SubjectNameStrategyTopicName, SubjectNameStrategyRecordName, SubjectNameStrategyTopicRecordName:
+	default:
+		return fmt.Errorf("invalid subject name strategy: %q", s.SubjectNameStrategy)
+	}
+
+	if s.Compatibility != "" && !slices.Contains(compatibilityLevels, s.Compatibility) {
+		return fmt.Errorf("invalid compatibility level: %q", s.Compatibility)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafka_Validate_Serialization(t *testing.T) {
+	newKafka := func() *Kafka {
+		return &Kafka{BootstrapServers: "localhost:9092", TopicPrefix: "prefix"}
+	}
+	{
+		// Defaults to JSON
+		k := newKafka()
+		assert.NoError(t, k.Validate())
+		assert.Equal(t, SerializerJSON, k.GetKeySerializer())
+		assert.Equal(t, SerializerJSON, k.GetValueSerializer())
+		assert.False(t, k.UsesAvro())
+	}
+	{
+		// Invalid serializer
+		k := newKafka()
+		k.ValueSerializer = "protobuf"
+		assert.ErrorContains(t, k.Validate(), `invalid value serializer: invalid serializer: "protobuf"`)
+	}
+	{
+		// Avro without a schema registry
+		k := newKafka()
+		k.KeySerializer = SerializerAvro
+		assert.ErrorContains(t, k.Validate(), "invalid schema registry: schema registry config is nil")
+	}
+	{
+		// Avro
+		k := newKafka()
+		k.ValueSerializer = SerializerAvro
+		k.SchemaRegistry = &SchemaRegistry{URL: "http://localhost:8081"}
+		assert.NoError(t, k.Validate())
+		assert.True(t, k.UsesAvro())
+	}
+}
+
+func TestSchemaRegistry_Validate(t *testing.T) {
+	{
+		// Missing URL
+		s := &SchemaRegistry{}
+		assert.ErrorContains(t, s.Validate(), "url is required")
+	}
+	{
+		// Default subject name strategy
+		s := &SchemaRegistry{URL: "http://localhost:8081"}
+		assert.NoError(t, s.Validate())
+		assert.Equal(t, SubjectNameStrategyTopicName, s.GetSubjectNameStrategy())
+	}
+	{
+		// Invalid subject name strategy
+		s := &SchemaRegistry{URL: "http://localhost:8081", SubjectNameStrategy: "foo"}
+		assert.ErrorContains(t, s.Validate(), `invalid subject name strategy: "foo"`)
+	}
+	{
+		// Invalid compatibility level
+		s := &SchemaRegistry{URL: "http://localhost:8081", Compatibility: "backward"}
+		assert.ErrorContains(t, s.Validate(), `invalid compatibility level: "backward"`)
+	}
+	{
+		// Valid
+		s := &SchemaRegistry{URL: "http://localhost:8081", SubjectNameStrategy: SubjectNameStrategyTopicRecordName, Compatibility: "BACKWARD"}
+		assert.NoError(t, s.Validate())
+	}
+}
@@ -3,4 +3,8 @@ package constants
 const (
 	DefaultBatchSize   = 5_000
 	DefaultPublishSize = 2_500
+	// DefaultShutdownTimeoutSeconds - How long we'll wait for in-flight writes to drain once we receive a shutdown signal.
+	DefaultShutdownTimeoutSeconds = 30
+	// DefaultUnavailableValuePlaceholder - Matches Debezium, so that destinations keep the existing value.
+	DefaultUnavailableValuePlaceholder = "__debezium_unavailable_value"
 )
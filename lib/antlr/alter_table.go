@@ -11,7 +11,7 @@ import (
 
 func processAlterTable(ctx *generated.AlterTableContext) ([]Event, error) {
 	var events []Event
-	tableName, err := getTableNameFromNode(ctx.TableName())
+	database, tableName, err := getTableNameFromNode(ctx.TableName())
 	if err != nil {
 		return nil, err
 	}
@@ -42,14 +42,14 @@ func processAlterTable(ctx *generated.AlterTableContext) ([]Event, error) {
 				}
 			}
 
-			events = append(events, AddColumnsEvent{TableName: tableName, Columns: cols})
+			events = append(events, AddColumnsEvent{Database: database, TableName: tableName, Columns: cols})
 		case *generated.AlterByAddColumnContext:
 			col, err := processAddOrModifyColumn(spec)
 			if err != nil {
 				return nil, err
 			}
 
-			events = append(events, AddColumnsEvent{TableName: tableName, Columns: []Column{col}})
+			events = append(events, AddColumnsEvent{Database: database, TableName: tableName, Columns: []Column{col}})
 		case
 			*generated.AlterByModifyColumnContext,
 			*generated.AlterByChangeDefaultContext:
@@ -58,9 +58,9 @@ func processAlterTable(ctx *generated.AlterTableContext) ([]Event, error) {
 				return nil, err
 			}
 
-			events = append(events, ModifyColumnEvent{TableName: tableName, Column: col})
+			events = append(events, ModifyColumnEvent{Database: database, TableName: tableName, Column: col})
 		case *generated.AlterByDropColumnContext:
-			dropColEvent, err := processDropColumn(tableName, spec)
+			dropColEvent, err := processDropColumn(database, tableName, spec)
 			if err != nil {
 				return nil, err
 			}
@@ -72,16 +72,37 @@ func processAlterTable(ctx *generated.AlterTableContext) ([]Event, error) {
 				return nil, err
 			}
 
-			events = append(events, AddPrimaryKeyEvent{TableName: tableName, Columns: cols})
+			events = append(events, AddPrimaryKeyEvent{Database: database, TableName: tableName, Columns: cols})
+		case *generated.AlterByAddUniqueKeyContext:
+			name := spec.GetIndexName()
+			if name == nil {
+				name = spec.GetName()
+			}
+
+			uniqueKey, err := processUniqueKey(name, spec.IndexColumnNames())
+			if err != nil {
+				return nil, fmt.Errorf("failed to process unique key: %w", err)
+			}
+
+			if len(uniqueKey.Columns) > 0 {
+				events = append(events, AddUniqueKeyEvent{Database: database, TableName: tableName, UniqueKey: uniqueKey})
+			}
+		case *generated.AlterByDropIndexContext:
+			indexName, err := getTextFromSingleNodeBranch(spec.Uid())
+			if err != nil {
+				return nil, err
+			}
+
+			events = append(events, DropIndexEvent{Database: database, TableName: tableName, IndexName: indexName})
 		case *generated.AlterByRenameColumnContext:
-			event, err := processRenameColumn(tableName, spec.AllUid())
+			event, err := processRenameColumn(database, tableName, spec.AllUid())
 			if err != nil {
 				return nil, err
 			}
 
 			events = append(events, event)
 		case *generated.AlterByChangeColumnContext:
-			event, err := processChangeColumn(tableName, spec)
+			event, err := processChangeColumn(database, tableName, spec)
 			if err != nil {
 				return nil, err
 			}
@@ -95,7 +116,7 @@ func processAlterTable(ctx *generated.AlterTableContext) ([]Event, error) {
 	return events, nil
 }
 
-func processRenameColumn(tableName string, allUids []generated.IUidContext) (RenameColumnEvent, error) {
+func processRenameColumn(database, tableName string, allUids []generated.IUidContext) (RenameColumnEvent, error) {
 	if len(allUids) != 2 {
 		// You can only do one column rename in an ALTER TABLE statement
 		return RenameColumnEvent{}, fmt.Errorf("expected 2 uids, got %d", len(allUids))
@@ -111,7 +132,7 @@ func processRenameColumn(tableName string, allUids []generated.IUidContext) (Ren
 		return RenameColumnEvent{}, err
 	}
 
-	return RenameColumnEvent{TableName: tableName, Column: Column{Name: newName, PreviousName: oldName}}, nil
+	return RenameColumnEvent{Database: database, TableName: tableName, Column: Column{Name: newName, PreviousName: oldName}}, nil
 }
 
 func processAddPrimaryKey(ctx *generated.AlterByAddPrimaryKeyContext) ([]Column, error) {
@@ -194,7 +215,7 @@ func processAddOrModifyColumn(ctx generated.IAlterSpecificationContext) (Column,
 	return col, nil
 }
 
-func processChangeColumn(tableName string, spec *generated.AlterByChangeColumnContext) (ModifyColumnEvent, error) {
+func processChangeColumn(database, tableName string, spec *generated.AlterByChangeColumnContext) (ModifyColumnEvent, error) {
 	col, err := processAddOrModifyColumn(spec)
 	if err != nil {
 		return ModifyColumnEvent{}, err
@@ -205,7 +226,7 @@ func processChangeColumn(tableName string, spec *generated.AlterByChangeColumnCo
 		return ModifyColumnEvent{}, fmt.Errorf("expected 2 or 3 uids, got %d", len(allUids))
 	}
 
-	renameEvent, err := processRenameColumn(tableName, allUids[:2])
+	renameEvent, err := processRenameColumn(database, tableName, allUids[:2])
 	if err != nil {
 		return ModifyColumnEvent{}, err
 	}
@@ -214,10 +235,10 @@ func processChangeColumn(tableName string, spec *generated.AlterByChangeColumnCo
 	col.PreviousName = renameEvent.Column.PreviousName
 	col.Name = renameEvent.Column.Name
 
-	return ModifyColumnEvent{TableName: tableName, Column: col}, nil
+	return ModifyColumnEvent{Database: database, TableName: tableName, Column: col}, nil
 }
 
-func processDropColumn(tableName string, ctx *generated.AlterByDropColumnContext) (DropColumnsEvent, error) {
+func processDropColumn(database, tableName string, ctx *generated.AlterByDropColumnContext) (DropColumnsEvent, error) {
 	var col Column
 	for _, child := range ctx.GetChildren() {
 		switch castedChild := child.(type) {
@@ -231,5 +252,5 @@ func processDropColumn(tableName string, ctx *generated.AlterByDropColumnContext
 		}
 	}
 
-	return DropColumnsEvent{TableName: tableName, Column: col}, nil
+	return DropColumnsEvent{Database: database, TableName: tableName, Column: col}, nil
 }
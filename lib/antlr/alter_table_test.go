@@ -153,18 +153,6 @@ func TestColumn_DefaultValue(t *testing.T) {
 func TestAlterTable(t *testing.T) {
 	{
 		// Irrelevant
-		{
-			// Truncating a table
-			events, err := Parse("TRUNCATE TABLE foo;")
-			assert.NoError(t, err)
-			assert.Empty(t, events)
-		}
-		{
-			// Dropping an index
-			events, err := Parse("ALTER TABLE table_name DROP INDEX index_name;")
-			assert.NoError(t, err)
-			assert.Empty(t, events)
-		}
 		{
 			// Adding an index
 			events, err := Parse("ALTER TABLE table_name ADD INDEX index_name (col1, col2);")
@@ -183,12 +171,6 @@ func TestAlterTable(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Empty(t, events)
 		}
-		{
-			// Adding a constraint
-			events, err := Parse("ALTER TABLE table_name ADD CONSTRAINT constraint_name UNIQUE (col1, col2);")
-			assert.NoError(t, err)
-			assert.Empty(t, events)
-		}
 		{
 			// Dropping a constraint
 			events, err := Parse("ALTER TABLE table_name DROP CONSTRAINT constraint_name;")
@@ -324,6 +306,26 @@ func TestAlterTable(t *testing.T) {
 				assert.Equal(t, Column{Name: "c4", DataType: "varchar(255)", PrimaryKey: false, Position: FirstPosition{}}, addColEvent.GetColumns()[0])
 			}
 		}
+		{
+			// Dropping an index
+			events, err := Parse("ALTER TABLE table_name DROP INDEX `index_name`;")
+			assert.NoError(t, err)
+			assert.Equal(t, []Event{DropIndexEvent{TableName: "table_name", IndexName: "`index_name`"}}, events)
+			assert.Equal(t, "index_name", events[0].(DropIndexEvent).GetIndexName())
+		}
+		{
+			// Adding a unique constraint
+			events, err := Parse("ALTER TABLE table_name ADD CONSTRAINT constraint_name UNIQUE (col1, col2);")
+			assert.NoError(t, err)
+			assert.Equal(t, []Event{AddUniqueKeyEvent{TableName: "table_name", UniqueKey: UniqueKey{Name: "constraint_name", Columns: []string{"col1", "col2"}}}}, events)
+		}
+		{
+			// Adding a unique index, the index name takes precedence and prefix lengths are ignored
+			events, err := Parse("ALTER TABLE table_name ADD CONSTRAINT c UNIQUE KEY `idx` (`col1`(10), col2 DESC);")
+			assert.NoError(t, err)
+			assert.Len(t, events, 1)
+			assert.Equal(t, UniqueKey{Name: "idx", Columns: []string{"col1", "col2"}}, events[0].(AddUniqueKeyEvent).GetUniqueKey())
+		}
 		{
 			// Adding a column without specifying "column"
 			events, err := Parse("ALTER TABLE `order` ADD cancelled TINYINT(1) DEFAULT 0 NOT NULL, ADD delivered TINYINT(1) DEFAULT 0 NOT NULL, ADD returning TINYINT(1) DEFAULT 0 NOT NULL;")
@@ -333,17 +335,17 @@ func TestAlterTable(t *testing.T) {
 			addColEvent1, isOk := events[0].(AddColumnsEvent)
 			assert.True(t, isOk)
 			assert.Equal(t, "order", addColEvent1.GetTable())
-			assertOneElement(t, Column{Name: "cancelled", DataType: "TINYINT(1)", DefaultValue: typing.ToPtr("0"), PrimaryKey: false}, addColEvent1.GetColumns())
+			assertOneElement(t, Column{Name: "cancelled", DataType: "TINYINT(1)", DefaultValue: typing.ToPtr("0"), PrimaryKey: false, NotNull: true}, addColEvent1.GetColumns())
 
 			addColEvent2, isOk := events[1].(AddColumnsEvent)
 			assert.True(t, isOk)
 			assert.Equal(t, "order", addColEvent2.GetTable())
-			assertOneElement(t, Column{Name: "delivered", DataType: "TINYINT(1)", DefaultValue: typing.ToPtr("0"), PrimaryKey: false}, addColEvent2.GetColumns())
+			assertOneElement(t, Column{Name: "delivered", DataType: "TINYINT(1)", DefaultValue: typing.ToPtr("0"), PrimaryKey: false, NotNull: true}, addColEvent2.GetColumns())
 
 			addColEvent3, isOk := events[2].(AddColumnsEvent)
 			assert.True(t, isOk)
 			assert.Equal(t, "order", addColEvent3.GetTable())
-			assertOneElement(t, Column{Name: "returning", DataType: "TINYINT(1)", DefaultValue: typing.ToPtr("0"), PrimaryKey: false}, addColEvent3.GetColumns())
+			assertOneElement(t, Column{Name: "returning", DataType: "TINYINT(1)", DefaultValue: typing.ToPtr("0"), PrimaryKey: false, NotNull: true}, addColEvent3.GetColumns())
 		}
 		{
 			// Adding column + including a comment
@@ -365,7 +367,7 @@ func TestAlterTable(t *testing.T) {
 			addColEvent, isOk := events[0].(AddColumnsEvent)
 			assert.True(t, isOk)
 			assert.Equal(t, "goods", addColEvent.GetTable())
-			assertOneElement(t, Column{Name: "id", DataType: "int(10) unsigned", PrimaryKey: true}, addColEvent.GetColumns())
+			assertOneElement(t, Column{Name: "id", DataType: "int(10) unsigned", PrimaryKey: true, NotNull: true}, addColEvent.GetColumns())
 		}
 	}
 	{
@@ -19,6 +19,9 @@ func (c Column) buildDataTypePrimaryKey(ctx generated.IColumnDefinitionContext)
 		switch castedConstraint := constraint.(type) {
 		case *generated.PrimaryKeyColumnConstraintContext:
 			returnedCol.PrimaryKey = true
+			returnedCol.NotNull = true
+		case *generated.NullColumnConstraintContext:
+			returnedCol.NotNull = castedConstraint.NullNotnull().NOT() != nil
 		case *generated.DefaultColumnConstraintContext:
 			returnedCol.DefaultValue = parseDefaultValue(castedConstraint.DefaultValue())
 		}
@@ -64,6 +67,16 @@ func (c Column) buildDataTypePrimaryKey(ctx generated.IColumnDefinitionContext)
 	return returnedCol, nil
 }
 
+func hasUniqueKeyConstraint(ctx *generated.ColumnDeclarationContext) bool {
+	for _, constraint := range ctx.ColumnDefinition().AllColumnConstraint() {
+		if _, isOk := constraint.(*generated.UniqueKeyColumnConstraintContext); isOk {
+			return true
+		}
+	}
+
+	return false
+}
+
 func processColumn(ctx *generated.ColumnDeclarationContext) (Column, error) {
 	var col Column
 	for _, colChild := range ctx.GetChildren() {
@@ -103,32 +116,67 @@ func processPrimaryKeyConstraintNode(node *generated.PrimaryKeyTableConstraintCo
 	return colNames, nil
 }
 
+// processUniqueKey - Builds a unique key from its name and columns, MySQL names unnamed indexes after their first column.
+func processUniqueKey(name generated.IUidContext, ctx generated.IIndexColumnNamesContext) (UniqueKey, error) {
+	var key UniqueKey
+	for _, indexColumn := range ctx.AllIndexColumnName() {
+		if indexColumn.Uid() == nil {
+			// Functional key parts cannot be used to identify rows, no columns are returned.
+			return UniqueKey{}, nil
+		}
+
+		colName, err := getTextFromSingleNodeBranch(indexColumn.Uid())
+		if err != nil {
+			return UniqueKey{}, err
+		}
+
+		key.Columns = append(key.Columns, colName)
+	}
+
+	if name != nil {
+		var err error
+		if key.Name, err = getTextFromSingleNodeBranch(name); err != nil {
+			return UniqueKey{}, err
+		}
+	} else if len(key.Columns) > 0 {
+		key.Name = key.Columns[0]
+	}
+
+	return key, nil
+}
+
 func processCopyTable(ctx *generated.CopyCreateTableContext) (Event, error) {
 	tableNames := ctx.AllTableName()
 	if len(tableNames) != 2 {
 		return nil, fmt.Errorf("expected exactly 2 table names, got %d", len(tableNames))
 	}
 
-	tableName, err := getTableNameFromNode(tableNames[0])
+	database, tableName, err := getTableNameFromNode(tableNames[0])
 	if err != nil {
 		return nil, err
 	}
 
-	copiedFromTableName, err := getTableNameFromNode(tableNames[1])
+	copiedFromDatabase, copiedFromTableName, err := getTableNameFromNode(tableNames[1])
 	if err != nil {
 		return nil, err
 	}
 
-	return CopyTableEvent{tableName: tableName, copyFromTableName: copiedFromTableName}, nil
+	return CopyTableEvent{
+		database:          database,
+		tableName:         tableName,
+		copyFromDatabase:  copiedFromDatabase,
+		copyFromTableName: copiedFromTableName,
+	}, nil
 }
 
 func processCreateTable(ctx *generated.ColumnCreateTableContext) (Event, error) {
-	tableName, err := getTableNameFromNode(ctx.TableName())
+	database, tableName, err := getTableNameFromNode(ctx.TableName())
 	if err != nil {
 		return nil, err
 	}
 
 	var columns []Column
+	var uniqueKeys []UniqueKey
 	for _, child := range ctx.GetChildren() {
 		switch castedChild := child.(type) {
 		case *generated.CreateDefinitionsContext:
@@ -141,9 +189,13 @@ func processCreateTable(ctx *generated.ColumnCreateTableContext) (Event, error)
 					}
 
 					columns = append(columns, _col)
+					if hasUniqueKeyConstraint(castedNode) {
+						uniqueKeys = append(uniqueKeys, UniqueKey{Name: _col.Name, Columns: []string{_col.Name}})
+					}
 				case *generated.ConstraintDeclarationContext:
 					for _, constraintChild := range castedNode.GetChildren() {
-						if casted, ok := constraintChild.(*generated.PrimaryKeyTableConstraintContext); ok {
+						switch casted := constraintChild.(type) {
+						case *generated.PrimaryKeyTableConstraintContext:
 							colNames, err := processPrimaryKeyConstraintNode(casted)
 							if err != nil {
 								return nil, err
@@ -156,6 +208,21 @@ func processCreateTable(ctx *generated.ColumnCreateTableContext) (Event, error)
 								}
 
 								columns[columnIdx].PrimaryKey = true
+								columns[columnIdx].NotNull = true
+							}
+						case *generated.UniqueKeyTableConstraintContext:
+							name := casted.GetIndex()
+							if name == nil {
+								name = casted.GetName()
+							}
+
+							uniqueKey, err := processUniqueKey(name, casted.IndexColumnNames())
+							if err != nil {
+								return nil, fmt.Errorf("failed to process unique key: %w", err)
+							}
+
+							if len(uniqueKey.Columns) > 0 {
+								uniqueKeys = append(uniqueKeys, uniqueKey)
 							}
 						}
 					}
@@ -172,5 +239,5 @@ func processCreateTable(ctx *generated.ColumnCreateTableContext) (Event, error)
 		return nil, fmt.Errorf("failed to extract columns")
 	}
 
-	return CreateTableEvent{TableName: tableName, Columns: columns}, nil
+	return CreateTableEvent{Database: database, TableName: tableName, Columns: columns, UniqueKeys: uniqueKeys}, nil
 }
@@ -95,7 +95,7 @@ func TestCreateTable(t *testing.T) {
 
 			assert.Equal(t, "table_name", createTableEvent.GetTable())
 			assert.Len(t, createTableEvent.GetColumns(), 2)
-			assert.Equal(t, []Column{{Name: "id", DataType: "INT", PrimaryKey: true}, {Name: "name", DataType: "VARCHAR(255)", PrimaryKey: false}}, createTableEvent.GetColumns())
+			assert.Equal(t, []Column{{Name: "id", DataType: "INT", PrimaryKey: true, NotNull: true}, {Name: "name", DataType: "VARCHAR(255)", PrimaryKey: false}}, createTableEvent.GetColumns())
 		}
 		{
 			// Two primary keys
@@ -107,7 +107,7 @@ func TestCreateTable(t *testing.T) {
 
 			assert.Equal(t, "table_name", createTableEvent.GetTable())
 			assert.Len(t, createTableEvent.GetColumns(), 2)
-			assert.Equal(t, []Column{{Name: "id", DataType: "INT", PrimaryKey: true}, {Name: "name", DataType: "VARCHAR(255)", PrimaryKey: true}}, createTableEvent.GetColumns())
+			assert.Equal(t, []Column{{Name: "id", DataType: "INT", PrimaryKey: true, NotNull: true}, {Name: "name", DataType: "VARCHAR(255)", PrimaryKey: true, NotNull: true}}, createTableEvent.GetColumns())
 		}
 	}
 	{
@@ -123,7 +123,7 @@ func TestCreateTable(t *testing.T) {
 		assert.Len(t, createTableEvent.GetColumns(), 5)
 		assert.Equal(t,
 			[]Column{
-				{Name: "id", DataType: "INT", PrimaryKey: true},
+				{Name: "id", DataType: "INT", PrimaryKey: true, NotNull: true},
 				{Name: "name", DataType: "VARCHAR(255)", PrimaryKey: false},
 				{Name: "tinyint1", DataType: "TINYINT(1)", PrimaryKey: false},
 				{Name: "bool_test", DataType: "BOOLEAN", PrimaryKey: false},
@@ -153,17 +153,49 @@ func TestCreateTable(t *testing.T) {
 		assert.Equal(t, "dt_table", createTableEvent.GetTable())
 		assert.Len(t, createTableEvent.GetColumns(), 9)
 		assert.Equal(t, []Column{
-			{Name: "dt1", DataType: "DATETIME", PrimaryKey: false},
-			{Name: "dt2", DataType: "DATETIME", PrimaryKey: false},
-			{Name: "dt3", DataType: "DATETIME", PrimaryKey: false},
-			{Name: "dt4", DataType: "DATETIME", PrimaryKey: false},
-			{Name: "dt5", DataType: "DATETIME", PrimaryKey: false},
-			{Name: "dt6", DataType: "DATETIME", PrimaryKey: false},
-			{Name: "dt7", DataType: "DATETIME", PrimaryKey: false},
+			{Name: "dt1", DataType: "DATETIME", PrimaryKey: false, NotNull: true},
+			{Name: "dt2", DataType: "DATETIME", PrimaryKey: false, NotNull: true},
+			{Name: "dt3", DataType: "DATETIME", PrimaryKey: false, NotNull: true},
+			{Name: "dt4", DataType: "DATETIME", PrimaryKey: false, NotNull: true},
+			{Name: "dt5", DataType: "DATETIME", PrimaryKey: false, NotNull: true},
+			{Name: "dt6", DataType: "DATETIME", PrimaryKey: false, NotNull: true},
+			{Name: "dt7", DataType: "DATETIME", PrimaryKey: false, NotNull: true},
 			{Name: "dt10", DataType: "DATETIME", PrimaryKey: false},
 			{Name: "dt11", DataType: "DATETIME", DefaultValue: typing.ToPtr("2038-01-01 00:00:00"), PrimaryKey: false},
 		}, createTableEvent.GetColumns())
 	}
+	{
+		// Create table with unique keys
+		events, err := Parse("CREATE TABLE `foo` (\n" +
+			"  `a` int NOT NULL,\n" +
+			"  `b` varchar(255) DEFAULT NULL,\n" +
+			"  `c` int NULL UNIQUE,\n" +
+			"  UNIQUE KEY `a_b` (`a`,`b`),\n" +
+			"  UNIQUE KEY (`b`(10)),\n" +
+			"  UNIQUE KEY `expr` ((`a` + 1))\n" +
+			") ENGINE=InnoDB;")
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+
+		createTableEvent, isOk := events[0].(CreateTableEvent)
+		assert.True(t, isOk)
+		assert.Equal(t,
+			[]Column{
+				{Name: "a", DataType: "int", NotNull: true},
+				{Name: "b", DataType: "varchar(255)"},
+				{Name: "c", DataType: "int"},
+			},
+			createTableEvent.GetColumns(),
+		)
+		assert.Equal(t,
+			[]UniqueKey{
+				{Name: "c", Columns: []string{"c"}},
+				{Name: "a_b", Columns: []string{"a", "b"}},
+				{Name: "b", Columns: []string{"b"}},
+			},
+			createTableEvent.GetUniqueKeys(),
+		)
+	}
 	{
 		// Create table (partitioned)
 		events, err := Parse(`CREATE TABLE table_items (id INT, purchased DATE)
@@ -200,7 +232,7 @@ func TestCreateTable(t *testing.T) {
 		assert.Len(t, createTableEvent.GetColumns(), 3)
 		assert.Equal(t,
 			[]Column{
-				{Name: "id", DataType: "INT UNSIGNED", PrimaryKey: true},
+				{Name: "id", DataType: "INT UNSIGNED", PrimaryKey: true, NotNull: true},
 				{Name: "vec1", DataType: "VECTOR", PrimaryKey: false},
 				{Name: "vec2", DataType: "VECTOR", PrimaryKey: false},
 			}, createTableEvent.GetColumns())
@@ -6,15 +6,16 @@ import (
 )
 
 func processDropTable(ctx *generated.DropTableContext) ([]Event, error) {
-	var tableNames []string
+	var databases, tableNames []string
 	for _, child := range ctx.GetChildren() {
 		if tableCtx, ok := child.(*generated.TablesContext); ok {
 			for _, tableNameChild := range tableCtx.AllTableName() {
-				tableName, err := getTableNameFromNode(tableNameChild)
+				database, tableName, err := getTableNameFromNode(tableNameChild)
 				if err != nil {
 					return nil, fmt.Errorf("failed to extract table name: %w", err)
 				}
 
+				databases = append(databases, database)
 				tableNames = append(tableNames, tableName)
 			}
 		}
@@ -25,8 +26,8 @@ func processDropTable(ctx *generated.DropTableContext) ([]Event, error) {
 	}
 
 	var events []Event
-	for _, tableName := range tableNames {
-		events = append(events, DropTableEvent{TableName: tableName})
+	for i, tableName := range tableNames {
+		events = append(events, DropTableEvent{Database: databases[i], TableName: tableName})
 	}
 
 	return events, nil
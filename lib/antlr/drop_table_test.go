@@ -2,8 +2,10 @@ package antlr
 
 import (
 	"fmt"
-	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDropTable(t *testing.T) {
@@ -17,6 +19,11 @@ func TestDropTable(t *testing.T) {
 			dropTableEvent, isOk := events[0].(DropTableEvent)
 			assert.True(t, isOk)
 			assert.Equal(t, "table_name", dropTableEvent.GetTable())
+			if strings.Contains(tblName, "db_name") {
+				assert.Equal(t, "db_name", dropTableEvent.GetDatabase())
+			} else {
+				assert.Empty(t, dropTableEvent.GetDatabase())
+			}
 		}
 	}
 	{
@@ -0,0 +1,122 @@
+package antlr
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/artie-labs/reader/lib/antlr/generated"
+)
+
+// ParseMariaDB parses DDL that was written by MariaDB, see [normalizeMariaDB] for the syntax that it supports on top of
+// [Parse]. The returned bool reports whether `IF [NOT] EXISTS` clauses were dropped, in which case MariaDB may have
+// skipped some of the changes and the events may not apply cleanly to the current schema.
+func ParseMariaDB(sqlCmd string) ([]Event, bool, error) {
+	normalized, conditional := normalizeMariaDB(sqlCmd)
+	events, err := Parse(normalized)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return events, conditional, nil
+}
+
+// normalizeMariaDB rewrites MariaDB specific DDL into its MySQL equivalent so that it can be parsed by the MySQL grammar:
+//   - `CREATE OR REPLACE TABLE` becomes `CREATE TABLE`, which replaces our schema of the table all the same.
+//   - `IF [NOT] EXISTS` is dropped from `ALTER TABLE`, `RENAME TABLE`, `CREATE INDEX` and `DROP INDEX` statements.
+func normalizeMariaDB(sqlCmd string) (string, bool) {
+	lexer := generated.NewMySqlLexer(antlr.NewInputStream(sqlCmd))
+	lexer.RemoveErrorListeners()
+
+	var tokens []antlr.Token
+	for _, token := range lexer.GetAllTokens() {
+		if token.GetChannel() == antlr.TokenDefaultChannel {
+			tokens = append(tokens, token)
+		}
+	}
+
+	// removed - Spans of tokens that are removed along with the whitespace between them, as [first, last] token pairs.
+	var removed [][2]antlr.Token
+	var conditional bool
+	for start := 0; start < len(tokens); {
+		end := slices.IndexFunc(tokens[start:], func(token antlr.Token) bool { return token.GetTokenType() == generated.MySqlLexerSEMI })
+		if end == -1 {
+			end = len(tokens)
+		} else {
+			end += start
+		}
+
+		statement := tokens[start:end]
+		if hasTokenTypes(statement, generated.MySqlLexerCREATE, generated.MySqlLexerOR, generated.MySqlLexerREPLACE) && isMariaDBTableStatement(statement[3:]) {
+			removed = append(removed, [2]antlr.Token{statement[1], statement[2]})
+		} else if isConditionalStatement(statement) {
+			for i := range statement {
+				var clause []antlr.Token
+				if hasTokenTypes(statement[i:], generated.MySqlLexerIF, generated.MySqlLexerNOT, generated.MySqlLexerEXISTS) {
+					clause = statement[i : i+3]
+				} else if hasTokenTypes(statement[i:], generated.MySqlLexerIF, generated.MySqlLexerEXISTS) {
+					clause = statement[i : i+2]
+				}
+
+				if len(clause) > 0 {
+					removed = append(removed, [2]antlr.Token{clause[0], clause[len(clause)-1]})
+					conditional = true
+				}
+			}
+		}
+
+		start = end + 1
+	}
+
+	if len(removed) == 0 {
+		return sqlCmd, false
+	}
+
+	// Token positions are rune offsets into the input.
+	runes := []rune(sqlCmd)
+	var sb strings.Builder
+	var last int
+	for _, span := range removed {
+		sb.WriteString(string(runes[last:span[0].GetStart()]))
+		last = span[1].GetStop() + 1
+	}
+	sb.WriteString(string(runes[last:]))
+	return sb.String(), conditional
+}
+
+// isMariaDBTableStatement returns whether the tokens that follow `CREATE OR REPLACE` create a table, `OR REPLACE` is
+// supported by the MySQL grammar for other objects such as views.
+func isMariaDBTableStatement(tokens []antlr.Token) bool {
+	return hasTokenTypes(tokens, generated.MySqlLexerTABLE) || hasTokenTypes(tokens, generated.MySqlLexerTEMPORARY, generated.MySqlLexerTABLE)
+}
+
+func isConditionalStatement(statement []antlr.Token) bool {
+	if len(statement) == 0 {
+		return false
+	}
+
+	// The object type follows the statement type and modifiers such as ONLINE, IGNORE or UNIQUE.
+	head := statement[:min(len(statement), 4)]
+	switch statement[0].GetTokenType() {
+	case generated.MySqlLexerALTER, generated.MySqlLexerRENAME:
+		return slices.ContainsFunc(head, func(token antlr.Token) bool { return token.GetTokenType() == generated.MySqlLexerTABLE })
+	case generated.MySqlLexerCREATE, generated.MySqlLexerDROP:
+		return slices.ContainsFunc(head, func(token antlr.Token) bool { return token.GetTokenType() == generated.MySqlLexerINDEX })
+	default:
+		return false
+	}
+}
+
+func hasTokenTypes(tokens []antlr.Token, tokenTypes ...int) bool {
+	if len(tokens) < len(tokenTypes) {
+		return false
+	}
+
+	for i, tokenType := range tokenTypes {
+		if tokens[i].GetTokenType() != tokenType {
+			return false
+		}
+	}
+
+	return true
+}
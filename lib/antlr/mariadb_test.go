@@ -0,0 +1,59 @@
+package antlr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeMariaDB(t *testing.T) {
+	{
+		// MySQL syntax is left as is
+		for _, query := range []string{
+			"CREATE TABLE IF NOT EXISTS foo (id INT)",
+			"CREATE OR REPLACE VIEW foo AS SELECT 1",
+			"DROP TABLE IF EXISTS foo",
+			"ALTER TABLE foo ADD COLUMN bar INT DEFAULT (IF(1, 2, 3))",
+		} {
+			normalized, conditional := normalizeMariaDB(query)
+			assert.Equal(t, query, normalized, query)
+			assert.False(t, conditional, query)
+		}
+	}
+	{
+		// CREATE OR REPLACE TABLE
+		normalized, conditional := normalizeMariaDB("create or replace table `foo` (id INT)")
+		assert.Equal(t, "create  table `foo` (id INT)", normalized)
+		assert.False(t, conditional)
+	}
+	{
+		// IF [NOT] EXISTS in ALTER TABLE, strings and comments are not touched
+		normalized, conditional := normalizeMariaDB("ALTER TABLE `naïve` ADD COLUMN IF NOT EXISTS bar VARCHAR(10) DEFAULT 'IF EXISTS' /* IF EXISTS */, DROP COLUMN IF EXISTS baz")
+		assert.Equal(t, "ALTER TABLE `naïve` ADD COLUMN  bar VARCHAR(10) DEFAULT 'IF EXISTS' /* IF EXISTS */, DROP COLUMN  baz", normalized)
+		assert.True(t, conditional)
+	}
+	{
+		// Multiple statements
+		normalized, conditional := normalizeMariaDB("DROP INDEX IF EXISTS foo_idx ON foo; CREATE OR REPLACE TEMPORARY TABLE bar (id INT)")
+		assert.Equal(t, "DROP INDEX  foo_idx ON foo; CREATE  TEMPORARY TABLE bar (id INT)", normalized)
+		assert.True(t, conditional)
+	}
+}
+
+func TestParseMariaDB(t *testing.T) {
+	{
+		events, conditional, err := ParseMariaDB("CREATE OR REPLACE TABLE foo (id INT PRIMARY KEY)")
+		assert.NoError(t, err)
+		assert.False(t, conditional)
+		assert.Equal(t, []Event{CreateTableEvent{TableName: "foo", Columns: []Column{{Name: "id", DataType: "INT", PrimaryKey: true, NotNull: true}}}}, events)
+	}
+	{
+		events, conditional, err := ParseMariaDB("ALTER TABLE foo ADD COLUMN IF NOT EXISTS bar INT, DROP COLUMN IF EXISTS baz")
+		assert.NoError(t, err)
+		assert.True(t, conditional)
+		assert.Equal(t, []Event{
+			AddColumnsEvent{TableName: "foo", Columns: []Column{{Name: "bar", DataType: "INT"}}},
+			DropColumnsEvent{TableName: "foo", Column: Column{Name: "baz"}},
+		}, events)
+	}
+}
@@ -85,6 +85,13 @@ func visit(tree antlr.Tree) ([]Event, error) {
 		return processDropTable(ctx)
 	case *generated.RenameTableContext:
 		return processRenameTable(ctx)
+	case *generated.TruncateTableContext:
+		evt, err := processTruncateTable(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return []Event{evt}, nil
 	case
 		*generated.StartTransactionContext,
 		*generated.CreateViewContext,
@@ -96,7 +103,6 @@ func visit(tree antlr.Tree) ([]Event, error) {
 		*generated.CreateEventContext,
 		*generated.DropEventContext,
 		*generated.EmptyStatement_Context,
-		*generated.TruncateTableContext,
 		*generated.AdministrationStatementContext,
 		*generated.CreateDatabaseContext,
 		*antlr.TerminalNodeImpl,
@@ -11,14 +11,14 @@ func processRenameTable(ctx *generated.RenameTableContext) ([]Event, error) {
 	for _, child := range ctx.GetChildren() {
 		switch castedChild := child.(type) {
 		case *generated.RenameTableClauseContext:
-			var allTableNames []string
+			var allTableNames [][2]string
 			for _, tableName := range castedChild.AllTableName() {
-				parsedTableName, err := getTableNameFromNode(tableName)
+				database, parsedTableName, err := getTableNameFromNode(tableName)
 				if err != nil {
 					return nil, fmt.Errorf("failed to get table name: %w", err)
 				}
 
-				allTableNames = append(allTableNames, parsedTableName)
+				allTableNames = append(allTableNames, [2]string{database, parsedTableName})
 			}
 
 			// Must be at least two table names
@@ -33,8 +33,10 @@ func processRenameTable(ctx *generated.RenameTableContext) ([]Event, error) {
 
 			for group := range slices.Chunk(allTableNames, 2) {
 				renameEvents = append(renameEvents, RenameTableEvent{
-					tableName:    group[0],
-					newTableName: group[1],
+					database:     group[0][0],
+					tableName:    group[0][1],
+					newDatabase:  group[1][0],
+					newTableName: group[1][1],
 				})
 			}
 		}
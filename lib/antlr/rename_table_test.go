@@ -27,7 +27,23 @@ func TestRenameTable(t *testing.T) {
 		renameTableEvent, isOk := events[0].(RenameTableEvent)
 		assert.True(t, isOk)
 
+		assert.Equal(t, "current_db", renameTableEvent.GetDatabase())
 		assert.Equal(t, "tbl_name", renameTableEvent.GetTable())
+		assert.Equal(t, "current_db", renameTableEvent.GetNewDatabase())
+		assert.Equal(t, "tbl_name", renameTableEvent.GetNewTableName())
+	}
+	{
+		// Moving a table to another database
+		events, err := Parse("RENAME TABLE tbl_name TO `other_db`.`tbl_name`;")
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+
+		renameTableEvent, isOk := events[0].(RenameTableEvent)
+		assert.True(t, isOk)
+
+		assert.Equal(t, "", renameTableEvent.GetDatabase())
+		assert.Equal(t, "tbl_name", renameTableEvent.GetTable())
+		assert.Equal(t, "other_db", renameTableEvent.GetNewDatabase())
 		assert.Equal(t, "tbl_name", renameTableEvent.GetNewTableName())
 	}
 	{
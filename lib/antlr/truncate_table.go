@@ -0,0 +1,16 @@
+package antlr
+
+import (
+	"fmt"
+
+	"github.com/artie-labs/reader/lib/antlr/generated"
+)
+
+func processTruncateTable(ctx *generated.TruncateTableContext) (Event, error) {
+	database, tableName, err := getTableNameFromNode(ctx.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract table name: %w", err)
+	}
+
+	return TruncateTableEvent{Database: database, TableName: tableName}, nil
+}
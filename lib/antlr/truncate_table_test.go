@@ -0,0 +1,25 @@
+package antlr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateTable(t *testing.T) {
+	for _, query := range []string{"TRUNCATE TABLE foo", "truncate `foo`;", "TRUNCATE TABLE `db`.`foo`"} {
+		events, err := Parse(query)
+		assert.NoError(t, err, query)
+		assert.Len(t, events, 1, query)
+
+		truncateTableEvent, isOk := events[0].(TruncateTableEvent)
+		assert.True(t, isOk, query)
+		assert.Equal(t, "foo", truncateTableEvent.GetTable(), query)
+		assert.Empty(t, truncateTableEvent.GetColumns(), query)
+		if query == "TRUNCATE TABLE `db`.`foo`" {
+			assert.Equal(t, "db", truncateTableEvent.GetDatabase())
+		} else {
+			assert.Empty(t, truncateTableEvent.GetDatabase(), query)
+		}
+	}
+}
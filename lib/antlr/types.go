@@ -9,6 +9,7 @@ type Column struct {
 	DataType     string
 	DefaultValue *string
 	PrimaryKey   bool
+	NotNull      bool
 	Position     Position
 }
 
@@ -18,6 +19,7 @@ func (c Column) clean() Column {
 		PreviousName: unescape(c.PreviousName),
 		DataType:     c.DataType,
 		PrimaryKey:   c.PrimaryKey,
+		NotNull:      c.NotNull,
 		Position:     c.Position,
 	}
 
@@ -28,7 +30,24 @@ func (c Column) clean() Column {
 	return col
 }
 
+type UniqueKey struct {
+	Name string
+	// Columns - Columns of the index, this is empty if the index includes an expression.
+	Columns []string
+}
+
+func (u UniqueKey) clean() UniqueKey {
+	key := UniqueKey{Name: unescape(u.Name)}
+	for _, col := range u.Columns {
+		key.Columns = append(key.Columns, unescape(col))
+	}
+
+	return key
+}
+
 type Event interface {
+	// GetDatabase returns the database that the table name was qualified with, this is empty if it was not qualified.
+	GetDatabase() string
 	GetTable() string
 	GetColumns() []Column
 }
@@ -56,14 +75,24 @@ func (a AfterPosition) Kind() string {
 }
 
 type RenameTableEvent struct {
+	database     string
 	tableName    string
+	newDatabase  string
 	newTableName string
 }
 
+func (r RenameTableEvent) GetDatabase() string {
+	return unescape(r.database)
+}
+
 func (r RenameTableEvent) GetTable() string {
 	return unescape(r.tableName)
 }
 
+func (r RenameTableEvent) GetNewDatabase() string {
+	return unescape(r.newDatabase)
+}
+
 func (r RenameTableEvent) GetNewTableName() string {
 	return unescape(r.newTableName)
 }
@@ -73,14 +102,24 @@ func (r RenameTableEvent) GetColumns() []Column {
 }
 
 type CopyTableEvent struct {
+	database          string
 	tableName         string
+	copyFromDatabase  string
 	copyFromTableName string
 }
 
+func (c CopyTableEvent) GetDatabase() string {
+	return unescape(c.database)
+}
+
 func (c CopyTableEvent) GetTable() string {
 	return unescape(c.tableName)
 }
 
+func (c CopyTableEvent) GetCopyFromDatabase() string {
+	return unescape(c.copyFromDatabase)
+}
+
 func (c CopyTableEvent) GetCopyFromTableName() string {
 	return unescape(c.copyFromTableName)
 }
@@ -90,8 +129,14 @@ func (c CopyTableEvent) GetColumns() []Column {
 }
 
 type CreateTableEvent struct {
-	TableName string
-	Columns   []Column
+	Database   string
+	TableName  string
+	Columns    []Column
+	UniqueKeys []UniqueKey
+}
+
+func (c CreateTableEvent) GetDatabase() string {
+	return unescape(c.Database)
 }
 
 func (c CreateTableEvent) GetTable() string {
@@ -107,11 +152,25 @@ func (c CreateTableEvent) GetColumns() []Column {
 	return cols
 }
 
+func (c CreateTableEvent) GetUniqueKeys() []UniqueKey {
+	var keys []UniqueKey
+	for _, key := range c.UniqueKeys {
+		keys = append(keys, key.clean())
+	}
+
+	return keys
+}
+
 type RenameColumnEvent struct {
+	Database  string
 	TableName string
 	Column    Column
 }
 
+func (r RenameColumnEvent) GetDatabase() string {
+	return unescape(r.Database)
+}
+
 func (r RenameColumnEvent) GetTable() string {
 	return unescape(r.TableName)
 }
@@ -121,10 +180,15 @@ func (r RenameColumnEvent) GetColumns() []Column {
 }
 
 type AddPrimaryKeyEvent struct {
+	Database  string
 	TableName string
 	Columns   []Column
 }
 
+func (a AddPrimaryKeyEvent) GetDatabase() string {
+	return unescape(a.Database)
+}
+
 func (a AddPrimaryKeyEvent) GetTable() string {
 	return unescape(a.TableName)
 }
@@ -138,11 +202,60 @@ func (a AddPrimaryKeyEvent) GetColumns() []Column {
 	return cols
 }
 
+type AddUniqueKeyEvent struct {
+	Database  string
+	TableName string
+	UniqueKey UniqueKey
+}
+
+func (a AddUniqueKeyEvent) GetDatabase() string {
+	return unescape(a.Database)
+}
+
+func (a AddUniqueKeyEvent) GetTable() string {
+	return unescape(a.TableName)
+}
+
+func (a AddUniqueKeyEvent) GetColumns() []Column {
+	return nil
+}
+
+func (a AddUniqueKeyEvent) GetUniqueKey() UniqueKey {
+	return a.UniqueKey.clean()
+}
+
+type DropIndexEvent struct {
+	Database  string
+	TableName string
+	IndexName string
+}
+
+func (d DropIndexEvent) GetDatabase() string {
+	return unescape(d.Database)
+}
+
+func (d DropIndexEvent) GetTable() string {
+	return unescape(d.TableName)
+}
+
+func (d DropIndexEvent) GetColumns() []Column {
+	return nil
+}
+
+func (d DropIndexEvent) GetIndexName() string {
+	return unescape(d.IndexName)
+}
+
 type ModifyColumnEvent struct {
+	Database  string
 	TableName string
 	Column    Column
 }
 
+func (a ModifyColumnEvent) GetDatabase() string {
+	return unescape(a.Database)
+}
+
 func (a ModifyColumnEvent) GetTable() string {
 	return unescape(a.TableName)
 }
@@ -152,10 +265,15 @@ func (a ModifyColumnEvent) GetColumns() []Column {
 }
 
 type DropColumnsEvent struct {
+	Database  string
 	TableName string
 	Column    Column
 }
 
+func (d DropColumnsEvent) GetDatabase() string {
+	return unescape(d.Database)
+}
+
 func (d DropColumnsEvent) GetTable() string {
 	return unescape(d.TableName)
 }
@@ -165,10 +283,15 @@ func (d DropColumnsEvent) GetColumns() []Column {
 }
 
 type AddColumnsEvent struct {
+	Database  string
 	TableName string
 	Columns   []Column
 }
 
+func (a AddColumnsEvent) GetDatabase() string {
+	return unescape(a.Database)
+}
+
 func (a AddColumnsEvent) GetTable() string {
 	return unescape(a.TableName)
 }
@@ -182,10 +305,32 @@ func (a AddColumnsEvent) GetColumns() []Column {
 	return cols
 }
 
+type TruncateTableEvent struct {
+	Database  string
+	TableName string
+}
+
+func (t TruncateTableEvent) GetDatabase() string {
+	return unescape(t.Database)
+}
+
+func (t TruncateTableEvent) GetTable() string {
+	return unescape(t.TableName)
+}
+
+func (t TruncateTableEvent) GetColumns() []Column {
+	return nil
+}
+
 type DropTableEvent struct {
+	Database  string
 	TableName string
 }
 
+func (d DropTableEvent) GetDatabase() string {
+	return unescape(d.Database)
+}
+
 func (d DropTableEvent) GetTable() string {
 	return unescape(d.TableName)
 }
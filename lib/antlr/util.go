@@ -41,18 +41,18 @@ func getTextFromSingleNodeBranch(tree antlr.Tree) (string, error) {
 	return getTextFromSingleNodeBranch(tree.GetChild(0))
 }
 
-// TODO: Extend this function to return the schema (if present)
-func getTableNameFromNode(ctx generated.ITableNameContext) (string, error) {
+// getTableNameFromNode returns the database that the table name is qualified with (if any) and the table name.
+func getTableNameFromNode(ctx generated.ITableNameContext) (string, string, error) {
 	children := ctx.GetChildren()
 	if len(children) != 1 {
-		return "", fmt.Errorf("unexpected number of children: %d", len(children))
+		return "", "", fmt.Errorf("unexpected number of children: %d", len(children))
 	}
 
 	var parts []string
 	for _, node := range children[0].GetChildren() {
 		part, err := getTextFromSingleNodeBranch(node)
 		if err != nil {
-			return "", err
+			return "", "", err
 		}
 
 		parts = append(parts, part)
@@ -60,12 +60,12 @@ func getTableNameFromNode(ctx generated.ITableNameContext) (string, error) {
 
 	switch len(parts) {
 	case 1:
-		return strings.TrimPrefix(parts[0], "."), nil
+		return "", strings.TrimPrefix(parts[0], "."), nil
 	case 2:
-		return strings.TrimPrefix(parts[1], "."), nil
+		return parts[0], strings.TrimPrefix(parts[1], "."), nil
 	case 3:
-		return strings.TrimPrefix(parts[2], "."), nil
+		return parts[0], strings.TrimPrefix(parts[2], "."), nil
 	default:
-		return "", fmt.Errorf("unexpected number of parts: %d, value: [%s]", len(parts), strings.Join(parts, ", "))
+		return "", "", fmt.Errorf("unexpected number of parts: %d, value: [%s]", len(parts), strings.Join(parts, ", "))
 	}
 }
@@ -0,0 +1,211 @@
+package avro
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// Encode encodes [value] using the Avro binary encoding. Records are expected to be a map[string]any.
+func Encode(schema Schema, value any) ([]byte, error) {
+	return appendValue(nil, schema, value)
+}
+
+func appendLong(buf []byte, value int64) []byte {
+	return binary.AppendVarint(buf, value)
+}
+
+func appendBytes(buf []byte, value []byte) []byte {
+	buf = appendLong(buf, int64(len(value)))
+	return append(buf, value...)
+}
+
+func appendValue(buf []byte, schema Schema, value any) ([]byte, error) {
+	if schema.Union != nil {
+		return appendUnion(buf, schema.Union, value)
+	}
+
+	switch schema.Type {
+	case Null:
+		if value != nil {
+			return nil, fmt.Errorf("expected nil got %T", value)
+		}
+		return buf, nil
+	case Boolean:
+		castValue, isOk := value.(bool)
+		if !isOk {
+			return nil, fmt.Errorf("expected bool got %T with value: %v", value, value)
+		}
+
+		if castValue {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case Int, Long:
+		castValue, err := asInt64(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if schema.Type == Int && (castValue < math.MinInt32 || castValue > math.MaxInt32) {
+			return nil, fmt.Errorf("value %d overflows int", castValue)
+		}
+		return appendLong(buf, castValue), nil
+	case Float:
+		castValue, err := asFloat64(value)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(castValue))), nil
+	case Double:
+		castValue, err := asFloat64(value)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(castValue)), nil
+	case Bytes, String:
+		switch castValue := value.(type) {
+		case []byte:
+			return appendBytes(buf, castValue), nil
+		case string:
+			return appendBytes(buf, []byte(castValue)), nil
+		default:
+			return nil, fmt.Errorf("expected []byte or string got %T with value: %v", value, value)
+		}
+	case Record:
+		castValue, isOk := value.(map[string]any)
+		if !isOk {
+			return nil, fmt.Errorf("expected map[string]any got %T with value: %v", value, value)
+		}
+
+		for _, field := range schema.Fields {
+			var err error
+			if buf, err = appendValue(buf, field.schema(), castValue[field.Name]); err != nil {
+				return nil, fmt.Errorf("failed to encode field %q: %w", field.Name, err)
+			}
+		}
+		return buf, nil
+	case Array:
+		return appendArray(buf, *schema.Items, value)
+	case Map:
+		castValue, isOk := value.(map[string]any)
+		if !isOk {
+			return nil, fmt.Errorf("expected map[string]any got %T with value: %v", value, value)
+		}
+
+		if len(castValue) > 0 {
+			buf = appendLong(buf, int64(len(castValue)))
+			// Sort the keys so that the encoding is deterministic.
+			keys := make([]string, 0, len(castValue))
+			for key := range castValue {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+
+			for _, key := range keys {
+				buf = appendBytes(buf, []byte(key))
+				var err error
+				if buf, err = appendValue(buf, *schema.Values, castValue[key]); err != nil {
+					return nil, fmt.Errorf("failed to encode map value for key %q: %w", key, err)
+				}
+			}
+		}
+		return appendLong(buf, 0), nil
+	default:
+		return nil, fmt.Errorf("unsupported avro type: %q", schema.Type)
+	}
+}
+
+// isNil returns true for nil and for typed nil maps, slices and pointers.
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	switch reflectValue := reflect.ValueOf(value); reflectValue.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Pointer:
+		return reflectValue.IsNil()
+	default:
+		return false
+	}
+}
+
+func appendUnion(buf []byte, union []Schema, value any) ([]byte, error) {
+	if isNil(value) {
+		value = nil
+	}
+
+	err := fmt.Errorf("value of type %T does not match any of the union's schemas", value)
+	for idx, schema := range union {
+		if isNull := schema.Union == nil && schema.Type == Null; isNull != (value == nil) {
+			continue
+		}
+
+		var out []byte
+		if out, err = appendValue(appendLong(buf, int64(idx)), schema, value); err == nil {
+			return out, nil
+		}
+	}
+
+	return nil, err
+}
+
+func appendArray(buf []byte, items Schema, value any) ([]byte, error) {
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("expected slice got %T with value: %v", value, value)
+	}
+
+	if reflectValue.Len() > 0 {
+		buf = appendLong(buf, int64(reflectValue.Len()))
+		for i := range reflectValue.Len() {
+			var err error
+			if buf, err = appendValue(buf, items, reflectValue.Index(i).Interface()); err != nil {
+				return nil, fmt.Errorf("failed to encode array element %d: %w", i, err)
+			}
+		}
+	}
+
+	return appendLong(buf, 0), nil
+}
+
+func asInt64(value any) (int64, error) {
+	switch castValue := value.(type) {
+	case int:
+		return int64(castValue), nil
+	case int8:
+		return int64(castValue), nil
+	case int16:
+		return int64(castValue), nil
+	case int32:
+		return int64(castValue), nil
+	case int64:
+		return castValue, nil
+	case uint8:
+		return int64(castValue), nil
+	case uint16:
+		return int64(castValue), nil
+	case uint32:
+		return int64(castValue), nil
+	case uint64:
+		if castValue > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows long", castValue)
+		}
+		return int64(castValue), nil
+	default:
+		return 0, fmt.Errorf("expected integer got %T with value: %v", value, value)
+	}
+}
+
+func asFloat64(value any) (float64, error) {
+	switch castValue := value.(type) {
+	case float32:
+		return float64(castValue), nil
+	case float64:
+		return castValue, nil
+	default:
+		return 0, fmt.Errorf("expected float got %T with value: %v", value, value)
+	}
+}
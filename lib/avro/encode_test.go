@@ -0,0 +1,71 @@
+package avro
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	tcs := []struct {
+		name        string
+		schema      Schema
+		value       any
+		expected    []byte
+		expectedErr string
+	}{
+		{name: "null", schema: Schema{Type: Null}, value: nil, expected: nil},
+		{name: "boolean", schema: Schema{Type: Boolean}, value: true, expected: []byte{1}},
+		{name: "int", schema: Schema{Type: Int}, value: int16(-64), expected: []byte{0x7f}},
+		{name: "int - overflow", schema: Schema{Type: Int}, value: int64(1 << 40), expectedErr: "overflows int"},
+		{name: "long", schema: Schema{Type: Long}, value: int64(64), expected: []byte{0x80, 0x01}},
+		{name: "float", schema: Schema{Type: Float}, value: float32(1), expected: []byte{0x00, 0x00, 0x80, 0x3f}},
+		{name: "double", schema: Schema{Type: Double}, value: 1.0, expected: []byte{0, 0, 0, 0, 0, 0, 0xf0, 0x3f}},
+		{name: "string", schema: Schema{Type: String}, value: "foo", expected: []byte{0x06, 'f', 'o', 'o'}},
+		{name: "bytes", schema: Schema{Type: Bytes}, value: []byte{0xff}, expected: []byte{0x02, 0xff}},
+		{name: "string - wrong type", schema: Schema{Type: String}, value: 1, expectedErr: "expected []byte or string got int"},
+		{name: "union - null", schema: Optional(Schema{Type: String}), value: nil, expected: []byte{0x00}},
+		{name: "union - typed nil", schema: Optional(Schema{Type: Map, Values: &Schema{Type: Long}}), value: map[string]any(nil), expected: []byte{0x00}},
+		{name: "union - value", schema: Optional(Schema{Type: String}), value: "a", expected: []byte{0x02, 0x02, 'a'}},
+		{
+			name:     "array",
+			schema:   Schema{Type: Array, Items: &Schema{Type: Long}},
+			value:    []any{int64(1), int64(2)},
+			expected: []byte{0x04, 0x02, 0x04, 0x00},
+		},
+		{name: "array - empty", schema: Schema{Type: Array, Items: &Schema{Type: Long}}, value: []string{}, expected: []byte{0x00}},
+		{
+			name:     "map",
+			schema:   Schema{Type: Map, Values: &Schema{Type: Long}},
+			value:    map[string]any{"b": int64(2), "a": int64(1)},
+			expected: []byte{0x04, 0x02, 'a', 0x02, 0x02, 'b', 0x04, 0x00},
+		},
+		{
+			name: "record",
+			schema: Schema{Type: Record, Name: "Foo", Fields: []Field{
+				{Name: "id", Schema: Schema{Type: Long}},
+				{Name: "name", Schema: Schema{Type: String}, Optional: true},
+			}},
+			value:    map[string]any{"id": int64(1)},
+			expected: []byte{0x02, 0x00},
+		},
+		{
+			name: "record - missing required field",
+			schema: Schema{Type: Record, Name: "Foo", Fields: []Field{
+				{Name: "id", Schema: Schema{Type: Long}},
+			}},
+			value:       map[string]any{},
+			expectedErr: `failed to encode field "id": expected integer got <nil>`,
+		},
+	}
+
+	for _, tc := range tcs {
+		actual, err := Encode(tc.schema, tc.value)
+		if tc.expectedErr != "" {
+			assert.ErrorContains(t, err, tc.expectedErr, tc.name)
+		} else {
+			assert.NoError(t, err, tc.name)
+			assert.Equal(t, tc.expected, actual, tc.name)
+		}
+	}
+}
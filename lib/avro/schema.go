@@ -0,0 +1,178 @@
+package avro
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type Type string
+
+const (
+	Null    Type = "null"
+	Boolean Type = "boolean"
+	Int     Type = "int"
+	Long    Type = "long"
+	Float   Type = "float"
+	Double  Type = "double"
+	Bytes   Type = "bytes"
+	String  Type = "string"
+	Record  Type = "record"
+	Array   Type = "array"
+	Map     Type = "map"
+)
+
+// Schema - The subset of the Avro specification that we need to describe Debezium events.
+type Schema struct {
+	Type Type
+	// Union - If this is set, the schema is a union of these schemas and [Type] is ignored.
+	Union []Schema
+
+	// Records
+	Name      string
+	Namespace string
+	Fields    []Field
+	// Arrays
+	Items *Schema
+	// Maps
+	Values *Schema
+
+	LogicalType string
+	Precision   int
+	Scale       int
+	// Properties - Additional attributes, e.g. `connect.name`.
+	Properties map[string]any
+}
+
+type Field struct {
+	Name   string
+	Schema Schema
+	// Optional - Whether the field is nullable, optional fields are a union of null and [Schema] that default to null.
+	Optional bool
+}
+
+func Optional(schema Schema) Schema {
+	return Schema{Union: []Schema{{Type: Null}, schema}}
+}
+
+// FullName returns the namespace qualified name of a record.
+func (s Schema) FullName() string {
+	if s.Namespace == "" {
+		return s.Name
+	}
+
+	return fmt.Sprintf("%s.%s", s.Namespace, s.Name)
+}
+
+func (f Field) schema() Schema {
+	if f.Optional {
+		return Optional(f.Schema)
+	}
+
+	return f.Schema
+}
+
+func (s Schema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.toJSON(make(map[string]bool)))
+}
+
+// toJSON builds the JSON representation of the schema. Records can only be defined once, so subsequent uses of a record
+// (tracked with [definedRecords]) refer to it by name.
+func (s Schema) toJSON(definedRecords map[string]bool) any {
+	if s.Union != nil {
+		out := make([]any, len(s.Union))
+		for i, schema := range s.Union {
+			out[i] = schema.toJSON(definedRecords)
+		}
+		return out
+	}
+
+	if s.Type != Record && s.Type != Array && s.Type != Map && s.LogicalType == "" && len(s.Properties) == 0 {
+		return s.Type
+	}
+
+	if s.Type == Record {
+		if definedRecords[s.FullName()] {
+			return s.FullName()
+		}
+		definedRecords[s.FullName()] = true
+	}
+
+	out := make(map[string]any)
+	for key, value := range s.Properties {
+		out[key] = value
+	}
+
+	out["type"] = s.Type
+	switch s.Type {
+	case Record:
+		fields := make([]map[string]any, len(s.Fields))
+		for i, field := range s.Fields {
+			fields[i] = map[string]any{"name": field.Name, "type": field.schema().toJSON(definedRecords)}
+			if field.Optional {
+				fields[i]["default"] = nil
+			}
+		}
+
+		out["name"] = s.Name
+		if s.Namespace != "" {
+			out["namespace"] = s.Namespace
+		}
+		out["fields"] = fields
+	case Array:
+		out["items"] = s.Items.toJSON(definedRecords)
+	case Map:
+		out["values"] = s.Values.toJSON(definedRecords)
+	}
+
+	if s.LogicalType != "" {
+		out["logicalType"] = s.LogicalType
+		if s.LogicalType == "decimal" {
+			out["precision"] = s.Precision
+			out["scale"] = s.Scale
+		}
+	}
+
+	return out
+}
+
+// String returns the JSON representation of the schema, this is what gets registered with a schema registry.
+func (s Schema) String() string {
+	bytes, err := json.Marshal(s)
+	if err != nil {
+		// This should never happen since the schema only contains types that can be marshalled.
+		panic(fmt.Sprintf("failed to marshal avro schema: %v", err))
+	}
+
+	return string(bytes)
+}
+
+// SanitizeName replaces characters that are not allowed in Avro names with underscores.
+func SanitizeName(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+
+	return sb.String()
+}
+
+// SanitizeNamespace sanitizes each of the dot-separated parts of a namespace.
+func SanitizeNamespace(namespace string) string {
+	parts := strings.Split(namespace, ".")
+	for i, part := range parts {
+		parts[i] = SanitizeName(part)
+	}
+
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,36 @@
+package avro
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_String(t *testing.T) {
+	{
+		// Primitive
+		assert.Equal(t, `"long"`, Schema{Type: Long}.String())
+		assert.Equal(t, `["null","string"]`, Optional(Schema{Type: String}).String())
+	}
+	{
+		// Logical type and properties
+		schema := Schema{Type: Bytes, LogicalType: "decimal", Precision: 5, Scale: 2, Properties: map[string]any{"connect.name": "foo"}}
+		assert.Equal(t, `{"connect.name":"foo","logicalType":"decimal","precision":5,"scale":2,"type":"bytes"}`, schema.String())
+	}
+	{
+		// Records that are used more than once are referenced by name
+		row := Schema{Type: Record, Name: "Value", Namespace: "a.b", Fields: []Field{{Name: "id", Schema: Schema{Type: Long}}}}
+		schema := Schema{Type: Record, Name: "Envelope", Namespace: "a.b", Fields: []Field{
+			{Name: "before", Schema: row, Optional: true},
+			{Name: "after", Schema: row, Optional: true},
+		}}
+		assert.Equal(t, `{"fields":[{"default":null,"name":"before","type":["null",{"fields":[{"name":"id","type":"long"}],"name":"Value","namespace":"a.b","type":"record"}]},{"default":null,"name":"after","type":["null","a.b.Value"]}],"name":"Envelope","namespace":"a.b","type":"record"}`, schema.String())
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	assert.Equal(t, "foo_bar", SanitizeName("foo_bar"))
+	assert.Equal(t, "foo_bar", SanitizeName("foo-bar"))
+	assert.Equal(t, "_1abc", SanitizeName("1abc"))
+	assert.Equal(t, "prefix.public.user_events", SanitizeNamespace("prefix.public.user-events"))
+}
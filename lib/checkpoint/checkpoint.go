@@ -0,0 +1,115 @@
+package checkpoint
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+	"github.com/artie-labs/reader/lib/storage/persistedmap"
+)
+
+// Checkpoint - Progress of a snapshot for a single table.
+type Checkpoint struct {
+	Done bool `yaml:"done,omitempty"`
+	// LastKey - Key of the last row that has been acknowledged by the destination.
+	LastKey []Value `yaml:"lastKey,omitempty"`
+}
+
+// Store - Persists snapshot checkpoints keyed by table, a nil [Store] does not persist anything. It is safe for
+// concurrent use since tables can be snapshotted concurrently.
+type Store struct {
+	mu          sync.Mutex
+	checkpoints *persistedmap.PersistedMap[Checkpoint]
+}
+
+// NewStore returns a [Store] that persists checkpoints under [namespace], checkpointing is disabled if it's empty.
+func NewStore(store offsetstore.OffsetStore, namespace string) *Store {
+	if namespace == "" {
+		return nil
+	}
+
+	return &Store{checkpoints: persistedmap.NewPersistedMap[Checkpoint](store, namespace)}
+}
+
+func (s *Store) Get(table string) (Checkpoint, bool) {
+	if s == nil {
+		return Checkpoint{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.checkpoints.Get(table)
+}
+
+// IsEmpty returns whether no checkpoints have been saved yet.
+func (s *Store) IsEmpty() bool {
+	if s == nil {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.checkpoints.Len() == 0
+}
+
+// IsDone returns whether [table] has already been snapshotted.
+func (s *Store) IsDone(table string) bool {
+	checkpoint, isOk := s.Get(table)
+	return isOk && checkpoint.Done
+}
+
+// IsResuming returns whether the snapshot of [table] resumes from a checkpoint, rows before the checkpoint have already
+// been written to the destination by a previous run.
+func (s *Store) IsResuming(table string) bool {
+	checkpoint, isOk := s.Get(table)
+	return isOk && !checkpoint.Done && len(checkpoint.LastKey) > 0
+}
+
+func (s *Store) SaveLastKey(table string, lastKey []Value) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkpoints.Set(table, Checkpoint{LastKey: lastKey}); err != nil {
+		return fmt.Errorf("failed to save checkpoint for %q: %w", table, err)
+	}
+
+	return nil
+}
+
+func (s *Store) MarkDone(table string) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkpoints.Set(table, Checkpoint{Done: true}); err != nil {
+		return fmt.Errorf("failed to mark %q as done: %w", table, err)
+	}
+
+	return nil
+}
+
+// Clear removes all checkpoints, this should be called once the whole snapshot has completed so that the next run
+// starts from scratch.
+func (s *Store) Clear() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkpoints.Clear(); err != nil {
+		return fmt.Errorf("failed to clear checkpoints: %w", err)
+	}
+
+	return nil
+}
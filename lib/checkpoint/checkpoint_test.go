@@ -0,0 +1,51 @@
+package checkpoint
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+)
+
+func TestStore(t *testing.T) {
+	{
+		// Checkpointing is disabled
+		store := NewStore(offsetstore.NewFileStore(), "")
+		assert.Nil(t, store)
+		assert.False(t, store.IsDone("foo"))
+		assert.False(t, store.IsResuming("foo"))
+		assert.NoError(t, store.SaveLastKey("foo", []Value{{Type: Int64, Value: "1"}}))
+		assert.NoError(t, store.MarkDone("foo"))
+		assert.NoError(t, store.Clear())
+	}
+	{
+		// Checkpoints are persisted
+		namespace := filepath.Join(t.TempDir(), "checkpoints.yaml")
+		store := NewStore(offsetstore.NewFileStore(), namespace)
+		assert.True(t, store.IsEmpty())
+		assert.NoError(t, store.SaveLastKey("db.foo", []Value{{Type: Int64, Value: "1"}}))
+		assert.NoError(t, store.MarkDone("db.bar"))
+
+		store = NewStore(offsetstore.NewFileStore(), namespace)
+		assert.False(t, store.IsEmpty())
+		checkpoint, isOk := store.Get("db.foo")
+		assert.True(t, isOk)
+		assert.Equal(t, Checkpoint{LastKey: []Value{{Type: Int64, Value: "1"}}}, checkpoint)
+		assert.False(t, store.IsDone("db.foo"))
+		assert.True(t, store.IsDone("db.bar"))
+
+		// Only tables with a last key are resumed
+		assert.True(t, store.IsResuming("db.foo"))
+		assert.False(t, store.IsResuming("db.bar"))
+		assert.False(t, store.IsResuming("db.baz"))
+
+		// Clearing removes every checkpoint
+		assert.NoError(t, store.Clear())
+		store = NewStore(offsetstore.NewFileStore(), namespace)
+		_, isOk = store.Get("db.foo")
+		assert.False(t, isOk)
+		assert.False(t, store.IsDone("db.bar"))
+	}
+}
@@ -0,0 +1,71 @@
+package checkpoint
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/artie-labs/reader/lib/debezium/transformer"
+	"github.com/artie-labs/reader/lib/iterator"
+	"github.com/artie-labs/reader/lib/kafkalib"
+	"github.com/artie-labs/reader/lib/rdbms/scan"
+)
+
+// scanTransformer - Saves the position of the scanner each time the writer commits, which happens once the rows that
+// have been read so far have been written to the destination.
+type scanTransformer struct {
+	*transformer.DebeziumTransformer
+	store   *Store
+	table   string
+	scanner *scan.Scanner
+}
+
+// NewTransformer builds the Debezium transformer for a table, if checkpointing is enabled the table will be resumed
+// from its last checkpoint and its progress will be saved as batches are written.
+func (s *Store) NewTransformer(adapter transformer.Adapter) (iterator.Iterator[[]kafkalib.Message], error) {
+	if s == nil {
+		return transformer.NewDebeziumTransformer(adapter)
+	}
+
+	iter, err := adapter.NewIterator()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator :%w", err)
+	}
+
+	dbzTransformer := transformer.NewDebeziumTransformerWithIterator(adapter, iter)
+	scanner, isOk := iter.(*scan.Scanner)
+	if !isOk {
+		slog.Warn("Snapshot checkpointing is not supported for tables that are scanned in parallel or that do not have a key", slog.String("table", adapter.TopicSuffix()))
+		return dbzTransformer, nil
+	}
+
+	table := adapter.TopicSuffix()
+	if checkpoint, isOk := s.Get(table); isOk && len(checkpoint.LastKey) > 0 {
+		lastKey, err := DecodeKey(checkpoint.LastKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode checkpoint for %q: %w", table, err)
+		}
+
+		if err = scanner.Resume(lastKey); err != nil {
+			return nil, fmt.Errorf("failed to resume %q from checkpoint: %w", table, err)
+		}
+
+		slog.Info("Resuming snapshot from checkpoint", slog.String("table", table), slog.Any("lastKey", lastKey))
+	}
+
+	return &scanTransformer{DebeziumTransformer: dbzTransformer, store: s, table: table, scanner: scanner}, nil
+}
+
+func (s *scanTransformer) CommitOffset() error {
+	lastKey := s.scanner.LastKey()
+	if lastKey == nil {
+		return nil
+	}
+
+	values, err := EncodeKey(lastKey)
+	if err != nil {
+		slog.Warn("Unable to checkpoint snapshot, key cannot be persisted", slog.String("table", s.table), slog.Any("err", err))
+		return nil
+	}
+
+	return s.store.SaveLastKey(s.table, values)
+}
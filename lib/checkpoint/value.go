@@ -0,0 +1,116 @@
+package checkpoint
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type ValueType string
+
+const (
+	Int64   ValueType = "int64"
+	Uint64  ValueType = "uint64"
+	Float64 ValueType = "float64"
+	Bool    ValueType = "bool"
+	String  ValueType = "string"
+	Bytes   ValueType = "bytes"
+	Time    ValueType = "time"
+	// ExtJSON - MongoDB Extended JSON, this is only decoded by the MongoDB source.
+	ExtJSON ValueType = "extjson"
+)
+
+// Value - A key value along with its type so that it can be passed back to the database driver after a restart.
+type Value struct {
+	Type  ValueType `yaml:"type"`
+	Value string    `yaml:"value"`
+}
+
+// EncodeKey encodes each of the values of a (composite) key.
+func EncodeKey(key []any) ([]Value, error) {
+	values := make([]Value, len(key))
+	for i, value := range key {
+		encoded, err := EncodeValue(value)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = encoded
+	}
+
+	return values, nil
+}
+
+// DecodeKey is the inverse of [EncodeKey].
+func DecodeKey(values []Value) ([]any, error) {
+	key := make([]any, len(values))
+	for i, value := range values {
+		decoded, err := value.Decode()
+		if err != nil {
+			return nil, err
+		}
+		key[i] = decoded
+	}
+
+	return key, nil
+}
+
+func EncodeValue(value any) (Value, error) {
+	switch castedValue := value.(type) {
+	case int:
+		return Value{Type: Int64, Value: strconv.FormatInt(int64(castedValue), 10)}, nil
+	case int8:
+		return Value{Type: Int64, Value: strconv.FormatInt(int64(castedValue), 10)}, nil
+	case int16:
+		return Value{Type: Int64, Value: strconv.FormatInt(int64(castedValue), 10)}, nil
+	case int32:
+		return Value{Type: Int64, Value: strconv.FormatInt(int64(castedValue), 10)}, nil
+	case int64:
+		return Value{Type: Int64, Value: strconv.FormatInt(castedValue, 10)}, nil
+	case uint:
+		return Value{Type: Uint64, Value: strconv.FormatUint(uint64(castedValue), 10)}, nil
+	case uint8:
+		return Value{Type: Uint64, Value: strconv.FormatUint(uint64(castedValue), 10)}, nil
+	case uint16:
+		return Value{Type: Uint64, Value: strconv.FormatUint(uint64(castedValue), 10)}, nil
+	case uint32:
+		return Value{Type: Uint64, Value: strconv.FormatUint(uint64(castedValue), 10)}, nil
+	case uint64:
+		return Value{Type: Uint64, Value: strconv.FormatUint(castedValue, 10)}, nil
+	case float32:
+		return Value{Type: Float64, Value: strconv.FormatFloat(float64(castedValue), 'g', -1, 32)}, nil
+	case float64:
+		return Value{Type: Float64, Value: strconv.FormatFloat(castedValue, 'g', -1, 64)}, nil
+	case bool:
+		return Value{Type: Bool, Value: strconv.FormatBool(castedValue)}, nil
+	case string:
+		return Value{Type: String, Value: castedValue}, nil
+	case []byte:
+		return Value{Type: Bytes, Value: base64.StdEncoding.EncodeToString(castedValue)}, nil
+	case time.Time:
+		return Value{Type: Time, Value: castedValue.Format(time.RFC3339Nano)}, nil
+	default:
+		return Value{}, fmt.Errorf("unsupported type %T", value)
+	}
+}
+
+func (v Value) Decode() (any, error) {
+	switch v.Type {
+	case Int64:
+		return strconv.ParseInt(v.Value, 10, 64)
+	case Uint64:
+		return strconv.ParseUint(v.Value, 10, 64)
+	case Float64:
+		return strconv.ParseFloat(v.Value, 64)
+	case Bool:
+		return strconv.ParseBool(v.Value)
+	case String:
+		return v.Value, nil
+	case Bytes:
+		return base64.StdEncoding.DecodeString(v.Value)
+	case Time:
+		return time.Parse(time.RFC3339Nano, v.Value)
+	default:
+		return nil, fmt.Errorf("unsupported value type %q", v.Type)
+	}
+}
@@ -0,0 +1,70 @@
+package checkpoint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeValue(t *testing.T) {
+	tcs := []struct {
+		name          string
+		value         any
+		expected      Value
+		expectedValue any
+	}{
+		{name: "int32", value: int32(-5), expected: Value{Type: Int64, Value: "-5"}, expectedValue: int64(-5)},
+		{name: "int64", value: int64(1234), expected: Value{Type: Int64, Value: "1234"}, expectedValue: int64(1234)},
+		{name: "uint64", value: uint64(18446744073709551615), expected: Value{Type: Uint64, Value: "18446744073709551615"}, expectedValue: uint64(18446744073709551615)},
+		{name: "float64", value: 1.5, expected: Value{Type: Float64, Value: "1.5"}, expectedValue: 1.5},
+		{name: "bool", value: true, expected: Value{Type: Bool, Value: "true"}, expectedValue: true},
+		{name: "string", value: "foo", expected: Value{Type: String, Value: "foo"}, expectedValue: "foo"},
+		{name: "bytes", value: []byte("foo"), expected: Value{Type: Bytes, Value: "Zm9v"}, expectedValue: []byte("foo")},
+		{
+			name:          "time",
+			value:         time.Date(2001, 2, 3, 4, 5, 6, 789, time.UTC),
+			expected:      Value{Type: Time, Value: "2001-02-03T04:05:06.000000789Z"},
+			expectedValue: time.Date(2001, 2, 3, 4, 5, 6, 789, time.UTC),
+		},
+	}
+
+	for _, tc := range tcs {
+		encoded, err := EncodeValue(tc.value)
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expected, encoded, tc.name)
+
+		decoded, err := encoded.Decode()
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedValue, decoded, tc.name)
+	}
+
+	{
+		// Unsupported type
+		_, err := EncodeValue(map[string]any{})
+		assert.ErrorContains(t, err, "unsupported type map[string]interface {}")
+	}
+	{
+		// Unsupported value type
+		_, err := Value{Type: ExtJSON, Value: "{}"}.Decode()
+		assert.ErrorContains(t, err, `unsupported value type "extjson"`)
+	}
+}
+
+func TestEncodeKey(t *testing.T) {
+	{
+		// Composite key
+		encoded, err := EncodeKey([]any{int32(1), "foo"})
+		assert.NoError(t, err)
+		assert.Equal(t, []Value{{Type: Int64, Value: "1"}, {Type: String, Value: "foo"}}, encoded)
+
+		decoded, err := DecodeKey(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{int64(1), "foo"}, decoded)
+	}
+	{
+		// Unsupported type
+		_, err := EncodeKey([]any{int32(1), map[string]any{}})
+		assert.ErrorContains(t, err, "unsupported type map[string]interface {}")
+	}
+}
@@ -0,0 +1,24 @@
+package transformer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// RowHashKey - Partition key of tables that have neither a primary key nor a unique index whose columns are all NOT NULL.
+// Its value is a hash of the converted row, so a row has the same key whether it was read by a snapshot or by streaming.
+const RowHashKey = "__artie_row_hash"
+
+// hashRow returns the value of [RowHashKey] for a row that has already been converted.
+func hashRow(dbzRow Row) (string, error) {
+	// Maps are marshalled with sorted keys, so this is stable.
+	bytes, err := json.Marshal(dbzRow)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal row: %w", err)
+	}
+
+	hash := sha256.Sum256(bytes)
+	return hex.EncodeToString(hash[:]), nil
+}
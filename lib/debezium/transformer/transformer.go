@@ -2,6 +2,7 @@ package transformer
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/artie-labs/transfer/lib/cdc/util"
@@ -34,6 +35,8 @@ type DebeziumTransformer struct {
 	schema          debezium.Schema
 	iter            RowsIterator
 	valueConverters map[string]converters.ValueConverter
+	// partitionKeySchema - Only has the row hash, if any, since the other partition key values are not converted.
+	partitionKeySchema debezium.FieldsObject
 }
 
 func NewDebeziumTransformer(adapter Adapter) (*DebeziumTransformer, error) {
@@ -61,14 +64,29 @@ func NewDebeziumTransformerWithIterator(adapter Adapter, iter RowsIterator) *Deb
 		}},
 	}
 
+	var partitionKeySchema debezium.FieldsObject
+	if slices.Contains(adapter.PartitionKeys(), RowHashKey) {
+		// Same as [convertPartitionKey], so that the key of tables without a primary key can be serialized with a schema.
+		partitionKeySchema = debezium.FieldsObject{
+			FieldObjectType: string(debezium.Struct),
+			Fields:          []debezium.Field{converters.StringPassthrough{}.ToField(RowHashKey)},
+		}
+	}
+
 	return &DebeziumTransformer{
-		adapter:         adapter,
-		schema:          schema,
-		iter:            iter,
-		valueConverters: valueConverters,
+		adapter:            adapter,
+		schema:             schema,
+		iter:               iter,
+		valueConverters:    valueConverters,
+		partitionKeySchema: partitionKeySchema,
 	}
 }
 
+// Close releases the resources held by the rows iterator, e.g. the goroutines of a table that is scanned in parallel.
+func (d *DebeziumTransformer) Close() error {
+	return iterator.Close(d.iter)
+}
+
 func (d *DebeziumTransformer) HasNext() bool {
 	return d != nil && d.iter.HasNext()
 }
@@ -90,19 +108,31 @@ func (d *DebeziumTransformer) Next() ([]kafkalib.Message, error) {
 			return nil, fmt.Errorf("failed to create Debezium payload: %w", err)
 		}
 
-		// TODO: debezium.FieldsObject is not set
-		result = append(result, kafkalib.NewMessage(d.adapter.TopicSuffix(), debezium.FieldsObject{}, d.partitionKey(row), &payload))
+		partitionKey, err := d.partitionKey(row, payload.Payload.After)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build partition key: %w", err)
+		}
+
+		result = append(result, kafkalib.NewMessage(d.adapter.TopicSuffix(), d.partitionKeySchema, partitionKey, &payload))
 	}
 
 	return result, nil
 }
 
-func (d *DebeziumTransformer) partitionKey(row Row) map[string]any {
+func (d *DebeziumTransformer) partitionKey(row Row, dbzRow Row) (map[string]any, error) {
 	result := make(map[string]any)
 	for _, key := range d.adapter.PartitionKeys() {
-		result[key] = row[key]
+		if key == RowHashKey {
+			hash, err := hashRow(dbzRow)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = hash
+		} else {
+			result[key] = row[key]
+		}
 	}
-	return result
+	return result, nil
 }
 
 func (d *DebeziumTransformer) createPayload(row Row) (util.SchemaEventPayload, error) {
@@ -151,6 +181,22 @@ func convertPartitionKey(valueConverters map[string]converters.ValueConverter, p
 	payload := make(map[string]any, len(partitionKeys))
 	pkFields := make([]debezium.Field, len(partitionKeys))
 	for _, key := range partitionKeys {
+		if key == RowHashKey {
+			dbzRow, err := convertRow(valueConverters, row)
+			if err != nil {
+				return debezium.PrimaryKeyPayload{}, err
+			}
+
+			hash, err := hashRow(dbzRow)
+			if err != nil {
+				return debezium.PrimaryKeyPayload{}, err
+			}
+
+			payload[key] = hash
+			pkFields = append(pkFields, converters.StringPassthrough{}.ToField(key))
+			continue
+		}
+
 		valueConverter, isOk := valueConverters[key]
 		if !isOk {
 			return debezium.PrimaryKeyPayload{}, fmt.Errorf("failed to get ValueConverter for key %q", key)
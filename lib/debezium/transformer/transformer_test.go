@@ -50,6 +50,27 @@ func TestConvertPartitionKey(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, map[string]any{"id": int64(12), "name": "bar"}, val)
 	}
+	{
+		// Row hash, identical rows have the same key
+		valueConverters := map[string]converters.ValueConverter{
+			"id":   converters.Int64Passthrough{},
+			"name": converters.StringPassthrough{},
+		}
+		pkPayload, err := convertPartitionKey(valueConverters, []string{RowHashKey}, row)
+		assert.NoError(t, err)
+
+		val, err := parseUsingTransfer(pkPayload)
+		assert.NoError(t, err)
+		assert.Len(t, val[RowHashKey], 64)
+
+		otherPayload, err := convertPartitionKey(valueConverters, []string{RowHashKey}, map[string]any{"name": "bar", "id": 12})
+		assert.NoError(t, err)
+		assert.Equal(t, pkPayload.Payload, otherPayload.Payload)
+
+		otherPayload, err = convertPartitionKey(valueConverters, []string{RowHashKey}, map[string]any{"name": "baz", "id": 12})
+		assert.NoError(t, err)
+		assert.NotEqual(t, pkPayload.Payload, otherPayload.Payload)
+	}
 }
 
 type testConverter struct {
@@ -282,6 +303,32 @@ func TestDebeziumTransformer_Next(t *testing.T) {
 		)
 		assert.Equal(t, expected, *payload)
 	}
+	{
+		// Table without a primary key, the row hash has a schema
+		transformer, err := NewDebeziumTransformer(mockAdatper{
+			fieldConverters: []FieldConverter{{Name: "foo", ValueConverter: testConverter{}}},
+			partitionKeys:   []string{RowHashKey},
+			iter:            iterator.Once([]Row{{"foo": "bar"}}),
+		})
+		assert.NoError(t, err)
+		results, err := iterator.Collect(transformer)
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Len(t, results[0], 1)
+
+		expectedHash, err := hashRow(Row{"foo": "converted-bar"})
+		assert.NoError(t, err)
+		assert.Equal(t,
+			debezium.PrimaryKeyPayload{
+				Schema: debezium.FieldsObject{
+					FieldObjectType: "struct",
+					Fields:          []debezium.Field{{FieldName: RowHashKey, Type: "string"}},
+				},
+				Payload: Row{RowHashKey: expectedHash},
+			},
+			results[0][0].PartitionKey(),
+		)
+	}
 }
 
 func TestDebeziumTransformer_CreatePayload(t *testing.T) {
@@ -360,7 +407,19 @@ func TestDebeziumTransformer_PartitionKey(t *testing.T) {
 	for _, testCase := range testCases {
 		transformer, err := NewDebeziumTransformer(mockAdatper{partitionKeys: testCase.keys})
 		assert.NoError(t, err)
-		assert.Equal(t, testCase.expected, transformer.partitionKey(testCase.row), testCase.name)
+		partitionKey, err := transformer.partitionKey(testCase.row, testCase.row)
+		assert.NoError(t, err, testCase.name)
+		assert.Equal(t, testCase.expected, partitionKey, testCase.name)
+	}
+	{
+		// Row hash
+		transformer, err := NewDebeziumTransformer(mockAdatper{partitionKeys: []string{RowHashKey}})
+		assert.NoError(t, err)
+		partitionKey, err := transformer.partitionKey(Row{"foo": "a"}, Row{"foo": "converted-a", "bar": nil})
+		assert.NoError(t, err)
+		expected, err := hashRow(Row{"bar": nil, "foo": "converted-a"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{RowHashKey: expected}, partitionKey)
 	}
 }
 
@@ -1,5 +1,11 @@
 package iterator
 
+import (
+	"context"
+	"io"
+	"time"
+)
+
 type Iterator[T any] interface {
 	HasNext() bool
 	Next() (T, error)
@@ -10,6 +16,40 @@ type StreamingIterator[T any] interface {
 	CommitOffset() error
 }
 
+// ContextIterator is implemented by iterators that wait for new items, [NextContext] returns early once [ctx] is done.
+type ContextIterator[T any] interface {
+	Iterator[T]
+	NextContext(ctx context.Context) (T, error)
+}
+
+// Next returns the next item of [iter], passing [ctx] along if it implements [ContextIterator].
+func Next[T any](ctx context.Context, iter Iterator[T]) (T, error) {
+	if contextIter, isOk := iter.(ContextIterator[T]); isOk {
+		return contextIter.NextContext(ctx)
+	}
+	return iter.Next()
+}
+
+// Sleep waits for [duration] or until [ctx] is done, whichever comes first.
+func Sleep(ctx context.Context, duration time.Duration) {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// Close releases the resources held by an [Iterator] that implements [io.Closer], it should be called if the iterator
+// is not read until the end.
+func Close[T any](iter Iterator[T]) error {
+	if closer, isOk := iter.(io.Closer); isOk {
+		return closer.Close()
+	}
+	return nil
+}
+
 // Collect returns a new slice containing all the items from an [Iterator].
 // Used for testing, use only with iterators containing a finite amount of items that fit in memory.
 func Collect[T any](iter Iterator[T]) ([]T, error) {
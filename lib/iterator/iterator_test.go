@@ -1,8 +1,10 @@
 package iterator
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,6 +15,43 @@ func (errorIterator) HasNext() bool { return true }
 
 func (errorIterator) Next() (int, error) { return 0, fmt.Errorf("error in Next()") }
 
+// waitingIterator waits for [ctx] to be done when it's passed one.
+type waitingIterator struct{}
+
+func (waitingIterator) HasNext() bool { return true }
+
+func (waitingIterator) Next() (int, error) { return 0, nil }
+
+func (waitingIterator) NextContext(ctx context.Context) (int, error) {
+	<-ctx.Done()
+	return 1, nil
+}
+
+func TestNext(t *testing.T) {
+	{
+		// Iterator that doesn't take a context.
+		item, err := Next(context.Background(), ForSlice([]int{5}))
+		assert.NoError(t, err)
+		assert.Equal(t, 5, item)
+	}
+	{
+		// The context is passed along.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		item, err := Next(ctx, waitingIterator{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, item)
+	}
+}
+
+func TestSleep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	Sleep(ctx, time.Minute)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
 func TestCollect(t *testing.T) {
 	{
 		// Empty iterator.
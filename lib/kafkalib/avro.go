@@ -0,0 +1,261 @@
+package kafkalib
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/artie-labs/transfer/lib/cdc/util"
+	"github.com/artie-labs/transfer/lib/debezium"
+
+	"github.com/artie-labs/reader/lib/avro"
+)
+
+// avroField - Column of a table and the Avro field that it maps to.
+type avroField struct {
+	column string
+	field  avro.Field
+	// jsonEncoded - Whether the value needs to be encoded as a JSON string, this is used for values that do not have a
+	// fixed shape (maps, untyped arrays and geometries).
+	jsonEncoded bool
+}
+
+func (a avroField) convertValue(value any) (any, error) {
+	if value == nil || !a.jsonEncoded {
+		return value, nil
+	}
+
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value for column %q: %w", a.column, err)
+	}
+
+	return string(bytes), nil
+}
+
+var variableScaleDecimalSchema = avro.Schema{
+	Type:      avro.Record,
+	Name:      "VariableScaleDecimal",
+	Namespace: "io.debezium.data",
+	Fields: []avro.Field{
+		{Name: "scale", Schema: avro.Schema{Type: avro.Int}},
+		{Name: "value", Schema: avro.Schema{Type: avro.Bytes}},
+	},
+}
+
+// logicalTypes - Avro logical types for Debezium types that have the same representation.
+var logicalTypes = map[debezium.SupportedDebeziumType]string{
+	debezium.Date:                  "date",
+	debezium.DateKafkaConnect:      "date",
+	debezium.Time:                  "time-millis",
+	debezium.TimeKafkaConnect:      "time-millis",
+	debezium.MicroTime:             "time-micros",
+	debezium.Timestamp:             "timestamp-millis",
+	debezium.TimestampKafkaConnect: "timestamp-millis",
+	debezium.MicroTimestamp:        "timestamp-micros",
+}
+
+func primitiveAvroType(fieldType debezium.FieldType) (avro.Type, bool) {
+	switch fieldType {
+	case debezium.Boolean:
+		return avro.Boolean, true
+	case debezium.Int16, debezium.Int32:
+		return avro.Int, true
+	case debezium.Int64:
+		return avro.Long, true
+	case debezium.Float:
+		return avro.Float, true
+	case debezium.Double:
+		return avro.Double, true
+	case debezium.String:
+		return avro.String, true
+	case debezium.Bytes:
+		return avro.Bytes, true
+	default:
+		return "", false
+	}
+}
+
+// toAvroField derives the Avro schema of a column from the [debezium.Field] produced by its value converter.
+func toAvroField(field debezium.Field, optional bool) (avroField, error) {
+	result := avroField{
+		column: field.FieldName,
+		field:  avro.Field{Name: avro.SanitizeName(field.FieldName), Optional: optional},
+	}
+
+	var schema avro.Schema
+	if avroType, isOk := primitiveAvroType(field.Type); isOk {
+		schema = avro.Schema{Type: avroType}
+		if logicalType, isOk := logicalTypes[field.DebeziumType]; isOk && (avroType == avro.Int || avroType == avro.Long) {
+			schema.LogicalType = logicalType
+		}
+
+		if field.DebeziumType == debezium.KafkaDecimalType {
+			scale, precision, err := field.GetScaleAndPrecision()
+			if err != nil {
+				return avroField{}, fmt.Errorf("failed to get scale and precision for column %q: %w", field.FieldName, err)
+			}
+
+			// Avro decimals require a precision, decimals without one are left as bytes.
+			if precision != nil {
+				schema.LogicalType = "decimal"
+				schema.Precision = int(*precision)
+				schema.Scale = int(scale)
+			}
+		}
+	} else {
+		switch field.Type {
+		case debezium.Struct:
+			if field.DebeziumType == debezium.KafkaVariableNumericType {
+				schema = variableScaleDecimalSchema
+			} else {
+				schema = avro.Schema{Type: avro.String}
+				result.jsonEncoded = true
+			}
+		case debezium.Array:
+			if field.ItemsMetadata != nil && field.ItemsMetadata.DebeziumType == debezium.JSON {
+				schema = avro.Schema{Type: avro.Array, Items: &avro.Schema{Type: avro.String}}
+			} else if itemType, isOk := primitiveItemType(field.ItemsMetadata); isOk {
+				schema = avro.Schema{Type: avro.Array, Items: &avro.Schema{Type: itemType}}
+			} else {
+				schema = avro.Schema{Type: avro.String}
+				result.jsonEncoded = true
+			}
+		case debezium.Map:
+			schema = avro.Schema{Type: avro.String}
+			result.jsonEncoded = true
+		default:
+			return avroField{}, fmt.Errorf("unsupported field type %q for column %q", field.Type, field.FieldName)
+		}
+	}
+
+	if field.DebeziumType != "" && schema.Type != avro.Record {
+		schema.Properties = map[string]any{"connect.name": string(field.DebeziumType)}
+	}
+
+	result.field.Schema = schema
+	return result, nil
+}
+
+func primitiveItemType(item *debezium.Item) (avro.Type, bool) {
+	if item == nil {
+		return "", false
+	}
+
+	return primitiveAvroType(item.Type)
+}
+
+// avroRecord - Avro record for a set of columns along with the information needed to convert rows to it.
+type avroRecord struct {
+	schema avro.Schema
+	fields []avroField
+}
+
+func newAvroRecord(name string, namespace string, fields []debezium.Field, optional bool) (avroRecord, error) {
+	record := avroRecord{schema: avro.Schema{Type: avro.Record, Name: name, Namespace: namespace}}
+	for _, field := range fields {
+		if field.FieldName == "" {
+			continue
+		}
+
+		converted, err := toAvroField(field, optional)
+		if err != nil {
+			return avroRecord{}, err
+		}
+
+		if slices.ContainsFunc(record.fields, func(f avroField) bool { return f.field.Name == converted.field.Name }) {
+			return avroRecord{}, fmt.Errorf("columns map to the same avro field name %q", converted.field.Name)
+		}
+
+		record.fields = append(record.fields, converted)
+		record.schema.Fields = append(record.schema.Fields, converted.field)
+	}
+
+	return record, nil
+}
+
+func (a avroRecord) convertRow(row map[string]any) (map[string]any, error) {
+	if row == nil {
+		return nil, nil
+	}
+
+	out := make(map[string]any, len(a.fields))
+	for _, field := range a.fields {
+		value, err := field.convertValue(row[field.column])
+		if err != nil {
+			return nil, err
+		}
+
+		out[field.field.Name] = value
+	}
+
+	return out, nil
+}
+
+func sourceAvroSchema(namespace string) avro.Schema {
+	return avro.Schema{
+		Type:      avro.Record,
+		Name:      "Source",
+		Namespace: namespace,
+		Fields: []avro.Field{
+			{Name: "connector", Schema: avro.Schema{Type: avro.String}},
+			{Name: "ts_ms", Schema: avro.Schema{Type: avro.Long}},
+			{Name: "db", Schema: avro.Schema{Type: avro.String}},
+			{Name: "schema", Schema: avro.Schema{Type: avro.String}, Optional: true},
+			{Name: "table", Schema: avro.Schema{Type: avro.String}},
+			{Name: "file", Schema: avro.Schema{Type: avro.String}, Optional: true},
+			{Name: "pos", Schema: avro.Schema{Type: avro.Long}, Optional: true},
+			{Name: "gtid", Schema: avro.Schema{Type: avro.String}, Optional: true},
+		},
+	}
+}
+
+func sourceToAvro(source util.Source) map[string]any {
+	out := map[string]any{
+		"connector": source.Connector,
+		"ts_ms":     source.TsMs,
+		"db":        source.Database,
+		"table":     source.Table,
+	}
+
+	if source.Schema != "" {
+		out["schema"] = source.Schema
+	}
+	if source.File != "" {
+		out["file"] = source.File
+	}
+	if source.Pos != 0 {
+		out["pos"] = source.Pos
+	}
+	if source.Gtid != nil {
+		out["gtid"] = *source.Gtid
+	}
+
+	return out
+}
+
+// buildEnvelopeSchema builds the schema of the Debezium envelope, rows are stored in the `before` and `after` fields.
+func buildEnvelopeSchema(namespace string, row avroRecord) avro.Schema {
+	return avro.Schema{
+		Type:      avro.Record,
+		Name:      "Envelope",
+		Namespace: namespace,
+		Fields: []avro.Field{
+			{Name: "before", Schema: row.schema, Optional: true},
+			{Name: "after", Schema: row.schema, Optional: true},
+			{Name: "source", Schema: sourceAvroSchema(namespace)},
+			{Name: "op", Schema: avro.Schema{Type: avro.String}},
+		},
+	}
+}
+
+// rowFields returns the columns of the row in the event, deletes only have a before row.
+func rowFields(event *util.SchemaEventPayload) []debezium.Field {
+	for _, label := range []debezium.FieldLabelKind{debezium.After, debezium.Before} {
+		if fieldsObject := event.Schema.GetSchemaFromLabel(label); fieldsObject != nil {
+			return fieldsObject.Fields
+		}
+	}
+
+	return nil
+}
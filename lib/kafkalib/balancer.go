@@ -0,0 +1,95 @@
+package kafkalib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/artie-labs/reader/config"
+)
+
+func newBalancer(cfg config.Kafka) (kafka.Balancer, error) {
+	switch cfg.Partitioning.GetStrategy() {
+	case config.PartitionStrategyKeyHash:
+		return kafka.Murmur2Balancer{}, nil
+	case config.PartitionStrategyRoundRobin:
+		return &kafka.RoundRobin{}, nil
+	case config.PartitionStrategyFixed:
+		partitions := make(map[string]int)
+		for topicSuffix, partition := range cfg.Partitioning.TablePartitions {
+			partitions[buildTopic(cfg.TopicPrefix, topicSuffix)] = partition
+		}
+		return fixedPartitionBalancer{partitions: partitions, fallback: kafka.Murmur2Balancer{}}, nil
+	case config.PartitionStrategyKeyColumns:
+		return keyColumnsBalancer{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported partition strategy: %q", cfg.Partitioning.GetStrategy())
+	}
+}
+
+// fixedPartitionBalancer publishes all the messages for a topic to the same partition.
+type fixedPartitionBalancer struct {
+	partitions map[string]int
+	fallback   kafka.Balancer
+}
+
+func (f fixedPartitionBalancer) Balance(msg kafka.Message, partitions ...int) int {
+	if partition, ok := f.partitions[msg.Topic]; ok {
+		if slices.Contains(partitions, partition) {
+			return partition
+		}
+
+		slog.Warn("Configured partition does not exist for topic, falling back to key hash",
+			slog.String("topic", msg.Topic),
+			slog.Int("partition", partition),
+			slog.Int("partitionCount", len(partitions)),
+		)
+	}
+
+	return f.fallback.Balance(msg, partitions...)
+}
+
+// keyColumnsBalancer hashes a subset of the partition key's columns with murmur2 so that rows sharing those values
+// land on the same partition. The values are taken from the partition key before it is serialized, see
+// [buildPartitionKey], since the serialized key may not be JSON (e.g. Avro).
+type keyColumnsBalancer struct {
+	hasher kafka.Murmur2Balancer
+}
+
+func (k keyColumnsBalancer) Balance(msg kafka.Message, partitions ...int) int {
+	key := msg.Key
+	if partitionKey, ok := msg.WriterData.([]byte); ok && len(partitionKey) > 0 {
+		key = partitionKey
+	}
+
+	return k.hasher.Balance(kafka.Message{Key: key}, partitions...)
+}
+
+// buildPartitionKey builds the bytes for [keyColumnsBalancer] to hash from the values of [columns] in the partition key,
+// it returns nil if none of the columns are present so that the message key is hashed instead.
+func buildPartitionKey(columns []string, partitionKeyValues map[string]any) ([]byte, error) {
+	var values [][]byte
+	for _, column := range columns {
+		value, ok := partitionKeyValues[column]
+		if !ok {
+			continue
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal value of key column %q: %w", column, err)
+		}
+
+		values = append(values, encoded)
+	}
+
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	return bytes.Join(values, []byte{0}), nil
+}
@@ -0,0 +1,119 @@
+package kafkalib
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/config"
+)
+
+func TestNewBalancer(t *testing.T) {
+	{
+		// Default
+		balancer, err := newBalancer(config.Kafka{})
+		assert.NoError(t, err)
+		assert.Equal(t, kafka.Murmur2Balancer{}, balancer)
+	}
+	{
+		// Round robin
+		balancer, err := newBalancer(config.Kafka{Partitioning: &config.KafkaPartitioning{Strategy: config.PartitionStrategyRoundRobin}})
+		assert.NoError(t, err)
+		assert.IsType(t, &kafka.RoundRobin{}, balancer)
+	}
+	{
+		// Fixed
+		balancer, err := newBalancer(config.Kafka{
+			TopicPrefix:  "prefix",
+			Partitioning: &config.KafkaPartitioning{Strategy: config.PartitionStrategyFixed, TablePartitions: map[string]int{"public.users": 2}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"prefix.public.users": 2}, balancer.(fixedPartitionBalancer).partitions)
+	}
+	{
+		// Invalid
+		_, err := newBalancer(config.Kafka{Partitioning: &config.KafkaPartitioning{Strategy: "foo"}})
+		assert.ErrorContains(t, err, `unsupported partition strategy: "foo"`)
+	}
+}
+
+func TestMurmur2Balancer(t *testing.T) {
+	// The default balancer should match the Java client: toPositive(murmur2(key)) % numPartitions
+	partitions := []int{0, 1, 2, 3, 4, 5, 6}
+	balancer, err := newBalancer(config.Kafka{})
+	assert.NoError(t, err)
+	// murmur2("kafka") = 0xd067cf64
+	assert.Equal(t, 3, balancer.Balance(kafka.Message{Key: []byte("kafka")}, partitions...))
+	// murmur2("34") = 0x873930da
+	assert.Equal(t, 6, balancer.Balance(kafka.Message{Key: []byte("34")}, partitions...))
+}
+
+func TestFixedPartitionBalancer_Balance(t *testing.T) {
+	balancer := fixedPartitionBalancer{partitions: map[string]int{"prefix.public.users": 2, "prefix.public.orders": 20}, fallback: kafka.Murmur2Balancer{}}
+	partitions := []int{0, 1, 2, 3}
+	{
+		// Configured table
+		assert.Equal(t, 2, balancer.Balance(kafka.Message{Topic: "prefix.public.users", Key: []byte("a")}, partitions...))
+		assert.Equal(t, 2, balancer.Balance(kafka.Message{Topic: "prefix.public.users", Key: []byte("b")}, partitions...))
+	}
+	{
+		// Configured partition does not exist
+		msg := kafka.Message{Topic: "prefix.public.orders", Key: []byte("a")}
+		assert.Equal(t, kafka.Murmur2Balancer{}.Balance(msg, partitions...), balancer.Balance(msg, partitions...))
+	}
+	{
+		// Table is not configured
+		msg := kafka.Message{Topic: "prefix.public.accounts", Key: []byte("a")}
+		assert.Equal(t, kafka.Murmur2Balancer{}.Balance(msg, partitions...), balancer.Balance(msg, partitions...))
+	}
+}
+
+func TestKeyColumnsBalancer_Balance(t *testing.T) {
+	partitions := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	balancer := keyColumnsBalancer{}
+	{
+		// Partition key is hashed instead of the message key
+		msg := kafka.Message{Key: []byte("avro"), WriterData: []byte("1\x00\"us\"")}
+		assert.Equal(t, kafka.Murmur2Balancer{}.Balance(kafka.Message{Key: []byte("1\x00\"us\"")}, partitions...), balancer.Balance(msg, partitions...))
+	}
+	{
+		// Without a partition key the message key is hashed
+		for _, msg := range []kafka.Message{{Key: []byte("a")}, {Key: []byte("a"), WriterData: []byte(nil)}} {
+			assert.Equal(t, kafka.Murmur2Balancer{}.Balance(msg, partitions...), balancer.Balance(msg, partitions...))
+		}
+	}
+}
+
+func TestBuildPartitionKey(t *testing.T) {
+	columns := []string{"tenant_id", "region"}
+	{
+		// Subset of the key
+		partitionKey, err := buildPartitionKey(columns, map[string]any{"id": 5, "region": "us", "tenant_id": int64(1)})
+		assert.NoError(t, err)
+		assert.Equal(t, "1\x00\"us\"", string(partitionKey))
+	}
+	{
+		// Same subset, different key, same partition key
+		a, err := buildPartitionKey(columns, map[string]any{"id": 5, "region": "us", "tenant_id": 1})
+		assert.NoError(t, err)
+		b, err := buildPartitionKey(columns, map[string]any{"id": 6, "region": "us", "tenant_id": 1})
+		assert.NoError(t, err)
+		assert.Equal(t, a, b)
+	}
+	{
+		// None of the columns are present or there are no key columns
+		partitionKey, err := buildPartitionKey(columns, map[string]any{"id": 5})
+		assert.NoError(t, err)
+		assert.Nil(t, partitionKey)
+
+		partitionKey, err = buildPartitionKey(nil, map[string]any{"id": 5})
+		assert.NoError(t, err)
+		assert.Nil(t, partitionKey)
+	}
+	{
+		// Value cannot be marshalled
+		_, err := buildPartitionKey(columns, map[string]any{"region": make(chan int)})
+		assert.ErrorContains(t, err, `failed to marshal value of key column "region"`)
+	}
+}
@@ -22,12 +22,20 @@ func NewMessage(topicSuffix string, partitionKeySchema debezium.FieldsObject, pa
 	}
 }
 
+func (r Message) TopicSuffix() string {
+	return r.topicSuffix
+}
+
 func (r Message) Topic(prefix string) string {
+	return buildTopic(prefix, r.topicSuffix)
+}
+
+func buildTopic(prefix string, topicSuffix string) string {
 	if prefix == "" {
-		return r.topicSuffix
+		return topicSuffix
 	}
 
-	return fmt.Sprintf("%s.%s", prefix, r.topicSuffix)
+	return fmt.Sprintf("%s.%s", prefix, topicSuffix)
 }
 
 func (r Message) PartitionKey() debezium.PrimaryKeyPayload {
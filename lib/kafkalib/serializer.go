@@ -0,0 +1,208 @@
+package kafkalib
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/artie-labs/transfer/lib/cdc/util"
+	"github.com/artie-labs/transfer/lib/debezium"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/avro"
+	"github.com/artie-labs/reader/lib/schemaregistry"
+)
+
+// Serializer - Encodes either the key or the value of a message.
+type Serializer interface {
+	Serialize(ctx context.Context, topic string, msg Message) ([]byte, error)
+}
+
+func NewSerializer(serializer config.Serializer, isKey bool, registry *schemaregistry.Client, strategy config.SubjectNameStrategy) Serializer {
+	switch serializer {
+	case config.SerializerAvro:
+		return &avroSerializer{
+			isKey:     isKey,
+			registry:  registry,
+			strategy:  strategy,
+			rowFields: make(map[string][]debezium.Field),
+		}
+	default:
+		return jsonSerializer{isKey: isKey}
+	}
+}
+
+type jsonSerializer struct {
+	isKey bool
+}
+
+func (j jsonSerializer) Serialize(_ context.Context, _ string, msg Message) ([]byte, error) {
+	if j.isKey {
+		return json.Marshal(msg.PartitionKey())
+	}
+
+	return json.Marshal(msg.Event())
+}
+
+// avroSerializer - Encodes messages with Avro using the Confluent wire format, a magic byte followed by the schema ID.
+type avroSerializer struct {
+	isKey    bool
+	registry *schemaregistry.Client
+	strategy config.SubjectNameStrategy
+
+	mu sync.Mutex
+	// rowFields - Last known columns for each topic, this is used for events that do not have a row (truncates) so that
+	// they are published with the same schema.
+	rowFields map[string][]debezium.Field
+}
+
+func (a *avroSerializer) Serialize(ctx context.Context, topic string, msg Message) ([]byte, error) {
+	event, isOk := msg.Event().(*util.SchemaEventPayload)
+	if !isOk {
+		return nil, fmt.Errorf("avro serialization is not supported for events of type %T", msg.Event())
+	}
+
+	namespace := avro.SanitizeNamespace(topic)
+	fields := a.getRowFields(topic, event)
+
+	var schema avro.Schema
+	var value any
+	if a.isKey {
+		key, err := buildAvroKey(namespace, fields, msg, event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build avro key: %w", err)
+		}
+
+		if key == nil {
+			// Messages without a partition key (e.g. truncates) have a null key.
+			return nil, nil
+		}
+
+		schema, value = key.schema, key.value
+	} else {
+		row, err := newAvroRecord("Value", namespace, fields, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build avro row schema: %w", err)
+		}
+
+		before, err := row.convertRow(event.Payload.Before)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert before row: %w", err)
+		}
+
+		after, err := row.convertRow(event.Payload.After)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert after row: %w", err)
+		}
+
+		schema = buildEnvelopeSchema(namespace, row)
+		value = map[string]any{
+			"before": before,
+			"after":  after,
+			"source": sourceToAvro(event.Payload.Source),
+			"op":     event.Payload.Operation,
+		}
+	}
+
+	schemaID, err := a.registry.GetOrRegister(ctx, a.subject(topic, schema), schema.String())
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := avro.Encode(schema, value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode avro: %w", err)
+	}
+
+	out := binary.BigEndian.AppendUint32([]byte{0}, uint32(schemaID))
+	return append(out, encoded...), nil
+}
+
+func (a *avroSerializer) getRowFields(topic string, event *util.SchemaEventPayload) []debezium.Field {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if fields := rowFields(event); fields != nil {
+		a.rowFields[topic] = fields
+		return fields
+	}
+
+	return a.rowFields[topic]
+}
+
+func (a *avroSerializer) subject(topic string, schema avro.Schema) string {
+	switch a.strategy {
+	case config.SubjectNameStrategyRecordName:
+		return schema.FullName()
+	case config.SubjectNameStrategyTopicRecordName:
+		return fmt.Sprintf("%s-%s", topic, schema.FullName())
+	default:
+		if a.isKey {
+			return fmt.Sprintf("%s-key", topic)
+		}
+		return fmt.Sprintf("%s-value", topic)
+	}
+}
+
+type avroKey struct {
+	schema avro.Schema
+	value  map[string]any
+}
+
+// buildAvroKey builds the key from the partition key schema, falling back to the row's columns if the message does not
+// have one (snapshots). Values are taken from the converted row so that they match the schema.
+func buildAvroKey(namespace string, fields []debezium.Field, msg Message, event *util.SchemaEventPayload) (*avroKey, error) {
+	keyValues := msg.PartitionKeyValues()
+	if len(keyValues) == 0 {
+		return nil, nil
+	}
+
+	var keyFields []debezium.Field
+	for _, field := range msg.PartitionKey().Schema.Fields {
+		if field.FieldName != "" {
+			keyFields = append(keyFields, field)
+		}
+	}
+
+	if len(keyFields) == 0 {
+		keyNames := make([]string, 0, len(keyValues))
+		for keyName := range keyValues {
+			keyNames = append(keyNames, keyName)
+		}
+		slices.Sort(keyNames)
+
+		for _, keyName := range keyNames {
+			idx := slices.IndexFunc(fields, func(f debezium.Field) bool { return f.FieldName == keyName })
+			if idx == -1 {
+				return nil, fmt.Errorf("failed to find schema for key column %q", keyName)
+			}
+			keyFields = append(keyFields, fields[idx])
+		}
+	}
+
+	record, err := newAvroRecord("Key", namespace, keyFields, false)
+	if err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]any, len(keyFields))
+	for _, field := range keyFields {
+		value, isOk := event.Payload.After[field.FieldName]
+		if !isOk {
+			if value, isOk = event.Payload.Before[field.FieldName]; !isOk {
+				value = keyValues[field.FieldName]
+			}
+		}
+		row[field.FieldName] = value
+	}
+
+	value, err := record.convertRow(row)
+	if err != nil {
+		return nil, err
+	}
+
+	return &avroKey{schema: record.schema, value: value}, nil
+}
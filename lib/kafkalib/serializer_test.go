@@ -0,0 +1,146 @@
+package kafkalib
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/artie-labs/transfer/lib/cdc/util"
+	"github.com/artie-labs/transfer/lib/debezium"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/avro"
+	"github.com/artie-labs/reader/lib/schemaregistry"
+	"github.com/artie-labs/reader/lib/schemaregistry/schemaregistrytest"
+)
+
+func TestAvroSerializer(t *testing.T) {
+	server := schemaregistrytest.NewServer()
+	defer server.Close()
+
+	fields := []debezium.Field{
+		{FieldName: "id", Type: debezium.Int64},
+		{FieldName: "first name", Type: debezium.String},
+		{FieldName: "tags", Type: debezium.Map},
+	}
+	event := &util.SchemaEventPayload{
+		Schema: debezium.Schema{FieldsObject: []debezium.FieldsObject{{Fields: fields, FieldLabel: debezium.After}}},
+		Payload: util.Payload{
+			After:     map[string]any{"id": int64(1), "first name": "foo", "tags": map[string]any{"a": "b"}},
+			Source:    util.Source{Connector: "postgresql", TsMs: 1000, Database: "db", Schema: "public", Table: "users"},
+			Operation: "c",
+		},
+	}
+
+	ctx := context.Background()
+	registry := schemaregistry.NewClient(config.SchemaRegistry{URL: server.URL})
+	valueSerializer := NewSerializer(config.SerializerAvro, false, registry, config.SubjectNameStrategyTopicName)
+	keySerializer := NewSerializer(config.SerializerAvro, true, registry, config.SubjectNameStrategyTopicName)
+	{
+		// Value
+		msg := NewMessage("public.users", debezium.FieldsObject{}, map[string]any{"id": int64(1)}, event)
+		value, err := valueSerializer.Serialize(ctx, "prefix.public.users", msg)
+		assert.NoError(t, err)
+		assert.Equal(t, byte(0), value[0])
+		schemaID := int(binary.BigEndian.Uint32(value[1:5]))
+
+		schemas := server.Schemas("prefix.public.users-value")
+		assert.Len(t, schemas, 1)
+		assert.Equal(t, schemas[0], server.Schema(schemaID))
+
+		row, err := newAvroRecord("Value", "prefix.public.users", fields, true)
+		assert.NoError(t, err)
+		expected, err := avro.Encode(buildEnvelopeSchema("prefix.public.users", row), map[string]any{
+			"after":  map[string]any{"id": int64(1), "first_name": "foo", "tags": `{"a":"b"}`},
+			"source": map[string]any{"connector": "postgresql", "ts_ms": int64(1000), "db": "db", "schema": "public", "table": "users"},
+			"op":     "c",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value[5:])
+	}
+	{
+		// Key falls back to the row's schema when the message does not have a key schema
+		msg := NewMessage("public.users", debezium.FieldsObject{}, map[string]any{"id": int64(1)}, event)
+		key, err := keySerializer.Serialize(ctx, "prefix.public.users", msg)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x02}, key[5:])
+		assert.Equal(t, []string{`{"fields":[{"name":"id","type":"long"}],"name":"Key","namespace":"prefix.public.users","type":"record"}`}, server.Schemas("prefix.public.users-key"))
+	}
+	{
+		// Tables without a primary key are keyed by the row hash, which is not a column of the row
+		keySchema := debezium.FieldsObject{Fields: []debezium.Field{{FieldName: "__artie_row_hash", Type: debezium.String}}}
+		msg := NewMessage("public.logs", keySchema, map[string]any{"__artie_row_hash": "abc"}, event)
+		key, err := keySerializer.Serialize(ctx, "prefix.public.logs", msg)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x06, 'a', 'b', 'c'}, key[5:])
+		assert.Equal(t, []string{`{"fields":[{"name":"__artie_row_hash","type":"string"}],"name":"Key","namespace":"prefix.public.logs","type":"record"}`}, server.Schemas("prefix.public.logs-key"))
+
+		// Without a key schema the row hash cannot be found in the row
+		msg = NewMessage("public.logs", debezium.FieldsObject{}, map[string]any{"__artie_row_hash": "abc"}, event)
+		_, err = keySerializer.Serialize(ctx, "prefix.public.logs", msg)
+		assert.ErrorContains(t, err, `failed to find schema for key column "__artie_row_hash"`)
+	}
+	{
+		// Truncates do not have a row or a key, the last known row schema is used
+		truncate := &util.SchemaEventPayload{Payload: util.Payload{Source: event.Payload.Source, Operation: "t"}}
+		msg := NewMessage("public.users", debezium.FieldsObject{}, nil, truncate)
+		key, err := keySerializer.Serialize(ctx, "prefix.public.users", msg)
+		assert.NoError(t, err)
+		assert.Nil(t, key)
+
+		_, err = valueSerializer.Serialize(ctx, "prefix.public.users", msg)
+		assert.NoError(t, err)
+		assert.Len(t, server.Schemas("prefix.public.users-value"), 1)
+	}
+	{
+		// Record name strategy
+		serializer := NewSerializer(config.SerializerAvro, false, registry, config.SubjectNameStrategyRecordName)
+		msg := NewMessage("public.users", debezium.FieldsObject{}, map[string]any{"id": int64(1)}, event)
+		_, err := serializer.Serialize(ctx, "prefix.public.users", msg)
+		assert.NoError(t, err)
+		assert.Len(t, server.Schemas("prefix.public.users.Envelope"), 1)
+	}
+	{
+		// Events that are not relational are not supported
+		msg := NewMessage("public.users", debezium.FieldsObject{}, nil, nil)
+		_, err := valueSerializer.Serialize(ctx, "prefix.public.users", msg)
+		assert.ErrorContains(t, err, "avro serialization is not supported for events of type <nil>")
+	}
+}
+
+func TestToAvroField(t *testing.T) {
+	{
+		// Decimal with a precision
+		field := debezium.Field{FieldName: "price", Type: debezium.Bytes, DebeziumType: debezium.KafkaDecimalType, Parameters: map[string]any{"scale": "2", debezium.KafkaDecimalPrecisionKey: "5"}}
+		result, err := toAvroField(field, true)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"connect.name":"org.apache.kafka.connect.data.Decimal","logicalType":"decimal","precision":5,"scale":2,"type":"bytes"}`, result.field.Schema.String())
+	}
+	{
+		// Timestamp
+		result, err := toAvroField(debezium.Field{FieldName: "ts", Type: debezium.Int64, DebeziumType: debezium.MicroTimestamp}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"connect.name":"io.debezium.time.MicroTimestamp","logicalType":"timestamp-micros","type":"long"}`, result.field.Schema.String())
+	}
+	{
+		// Variable scale decimal
+		result, err := toAvroField(debezium.Field{FieldName: "num", Type: debezium.Struct, DebeziumType: debezium.KafkaVariableNumericType}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, variableScaleDecimalSchema, result.field.Schema)
+	}
+	{
+		// Untyped arrays are JSON encoded
+		result, err := toAvroField(debezium.Field{FieldName: "arr", Type: debezium.Array}, true)
+		assert.NoError(t, err)
+		assert.True(t, result.jsonEncoded)
+		value, err := result.convertValue([]any{1, "a"})
+		assert.NoError(t, err)
+		assert.Equal(t, `[1,"a"]`, value)
+	}
+	{
+		// Columns that map to the same name
+		_, err := newAvroRecord("Value", "ns", []debezium.Field{{FieldName: "a-b", Type: debezium.String}, {FieldName: "a_b", Type: debezium.String}}, true)
+		assert.ErrorContains(t, err, `columns map to the same avro field name "a_b"`)
+	}
+}
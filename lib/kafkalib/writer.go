@@ -16,6 +16,7 @@ import (
 
 	"github.com/artie-labs/reader/config"
 	"github.com/artie-labs/reader/lib/mtr"
+	"github.com/artie-labs/reader/lib/schemaregistry"
 )
 
 func newWriter(ctx context.Context, cfg config.Kafka) (*kafka.Writer, error) {
@@ -26,10 +27,15 @@ func newWriter(ctx context.Context, cfg config.Kafka) (*kafka.Writer, error) {
 		return nil, fmt.Errorf("failed to create kafka transport: %w", err)
 	}
 
+	balancer, err := newBalancer(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka balancer: %w", err)
+	}
+
 	writer := &kafka.Writer{
 		Addr:                   kafka.TCP(cfg.BootstrapAddresses()...),
 		AllowAutoTopicCreation: true,
-		Balancer:               &kafka.LeastBytes{},
+		Balancer:               balancer,
 		Compression:            kafka.Gzip,
 		Transport:              transport,
 		WriteTimeout:           5 * time.Second,
@@ -40,9 +46,11 @@ func newWriter(ctx context.Context, cfg config.Kafka) (*kafka.Writer, error) {
 }
 
 type BatchWriter struct {
-	writer *kafka.Writer
-	cfg    config.Kafka
-	statsD mtr.Client
+	writer          *kafka.Writer
+	cfg             config.Kafka
+	statsD          mtr.Client
+	keySerializer   Serializer
+	valueSerializer Serializer
 }
 
 func NewBatchWriter(ctx context.Context, cfg config.Kafka, statsD mtr.Client) (*BatchWriter, error) {
@@ -58,7 +66,20 @@ func NewBatchWriter(ctx context.Context, cfg config.Kafka, statsD mtr.Client) (*
 	if err != nil {
 		return nil, err
 	}
-	return &BatchWriter{writer, cfg, statsD}, nil
+	var registry *schemaregistry.Client
+	var strategy config.SubjectNameStrategy
+	if cfg.UsesAvro() {
+		registry = schemaregistry.NewClient(*cfg.SchemaRegistry)
+		strategy = cfg.SchemaRegistry.GetSubjectNameStrategy()
+	}
+
+	return &BatchWriter{
+		writer:          writer,
+		cfg:             cfg,
+		statsD:          statsD,
+		keySerializer:   NewSerializer(cfg.GetKeySerializer(), true, registry, strategy),
+		valueSerializer: NewSerializer(cfg.GetValueSerializer(), false, registry, strategy),
+	}, nil
 }
 
 func (b *BatchWriter) reload(ctx context.Context) error {
@@ -76,21 +97,29 @@ func (b *BatchWriter) reload(ctx context.Context) error {
 	return nil
 }
 
-func buildKafkaMessageWrapper(topicPrefix string, rawMessage Message) (KafkaMessageWrapper, error) {
-	valueBytes, err := json.Marshal(rawMessage.Event())
+// buildKafkaMessageWrapper serializes [rawMessage], the values of [keyColumns] are kept for [keyColumnsBalancer].
+func buildKafkaMessageWrapper(ctx context.Context, topicPrefix string, keySerializer Serializer, valueSerializer Serializer, keyColumns []string, rawMessage Message) (KafkaMessageWrapper, error) {
+	topic := rawMessage.Topic(topicPrefix)
+	valueBytes, err := valueSerializer.Serialize(ctx, topic, rawMessage)
+	if err != nil {
+		return KafkaMessageWrapper{}, fmt.Errorf("failed to serialize value: %w", err)
+	}
+
+	keyBytes, err := keySerializer.Serialize(ctx, topic, rawMessage)
 	if err != nil {
-		return KafkaMessageWrapper{}, err
+		return KafkaMessageWrapper{}, fmt.Errorf("failed to serialize key: %w", err)
 	}
 
-	keyBytes, err := json.Marshal(rawMessage.PartitionKey())
+	partitionKey, err := buildPartitionKey(keyColumns, rawMessage.PartitionKeyValues())
 	if err != nil {
-		return KafkaMessageWrapper{}, err
+		return KafkaMessageWrapper{}, fmt.Errorf("failed to build partition key: %w", err)
 	}
 
 	return KafkaMessageWrapper{
-		Topic:        rawMessage.Topic(topicPrefix),
+		Topic:        topic,
 		MessageKey:   keyBytes,
 		MessageValue: valueBytes,
+		PartitionKey: partitionKey,
 	}, nil
 }
 
@@ -99,6 +128,8 @@ type KafkaMessageWrapper struct {
 	Topic        string `json:"topic"`
 	MessageKey   []byte `json:"messageKey"`
 	MessageValue []byte `json:"messageValue"`
+	// PartitionKey - Bytes that [keyColumnsBalancer] hashes, this is not published.
+	PartitionKey []byte `json:"partitionKey,omitempty"`
 }
 
 func (k KafkaMessageWrapper) Key() string {
@@ -107,9 +138,10 @@ func (k KafkaMessageWrapper) Key() string {
 
 func (k KafkaMessageWrapper) toKafkaMessage() kafka.Message {
 	return kafka.Message{
-		Topic: k.Topic,
-		Key:   k.MessageKey,
-		Value: k.MessageValue,
+		Topic:      k.Topic,
+		Key:        k.MessageKey,
+		Value:      k.MessageValue,
+		WriterData: k.PartitionKey,
 	}
 }
 
@@ -171,7 +203,7 @@ func (b *BatchWriter) Write(ctx context.Context, rawMsgs []Message) error {
 	var sampleExecutionTime time.Time
 	for _, rawMsg := range rawMsgs {
 		sampleExecutionTime = rawMsg.Event().GetExecutionTime()
-		msg, err := buildKafkaMessageWrapper(b.cfg.TopicPrefix, rawMsg)
+		msg, err := buildKafkaMessageWrapper(ctx, b.cfg.TopicPrefix, b.keySerializer, b.valueSerializer, b.cfg.Partitioning.GetKeyColumns(), rawMsg)
 		if err != nil {
 			return fmt.Errorf("failed to build kafka message: %w", err)
 		}
@@ -184,10 +216,10 @@ func (b *BatchWriter) Write(ctx context.Context, rawMsgs []Message) error {
 
 func (*BatchWriter) BeforeBackfill(_ context.Context, _ string) error { return nil }
 
-func (b *BatchWriter) OnComplete(_ context.Context) error {
+func (b *BatchWriter) OnComplete(_ context.Context, _ []string) error {
 	return nil
 }
 
-func (b *BatchWriter) CreateTable(_ context.Context, _ string, _ []columns.Column) error {
+func (b *BatchWriter) CreateTable(_ context.Context, _ string, _ string, _ []columns.Column) error {
 	return nil
 }
@@ -1,6 +1,8 @@
 package kafkalib
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/artie-labs/transfer/lib/cdc/util"
@@ -27,7 +29,7 @@ func TestNewMessage(t *testing.T) {
 		},
 	)
 
-	msg, err := buildKafkaMessageWrapper("topic-prefix", rawMessage)
+	msg, err := buildKafkaMessageWrapper(context.Background(), "topic-prefix", jsonSerializer{isKey: true}, jsonSerializer{}, nil, rawMessage)
 	assert.NoError(t, err)
 	assert.Equal(t, "topic-prefix.topic-suffix", msg.Topic)
 	assert.Equal(t, `{"schema":{"type":"","fields":null,"optional":false,"field":""},"payload":{"key":"value"}}`, string(msg.MessageKey))
@@ -38,3 +40,20 @@ func TestNewMessage(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, pkMap, returnedPkMap)
 }
+
+func TestBuildKafkaMessageWrapper_KeyColumns(t *testing.T) {
+	rawMessage := NewMessage("topic-suffix", debezium.FieldsObject{}, map[string]any{"id": 5, "tenant_id": 1}, &util.SchemaEventPayload{})
+
+	// The partition key is built before the key is serialized, so it does not depend on the key serializer
+	msg, err := buildKafkaMessageWrapper(context.Background(), "topic-prefix", jsonSerializer{isKey: true}, jsonSerializer{}, []string{"tenant_id"}, rawMessage)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1"), msg.PartitionKey)
+
+	// The partition key is kept when the message is encoded for batching, but not published
+	encoded, err := encoder(msg)
+	assert.NoError(t, err)
+	var decoded KafkaMessageWrapper
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, []byte("1"), decoded.toKafkaMessage().WriterData)
+	assert.Empty(t, decoded.toKafkaMessage().Headers)
+}
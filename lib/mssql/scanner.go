@@ -2,6 +2,7 @@ package mssql
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -20,6 +21,7 @@ import (
 const (
 	TimeMicro      = "15:04:05.000000"
 	TimeNano       = "15:04:05.000000000"
+	DateTimeMilli  = "2006-01-02 15:04:05.000"
 	DateTimeMicro  = "2006-01-02 15:04:05.000000"
 	DateTimeNano   = "2006-01-02 15:04:05.000000000"
 	DateTimeOffset = "2006-01-02 15:04:05.0000000 -07:00"
@@ -41,6 +43,22 @@ func NewScanner(db *sql.DB, table Table, columns []schema.Column, cfg scan.Scann
 	return scan.NewScanner(db, primaryKeyBounds, cfg, adapter)
 }
 
+// NewKeylessScanner returns a scanner that reads every row of a table that has no key, it returns
+// [rdbms.ErrNoPkValuesForEmptyTable] if the table is empty.
+func NewKeylessScanner(db *sql.DB, table Table, columns []schema.Column, cfg scan.ScannerConfig) (*scan.FullScanner, error) {
+	var value any
+	query := fmt.Sprintf("SELECT TOP 1 1 FROM %s", dialect.NewTableIdentifier(table.Schema, table.Name).FullyQualifiedName())
+	if err := db.QueryRow(query).Scan(&value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, rdbms.ErrNoPkValuesForEmptyTable
+		}
+		return nil, fmt.Errorf("failed to check whether table is empty: %w", err)
+	}
+
+	adapter := scanAdapter{schema: table.Schema, tableName: table.Name, columns: columns}
+	return scan.NewFullScanner(db, adapter.buildFullScanQuery(), nil, cfg, adapter)
+}
+
 type scanAdapter struct {
 	schema    string
 	tableName string
@@ -178,6 +196,17 @@ func (s scanAdapter) BuildQuery(primaryKeys []primary_key.Key, isFirstBatch bool
 	), slices.Concat(startingValues, endingValues), nil
 }
 
+func (s scanAdapter) buildFullScanQuery() string {
+	mssqlDialect := dialect.MSSQLDialect{}
+	colNames := make([]string, len(s.columns))
+	for idx, col := range s.columns {
+		colNames[idx] = mssqlDialect.QuoteIdentifier(col.Name)
+	}
+
+	return fmt.Sprintf("SELECT %s FROM %s.%s", strings.Join(colNames, ","),
+		mssqlDialect.QuoteIdentifier(s.schema), mssqlDialect.QuoteIdentifier(s.tableName))
+}
+
 func (s scanAdapter) ParseRow(values []any) error {
 	for i, value := range values {
 		parsedValue, err := parse.ParseValue(s.columns[i].Type, value)
@@ -190,3 +219,120 @@ func (s scanAdapter) ParseRow(values []any) error {
 
 	return nil
 }
+
+func (s scanAdapter) BuildSampleQuery(primaryKeys []primary_key.Key, sampleSize uint) (string, []any, error) {
+	mssqlDialect := dialect.MSSQLDialect{}
+	colNames := make([]string, len(s.columns))
+	for idx, col := range s.columns {
+		colNames[idx] = mssqlDialect.QuoteIdentifier(col.Name)
+	}
+
+	startingValues := make([]any, len(primaryKeys))
+	endingValues := make([]any, len(primaryKeys))
+	for i, pk := range primaryKeys {
+		pkStartVal, err := s.encodePrimaryKeyValue(pk.Name, pk.StartingValue)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encode start primary key val: %w", err)
+		}
+
+		pkEndVal, err := s.encodePrimaryKeyValue(pk.Name, pk.EndingValue)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encode end primary key val: %w", err)
+		}
+
+		startingValues[i] = pkStartVal
+		endingValues[i] = pkEndVal
+	}
+
+	quotedKeyNames := make([]string, len(primaryKeys))
+	for i, key := range primaryKeys {
+		quotedKeyNames[i] = mssqlDialect.QuoteIdentifier(key.Name)
+	}
+
+	tableName := fmt.Sprintf("%s.%s", mssqlDialect.QuoteIdentifier(s.schema), mssqlDialect.QuoteIdentifier(s.tableName))
+	// Rows are sampled using the estimated row count of the table, NEWID() is used so that RAND() is evaluated per row.
+	return fmt.Sprintf(`SELECT %s FROM %s WHERE (%s) >= (%s) AND (%s) <= (%s) AND RAND(CHECKSUM(NEWID())) < CAST(? AS FLOAT) / (SELECT CASE WHEN SUM(rows) > 0 THEN SUM(rows) ELSE 1 END FROM sys.partitions WHERE object_id = OBJECT_ID(?) AND index_id IN (0, 1)) ORDER BY %s`,
+		// SELECT
+		strings.Join(colNames, ","),
+		// FROM
+		tableName,
+		// WHERE (pk) >= (123)
+		strings.Join(quotedKeyNames, ","), strings.Join(rdbms.QueryPlaceholders("?", len(startingValues)), ","),
+		strings.Join(quotedKeyNames, ","), strings.Join(rdbms.QueryPlaceholders("?", len(endingValues)), ","),
+		// ORDER BY
+		strings.Join(quotedKeyNames, ","),
+	), slices.Concat(startingValues, endingValues, []any{sampleSize, tableName}), nil
+}
+
+// NewPollAdapter returns the adapter that is used to poll [table] for changes.
+func NewPollAdapter(table Table, columns []schema.Column) scan.PollAdapter {
+	return scanAdapter{schema: table.Schema, tableName: table.Name, columns: columns}
+}
+
+func (s scanAdapter) BuildPollQuery(cursorColumn string, primaryKeys []string, after []any, lag time.Duration, batchSize uint) (string, []any, error) {
+	cursorIdx := slices.IndexFunc(s.columns, func(x schema.Column) bool { return x.Name == cursorColumn })
+	if cursorIdx < 0 {
+		return "", nil, fmt.Errorf("cursor column %q is not one of the selected columns", cursorColumn)
+	}
+
+	mssqlDialect := dialect.MSSQLDialect{}
+	colNames := make([]string, len(s.columns))
+	for idx, col := range s.columns {
+		colNames[idx] = mssqlDialect.QuoteIdentifier(col.Name)
+	}
+
+	keyNames := append([]string{cursorColumn}, primaryKeys...)
+	quotedKeyNames := make([]string, len(keyNames))
+	for i, key := range keyNames {
+		quotedKeyNames[i] = mssqlDialect.QuoteIdentifier(key)
+	}
+
+	var conditions []string
+	var parameters []any
+	if len(after) > 0 {
+		if len(after) != len(keyNames) {
+			return "", nil, fmt.Errorf("expected %d cursor values, got %d", len(keyNames), len(after))
+		}
+
+		values := make([]any, len(after))
+		for i, value := range after {
+			var err error
+			if castedValue, isOk := value.(time.Time); isOk && i == 0 && s.columns[cursorIdx].Type == schema.Datetime2 {
+				// Keep the milliseconds, otherwise rows that were updated within the same second would be read again.
+				values[i] = castedValue.Format(DateTimeMilli)
+			} else if values[i], err = s.encodePrimaryKeyValue(keyNames[i], value); err != nil {
+				return "", nil, fmt.Errorf("failed to encode cursor value: %w", err)
+			}
+		}
+
+		// SQL Server does not support row value comparisons, so (a, b) > (1, 2) is expanded to a > 1 OR (a = 1 AND b > 2).
+		var comparisons []string
+		for i := range keyNames {
+			var parts []string
+			for j := range i {
+				parts = append(parts, fmt.Sprintf("%s = ?", quotedKeyNames[j]))
+				parameters = append(parameters, values[j])
+			}
+			parts = append(parts, fmt.Sprintf("%s > ?", quotedKeyNames[i]))
+			parameters = append(parameters, values[i])
+			comparisons = append(comparisons, fmt.Sprintf("(%s)", strings.Join(parts, " AND ")))
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s)", strings.Join(comparisons, " OR ")))
+	}
+
+	conditions = append(conditions, fmt.Sprintf("%s <= DATEADD(SECOND, -?, SYSDATETIME())", quotedKeyNames[0]))
+	parameters = append(parameters, int64(lag/time.Second))
+
+	return fmt.Sprintf(`SELECT TOP %d %s FROM %s.%s WHERE %s ORDER BY %s`,
+		// TOP
+		batchSize,
+		// SELECT
+		strings.Join(colNames, ","),
+		// FROM
+		mssqlDialect.QuoteIdentifier(s.schema), mssqlDialect.QuoteIdentifier(s.tableName),
+		// WHERE
+		strings.Join(conditions, " AND "),
+		// ORDER BY
+		strings.Join(quotedKeyNames, ","),
+	), parameters, nil
+}
@@ -164,3 +164,40 @@ func TestScanAdapter_EncodePrimaryKeyValue(t *testing.T) {
 		assert.Equal(t, "2021-01-01 12:34:56.7890123 +00:00", val)
 	}
 }
+
+func TestScanAdapter_BuildPollQuery(t *testing.T) {
+	adapter := scanAdapter{
+		schema:    "dbo",
+		tableName: "table",
+		columns: []schema.Column{
+			{Name: "a", Type: schema.Int64},
+			{Name: "b", Type: schema.Int64},
+			{Name: "updated_at", Type: schema.Datetime2},
+		},
+	}
+	{
+		// first poll
+		query, parameters, err := adapter.BuildPollQuery("updated_at", []string{"a", "b"}, nil, 5*time.Second, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT TOP 10 "a","b","updated_at" FROM "dbo"."table" WHERE "updated_at" <= DATEADD(SECOND, -?, SYSDATETIME()) ORDER BY "updated_at","a","b"`, query)
+		assert.Equal(t, []any{int64(5)}, parameters)
+	}
+	{
+		// after the last row that was read, the milliseconds of the cursor value are kept
+		updatedAt := time.Date(2021, 1, 1, 12, 34, 56, 789_000_000, time.UTC)
+		query, parameters, err := adapter.BuildPollQuery("updated_at", []string{"a", "b"}, []any{updatedAt, int64(1), int64(2)}, 5*time.Second, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT TOP 10 "a","b","updated_at" FROM "dbo"."table" WHERE (("updated_at" > ?) OR ("updated_at" = ? AND "a" > ?) OR ("updated_at" = ? AND "a" = ? AND "b" > ?)) AND "updated_at" <= DATEADD(SECOND, -?, SYSDATETIME()) ORDER BY "updated_at","a","b"`, query)
+		ts := "2021-01-01 12:34:56.789"
+		assert.Equal(t, []any{ts, ts, int64(1), ts, int64(1), int64(2), int64(5)}, parameters)
+	}
+}
+
+func TestScanAdapter_BuildFullScanQuery(t *testing.T) {
+	adapter := scanAdapter{
+		schema:    "dbo",
+		tableName: "table",
+		columns:   []schema.Column{{Name: "a", Type: schema.Int64}, {Name: "b", Type: schema.Bit}},
+	}
+	assert.Equal(t, `SELECT "a","b" FROM "dbo"."table"`, adapter.buildFullScanQuery())
+}
@@ -201,6 +201,35 @@ func FetchPrimaryKeys(db *sql.DB, schema, table string) ([]string, error) {
 	return primaryKeys, nil
 }
 
+// Filtered indexes, disabled indexes and included columns are skipped.
+const uniqueKeysQuery = `
+SELECT
+    i.name,
+    c.name,
+    CAST(CASE WHEN c.is_nullable = 0 THEN 1 ELSE 0 END AS BIT)
+FROM
+    sys.indexes i
+    JOIN sys.tables t ON t.object_id = i.object_id
+    JOIN sys.schemas s ON s.schema_id = t.schema_id
+    JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+    JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+WHERE
+    s.name = ?
+    AND t.name = ?
+    AND i.is_unique = 1
+    AND i.is_primary_key = 0
+    AND i.has_filter = 0
+    AND i.is_disabled = 0
+    AND ic.is_included_column = 0
+ORDER BY
+    i.name, ic.key_ordinal;
+`
+
+// FetchUniqueKey returns the columns of the first unique index (by name) whose columns are all NOT NULL.
+func FetchUniqueKey(db *sql.DB, schema, table string) ([]string, error) {
+	return rdbms.FetchUniqueKey(db, strings.TrimSpace(uniqueKeysQuery), mssql.VarChar(schema), mssql.VarChar(table))
+}
+
 func buildPkValuesQuery(keys []Column, schema string, tableName string, desc bool) string {
 	var escapedCols []string
 	for _, col := range keys {
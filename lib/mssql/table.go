@@ -3,6 +3,7 @@ package mssql
 import (
 	"database/sql"
 	"fmt"
+	"log/slog"
 
 	"github.com/artie-labs/reader/lib/mssql/parse"
 	"github.com/artie-labs/reader/lib/mssql/schema"
@@ -26,7 +27,9 @@ func (t *Table) PrimaryKeys() []string {
 	return t.primaryKeys
 }
 
-func LoadTable(db *sql.DB, _schema string, name string) (*Table, error) {
+// LoadTable - Loads the columns and the key of a table. The key is [primaryKeysOverride] if set, otherwise the primary key
+// and then the first unique index whose columns are all NOT NULL. Tables without any of these have no key.
+func LoadTable(db *sql.DB, _schema string, name string, primaryKeysOverride []string) (*Table, error) {
 	tbl := &Table{
 		Name:   name,
 		Schema: _schema,
@@ -37,12 +40,26 @@ func LoadTable(db *sql.DB, _schema string, name string) (*Table, error) {
 		return nil, fmt.Errorf("failed to describe table %s.%s: %w", tbl.Schema, tbl.Name, err)
 	}
 
+	tbl.columns = cols
+	if len(primaryKeysOverride) > 0 {
+		tbl.primaryKeys = primaryKeysOverride
+		return tbl, nil
+	}
+
 	primaryKeys, err := schema.FetchPrimaryKeys(db, tbl.Schema, tbl.Name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve primary keys: %w", err)
 	}
 
-	tbl.columns = cols
+	if len(primaryKeys) == 0 {
+		if primaryKeys, err = schema.FetchUniqueKey(db, tbl.Schema, tbl.Name); err != nil {
+			return nil, fmt.Errorf("failed to retrieve unique key: %w", err)
+		}
+
+		slog.Info("Table does not have a primary key", slog.String("schema", tbl.Schema), slog.String("table", tbl.Name),
+			slog.Any("uniqueKey", primaryKeys))
+	}
+
 	tbl.primaryKeys = primaryKeys
 	return tbl, nil
 }
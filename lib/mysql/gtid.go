@@ -13,6 +13,10 @@ func ShouldProcessRow(set mysql.GTIDSet, currentGTID string) (bool, error) {
 		return true, nil
 	}
 
+	if mariadbGTIDSet, ok := set.(*mysql.MariadbGTIDSet); ok {
+		return shouldProcessMariaDBRow(mariadbGTIDSet, currentGTID)
+	}
+
 	gtidSet, ok := set.(*mysql.MysqlGTIDSet)
 	if !ok {
 		return false, fmt.Errorf("unsupported GTID set type: %T", set)
@@ -50,3 +54,26 @@ func ShouldProcessRow(set mysql.GTIDSet, currentGTID string) (bool, error) {
 	// We should process if the current txID is above or equal to the highest txID we have seen
 	return txID >= highestTxID, nil
 }
+
+// shouldProcessMariaDBRow - MariaDB GTIDs are formatted as `domain-server-sequence`, the sequence number increases within
+// a replication domain regardless of which server the transaction originated from.
+func shouldProcessMariaDBRow(set *mysql.MariadbGTIDSet, currentGTID string) (bool, error) {
+	gtid, err := mysql.ParseMariadbGTID(currentGTID)
+	if err != nil {
+		return false, fmt.Errorf("invalid GTID format: %q: %w", currentGTID, err)
+	}
+
+	seenGTIDs, ok := set.Sets[gtid.DomainID]
+	if !ok {
+		// We have not seen this domain before, so we should process it.
+		return true, nil
+	}
+
+	var highestSequenceNumber uint64
+	for _, seenGTID := range seenGTIDs {
+		highestSequenceNumber = max(highestSequenceNumber, seenGTID.SequenceNumber)
+	}
+
+	// We should process if the current sequence number is above or equal to the highest sequence number we have seen
+	return gtid.SequenceNumber >= highestSequenceNumber, nil
+}
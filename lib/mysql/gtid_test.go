@@ -65,3 +65,33 @@ func TestShouldProcessRow(t *testing.T) {
 		assert.True(t, shouldProcess)
 	}
 }
+
+func TestShouldProcessRow_MariaDB(t *testing.T) {
+	set, err := mysql.ParseGTIDSet(mysql.MariaDBFlavor, "0-1-10,1-2-5")
+	assert.NoError(t, err)
+	{
+		// Sequence number is lower than the highest we have seen for the domain, even if the server changed
+		shouldProcess, err := ShouldProcessRow(set, "0-3-9")
+		assert.NoError(t, err)
+		assert.False(t, shouldProcess)
+	}
+	{
+		// Sequence number is equal to or higher than the highest we have seen
+		for _, gtid := range []string{"0-1-10", "0-3-11", "1-2-6"} {
+			shouldProcess, err := ShouldProcessRow(set, gtid)
+			assert.NoError(t, err)
+			assert.True(t, shouldProcess, gtid)
+		}
+	}
+	{
+		// We have not seen the domain before
+		shouldProcess, err := ShouldProcessRow(set, "2-1-1")
+		assert.NoError(t, err)
+		assert.True(t, shouldProcess)
+	}
+	{
+		// MySQL GTIDs are rejected
+		_, err := ShouldProcessRow(set, getGTID(uuid.New(), 1))
+		assert.ErrorContains(t, err, "invalid GTID format")
+	}
+}
@@ -1,7 +1,9 @@
 package scanner
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"slices"
 	"strconv"
@@ -15,7 +17,7 @@ import (
 	"github.com/artie-labs/reader/lib/rdbms/scan"
 )
 
-func NewScanner(db *sql.DB, table mysql.Table, columns []schema.Column, cfg scan.ScannerConfig) (*scan.Scanner, error) {
+func NewScanner(db rdbms.Querier, table mysql.Table, columns []schema.Column, cfg scan.ScannerConfig) (*scan.Scanner, error) {
 	primaryKeyBounds, err := table.FetchPrimaryKeysBounds(db)
 	if err != nil {
 		return nil, err
@@ -25,6 +27,22 @@ func NewScanner(db *sql.DB, table mysql.Table, columns []schema.Column, cfg scan
 	return scan.NewScanner(db, primaryKeyBounds, cfg, adapter)
 }
 
+// NewKeylessScanner returns a scanner that reads every row of a table that has no key, it returns
+// [rdbms.ErrNoPkValuesForEmptyTable] if the table is empty.
+func NewKeylessScanner(db rdbms.Querier, table mysql.Table, columns []schema.Column, cfg scan.ScannerConfig) (*scan.FullScanner, error) {
+	var value any
+	query := fmt.Sprintf("SELECT 1 FROM %s LIMIT 1", schema.QuoteIdentifier(table.Name))
+	if err := db.QueryRowContext(context.Background(), query).Scan(&value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, rdbms.ErrNoPkValuesForEmptyTable
+		}
+		return nil, fmt.Errorf("failed to check whether table is empty: %w", err)
+	}
+
+	adapter := scanAdapter{tableName: table.Name, columns: columns}
+	return scan.NewFullScanner(db, adapter.buildFullScanQuery(), nil, cfg, adapter)
+}
+
 type scanAdapter struct {
 	tableName string
 	columns   []schema.Column
@@ -163,6 +181,103 @@ func (s scanAdapter) BuildQuery(primaryKeys []primary_key.Key, isFirstBatch bool
 	), slices.Concat(startingValues, endingValues), nil
 }
 
+func (s scanAdapter) buildFullScanQuery() string {
+	colNames := make([]string, len(s.columns))
+	for idx, col := range s.columns {
+		colNames[idx] = schema.QuoteIdentifier(col.Name)
+	}
+
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(colNames, ","), schema.QuoteIdentifier(s.tableName))
+}
+
 func (s scanAdapter) ParseRow(values []any) error {
 	return schema.ConvertValues(values, s.columns)
 }
+
+// BuildSampleQuery only selects the key columns, the other columns are NULL so that rows can still be parsed with
+// [scanAdapter.ParseRow]. This lets MySQL read the keys from an index instead of the rows and RAND() is only evaluated
+// against the keys.
+func (s scanAdapter) BuildSampleQuery(primaryKeys []primary_key.Key, sampleSize uint) (string, []any, error) {
+	colNames := make([]string, len(s.columns))
+	for idx, col := range s.columns {
+		if slices.ContainsFunc(primaryKeys, func(key primary_key.Key) bool { return key.Name == col.Name }) {
+			colNames[idx] = schema.QuoteIdentifier(col.Name)
+		} else {
+			colNames[idx] = "NULL AS " + schema.QuoteIdentifier(col.Name)
+		}
+	}
+
+	var startingValues = make([]any, len(primaryKeys))
+	var endingValues = make([]any, len(startingValues))
+	for i, pk := range primaryKeys {
+		startingValues[i] = pk.StartingValue
+		endingValues[i] = pk.EndingValue
+	}
+
+	quotedKeyNames := make([]string, len(primaryKeys))
+	for i, key := range primaryKeys {
+		quotedKeyNames[i] = schema.QuoteIdentifier(key.Name)
+	}
+
+	// Rows are sampled using the estimated row count of the table.
+	return fmt.Sprintf(`SELECT %s FROM %s WHERE (%s) >= (%s) AND (%s) <= (%s) AND RAND() < ? / GREATEST(COALESCE((SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?), 0), 1) ORDER BY %s`,
+		// SELECT
+		strings.Join(colNames, ","),
+		// FROM
+		schema.QuoteIdentifier(s.tableName),
+		// WHERE (pk) >= (123)
+		strings.Join(quotedKeyNames, ","), strings.Join(rdbms.QueryPlaceholders("?", len(startingValues)), ","),
+		strings.Join(quotedKeyNames, ","), strings.Join(rdbms.QueryPlaceholders("?", len(endingValues)), ","),
+		// ORDER BY
+		strings.Join(quotedKeyNames, ","),
+	), slices.Concat(startingValues, endingValues, []any{sampleSize, s.tableName}), nil
+}
+
+// NewPollAdapter returns the adapter that is used to poll [table] for changes.
+func NewPollAdapter(table mysql.Table, columns []schema.Column) scan.PollAdapter {
+	return scanAdapter{tableName: table.Name, columns: columns}
+}
+
+func (s scanAdapter) BuildPollQuery(cursorColumn string, primaryKeys []string, after []any, lag time.Duration, batchSize uint) (string, []any, error) {
+	if !slices.ContainsFunc(s.columns, func(x schema.Column) bool { return x.Name == cursorColumn }) {
+		return "", nil, fmt.Errorf("cursor column %q is not one of the selected columns", cursorColumn)
+	}
+
+	colNames := make([]string, len(s.columns))
+	for idx, col := range s.columns {
+		colNames[idx] = schema.QuoteIdentifier(col.Name)
+	}
+
+	quotedKeyNames := []string{schema.QuoteIdentifier(cursorColumn)}
+	for _, key := range primaryKeys {
+		quotedKeyNames = append(quotedKeyNames, schema.QuoteIdentifier(key))
+	}
+
+	var conditions []string
+	var parameters []any
+	if len(after) > 0 {
+		if len(after) != len(quotedKeyNames) {
+			return "", nil, fmt.Errorf("expected %d cursor values, got %d", len(quotedKeyNames), len(after))
+		}
+
+		// WHERE (updated_at, pk) > (?, ?)
+		conditions = append(conditions, fmt.Sprintf("(%s) > (%s)", strings.Join(quotedKeyNames, ","), strings.Join(rdbms.QueryPlaceholders("?", len(after)), ",")))
+		parameters = append(parameters, after...)
+	}
+
+	conditions = append(conditions, fmt.Sprintf("%s <= NOW(6) - INTERVAL ? SECOND", quotedKeyNames[0]))
+	parameters = append(parameters, int64(lag/time.Second))
+
+	return fmt.Sprintf(`SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT %d`,
+		// SELECT
+		strings.Join(colNames, ","),
+		// FROM
+		schema.QuoteIdentifier(s.tableName),
+		// WHERE
+		strings.Join(conditions, " AND "),
+		// ORDER BY
+		strings.Join(quotedKeyNames, ","),
+		// LIMIT
+		batchSize,
+	), parameters, nil
+}
@@ -255,4 +255,44 @@ func TestScanAdapter_BuildQuery(t *testing.T) {
 		assert.Equal(t, "SELECT `foo`,`bar` FROM `table` WHERE (`foo`) >= (?) AND (`foo`) <= (?) ORDER BY `foo` LIMIT 12", query)
 		assert.Equal(t, []any{"a", "b"}, parameters)
 	}
+	{
+		// sample, only the key columns are selected
+		query, parameters, err := adapter.BuildSampleQuery(keys, 400)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT `foo`,NULL AS `bar` FROM `table` WHERE (`foo`) >= (?) AND (`foo`) <= (?) AND RAND() < ? / GREATEST(COALESCE((SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?), 0), 1) ORDER BY `foo`", query)
+		assert.Equal(t, []any{"a", "b", uint(400), "table"}, parameters)
+	}
+	{
+		// full scan
+		assert.Equal(t, "SELECT `foo`,`bar` FROM `table`", adapter.buildFullScanQuery())
+	}
+}
+
+func TestScanAdapter_BuildPollQuery(t *testing.T) {
+	adapter := scanAdapter{
+		tableName: "table",
+		columns: []schema.Column{
+			{Name: "id"},
+			{Name: "updated_at"},
+		},
+	}
+	{
+		// first poll
+		query, parameters, err := adapter.BuildPollQuery("updated_at", []string{"id"}, nil, 5*time.Second, 12)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT `id`,`updated_at` FROM `table` WHERE `updated_at` <= NOW(6) - INTERVAL ? SECOND ORDER BY `updated_at`,`id` LIMIT 12", query)
+		assert.Equal(t, []any{int64(5)}, parameters)
+	}
+	{
+		// after the last row that was read
+		query, parameters, err := adapter.BuildPollQuery("updated_at", []string{"id"}, []any{"2024-01-01", int64(3)}, 5*time.Second, 12)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT `id`,`updated_at` FROM `table` WHERE (`updated_at`,`id`) > (?,?) AND `updated_at` <= NOW(6) - INTERVAL ? SECOND ORDER BY `updated_at`,`id` LIMIT 12", query)
+		assert.Equal(t, []any{"2024-01-01", int64(3), int64(5)}, parameters)
+	}
+	{
+		// cursor column is not selected
+		_, _, err := adapter.BuildPollQuery("created_at", []string{"id"}, nil, 5*time.Second, 12)
+		assert.ErrorContains(t, err, `cursor column "created_at" is not one of the selected columns`)
+	}
 }
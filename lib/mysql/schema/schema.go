@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -94,8 +95,32 @@ func ListTables(db *sql.DB, dbName string) ([]string, error) {
 	return tables, nil
 }
 
-func GetCreateTableDDL(db *sql.DB, table string) (string, error) {
-	row := db.QueryRow("SHOW CREATE TABLE " + QuoteIdentifier(table))
+// ListDatabases returns the name of every database that the user can see.
+func ListDatabases(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SHOW DATABASES")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list databases: %w", err)
+	}
+
+	defer rows.Close()
+	var databases []string
+	for rows.Next() {
+		var database string
+		if err = rows.Scan(&database); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		databases = append(databases, database)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	return databases, nil
+}
+
+func GetCreateTableDDL(db *sql.DB, dbName string, table string) (string, error) {
+	row := db.QueryRow(fmt.Sprintf("SHOW CREATE TABLE %s.%s", QuoteIdentifier(dbName), QuoteIdentifier(table)))
 	var unused string
 	var createTableDDL string
 	if err := row.Scan(&unused, &createTableDDL); err != nil {
@@ -105,8 +130,8 @@ func GetCreateTableDDL(db *sql.DB, table string) (string, error) {
 	return createTableDDL, nil
 }
 
-func DescribeTable(db *sql.DB, table string) ([]Column, error) {
-	r, err := db.Query("DESCRIBE " + QuoteIdentifier(table))
+func DescribeTable(db rdbms.Querier, table string) ([]Column, error) {
+	r, err := db.QueryContext(context.Background(), "DESCRIBE "+QuoteIdentifier(table))
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe table %q: %w", table, err)
 	}
@@ -305,9 +330,9 @@ WHERE table_constraints.constraint_type='PRIMARY KEY'
   AND table_constraints.table_name=?
 `
 
-func FetchPrimaryKeys(db *sql.DB, table string) ([]string, error) {
+func FetchPrimaryKeys(db rdbms.Querier, table string) ([]string, error) {
 	query := strings.TrimSpace(primaryKeysQuery)
-	rows, err := db.Query(query, table)
+	rows, err := db.QueryContext(context.Background(), query, table)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run query: %s: %w", query, err)
 	}
@@ -324,6 +349,21 @@ func FetchPrimaryKeys(db *sql.DB, table string) ([]string, error) {
 	return primaryKeys, nil
 }
 
+const uniqueKeysQuery = `
+SELECT INDEX_NAME, COLUMN_NAME, NULLABLE = ''
+FROM information_schema.STATISTICS
+WHERE TABLE_SCHEMA = DATABASE()
+  AND TABLE_NAME = ?
+  AND NON_UNIQUE = 0
+  AND INDEX_NAME <> 'PRIMARY'
+ORDER BY INDEX_NAME, SEQ_IN_INDEX
+`
+
+// FetchUniqueKey returns the columns of the first unique index (by name) whose columns are all NOT NULL.
+func FetchUniqueKey(db rdbms.Querier, table string) ([]string, error) {
+	return rdbms.FetchUniqueKey(db, strings.TrimSpace(uniqueKeysQuery), table)
+}
+
 func buildPkValuesQuery(keys []Column, tableName string, descending bool) string {
 	quotedColumns := make([]string, len(keys))
 	for i, col := range keys {
@@ -349,7 +389,7 @@ func buildPkValuesQuery(keys []Column, tableName string, descending bool) string
 	)
 }
 
-func fetchPrimaryKeyValues(db *sql.DB, table string, primaryKeys []Column, descending bool) ([]any, error) {
+func fetchPrimaryKeyValues(db rdbms.Querier, table string, primaryKeys []Column, descending bool) ([]any, error) {
 	result := make([]any, len(primaryKeys))
 	resultPtrs := make([]any, len(primaryKeys))
 	for i := range result {
@@ -366,7 +406,7 @@ func fetchPrimaryKeyValues(db *sql.DB, table string, primaryKeys []Column, desce
 	// We're using a prepared statement to force the driver to return native types.
 	// This is necessary because otherwise the values returned will be []uint8.
 	// See https://github.com/go-sql-driver/mysql/issues/861
-	stmt, err := db.Prepare(query)
+	stmt, err := db.PrepareContext(context.Background(), query)
 	if err != nil {
 		return nil, err
 	}
@@ -386,7 +426,7 @@ func fetchPrimaryKeyValues(db *sql.DB, table string, primaryKeys []Column, desce
 	return result, nil
 }
 
-func FetchPrimaryKeysBounds(db *sql.DB, table string, primaryKeys []Column) ([]primary_key.Bounds, error) {
+func FetchPrimaryKeysBounds(db rdbms.Querier, table string, primaryKeys []Column) ([]primary_key.Bounds, error) {
 	minValues, err := fetchPrimaryKeyValues(db, table, primaryKeys, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve lower bounds for primary keys: %w", err)
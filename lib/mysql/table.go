@@ -1,10 +1,11 @@
 package mysql
 
 import (
-	"database/sql"
 	"fmt"
+	"log/slog"
 
 	"github.com/artie-labs/reader/lib/mysql/schema"
+	"github.com/artie-labs/reader/lib/rdbms"
 	"github.com/artie-labs/reader/lib/rdbms/column"
 	"github.com/artie-labs/reader/lib/rdbms/primary_key"
 )
@@ -16,7 +17,9 @@ type Table struct {
 	PrimaryKeys []string
 }
 
-func LoadTable(db *sql.DB, name string) (*Table, error) {
+// LoadTable - Loads the columns and the key of a table. The key is [primaryKeysOverride] if set, otherwise the primary key
+// and then the first unique index whose columns are all NOT NULL. Tables without any of these have no key.
+func LoadTable(db rdbms.Querier, name string, primaryKeysOverride []string) (*Table, error) {
 	tbl := &Table{
 		Name: name,
 	}
@@ -26,14 +29,27 @@ func LoadTable(db *sql.DB, name string) (*Table, error) {
 		return nil, fmt.Errorf("failed to describe table %q: %w", tbl.Name, err)
 	}
 
+	if len(primaryKeysOverride) > 0 {
+		tbl.PrimaryKeys = primaryKeysOverride
+		return tbl, nil
+	}
+
 	if tbl.PrimaryKeys, err = schema.FetchPrimaryKeys(db, tbl.Name); err != nil {
 		return nil, fmt.Errorf("failed to retrieve primary keys: %w", err)
 	}
 
+	if len(tbl.PrimaryKeys) == 0 {
+		if tbl.PrimaryKeys, err = schema.FetchUniqueKey(db, tbl.Name); err != nil {
+			return nil, fmt.Errorf("failed to retrieve unique key: %w", err)
+		}
+
+		slog.Info("Table does not have a primary key", slog.String("table", tbl.Name), slog.Any("uniqueKey", tbl.PrimaryKeys))
+	}
+
 	return tbl, nil
 }
 
-func (t *Table) FetchPrimaryKeysBounds(db *sql.DB) ([]primary_key.Key, error) {
+func (t *Table) FetchPrimaryKeysBounds(db rdbms.Querier) ([]primary_key.Key, error) {
 	keyColumns, err := column.ByNames(t.Columns, t.PrimaryKeys)
 	if err != nil {
 		return nil, fmt.Errorf("missing primary key columns: %w", err)
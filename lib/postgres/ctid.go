@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/artie-labs/reader/lib/iterator"
+	"github.com/artie-labs/reader/lib/postgres/schema"
+	"github.com/artie-labs/reader/lib/rdbms"
+	"github.com/artie-labs/reader/lib/rdbms/scan"
+)
+
+// defaultRowsPerPage - Estimate that is used when the table has not been analyzed yet.
+const defaultRowsPerPage = 100
+
+const tableSizeQuery = `
+SELECT (pg_relation_size(c.oid) / current_setting('block_size')::int)::bigint, c.reltuples::bigint, c.relpages::bigint
+FROM   pg_class c
+WHERE  c.oid = $1::regclass`
+
+// ctidScanner - Scans a table that has no key in ranges of pages using the physical location of rows (ctid), so every
+// query is bounded. Each range is read with a [scan.FullScanner].
+type ctidScanner struct {
+	// immutable
+	db            *sql.DB
+	cfg           scan.ScannerConfig
+	adapter       scanAdapter
+	pageCount     uint64
+	pagesPerQuery uint64
+
+	// mutable
+	nextPage uint64
+	current  *scan.FullScanner
+}
+
+// NewKeylessScanner returns a scanner that reads every row of a table that has no key, it returns
+// [rdbms.ErrNoPkValuesForEmptyTable] if the table is empty.
+func NewKeylessScanner(db *sql.DB, table Table, columns []schema.Column, cfg scan.ScannerConfig) (iterator.Iterator[[]map[string]any], error) {
+	var value any
+	query := fmt.Sprintf("SELECT 1 FROM %s LIMIT 1", pgx.Identifier{table.Schema, table.Name}.Sanitize())
+	if err := db.QueryRow(query).Scan(&value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, rdbms.ErrNoPkValuesForEmptyTable
+		}
+		return nil, fmt.Errorf("failed to check whether table is empty: %w", err)
+	}
+
+	var pageCount, tuples, pages int64
+	err := db.QueryRow(strings.TrimSpace(tableSizeQuery), pgx.Identifier{table.Schema, table.Name}.Sanitize()).Scan(&pageCount, &tuples, &pages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve table size: %w", err)
+	}
+
+	scanner := &ctidScanner{
+		db:            db,
+		cfg:           cfg,
+		adapter:       scanAdapter{schema: table.Schema, tableName: table.Name, columns: columns},
+		pageCount:     uint64(max(pageCount, 1)),
+		pagesPerQuery: pagesPerQuery(cfg.BatchSize, tuples, pages),
+	}
+
+	slog.Info("Scanning table by ctid", slog.Uint64("pageCount", scanner.pageCount), slog.Uint64("pagesPerQuery", scanner.pagesPerQuery))
+	return scanner, nil
+}
+
+// pagesPerQuery estimates how many pages hold [batchSize] rows using the statistics of the table.
+func pagesPerQuery(batchSize uint, tuples, pages int64) uint64 {
+	rowsPerPage := uint64(defaultRowsPerPage)
+	if tuples > 0 && pages > 0 {
+		rowsPerPage = uint64(max(tuples/pages, 1))
+	}
+
+	return max(uint64(batchSize)/rowsPerPage, 1)
+}
+
+func (c *ctidScanner) HasNext() bool {
+	return (c.current != nil && c.current.HasNext()) || c.nextPage < c.pageCount
+}
+
+func (c *ctidScanner) Next() ([]map[string]any, error) {
+	if !c.HasNext() {
+		return nil, fmt.Errorf("no more rows to scan")
+	}
+
+	if c.current == nil || !c.current.HasNext() {
+		startPage := c.nextPage
+		c.nextPage += c.pagesPerQuery
+
+		// The last range is unbounded so that pages which were added since the scan started are included.
+		var endPage uint64
+		if c.nextPage < c.pageCount {
+			endPage = c.nextPage
+		}
+
+		query, parameters := c.adapter.buildCtidQuery(startPage, endPage)
+		var err error
+		if c.current, err = scan.NewFullScanner(c.db, query, parameters, c.cfg, c.adapter); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.current.Next()
+}
+
+// buildCtidQuery builds a query that returns the rows that are stored in pages [startPage] up to [endPage], an
+// [endPage] of zero means that there is no upper bound.
+func (s scanAdapter) buildCtidQuery(startPage, endPage uint64) (string, []any) {
+	castedColumns := make([]string, len(s.columns))
+	for i, col := range s.columns {
+		castedColumns[i] = castColumn(col)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE ctid >= $1::tid`,
+		strings.Join(castedColumns, ","),
+		pgx.Identifier{s.schema, s.tableName}.Sanitize(),
+	)
+	parameters := []any{fmt.Sprintf("(%d,0)", startPage)}
+
+	if endPage > 0 {
+		query += ` AND ctid < $2::tid`
+		parameters = append(parameters, fmt.Sprintf("(%d,0)", endPage))
+	}
+
+	return query, parameters
+}
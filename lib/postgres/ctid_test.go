@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/lib/postgres/schema"
+)
+
+func TestPagesPerQuery(t *testing.T) {
+	{
+		// Table has not been analyzed
+		assert.Equal(t, uint64(50), pagesPerQuery(5_000, -1, 0))
+	}
+	{
+		// 20 rows per page
+		assert.Equal(t, uint64(250), pagesPerQuery(5_000, 2_000, 100))
+	}
+	{
+		// Rows that are larger than a page
+		assert.Equal(t, uint64(5_000), pagesPerQuery(5_000, 10, 100))
+	}
+	{
+		// Batch size is smaller than a page
+		assert.Equal(t, uint64(1), pagesPerQuery(10, 2_000, 10))
+	}
+}
+
+func TestScanAdapter_BuildCtidQuery(t *testing.T) {
+	adapter := scanAdapter{
+		schema:    "schema",
+		tableName: "table",
+		columns: []schema.Column{
+			{Name: "a", Type: schema.Int64},
+			{Name: "b", Type: schema.Array},
+		},
+	}
+	{
+		// Bounded
+		query, parameters := adapter.buildCtidQuery(10, 20)
+		assert.Equal(t, `SELECT "a",ARRAY_TO_JSON("b")::TEXT as "b" FROM "schema"."table" WHERE ctid >= $1::tid AND ctid < $2::tid`, query)
+		assert.Equal(t, []any{"(10,0)", "(20,0)"}, parameters)
+	}
+	{
+		// Unbounded
+		query, parameters := adapter.buildCtidQuery(10, 0)
+		assert.Equal(t, `SELECT "a",ARRAY_TO_JSON("b")::TEXT as "b" FROM "schema"."table" WHERE ctid >= $1::tid`, query)
+		assert.Equal(t, []any{"(10,0)"}, parameters)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 
@@ -205,3 +206,89 @@ func (s scanAdapter) ParseRow(values []any) error {
 	}
 	return nil
 }
+
+func (s scanAdapter) BuildSampleQuery(primaryKeys []primary_key.Key, sampleSize uint) (string, []any, error) {
+	castedColumns := make([]string, len(s.columns))
+	for i, col := range s.columns {
+		castedColumns[i] = castColumn(col)
+	}
+
+	startingValues := make([]any, len(primaryKeys))
+	endingValues := make([]any, len(primaryKeys))
+	for i, pk := range primaryKeys {
+		startingValues[i] = pk.StartingValue
+		endingValues[i] = pk.EndingValue
+	}
+
+	quotedKeyNames := make([]string, len(primaryKeys))
+	for i, key := range primaryKeys {
+		quotedKeyNames[i] = pgx.Identifier{key.Name}.Sanitize()
+	}
+
+	tableName := pgx.Identifier{s.schema, s.tableName}.Sanitize()
+	offset := len(startingValues) + len(endingValues)
+	// Rows are sampled using the estimated row count of the table.
+	return fmt.Sprintf(`SELECT %s FROM %s WHERE row(%s) >= row(%s) AND row(%s) <= row(%s) AND random() < $%d::float8 / GREATEST((SELECT reltuples FROM pg_class WHERE oid = $%d::regclass), 1) ORDER BY %s`,
+		// SELECT
+		strings.Join(castedColumns, ","),
+		// FROM
+		tableName,
+		// WHERE row(pk) >= row($1)
+		strings.Join(quotedKeyNames, ","), strings.Join(queryPlaceholders(0, len(startingValues)), ","),
+		// AND row(pk) <= row($2)
+		strings.Join(quotedKeyNames, ","), strings.Join(queryPlaceholders(len(startingValues), len(endingValues)), ","),
+		// AND random() < $3 / reltuples
+		offset+1, offset+2,
+		// ORDER BY
+		strings.Join(quotedKeyNames, ","),
+	), slices.Concat(startingValues, endingValues, []any{sampleSize, tableName}), nil
+}
+
+// NewPollAdapter returns the adapter that is used to poll [table] for changes.
+func NewPollAdapter(table Table, columns []schema.Column) scan.PollAdapter {
+	return scanAdapter{schema: table.Schema, tableName: table.Name, columns: columns}
+}
+
+func (s scanAdapter) BuildPollQuery(cursorColumn string, primaryKeys []string, after []any, lag time.Duration, batchSize uint) (string, []any, error) {
+	if !slices.ContainsFunc(s.columns, func(x schema.Column) bool { return x.Name == cursorColumn }) {
+		return "", nil, fmt.Errorf("cursor column %q is not one of the selected columns", cursorColumn)
+	}
+
+	castedColumns := make([]string, len(s.columns))
+	for i, col := range s.columns {
+		castedColumns[i] = castColumn(col)
+	}
+
+	quotedKeyNames := []string{pgx.Identifier{cursorColumn}.Sanitize()}
+	for _, key := range primaryKeys {
+		quotedKeyNames = append(quotedKeyNames, pgx.Identifier{key}.Sanitize())
+	}
+
+	var conditions []string
+	var parameters []any
+	if len(after) > 0 {
+		if len(after) != len(quotedKeyNames) {
+			return "", nil, fmt.Errorf("expected %d cursor values, got %d", len(quotedKeyNames), len(after))
+		}
+
+		// WHERE row(updated_at, pk) > row($1, $2)
+		conditions = append(conditions, fmt.Sprintf("row(%s) > row(%s)", strings.Join(quotedKeyNames, ","), strings.Join(queryPlaceholders(0, len(after)), ",")))
+		parameters = append(parameters, after...)
+	}
+
+	conditions = append(conditions, fmt.Sprintf("%s <= now() - make_interval(secs => $%d)", quotedKeyNames[0], len(parameters)+1))
+	parameters = append(parameters, lag.Seconds())
+
+	return fmt.Sprintf(`SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT %d`,
+		// SELECT
+		strings.Join(castedColumns, ","),
+		// FROM
+		pgx.Identifier{s.schema, s.tableName}.Sanitize(),
+		// WHERE
+		strings.Join(conditions, " AND "),
+		// ORDER BY
+		strings.Join(quotedKeyNames, ","),
+		// LIMIT
+		batchSize,
+	), parameters, nil
+}
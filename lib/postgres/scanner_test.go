@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -72,6 +73,42 @@ func TestScanAdapter_BuildQuery(t *testing.T) {
 		assert.Equal(t, `SELECT "a","b","c","e","f",ARRAY_TO_JSON("g")::TEXT as "g" FROM "schema"."table" WHERE row("a","b","c") > row($1,$2,$3) AND row("a","b","c") <= row($4,$5,$6) ORDER BY "a","b","c" LIMIT 2`, query)
 		assert.Equal(t, []any{int64(1), int64(2), "3", int64(4), int64(5), "6"}, parameters)
 	}
+	{
+		// sample
+		query, parameters, err := adapter.BuildSampleQuery(primaryKeys, 400)
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT "a","b","c","e","f",ARRAY_TO_JSON("g")::TEXT as "g" FROM "schema"."table" WHERE row("a","b","c") >= row($1,$2,$3) AND row("a","b","c") <= row($4,$5,$6) AND random() < $7::float8 / GREATEST((SELECT reltuples FROM pg_class WHERE oid = $8::regclass), 1) ORDER BY "a","b","c"`, query)
+		assert.Equal(t, []any{int64(1), int64(2), "3", int64(4), int64(5), "6", uint(400), `"schema"."table"`}, parameters)
+	}
+}
+
+func TestScanAdapter_BuildPollQuery(t *testing.T) {
+	cols := []schema.Column{
+		{Name: "id", Type: schema.Int64},
+		{Name: "updated_at", Type: schema.Timestamp},
+		{Name: "tags", Type: schema.Array},
+	}
+	adapter := scanAdapter{schema: "schema", tableName: "table", columns: cols}
+	{
+		// first poll
+		query, parameters, err := adapter.BuildPollQuery("updated_at", []string{"id"}, nil, 5*time.Second, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT "id","updated_at",ARRAY_TO_JSON("tags")::TEXT as "tags" FROM "schema"."table" WHERE "updated_at" <= now() - make_interval(secs => $1) ORDER BY "updated_at","id" LIMIT 10`, query)
+		assert.Equal(t, []any{float64(5)}, parameters)
+	}
+	{
+		// after the last row that was read
+		updatedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		query, parameters, err := adapter.BuildPollQuery("updated_at", []string{"id"}, []any{updatedAt, int64(3)}, 5*time.Second, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT "id","updated_at",ARRAY_TO_JSON("tags")::TEXT as "tags" FROM "schema"."table" WHERE row("updated_at","id") > row($1,$2) AND "updated_at" <= now() - make_interval(secs => $3) ORDER BY "updated_at","id" LIMIT 10`, query)
+		assert.Equal(t, []any{updatedAt, int64(3), float64(5)}, parameters)
+	}
+	{
+		// wrong number of cursor values
+		_, _, err := adapter.BuildPollQuery("updated_at", []string{"id"}, []any{int64(3)}, 5*time.Second, 10)
+		assert.ErrorContains(t, err, "expected 2 cursor values, got 1")
+	}
 }
 
 func TestScanAdapter_ParsePrimaryKeyValueForOverrides(t *testing.T) {
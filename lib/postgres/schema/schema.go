@@ -239,6 +239,26 @@ func FetchPrimaryKeys(db *sql.DB, schema, table string) ([]string, error) {
 	return primaryKeys, nil
 }
 
+// Partial indexes and the INCLUDE columns of covering indexes are skipped, expressions have no attribute.
+const uniqueKeysQuery = `
+SELECT c.relname::text, a.attname::text, a.attnotnull
+FROM   pg_index i
+JOIN   pg_class c ON c.oid = i.indexrelid
+CROSS JOIN LATERAL unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, position)
+LEFT JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+WHERE  i.indrelid = $1::regclass
+AND    i.indisunique
+AND    NOT i.indisprimary
+AND    i.indisvalid
+AND    i.indpred IS NULL
+AND    k.position <= i.indnkeyatts
+ORDER BY c.relname, k.position;`
+
+// FetchUniqueKey returns the columns of the first unique index (by name) whose columns are all NOT NULL.
+func FetchUniqueKey(db *sql.DB, schema, table string) ([]string, error) {
+	return rdbms.FetchUniqueKey(db, strings.TrimSpace(uniqueKeysQuery), pgx.Identifier{schema, table}.Sanitize())
+}
+
 type buildPkValuesQueryArgs struct {
 	Keys       []Column
 	Schema     string
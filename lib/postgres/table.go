@@ -3,6 +3,7 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
+	"log/slog"
 
 	"github.com/artie-labs/reader/lib/postgres/parse"
 	"github.com/artie-labs/reader/lib/postgres/schema"
@@ -18,6 +19,8 @@ type Table struct {
 	PrimaryKeys []string
 }
 
+// LoadTable - Loads the columns and the key of a table. The key is [primaryKeysOverride] if set, otherwise the primary key
+// and then the first unique index whose columns are all NOT NULL. Tables without any of these have no key.
 func LoadTable(db *sql.DB, _schema string, name string, primaryKeysOverride []string) (*Table, error) {
 	tbl := &Table{
 		Name:   name,
@@ -31,10 +34,20 @@ func LoadTable(db *sql.DB, _schema string, name string, primaryKeysOverride []st
 
 	if len(primaryKeysOverride) > 0 {
 		tbl.PrimaryKeys = primaryKeysOverride
-	} else {
-		if tbl.PrimaryKeys, err = schema.FetchPrimaryKeys(db, tbl.Schema, tbl.Name); err != nil {
-			return nil, fmt.Errorf("failed to retrieve primary keys: %w", err)
+		return tbl, nil
+	}
+
+	if tbl.PrimaryKeys, err = schema.FetchPrimaryKeys(db, tbl.Schema, tbl.Name); err != nil {
+		return nil, fmt.Errorf("failed to retrieve primary keys: %w", err)
+	}
+
+	if len(tbl.PrimaryKeys) == 0 {
+		if tbl.PrimaryKeys, err = schema.FetchUniqueKey(db, tbl.Schema, tbl.Name); err != nil {
+			return nil, fmt.Errorf("failed to retrieve unique key: %w", err)
 		}
+
+		slog.Info("Table does not have a primary key", slog.String("schema", tbl.Schema), slog.String("table", tbl.Name),
+			slog.Any("uniqueKey", tbl.PrimaryKeys))
 	}
 
 	return tbl, nil
@@ -0,0 +1,252 @@
+package poll
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/artie-labs/transfer/lib/cdc/util"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/checkpoint"
+	"github.com/artie-labs/reader/lib/debezium/transformer"
+	"github.com/artie-labs/reader/lib/iterator"
+	"github.com/artie-labs/reader/lib/kafkalib"
+	"github.com/artie-labs/reader/lib/rdbms/scan"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+	"github.com/artie-labs/reader/lib/storage/persistedmap"
+)
+
+// Adapter - A table that can be polled for changes, this is implemented by the adapters of each source.
+type Adapter interface {
+	transformer.Adapter
+	// NewPoller returns a poller that reads the rows that have changed according to [cursorColumn].
+	NewPoller(cursorColumn string, lag time.Duration) (*scan.Poller, error)
+	// NewPrimaryKeysIterator returns an iterator over the primary keys of every row, it is used to detect deletes.
+	NewPrimaryKeysIterator() (transformer.RowsIterator, error)
+}
+
+type Table struct {
+	Adapter      Adapter
+	CursorColumn string
+	// Source - Connector, database and schema that are included in every event.
+	Source util.Source
+}
+
+// offset - Position of a table, this is persisted once the rows up to it have been written.
+type offset struct {
+	// Cursor - Cursor value followed by the primary key values of the last row that has been written.
+	Cursor []checkpoint.Value `yaml:"cursor,omitempty"`
+	// CaughtUp - Whether the table has been read to the end at least once.
+	CaughtUp bool `yaml:"caughtUp,omitempty"`
+}
+
+type table struct {
+	Table
+	poller *scan.Poller
+	dbz    transformer.LightDebeziumTransformer
+	// caughtUp - Whether the table has been read to the end at least once. We cannot tell inserts apart from updates, so
+	// rows that are read before then are emitted as creates and rows that are read afterwards as updates.
+	caughtUp bool
+	// primaryKeys - Primary keys of every row as of the last time deletes were detected, this is nil until then.
+	primaryKeys         map[string]transformer.Row
+	lastDeleteDetection time.Time
+}
+
+// Iterator - Streams changes by polling tables, see [config.PollingSettings].
+type Iterator struct {
+	tables   []*table
+	settings config.PollingSettings
+	offsets  *persistedmap.PersistedMap[offset]
+}
+
+func NewIterator(tables []Table, settings config.PollingSettings, store offsetstore.OffsetStore) (*Iterator, error) {
+	offsets := persistedmap.NewPersistedMap[offset](store, settings.OffsetFile)
+	iter := &Iterator{settings: settings, offsets: offsets}
+	for _, tbl := range tables {
+		poller, err := tbl.Adapter.NewPoller(tbl.CursorColumn, settings.GetLag())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create poller for %q: %w", tbl.Adapter.TopicSuffix(), err)
+		}
+
+		polledTable := &table{
+			Table:  tbl,
+			poller: poller,
+			dbz:    transformer.NewLightDebeziumTransformer(tbl.Adapter.TableName(), tbl.Adapter.PartitionKeys(), tbl.Adapter.FieldConverters()),
+		}
+
+		if pos, isOk := offsets.Get(tbl.Adapter.TopicSuffix()); isOk {
+			cursor, err := checkpoint.DecodeKey(pos.Cursor)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode cursor for %q: %w", tbl.Adapter.TopicSuffix(), err)
+			}
+
+			slog.Info("Found offset", slog.String("table", tbl.Adapter.TopicSuffix()), slog.Any("cursor", cursor))
+			if len(cursor) > 0 {
+				poller.Resume(cursor)
+			}
+			polledTable.caughtUp = pos.CaughtUp
+		}
+
+		iter.tables = append(iter.tables, polledTable)
+	}
+
+	return iter, nil
+}
+
+func (i *Iterator) HasNext() bool {
+	return true
+}
+
+func (i *Iterator) Next() ([]kafkalib.Message, error) {
+	return i.NextContext(context.Background())
+}
+
+// NextContext polls every table, if they have all caught up it waits for the polling interval or until [ctx] is done.
+func (i *Iterator) NextContext(ctx context.Context) ([]kafkalib.Message, error) {
+	var result []kafkalib.Message
+	for _, tbl := range i.tables {
+		msgs, err := i.poll(tbl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to poll %q: %w", tbl.Adapter.TopicSuffix(), err)
+		}
+
+		result = append(result, msgs...)
+	}
+
+	if len(result) == 0 {
+		// Every table has caught up, let's wait before polling again.
+		iterator.Sleep(ctx, i.settings.GetInterval())
+	}
+
+	return result, nil
+}
+
+func (i *Iterator) CommitOffset() error {
+	for _, tbl := range i.tables {
+		cursor, err := checkpoint.EncodeKey(tbl.poller.Cursor())
+		if err != nil {
+			return fmt.Errorf("failed to encode cursor for %q: %w", tbl.Adapter.TopicSuffix(), err)
+		}
+
+		if err = i.offsets.Set(tbl.Adapter.TopicSuffix(), offset{Cursor: cursor, CaughtUp: tbl.caughtUp}); err != nil {
+			return fmt.Errorf("failed to persist offset for %q: %w", tbl.Adapter.TopicSuffix(), err)
+		}
+	}
+
+	return nil
+}
+
+func (i *Iterator) poll(tbl *table) ([]kafkalib.Message, error) {
+	rows, err := tbl.poller.Poll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) > 0 {
+		op := "u"
+		if !tbl.caughtUp {
+			op = "c"
+		}
+
+		msgs := make([]kafkalib.Message, len(rows))
+		for idx, row := range rows {
+			if msgs[idx], err = tbl.buildMessage(nil, row, op); err != nil {
+				return nil, err
+			}
+		}
+
+		return msgs, nil
+	}
+
+	tbl.caughtUp = true
+	if interval := i.settings.GetDeleteDetectionInterval(); interval > 0 && time.Since(tbl.lastDeleteDetection) >= interval {
+		return tbl.detectDeletes()
+	}
+
+	return nil, nil
+}
+
+// detectDeletes reads the primary keys of every row and emits deletes for the rows that existed the last time this was
+// called. The first call only records the primary keys, so rows that are deleted while the reader is not running are
+// not detected.
+func (t *table) detectDeletes() ([]kafkalib.Message, error) {
+	iter, err := t.Adapter.NewPrimaryKeysIterator()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create primary keys iterator: %w", err)
+	}
+
+	batches, err := iterator.Collect(iter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read primary keys: %w", err)
+	}
+
+	primaryKeys := make(map[string]transformer.Row)
+	for _, batch := range batches {
+		for _, row := range batch {
+			primaryKeys[primaryKeyString(row, t.Adapter.PartitionKeys())] = row
+		}
+	}
+
+	deleted := deletedRows(t.primaryKeys, primaryKeys)
+	t.primaryKeys = primaryKeys
+	t.lastDeleteDetection = time.Now()
+
+	msgs := make([]kafkalib.Message, len(deleted))
+	for idx, row := range deleted {
+		if msgs[idx], err = t.buildMessage(row, nil, "d"); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(msgs) > 0 {
+		slog.Info("Detected deletes", slog.String("table", t.Adapter.TopicSuffix()), slog.Int("count", len(msgs)))
+	}
+
+	return msgs, nil
+}
+
+func (t *table) buildMessage(before, after transformer.Row, op string) (kafkalib.Message, error) {
+	source := t.Source
+	source.Table = t.Adapter.TableName()
+	source.TsMs = time.Now().UnixMilli()
+
+	payload, err := t.dbz.BuildEventPayload(source, before, after, op)
+	if err != nil {
+		return kafkalib.Message{}, fmt.Errorf("failed to build event payload: %w", err)
+	}
+
+	partitionKey, err := t.dbz.BuildPartitionKey(before, after)
+	if err != nil {
+		return kafkalib.Message{}, fmt.Errorf("failed to build partition key: %w", err)
+	}
+
+	return kafkalib.NewMessage(t.Adapter.TopicSuffix(), partitionKey.Schema, partitionKey.Payload, &payload), nil
+}
+
+// deletedRows returns the rows of [previous] that are no longer in [current], nothing is returned if [previous] is nil.
+func deletedRows(previous, current map[string]transformer.Row) []transformer.Row {
+	var deleted []transformer.Row
+	for key, row := range previous {
+		if _, isOk := current[key]; !isOk {
+			deleted = append(deleted, row)
+		}
+	}
+
+	return deleted
+}
+
+func primaryKeyString(row transformer.Row, primaryKeys []string) string {
+	values := make([]string, len(primaryKeys))
+	for i, key := range primaryKeys {
+		if bytes, isOk := row[key].([]byte); isOk {
+			values[i] = string(bytes)
+		} else {
+			values[i] = fmt.Sprint(row[key])
+		}
+	}
+
+	return strings.Join(values, "\x00")
+}
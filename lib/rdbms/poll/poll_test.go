@@ -0,0 +1,121 @@
+package poll
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/artie-labs/transfer/lib/cdc/util"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/debezium/converters"
+	"github.com/artie-labs/reader/lib/debezium/transformer"
+	"github.com/artie-labs/reader/lib/iterator"
+	"github.com/artie-labs/reader/lib/rdbms/scan"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+)
+
+type mockAdapter struct {
+	primaryKeys []transformer.Row
+}
+
+func (mockAdapter) TableName() string {
+	return "foo"
+}
+
+func (mockAdapter) TopicSuffix() string {
+	return "db.foo"
+}
+
+func (mockAdapter) PartitionKeys() []string {
+	return []string{"id"}
+}
+
+func (mockAdapter) FieldConverters() []transformer.FieldConverter {
+	return []transformer.FieldConverter{
+		{Name: "id", ValueConverter: converters.Int64Passthrough{}},
+		{Name: "updated_at", ValueConverter: converters.StringPassthrough{}},
+	}
+}
+
+func (mockAdapter) NewIterator() (transformer.RowsIterator, error) {
+	panic("not implemented")
+}
+
+func (m mockAdapter) NewPoller(cursorColumn string, lag time.Duration) (*scan.Poller, error) {
+	return scan.NewPoller(nil, cursorColumn, m.PartitionKeys(), scan.PollerConfig{ErrorRetries: 1, Lag: lag}, nil)
+}
+
+func (m *mockAdapter) NewPrimaryKeysIterator() (transformer.RowsIterator, error) {
+	return iterator.Once(m.primaryKeys), nil
+}
+
+func TestIterator_CommitOffset(t *testing.T) {
+	settings := config.PollingSettings{Enabled: true, OffsetFile: filepath.Join(t.TempDir(), "offsets.yaml")}
+	tables := []Table{{Adapter: &mockAdapter{}, CursorColumn: "updated_at"}}
+
+	iter, err := NewIterator(tables, settings, offsetstore.NewFileStore())
+	assert.NoError(t, err)
+	assert.Nil(t, iter.tables[0].poller.Cursor())
+	assert.False(t, iter.tables[0].caughtUp)
+
+	iter.tables[0].poller.Resume([]any{"2024-01-01 00:00:00", int64(3)})
+	iter.tables[0].caughtUp = true
+	assert.NoError(t, iter.CommitOffset())
+
+	// The cursor is picked up after a restart.
+	iter, err = NewIterator(tables, settings, offsetstore.NewFileStore())
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"2024-01-01 00:00:00", int64(3)}, iter.tables[0].poller.Cursor())
+	assert.True(t, iter.tables[0].caughtUp)
+}
+
+func TestIterator_NextContext(t *testing.T) {
+	settings := config.PollingSettings{Enabled: true, IntervalSeconds: 3600, OffsetFile: filepath.Join(t.TempDir(), "offsets.yaml")}
+	iter, err := NewIterator(nil, settings, offsetstore.NewFileStore())
+	assert.NoError(t, err)
+
+	// Waiting for the next poll stops once the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	msgs, err := iter.NextContext(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestTable_DetectDeletes(t *testing.T) {
+	adapter := &mockAdapter{primaryKeys: []transformer.Row{{"id": int64(1)}, {"id": int64(2)}, {"id": int64(3)}}}
+	tbl := &table{
+		Table: Table{Adapter: adapter, Source: util.Source{Connector: "mysql", Database: "db"}},
+		dbz:   transformer.NewLightDebeziumTransformer(adapter.TableName(), adapter.PartitionKeys(), adapter.FieldConverters()),
+	}
+
+	// The first run only records the primary keys.
+	msgs, err := tbl.detectDeletes()
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+	assert.Len(t, tbl.primaryKeys, 3)
+
+	adapter.primaryKeys = []transformer.Row{{"id": int64(1)}, {"id": int64(3)}}
+	msgs, err = tbl.detectDeletes()
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, map[string]any{"id": int64(2)}, msgs[0].PartitionKeyValues())
+
+	event, isOk := msgs[0].Event().(*util.SchemaEventPayload)
+	assert.True(t, isOk)
+	assert.Equal(t, "d", event.Payload.Operation)
+	assert.Equal(t, map[string]any{"id": int64(2)}, event.Payload.Before)
+	assert.Nil(t, event.Payload.After)
+	assert.Equal(t, "db", event.Payload.Source.Database)
+	assert.Equal(t, "foo", event.Payload.Source.Table)
+}
+
+func TestPrimaryKeyString(t *testing.T) {
+	assert.Equal(t, "1\x00foo", primaryKeyString(map[string]any{"a": int32(1), "b": []byte("foo"), "c": "bar"}, []string{"a", "b"}))
+	assert.Equal(t, primaryKeyString(map[string]any{"a": "foo"}, []string{"a"}), primaryKeyString(map[string]any{"a": []byte("foo")}, []string{"a"}))
+}
@@ -0,0 +1,14 @@
+package rdbms
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Querier - Subset of [sql.DB] that is also implemented by [sql.Conn] and [sql.Tx], this allows reads to be pinned to a
+// single connection (e.g. one that holds a consistent snapshot).
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
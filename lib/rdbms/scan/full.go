@@ -0,0 +1,88 @@
+package scan
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/artie-labs/transfer/lib/retry"
+
+	"github.com/artie-labs/reader/lib/rdbms"
+)
+
+// FullScanner - Reads every row that is returned by a single query, this is used for tables that do not have a key to
+// paginate with. Rows are streamed from the database and returned in batches, so unlike [Scanner] it cannot be resumed.
+type FullScanner struct {
+	// immutable
+	db         rdbms.Querier
+	query      string
+	parameters []any
+	batchSize  uint
+	retryCfg   retry.RetryConfig
+	adapter    ScanAdapter
+
+	// mutable
+	rows    *sql.Rows
+	columns []string
+	done    bool
+}
+
+func NewFullScanner(db rdbms.Querier, query string, parameters []any, cfg ScannerConfig, adapter ScanAdapter) (*FullScanner, error) {
+	retryCfg, err := retry.NewJitterRetryConfig(jitterBaseMs, jitterMaxMs, cfg.ErrorRetries, retry.AlwaysRetry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build retry config: %w", err)
+	}
+
+	return &FullScanner{
+		db:         db,
+		query:      query,
+		parameters: parameters,
+		batchSize:  max(cfg.BatchSize, 1),
+		retryCfg:   retryCfg,
+		adapter:    adapter,
+	}, nil
+}
+
+func (f *FullScanner) HasNext() bool {
+	return !f.done
+}
+
+func (f *FullScanner) Next() ([]map[string]any, error) {
+	if !f.HasNext() {
+		return nil, fmt.Errorf("no more rows to scan")
+	}
+
+	rows, err := f.next()
+	if err != nil || uint(len(rows)) < f.batchSize {
+		f.close()
+	}
+	return rows, err
+}
+
+func (f *FullScanner) next() ([]map[string]any, error) {
+	if f.rows == nil {
+		// Only opening the query is retried, we cannot pick up part way through the results.
+		slog.Info("Full scan query", slog.String("query", f.query), slog.Any("parameters", f.parameters))
+		rows, err := retry.WithRetriesAndResult(f.retryCfg, func(_ int, _ error) (*sql.Rows, error) {
+			return f.db.QueryContext(context.Background(), f.query, f.parameters...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		f.rows = rows
+
+		if f.columns, err = rows.Columns(); err != nil {
+			return nil, fmt.Errorf("failed to get columns: %w", err)
+		}
+	}
+
+	return readRows(f.rows, f.columns, f.adapter, f.batchSize)
+}
+
+func (f *FullScanner) close() {
+	f.done = true
+	if f.rows != nil {
+		f.rows.Close()
+	}
+}
@@ -0,0 +1,114 @@
+package scan
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/artie-labs/reader/lib/iterator"
+)
+
+type scanResult struct {
+	rows []map[string]any
+	err  error
+}
+
+// parallelScanner - Scans chunks of a table concurrently, batches are returned in the order in which they are scanned.
+// The number of batches that are buffered is bounded by the number of chunks so that scanning is throttled by the
+// writer.
+type parallelScanner struct {
+	chunks  []iterator.Iterator[[]map[string]any]
+	results chan scanResult
+	stop    chan struct{}
+	// stopOnce - Guards closing [stop], which happens on the first error or when the scanner is closed.
+	stopOnce sync.Once
+
+	// mutable
+	started bool
+	pending *scanResult
+	done    bool
+}
+
+func newParallelScanner(chunks []iterator.Iterator[[]map[string]any]) *parallelScanner {
+	return &parallelScanner{
+		chunks:  chunks,
+		results: make(chan scanResult, len(chunks)),
+		stop:    make(chan struct{}),
+	}
+}
+
+func (p *parallelScanner) start() {
+	var wg sync.WaitGroup
+	for _, chunk := range p.chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.scanChunk(chunk)
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(p.results)
+	}()
+}
+
+func (p *parallelScanner) scanChunk(chunk iterator.Iterator[[]map[string]any]) {
+	for chunk.HasNext() {
+		rows, err := chunk.Next()
+		select {
+		case p.results <- scanResult{rows: rows, err: err}:
+		case <-p.stop:
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (p *parallelScanner) HasNext() bool {
+	if p.pending != nil {
+		return true
+	} else if p.done {
+		return false
+	}
+
+	if !p.started {
+		p.started = true
+		p.start()
+	}
+
+	result, isOk := <-p.results
+	if !isOk {
+		p.done = true
+		return false
+	}
+
+	p.pending = &result
+	return true
+}
+
+func (p *parallelScanner) Next() ([]map[string]any, error) {
+	if !p.HasNext() {
+		return nil, fmt.Errorf("no more rows to scan")
+	}
+
+	result := *p.pending
+	p.pending = nil
+	if result.err != nil {
+		// Stop scanning the other chunks.
+		p.Close()
+		return nil, result.err
+	}
+
+	return result.rows, nil
+}
+
+// Close stops scanning the chunks, goroutines that are running a query exit once it returns.
+func (p *parallelScanner) Close() error {
+	p.done = true
+	p.pending = nil
+	p.stopOnce.Do(func() { close(p.stop) })
+	return nil
+}
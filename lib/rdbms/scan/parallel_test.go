@@ -0,0 +1,100 @@
+package scan
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/lib/iterator"
+)
+
+// endlessChunk always has another batch.
+type endlessChunk struct{}
+
+func (endlessChunk) HasNext() bool {
+	return true
+}
+
+func (endlessChunk) Next() ([]map[string]any, error) {
+	return []map[string]any{{"a": 1}}, nil
+}
+
+type mockChunk struct {
+	batches [][]map[string]any
+	err     error
+}
+
+func (m *mockChunk) HasNext() bool {
+	return len(m.batches) > 0 || m.err != nil
+}
+
+func (m *mockChunk) Next() ([]map[string]any, error) {
+	if len(m.batches) == 0 {
+		err := m.err
+		m.err = nil
+		return nil, err
+	}
+
+	batch := m.batches[0]
+	m.batches = m.batches[1:]
+	return batch, nil
+}
+
+func TestParallelScanner(t *testing.T) {
+	{
+		// All the batches from every chunk are returned
+		scanner := newParallelScanner([]iterator.Iterator[[]map[string]any]{
+			&mockChunk{batches: [][]map[string]any{{{"a": 1}, {"a": 2}}, {{"a": 3}}}},
+			&mockChunk{},
+			&mockChunk{batches: [][]map[string]any{{{"a": 4}}}},
+		})
+
+		batches, err := iterator.Collect(scanner)
+		assert.NoError(t, err)
+
+		var values []int
+		for _, batch := range batches {
+			for _, row := range batch {
+				values = append(values, row["a"].(int))
+			}
+		}
+		slices.Sort(values)
+		assert.Equal(t, []int{1, 2, 3, 4}, values)
+		assert.False(t, scanner.HasNext())
+	}
+	{
+		// Errors are returned and stop the scan
+		scanner := newParallelScanner([]iterator.Iterator[[]map[string]any]{
+			&mockChunk{err: fmt.Errorf("failed to scan")},
+		})
+
+		_, err := iterator.Collect(scanner)
+		assert.ErrorContains(t, err, "failed to scan")
+		assert.False(t, scanner.HasNext())
+	}
+	{
+		// Closing the scanner before it has been read until the end stops the goroutines
+		scanner := newParallelScanner([]iterator.Iterator[[]map[string]any]{endlessChunk{}, endlessChunk{}})
+		assert.True(t, scanner.HasNext())
+		assert.NoError(t, scanner.Close())
+		assert.False(t, scanner.HasNext())
+		assert.NoError(t, scanner.Close())
+
+		// [results] is closed once every goroutine has returned.
+		assert.Eventually(t, func() bool {
+			for {
+				select {
+				case _, isOk := <-scanner.results:
+					if !isOk {
+						return true
+					}
+				default:
+					return false
+				}
+			}
+		}, time.Second, 10*time.Millisecond)
+	}
+}
@@ -0,0 +1,90 @@
+package scan
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/artie-labs/transfer/lib/retry"
+
+	"github.com/artie-labs/reader/lib/rdbms"
+)
+
+type PollerConfig struct {
+	BatchSize    uint
+	ErrorRetries int
+	// Lag - Rows whose cursor value is within this duration of the database's current time are left for the next poll.
+	Lag time.Duration
+}
+
+// Poller - Reads the rows of a table that have been inserted or updated since the last poll. Rows are read in order of a
+// cursor column (e.g. `updated_at`) tie-broken by the primary keys, and the last row that was read is where the next
+// poll picks up from.
+type Poller struct {
+	// immutable
+	db           rdbms.Querier
+	cursorColumn string
+	primaryKeys  []string
+	cfg          PollerConfig
+	retryCfg     retry.RetryConfig
+	adapter      PollAdapter
+
+	// mutable
+	cursor []any
+}
+
+func NewPoller(db rdbms.Querier, cursorColumn string, primaryKeys []string, cfg PollerConfig, adapter PollAdapter) (*Poller, error) {
+	if len(primaryKeys) == 0 {
+		return nil, fmt.Errorf("polling requires a primary key")
+	}
+
+	retryCfg, err := retry.NewJitterRetryConfig(jitterBaseMs, jitterMaxMs, cfg.ErrorRetries, retry.AlwaysRetry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build retry config: %w", err)
+	}
+
+	return &Poller{
+		db:           db,
+		cursorColumn: cursorColumn,
+		primaryKeys:  primaryKeys,
+		cfg:          cfg,
+		retryCfg:     retryCfg,
+		adapter:      adapter,
+	}, nil
+}
+
+// Resume makes the poller pick up after [cursor], which was previously returned by [Poller.Cursor].
+func (p *Poller) Resume(cursor []any) {
+	p.cursor = cursor
+}
+
+// Cursor returns the cursor value followed by the primary key values of the last row that has been read, or nil if
+// nothing has been read yet.
+func (p *Poller) Cursor() []any {
+	return p.cursor
+}
+
+// Poll returns the next batch of rows, no rows means that the poller has caught up.
+func (p *Poller) Poll() ([]map[string]any, error) {
+	query, parameters, err := p.adapter.BuildPollQuery(p.cursorColumn, p.primaryKeys, p.cursor, p.cfg.Lag, p.cfg.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build poll query: %w", err)
+	}
+
+	slog.Debug("Poll query", slog.String("query", query), slog.Any("parameters", parameters))
+	rows, err := queryRows(p.db, p.retryCfg, p.adapter, query, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) > 0 {
+		lastRow := rows[len(rows)-1]
+		cursor := []any{lastRow[p.cursorColumn]}
+		for _, key := range p.primaryKeys {
+			cursor = append(cursor, lastRow[key])
+		}
+		p.cursor = cursor
+	}
+
+	return rows, nil
+}
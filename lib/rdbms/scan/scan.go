@@ -1,10 +1,13 @@
 package scan
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/artie-labs/reader/lib/rdbms"
 	"github.com/artie-labs/reader/lib/rdbms/primary_key"
 	"github.com/artie-labs/transfer/lib/retry"
 )
@@ -19,20 +22,35 @@ type ScannerConfig struct {
 	OptionalStartingValues []string
 	OptionalEndingValues   []string
 	ErrorRetries           int
+	// Parallelism - Number of chunks that the table will be split into and scanned concurrently.
+	Parallelism uint
 }
 
 type ScanAdapter interface {
 	ParsePrimaryKeyValueForOverrides(columnName string, value string) (any, error)
 	BuildQuery(primaryKeys []primary_key.Key, isFirstBatch bool, batchSize uint) (string, []any, error)
+	// BuildSampleQuery builds a query that returns roughly [sampleSize] random rows within the primary key bounds,
+	// ordered by primary key.
+	BuildSampleQuery(primaryKeys []primary_key.Key, sampleSize uint) (string, []any, error)
 	ParseRow(row []any) error
 }
 
+// PollAdapter - A [ScanAdapter] that can also build the queries that are used to poll a table for changes.
+type PollAdapter interface {
+	ScanAdapter
+	// BuildPollQuery builds a query that returns up to [batchSize] rows ordered by [cursorColumn] and then [primaryKeys].
+	// If [after] is set (the cursor value followed by the primary key values) only rows that sort after it are returned.
+	// Rows whose cursor value is within [lag] of the database's current time are left for the next poll.
+	BuildPollQuery(cursorColumn string, primaryKeys []string, after []any, lag time.Duration, batchSize uint) (string, []any, error)
+}
+
 type Scanner struct {
 	// immutable
-	db        *sql.DB
-	batchSize uint
-	retryCfg  retry.RetryConfig
-	adapter   ScanAdapter
+	db          rdbms.Querier
+	batchSize   uint
+	parallelism uint
+	retryCfg    retry.RetryConfig
+	adapter     ScanAdapter
 
 	// mutable
 	primaryKeys  *primary_key.Keys
@@ -40,7 +58,7 @@ type Scanner struct {
 	done         bool
 }
 
-func NewScanner(db *sql.DB, _primaryKeys []primary_key.Key, cfg ScannerConfig, adapter ScanAdapter) (*Scanner, error) {
+func NewScanner(db rdbms.Querier, _primaryKeys []primary_key.Key, cfg ScannerConfig, adapter ScanAdapter) (*Scanner, error) {
 	optionalStartingValues, err := parsePkValueOverrides(cfg.OptionalStartingValues, _primaryKeys, adapter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse optional starting values: %w", err)
@@ -64,6 +82,7 @@ func NewScanner(db *sql.DB, _primaryKeys []primary_key.Key, cfg ScannerConfig, a
 	return &Scanner{
 		db:           db,
 		batchSize:    cfg.BatchSize,
+		parallelism:  cfg.Parallelism,
 		retryCfg:     retryCfg,
 		adapter:      adapter,
 		primaryKeys:  primaryKeys,
@@ -72,6 +91,29 @@ func NewScanner(db *sql.DB, _primaryKeys []primary_key.Key, cfg ScannerConfig, a
 	}, nil
 }
 
+// Resume makes the scanner pick up after [lastKey], which is the key of the last row that was previously scanned.
+func (s *Scanner) Resume(lastKey []any) error {
+	if err := s.primaryKeys.LoadValues(lastKey, nil); err != nil {
+		return fmt.Errorf("failed to load last key: %w", err)
+	}
+
+	s.isFirstBatch = false
+	return nil
+}
+
+// LastKey returns the key of the last row that has been scanned, or nil if nothing has been scanned yet.
+func (s *Scanner) LastKey() []any {
+	if s.isFirstBatch {
+		return nil
+	}
+
+	lastKey := make([]any, len(s.primaryKeys.Keys()))
+	for i, key := range s.primaryKeys.Keys() {
+		lastKey[i] = key.StartingValue
+	}
+	return lastKey
+}
+
 func (s *Scanner) HasNext() bool {
 	return !s.done
 }
@@ -122,19 +164,28 @@ func (s *Scanner) scan() ([]map[string]any, error) {
 	}
 
 	slog.Info("Scan query", slog.String("query", query), slog.Any("parameters", parameters))
+	return queryRows(s.db, s.retryCfg, s.adapter, query, parameters)
+}
 
-	rows, err := retry.WithRetriesAndResult(s.retryCfg, func(_ int, _ error) (*sql.Rows, error) {
-		return s.db.Query(query, parameters...)
+func queryRows(db rdbms.Querier, retryCfg retry.RetryConfig, adapter ScanAdapter, query string, parameters []any) ([]map[string]any, error) {
+	rows, err := retry.WithRetriesAndResult(retryCfg, func(_ int, _ error) (*sql.Rows, error) {
+		return db.QueryContext(context.Background(), query, parameters...)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("scan failed: %w", err)
 	}
+	defer rows.Close()
 
 	columns, err := rows.Columns()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get columns: %w", err)
 	}
 
+	return readRows(rows, columns, adapter, 0)
+}
+
+// readRows reads up to [limit] rows from [rows], or all of them if [limit] is zero.
+func readRows(rows *sql.Rows, columns []string, adapter ScanAdapter, limit uint) ([]map[string]any, error) {
 	values := make([]any, len(columns))
 	valuePtrs := make([]any, len(values))
 	for i := range values {
@@ -142,12 +193,12 @@ func (s *Scanner) scan() ([]map[string]any, error) {
 	}
 
 	var rowsData []map[string]any
-	for rows.Next() {
+	for (limit == 0 || uint(len(rowsData)) < limit) && rows.Next() {
 		if err := rows.Scan(valuePtrs...); err != nil {
 			return nil, err
 		}
 
-		if err := s.adapter.ParseRow(values); err != nil {
+		if err := adapter.ParseRow(values); err != nil {
 			return nil, err
 		}
 
@@ -157,7 +208,7 @@ func (s *Scanner) scan() ([]map[string]any, error) {
 		}
 		rowsData = append(rowsData, row)
 	}
-	return rowsData, nil
+	return rowsData, rows.Err()
 }
 
 // parsePkValueOverrides converts primary key starting/ending string values coming from db config files into values
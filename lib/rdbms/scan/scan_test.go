@@ -24,6 +24,10 @@ func (mockAdapter) BuildQuery(primaryKeys []primary_key.Key, isFirstBatch bool,
 	panic("not implemented")
 }
 
+func (mockAdapter) BuildSampleQuery(primaryKeys []primary_key.Key, sampleSize uint) (string, []any, error) {
+	panic("not implemented")
+}
+
 func (mockAdapter) ParseRow(row []any) error {
 	panic("not implemented")
 }
@@ -59,3 +63,25 @@ func TestParsePkValueOverrides(t *testing.T) {
 		assert.Equal(t, []any{"parsed-foo-123", "parsed-bar-456"}, result)
 	}
 }
+
+func TestScanner_Resume(t *testing.T) {
+	scanner := &Scanner{
+		primaryKeys:  primary_key.NewKeys([]primary_key.Key{{Name: "a", StartingValue: 1, EndingValue: 10}, {Name: "b", StartingValue: "x", EndingValue: "z"}}),
+		isFirstBatch: true,
+	}
+	{
+		// Nothing has been scanned yet
+		assert.Nil(t, scanner.LastKey())
+	}
+	{
+		// Wrong number of values
+		assert.ErrorContains(t, scanner.Resume([]any{5}), "failed to load last key: keys (2), and passed in values (1) length does not match")
+	}
+	{
+		// Happy path
+		assert.NoError(t, scanner.Resume([]any{5, "y"}))
+		assert.False(t, scanner.isFirstBatch)
+		assert.Equal(t, []any{5, "y"}, scanner.LastKey())
+		assert.Equal(t, []primary_key.Key{{Name: "a", StartingValue: 5, EndingValue: 10}, {Name: "b", StartingValue: "y", EndingValue: "z"}}, scanner.primaryKeys.Keys())
+	}
+}
@@ -0,0 +1,169 @@
+package scan
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+
+	"github.com/artie-labs/reader/lib/iterator"
+	"github.com/artie-labs/reader/lib/rdbms/primary_key"
+)
+
+// samplesPerChunk - Number of rows that are sampled for each chunk when the primary keys cannot be split evenly.
+const samplesPerChunk = 100
+
+// Parallelize splits the scanner into chunks that are scanned concurrently if parallelism has been configured,
+// otherwise the scanner is returned as is.
+func (s *Scanner) Parallelize() (iterator.Iterator[[]map[string]any], error) {
+	if s.parallelism <= 1 {
+		return s, nil
+	}
+
+	splitPoints, err := s.splitPoints()
+	if err != nil {
+		return nil, fmt.Errorf("failed to split primary keys: %w", err)
+	}
+
+	if len(splitPoints) == 0 {
+		slog.Info("Primary keys cannot be split, the table will be scanned sequentially")
+		return s, nil
+	}
+
+	slog.Info("Scanning table in parallel", slog.Int("chunks", len(splitPoints)+1), slog.Any("splitPoints", splitPoints))
+	return newParallelScanner(s.chunks(splitPoints)), nil
+}
+
+// chunks splits the scanner at [splitPoints], each chunk ends at a split point (inclusive) and the next one starts
+// right after it.
+func (s *Scanner) chunks(splitPoints [][]any) []iterator.Iterator[[]map[string]any] {
+	chunks := make([]iterator.Iterator[[]map[string]any], 0, len(splitPoints)+1)
+	var start []any
+	for _, end := range append(splitPoints, nil) {
+		chunks = append(chunks, s.chunk(start, end))
+		start = end
+	}
+	return chunks
+}
+
+// chunk returns a copy of the scanner for the rows after [start] up to and including [end], a nil value means that the
+// bound is not changed.
+func (s *Scanner) chunk(start []any, end []any) *Scanner {
+	keys := slices.Clone(s.primaryKeys.Keys())
+	for i := range keys {
+		if start != nil {
+			keys[i].StartingValue = start[i]
+		}
+		if end != nil {
+			keys[i].EndingValue = end[i]
+		}
+	}
+
+	return &Scanner{
+		db:           s.db,
+		batchSize:    s.batchSize,
+		retryCfg:     s.retryCfg,
+		adapter:      s.adapter,
+		primaryKeys:  primary_key.NewKeys(keys),
+		isFirstBatch: start == nil && s.isFirstBatch,
+	}
+}
+
+// splitPoints returns the keys that the table should be split at. Numeric primary keys are split evenly, otherwise the
+// split points are picked from a sample of the table.
+func (s *Scanner) splitPoints() ([][]any, error) {
+	keys := s.primaryKeys.Keys()
+	if len(keys) == 1 {
+		if splitPoints, isOk := splitNumericRange(keys[0].StartingValue, keys[0].EndingValue, s.parallelism); isOk {
+			return splitPoints, nil
+		}
+	}
+
+	query, parameters, err := s.adapter.BuildSampleQuery(keys, s.parallelism*samplesPerChunk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sample query: %w", err)
+	}
+
+	slog.Info("Sample query", slog.String("query", query), slog.Any("parameters", parameters))
+	rows, err := queryRows(s.db, s.retryCfg, s.adapter, query, parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample primary keys: %w", err)
+	}
+
+	samples := make([][]any, len(rows))
+	for i, row := range rows {
+		samples[i] = make([]any, len(keys))
+		for j, key := range keys {
+			samples[i][j] = row[key.Name]
+		}
+	}
+
+	return pickSplitPoints(samples, s.parallelism), nil
+}
+
+// pickSplitPoints picks the keys that split [samples], which are ordered by primary key, into [count] chunks.
+func pickSplitPoints(samples [][]any, count uint) [][]any {
+	var splitPoints [][]any
+	for i := uint(1); i < count; i++ {
+		idx := int(i * uint(len(samples)) / count)
+		if idx == 0 || (len(splitPoints) > 0 && reflect.DeepEqual(splitPoints[len(splitPoints)-1], samples[idx-1])) {
+			continue
+		}
+		splitPoints = append(splitPoints, samples[idx-1])
+	}
+	return splitPoints
+}
+
+// splitNumericRange splits [start, end] into [count] ranges of the same size, this returns false if the bounds are
+// not numbers of the same type.
+func splitNumericRange(start any, end any, count uint) ([][]any, bool) {
+	startValue, endValue := reflect.ValueOf(start), reflect.ValueOf(end)
+	if !startValue.IsValid() || !endValue.IsValid() || startValue.Type() != endValue.Type() {
+		return nil, false
+	}
+
+	var splitPoints [][]any
+	appendPoint := func(value reflect.Value) {
+		point := value.Convert(startValue.Type()).Interface()
+		if len(splitPoints) == 0 || splitPoints[len(splitPoints)-1][0] != point {
+			splitPoints = append(splitPoints, []any{point})
+		}
+	}
+
+	switch {
+	case startValue.CanInt():
+		if endValue.Int() <= startValue.Int() {
+			return nil, true
+		}
+
+		// The difference between two int64s always fits in a uint64.
+		span := uint64(endValue.Int() - startValue.Int())
+		for i := uint64(1); i < uint64(count); i++ {
+			offset := span/uint64(count)*i + span%uint64(count)*i/uint64(count)
+			appendPoint(reflect.ValueOf(startValue.Int() + int64(offset)))
+		}
+	case startValue.CanUint():
+		if endValue.Uint() <= startValue.Uint() {
+			return nil, true
+		}
+
+		span := endValue.Uint() - startValue.Uint()
+		for i := uint64(1); i < uint64(count); i++ {
+			offset := span/uint64(count)*i + span%uint64(count)*i/uint64(count)
+			appendPoint(reflect.ValueOf(startValue.Uint() + offset))
+		}
+	case startValue.CanFloat():
+		if endValue.Float() <= startValue.Float() {
+			return nil, true
+		}
+
+		span := endValue.Float() - startValue.Float()
+		for i := uint(1); i < count; i++ {
+			appendPoint(reflect.ValueOf(startValue.Float() + span*float64(i)/float64(count)))
+		}
+	default:
+		return nil, false
+	}
+
+	return splitPoints, true
+}
@@ -0,0 +1,98 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/lib/rdbms/primary_key"
+)
+
+func TestSplitNumericRange(t *testing.T) {
+	{
+		// Not numbers
+		_, isOk := splitNumericRange("a", "z", 4)
+		assert.False(t, isOk)
+	}
+	{
+		// Different types
+		_, isOk := splitNumericRange(int32(1), int64(100), 4)
+		assert.False(t, isOk)
+	}
+	{
+		// Empty range
+		splitPoints, isOk := splitNumericRange(int64(5), int64(5), 4)
+		assert.True(t, isOk)
+		assert.Empty(t, splitPoints)
+	}
+	{
+		// Signed integers, the type is preserved
+		splitPoints, isOk := splitNumericRange(int32(-100), int32(100), 4)
+		assert.True(t, isOk)
+		assert.Equal(t, [][]any{{int32(-50)}, {int32(0)}, {int32(50)}}, splitPoints)
+	}
+	{
+		// Full int64 range
+		splitPoints, isOk := splitNumericRange(int64(-9223372036854775808), int64(9223372036854775807), 2)
+		assert.True(t, isOk)
+		assert.Equal(t, [][]any{{int64(-1)}}, splitPoints)
+	}
+	{
+		// Range that is smaller than the number of chunks
+		splitPoints, isOk := splitNumericRange(uint64(1), uint64(3), 8)
+		assert.True(t, isOk)
+		assert.Equal(t, [][]any{{uint64(1)}, {uint64(2)}}, splitPoints)
+	}
+	{
+		// Floats
+		splitPoints, isOk := splitNumericRange(0.0, 1.0, 4)
+		assert.True(t, isOk)
+		assert.Equal(t, [][]any{{0.25}, {0.5}, {0.75}}, splitPoints)
+	}
+}
+
+func TestPickSplitPoints(t *testing.T) {
+	samples := [][]any{{"a", 1}, {"a", 2}, {"b", 1}, {"c", 1}, {"c", 2}, {"d", 1}, {"e", 1}, {"f", 1}}
+	{
+		// No samples
+		assert.Empty(t, pickSplitPoints(nil, 4))
+	}
+	{
+		// More samples than chunks
+		assert.Equal(t, [][]any{{"a", 2}, {"c", 1}, {"d", 1}}, pickSplitPoints(samples, 4))
+	}
+	{
+		// Fewer samples than chunks
+		assert.Equal(t, [][]any{{"a", 1}, {"a", 2}}, pickSplitPoints(samples[:3], 8))
+	}
+}
+
+func TestScanner_Chunks(t *testing.T) {
+	scanner := &Scanner{
+		batchSize:    10,
+		primaryKeys:  primary_key.NewKeys([]primary_key.Key{{Name: "a", StartingValue: int64(1), EndingValue: int64(100)}}),
+		isFirstBatch: true,
+	}
+
+	chunks := scanner.chunks([][]any{{int64(25)}, {int64(50)}})
+	assert.Len(t, chunks, 3)
+	{
+		// First chunk includes the lower bound
+		chunk := chunks[0].(*Scanner)
+		assert.True(t, chunk.isFirstBatch)
+		assert.Equal(t, uint(10), chunk.batchSize)
+		assert.Equal(t, []primary_key.Key{{Name: "a", StartingValue: int64(1), EndingValue: int64(25)}}, chunk.primaryKeys.Keys())
+	}
+	{
+		// Following chunks start after the previous split point
+		chunk := chunks[1].(*Scanner)
+		assert.False(t, chunk.isFirstBatch)
+		assert.Equal(t, []primary_key.Key{{Name: "a", StartingValue: int64(25), EndingValue: int64(50)}}, chunk.primaryKeys.Keys())
+
+		chunk = chunks[2].(*Scanner)
+		assert.False(t, chunk.isFirstBatch)
+		assert.Equal(t, []primary_key.Key{{Name: "a", StartingValue: int64(50), EndingValue: int64(100)}}, chunk.primaryKeys.Keys())
+	}
+	// The original scanner is not modified
+	assert.Equal(t, []primary_key.Key{{Name: "a", StartingValue: int64(1), EndingValue: int64(100)}}, scanner.primaryKeys.Keys())
+}
@@ -0,0 +1,63 @@
+package rdbms
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type indexColumn struct {
+	Index  string
+	Column string
+	// NotNull - Whether the column is declared NOT NULL, this is false for expressions.
+	NotNull bool
+}
+
+// FetchUniqueKey runs [query], which returns the index name, column name (NULL for expressions) and whether the column
+// is NOT NULL for every column of the table's unique indexes ordered by index name and then position within the index.
+// It returns the columns of the first index whose columns are all NOT NULL, these identify a row as well as a primary
+// key would. Nothing is returned if there is no such index.
+func FetchUniqueKey(db Querier, query string, args ...any) ([]string, error) {
+	rows, err := db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run query: %s: %w", query, err)
+	}
+	defer rows.Close()
+
+	var columns []indexColumn
+	for rows.Next() {
+		var index string
+		var column sql.NullString
+		var notNull sql.NullBool
+		if err = rows.Scan(&index, &column, &notNull); err != nil {
+			return nil, err
+		}
+		columns = append(columns, indexColumn{Index: index, Column: column.String, NotNull: column.Valid && notNull.Bool})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return firstNotNullIndex(columns), nil
+}
+
+func firstNotNullIndex(columns []indexColumn) []string {
+	var keys []string
+	notNull := true
+	for i, column := range columns {
+		keys = append(keys, column.Column)
+		notNull = notNull && column.NotNull
+
+		if i == len(columns)-1 || columns[i+1].Index != column.Index {
+			if notNull {
+				return keys
+			}
+
+			keys = nil
+			notNull = true
+		}
+	}
+
+	return nil
+}
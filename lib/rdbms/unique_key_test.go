@@ -0,0 +1,35 @@
+package rdbms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstNotNullIndex(t *testing.T) {
+	{
+		// No unique indexes
+		assert.Nil(t, firstNotNullIndex(nil))
+	}
+	{
+		// Indexes with a nullable column or an expression are skipped
+		columns := []indexColumn{
+			{Index: "a_idx", Column: "a", NotNull: true},
+			{Index: "a_idx", Column: "b", NotNull: false},
+			{Index: "b_idx", Column: "", NotNull: false},
+			{Index: "c_idx", Column: "c", NotNull: true},
+			{Index: "c_idx", Column: "d", NotNull: true},
+			{Index: "d_idx", Column: "e", NotNull: true},
+		}
+		assert.Equal(t, []string{"c", "d"}, firstNotNullIndex(columns))
+	}
+	{
+		// Every index has a nullable column
+		columns := []indexColumn{
+			{Index: "a_idx", Column: "a", NotNull: false},
+			{Index: "b_idx", Column: "b", NotNull: true},
+			{Index: "b_idx", Column: "c", NotNull: false},
+		}
+		assert.Nil(t, firstNotNullIndex(columns))
+	}
+}
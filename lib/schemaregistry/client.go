@@ -0,0 +1,182 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/artie-labs/reader/config"
+)
+
+const contentType = "application/vnd.schemaregistry.v1+json"
+
+// Error codes that are returned by the schema registry when a subject or version does not exist.
+const (
+	errorCodeSubjectNotFound = 40401
+	errorCodeVersionNotFound = 40402
+)
+
+type Error struct {
+	StatusCode int
+	ErrorCode  int    `json:"error_code"`
+	Message    string `json:"message"`
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("schema registry returned status %d (error code %d): %s", e.StatusCode, e.ErrorCode, e.Message)
+}
+
+func isNotFound(err error) bool {
+	var registryErr Error
+	if !errors.As(err, &registryErr) {
+		return false
+	}
+
+	return registryErr.ErrorCode == errorCodeSubjectNotFound || registryErr.ErrorCode == errorCodeVersionNotFound
+}
+
+// Client - Client for a Confluent-compatible schema registry. Schema IDs are cached so that each schema is only
+// registered once per subject.
+type Client struct {
+	cfg        config.SchemaRegistry
+	httpClient *http.Client
+
+	mu sync.Mutex
+	// ids - Schema IDs keyed by subject and then by schema.
+	ids map[string]map[string]int
+}
+
+func NewClient(cfg config.SchemaRegistry) *Client {
+	return &Client{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		ids:        make(map[string]map[string]int),
+	}
+}
+
+// GetOrRegister returns the ID of [schema], registering it under [subject] if needed. If a compatibility level has been
+// configured, it will be set on the subject and the schema will be checked for compatibility before it is registered.
+func (c *Client) GetOrRegister(ctx context.Context, subject string, schema string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if id, isOk := c.ids[subject][schema]; isOk {
+		return id, nil
+	}
+
+	if _, isOk := c.ids[subject]; !isOk && c.cfg.Compatibility != "" {
+		if err := c.setCompatibility(ctx, subject, c.cfg.Compatibility); err != nil {
+			return 0, fmt.Errorf("failed to set compatibility for subject %q: %w", subject, err)
+		}
+	}
+
+	isCompatible, err := c.isCompatible(ctx, subject, schema)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check compatibility for subject %q: %w", subject, err)
+	}
+
+	if !isCompatible {
+		return 0, fmt.Errorf("schema is not compatible with the latest version of subject %q", subject)
+	}
+
+	id, err := c.register(ctx, subject, schema)
+	if err != nil {
+		return 0, fmt.Errorf("failed to register schema for subject %q: %w", subject, err)
+	}
+
+	if _, isOk := c.ids[subject]; !isOk {
+		c.ids[subject] = make(map[string]int)
+	}
+
+	c.ids[subject][schema] = id
+	return id, nil
+}
+
+func (c *Client) setCompatibility(ctx context.Context, subject string, compatibility string) error {
+	return c.do(ctx, http.MethodPut, fmt.Sprintf("/config/%s", url.PathEscape(subject)), map[string]string{"compatibility": compatibility}, nil)
+}
+
+func (c *Client) isCompatible(ctx context.Context, subject string, schema string) (bool, error) {
+	var resp struct {
+		IsCompatible bool `json:"is_compatible"`
+	}
+
+	err := c.do(ctx, http.MethodPost, fmt.Sprintf("/compatibility/subjects/%s/versions/latest", url.PathEscape(subject)), map[string]string{"schema": schema}, &resp)
+	if err != nil {
+		if isNotFound(err) {
+			// There is nothing to be compatible with yet.
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	return resp.IsCompatible, nil
+}
+
+func (c *Client) register(ctx context.Context, subject string, schema string) (int, error) {
+	var resp struct {
+		ID int `json:"id"`
+	}
+
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/subjects/%s/versions", url.PathEscape(subject)), map[string]string{"schema": schema}, &resp); err != nil {
+		return 0, err
+	}
+
+	return resp.ID, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body any, out any) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.cfg.URL, "/")+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", contentType)
+	if c.cfg.Username != "" {
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		registryErr := Error{StatusCode: resp.StatusCode}
+		if err = json.Unmarshal(respBody, &registryErr); err != nil {
+			registryErr.Message = string(respBody)
+		}
+		return registryErr
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err = json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
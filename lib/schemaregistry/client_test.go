@@ -0,0 +1,55 @@
+package schemaregistry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/schemaregistry/schemaregistrytest"
+)
+
+func TestClient_GetOrRegister(t *testing.T) {
+	server := schemaregistrytest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	{
+		// Schemas are registered once and then cached
+		client := NewClient(config.SchemaRegistry{URL: server.URL})
+		id, err := client.GetOrRegister(ctx, "foo-value", `"string"`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, id)
+
+		id, err = client.GetOrRegister(ctx, "foo-value", `"string"`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, id)
+
+		id, err = client.GetOrRegister(ctx, "foo-value", `"long"`)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, id)
+		assert.Equal(t, []string{`"string"`, `"long"`}, server.Schemas("foo-value"))
+		assert.Empty(t, server.Compatibility("foo-value"))
+	}
+	{
+		// Compatibility level gets set on the subject
+		client := NewClient(config.SchemaRegistry{URL: server.URL, Compatibility: "FULL"})
+		_, err := client.GetOrRegister(ctx, "bar-value", `"string"`)
+		assert.NoError(t, err)
+		assert.Equal(t, "FULL", server.Compatibility("bar-value"))
+	}
+	{
+		// Incompatible schema
+		server.Incompatible["foo-value"] = true
+		client := NewClient(config.SchemaRegistry{URL: server.URL})
+		_, err := client.GetOrRegister(ctx, "foo-value", `"int"`)
+		assert.ErrorContains(t, err, `schema is not compatible with the latest version of subject "foo-value"`)
+	}
+	{
+		// Registry returns an error
+		client := NewClient(config.SchemaRegistry{URL: server.URL})
+		_, err := client.GetOrRegister(ctx, "baz-value", "")
+		assert.ErrorContains(t, err, "schema registry returned status 422 (error code 42201): Invalid schema")
+	}
+}
@@ -0,0 +1,140 @@
+// Package schemaregistrytest provides an in-memory stand-in for a Confluent-compatible schema registry.
+package schemaregistrytest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+)
+
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	schemas       []string
+	subjects      map[string][]int
+	compatibility map[string]string
+	// Incompatible - Subjects for which compatibility checks will fail.
+	Incompatible map[string]bool
+}
+
+func NewServer() *Server {
+	server := &Server{
+		subjects:      make(map[string][]int),
+		compatibility: make(map[string]string),
+		Incompatible:  make(map[string]bool),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /subjects/{subject}/versions", server.register)
+	mux.HandleFunc("POST /compatibility/subjects/{subject}/versions/latest", server.checkCompatibility)
+	mux.HandleFunc("PUT /config/{subject}", server.setCompatibility)
+	server.Server = httptest.NewServer(mux)
+	return server
+}
+
+// Schemas returns the schemas that have been registered under [subject], in order.
+func (s *Server) Schemas(subject string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var schemas []string
+	for _, id := range s.subjects[subject] {
+		schemas = append(schemas, s.schemas[id-1])
+	}
+	return schemas
+}
+
+// Schema returns the schema with [id].
+func (s *Server) Schema(id int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.schemas[id-1]
+}
+
+func (s *Server) Compatibility(subject string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.compatibility[subject]
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func readSchema(r *http.Request) (string, bool) {
+	var req struct {
+		Schema string `json:"schema"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Schema == "" {
+		return "", false
+	}
+
+	return req.Schema, true
+}
+
+func (s *Server) register(w http.ResponseWriter, r *http.Request) {
+	schema, isOk := readSchema(r)
+	if !isOk {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error_code": 42201, "message": "Invalid schema"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := slices.Index(s.schemas, schema) + 1
+	if id == 0 {
+		s.schemas = append(s.schemas, schema)
+		id = len(s.schemas)
+	}
+
+	subject := r.PathValue("subject")
+	if !slices.Contains(s.subjects[subject], id) {
+		s.subjects[subject] = append(s.subjects[subject], id)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"id": id})
+}
+
+func (s *Server) checkCompatibility(w http.ResponseWriter, r *http.Request) {
+	if _, isOk := readSchema(r); !isOk {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error_code": 42201, "message": "Invalid schema"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subject := r.PathValue("subject")
+	if _, isOk := s.subjects[subject]; !isOk {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error_code": 40401, "message": "Subject not found."})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"is_compatible": !s.Incompatible[subject]})
+}
+
+func (s *Server) setCompatibility(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Compatibility string `json:"compatibility"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error_code": 42203, "message": "Invalid compatibility level"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.compatibility[r.PathValue("subject")] = req.Compatibility
+	writeJSON(w, http.StatusOK, req)
+}
@@ -0,0 +1,87 @@
+package signal
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// invalidFileGracePeriod - How long a file that cannot be read or parsed is retried for, it may still be being written.
+const invalidFileGracePeriod = time.Minute
+
+// DirectoryReader reads signals from the JSON files in a directory, each file is only read once.
+type DirectoryReader struct {
+	dir string
+	// readFiles - Names of the files whose signals have already been returned, or that were given up on.
+	readFiles map[string]bool
+	// invalidFiles - When each file that could not be read or parsed first failed.
+	invalidFiles map[string]time.Time
+}
+
+func NewDirectoryReader(dir string) *DirectoryReader {
+	return &DirectoryReader{dir: dir, readFiles: make(map[string]bool), invalidFiles: make(map[string]time.Time)}
+}
+
+// Read reads the signals from the files that have been added since the last call, in the order of their file names.
+// A signal's ID defaults to the name of its file. Files that cannot be parsed are skipped and retried on the next call
+// since they may still be being written, they are given up on after [invalidFileGracePeriod].
+func (d *DirectoryReader) Read() ([]Signal, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signal directory: %w", err)
+	}
+
+	var signals []Signal
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" || d.readFiles[entry.Name()] {
+			continue
+		}
+
+		signal, err := readFile(filepath.Join(d.dir, entry.Name()))
+		if err != nil {
+			firstFailure, isOk := d.invalidFiles[entry.Name()]
+			if !isOk {
+				firstFailure = time.Now()
+				d.invalidFiles[entry.Name()] = firstFailure
+			}
+
+			if time.Since(firstFailure) < invalidFileGracePeriod {
+				slog.Warn("Failed to read signal file, it will be retried", slog.String("file", entry.Name()), slog.Any("err", err))
+				continue
+			}
+
+			slog.Warn("Skipping signal file, it could not be read", slog.String("file", entry.Name()), slog.Any("err", err))
+			delete(d.invalidFiles, entry.Name())
+			d.readFiles[entry.Name()] = true
+			continue
+		}
+
+		if signal.ID == "" {
+			signal.ID = strings.TrimSuffix(entry.Name(), ".json")
+		}
+
+		signals = append(signals, signal)
+		delete(d.invalidFiles, entry.Name())
+		d.readFiles[entry.Name()] = true
+	}
+
+	return signals, nil
+}
+
+func readFile(path string) (Signal, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return Signal{}, fmt.Errorf("failed to read signal file: %w", err)
+	}
+
+	var signal Signal
+	if err = json.Unmarshal(bytes, &signal); err != nil {
+		return Signal{}, fmt.Errorf("failed to parse signal file: %w", err)
+	}
+
+	return signal, nil
+}
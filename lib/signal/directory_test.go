@@ -0,0 +1,61 @@
+package signal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirectoryReader_Read(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2.json"), []byte(`{"id": "resume-1", "type": "resume"}`), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "1.json"), []byte(`{"type": "execute-snapshot", "data": {"table": "foo"}}`), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a signal"), 0o644))
+
+	reader := NewDirectoryReader(dir)
+	signals, err := reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, []Signal{
+		{ID: "1", Type: ExecuteSnapshot, Data: []byte(`{"table": "foo"}`)},
+		{ID: "resume-1", Type: Resume},
+	}, signals)
+
+	// Files that have already been read are skipped
+	signals, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Empty(t, signals)
+
+	// Invalid files are retried without holding back the other signals
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "3.json"), []byte(`{"type":`), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "4.json"), []byte(`{"type": "pause"}`), 0o644))
+	signals, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, []Signal{{ID: "4", Type: Pause}}, signals)
+	assert.Contains(t, reader.invalidFiles, "3.json")
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "3.json"), []byte(`{"type": "resume"}`), 0o644))
+	signals, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, []Signal{{ID: "3", Type: Resume}}, signals)
+	assert.Empty(t, reader.invalidFiles)
+
+	// Invalid files are given up on after the grace period
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "5.json"), []byte(`{"type":`), 0o644))
+	signals, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Empty(t, signals)
+
+	reader.invalidFiles["5.json"] = time.Now().Add(-invalidFileGracePeriod)
+	signals, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Empty(t, signals)
+	assert.True(t, reader.readFiles["5.json"])
+	assert.Empty(t, reader.invalidFiles)
+
+	// Missing directory
+	_, err = NewDirectoryReader(filepath.Join(dir, "missing")).Read()
+	assert.ErrorContains(t, err, "failed to read signal directory")
+}
@@ -0,0 +1,58 @@
+package signal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Type string
+
+const (
+	// ExecuteSnapshot - Snapshots a table, optionally limited to a primary key range.
+	ExecuteSnapshot Type = "execute-snapshot"
+	// StopSnapshot - Stops the snapshot of a table, or every snapshot if no table is passed in.
+	StopSnapshot Type = "stop-snapshot"
+	// Pause - Pauses snapshots, streaming is not affected.
+	Pause Type = "pause"
+	// Resume - Resumes snapshots that have been paused.
+	Resume Type = "resume"
+)
+
+// Signal - A command that is sent to a running reader.
+type Signal struct {
+	ID   string          `json:"id" yaml:"id"`
+	Type Type            `json:"type" yaml:"type"`
+	Data json.RawMessage `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+// SnapshotData - Data of [ExecuteSnapshot] and [StopSnapshot] signals.
+type SnapshotData struct {
+	Table string `json:"table"`
+	// PKStart - Comma separated primary key values that the snapshot starts at (inclusive).
+	PKStart string `json:"pkStart,omitempty"`
+	// PKEnd - Comma separated primary key values that the snapshot ends at (inclusive).
+	PKEnd string `json:"pkEnd,omitempty"`
+}
+
+// New builds a signal from the columns of a row in a signal table, [data] is a JSON document that may be empty.
+func New(id string, signalType string, data string) Signal {
+	signal := Signal{ID: id, Type: Type(signalType)}
+	if data != "" {
+		signal.Data = json.RawMessage(data)
+	}
+
+	return signal
+}
+
+func (s Signal) SnapshotData() (SnapshotData, error) {
+	var data SnapshotData
+	if len(s.Data) == 0 {
+		return data, nil
+	}
+
+	if err := json.Unmarshal(s.Data, &data); err != nil {
+		return SnapshotData{}, fmt.Errorf("failed to parse data of signal %q: %w", s.ID, err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,27 @@
+package signal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignal_SnapshotData(t *testing.T) {
+	{
+		// No data
+		data, err := New("1", "stop-snapshot", "").SnapshotData()
+		assert.NoError(t, err)
+		assert.Equal(t, SnapshotData{}, data)
+	}
+	{
+		// Primary key range
+		data, err := New("1", "execute-snapshot", `{"table": "foo", "pkStart": "1,a", "pkEnd": "5,b"}`).SnapshotData()
+		assert.NoError(t, err)
+		assert.Equal(t, SnapshotData{Table: "foo", PKStart: "1,a", PKEnd: "5,b"}, data)
+	}
+	{
+		// Invalid JSON
+		_, err := New("1", "execute-snapshot", `{"table":`).SnapshotData()
+		assert.ErrorContains(t, err, `failed to parse data of signal "1"`)
+	}
+}
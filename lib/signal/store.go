@@ -0,0 +1,98 @@
+package signal
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+	"github.com/artie-labs/reader/lib/storage/persistedmap"
+)
+
+// Record - A signal that has been received.
+type Record struct {
+	Type Type   `yaml:"type"`
+	Data string `yaml:"data,omitempty"`
+	// Done - Whether the signal has been fully processed, snapshots are only done once the whole table has been read.
+	Done bool `yaml:"done,omitempty"`
+	// Order - Signals are processed in the order that they were received.
+	Order int `yaml:"order"`
+}
+
+// Store - Acknowledges signals so that they are not processed again after a restart.
+type Store struct {
+	mu      sync.Mutex
+	records *persistedmap.PersistedMap[Record]
+}
+
+func NewStore(store offsetstore.OffsetStore, namespace string) *Store {
+	return &Store{records: persistedmap.NewPersistedMap[Record](store, namespace)}
+}
+
+// IsReceived returns whether the signal with [id] has already been received.
+func (s *Store) IsReceived(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, isOk := s.records.Get(id)
+	return isOk
+}
+
+// Receive acknowledges [signal], [done] should be false if it still needs to be processed after a restart.
+func (s *Store) Receive(signal Signal, done bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := Record{Type: signal.Type, Data: string(signal.Data), Done: done, Order: s.records.Len() + 1}
+	if err := s.records.Set(signal.ID, record); err != nil {
+		return fmt.Errorf("failed to acknowledge signal %q: %w", signal.ID, err)
+	}
+
+	return nil
+}
+
+func (s *Store) MarkDone(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, isOk := s.records.Get(id)
+	if !isOk {
+		return fmt.Errorf("signal %q has not been received", id)
+	}
+
+	record.Done = true
+	if err := s.records.Set(id, record); err != nil {
+		return fmt.Errorf("failed to mark signal %q as done: %w", id, err)
+	}
+
+	return nil
+}
+
+// Pending returns the signals that have been received but not fully processed, in the order that they were received.
+func (s *Store) Pending() []Signal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type pendingRecord struct {
+		id     string
+		record Record
+	}
+
+	var pending []pendingRecord
+	for _, id := range s.records.Keys() {
+		if record, _ := s.records.Get(id); !record.Done {
+			pending = append(pending, pendingRecord{id: id, record: record})
+		}
+	}
+
+	slices.SortFunc(pending, func(a, b pendingRecord) int {
+		return cmp.Compare(a.record.Order, b.record.Order)
+	})
+
+	signals := make([]Signal, len(pending))
+	for i, p := range pending {
+		signals[i] = New(p.id, string(p.record.Type), p.record.Data)
+	}
+	return signals
+}
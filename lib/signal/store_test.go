@@ -0,0 +1,31 @@
+package signal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+)
+
+func TestStore(t *testing.T) {
+	namespace := filepath.Join(t.TempDir(), "signals.yaml")
+	store := NewStore(offsetstore.NewFileStore(), namespace)
+	assert.False(t, store.IsReceived("b"))
+	assert.Empty(t, store.Pending())
+
+	assert.NoError(t, store.Receive(New("b", "execute-snapshot", `{"table":"foo"}`), false))
+	assert.NoError(t, store.Receive(New("a", "pause", ""), false))
+	assert.NoError(t, store.Receive(New("c", "stop-snapshot", ""), true))
+	assert.ErrorContains(t, store.MarkDone("d"), `signal "d" has not been received`)
+
+	// Signals are persisted, pending signals are returned in the order that they were received.
+	store = NewStore(offsetstore.NewFileStore(), namespace)
+	assert.True(t, store.IsReceived("b"))
+	assert.True(t, store.IsReceived("c"))
+	assert.Equal(t, []Signal{New("b", "execute-snapshot", `{"table":"foo"}`), New("a", "pause", "")}, store.Pending())
+
+	assert.NoError(t, store.MarkDone("b"))
+	assert.Equal(t, []Signal{New("a", "pause", "")}, NewStore(offsetstore.NewFileStore(), namespace).Pending())
+}
@@ -0,0 +1,104 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Table - A table to snapshot, [Run] returns the number of rows that have been written.
+type Table struct {
+	Name string
+	Run  func(ctx context.Context) (int, error)
+}
+
+// Result - Outcome of the snapshot of a table.
+type Result struct {
+	Table    string
+	Rows     int
+	Duration time.Duration
+	Err      error
+}
+
+// Run snapshots [tables] using up to [maxConcurrentTables] workers. A table failing does not stop the other tables, the
+// errors are returned once every table has finished. A summary of every table is logged at the end.
+func Run(ctx context.Context, tables []Table, maxConcurrentTables int) ([]Result, error) {
+	start := time.Now()
+	results := make([]Result, len(tables))
+	sem := make(chan struct{}, max(1, maxConcurrentTables))
+	var wg sync.WaitGroup
+	for i, table := range tables {
+		results[i].Table = table.Name
+
+		if err := acquire(ctx, sem); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			tableStart := time.Now()
+			results[i].Rows, results[i].Err = table.Run(ctx)
+			results[i].Duration = time.Since(tableStart)
+		}()
+	}
+
+	wg.Wait()
+	logSummary(results, time.Since(start))
+
+	var errs []error
+	for _, result := range results {
+		errs = append(errs, result.Err)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// acquire waits for a worker to be available, this returns an error if [ctx] is done.
+func acquire(ctx context.Context, sem chan struct{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func logSummary(results []Result, duration time.Duration) {
+	var failed, rows int
+	for _, result := range results {
+		attrs := []any{
+			slog.String("table", result.Table),
+			slog.Bool("success", result.Err == nil),
+			slog.Int("rows", result.Rows),
+			slog.Duration("duration", result.Duration),
+		}
+
+		if result.Err != nil {
+			failed++
+			slog.Error("Snapshot summary", append(attrs, slog.Any("err", result.Err))...)
+		} else {
+			slog.Info("Snapshot summary", attrs...)
+		}
+
+		rows += result.Rows
+	}
+
+	slog.Info("Finished snapshotting all tables",
+		slog.Int("tables", len(results)),
+		slog.Int("failed", failed),
+		slog.Int("rows", rows),
+		slog.Duration("duration", duration),
+	)
+}
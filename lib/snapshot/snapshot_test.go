@@ -0,0 +1,69 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	{
+		// Tables are snapshotted concurrently up to the limit
+		var running, maxRunning atomic.Int32
+		tables := make([]Table, 6)
+		for i := range tables {
+			tables[i] = Table{
+				Name: fmt.Sprintf("table_%d", i),
+				Run: func(ctx context.Context) (int, error) {
+					current := running.Add(1)
+					defer running.Add(-1)
+					for {
+						previous := maxRunning.Load()
+						if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+							break
+						}
+					}
+
+					time.Sleep(10 * time.Millisecond)
+					return i, nil
+				},
+			}
+		}
+
+		results, err := Run(context.Background(), tables, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), maxRunning.Load())
+		assert.Len(t, results, 6)
+		for i, result := range results {
+			assert.Equal(t, fmt.Sprintf("table_%d", i), result.Table)
+			assert.Equal(t, i, result.Rows)
+			assert.NoError(t, result.Err)
+		}
+	}
+	{
+		// A failing table does not stop the other tables
+		tables := []Table{
+			{Name: "foo", Run: func(ctx context.Context) (int, error) { return 0, fmt.Errorf("failed to snapshot foo") }},
+			{Name: "bar", Run: func(ctx context.Context) (int, error) { return 5, nil }},
+			{Name: "baz", Run: func(ctx context.Context) (int, error) { return 0, fmt.Errorf("failed to snapshot baz") }},
+		}
+
+		results, err := Run(context.Background(), tables, 0)
+		assert.ErrorContains(t, err, "failed to snapshot foo\nfailed to snapshot baz")
+		assert.Equal(t, 5, results[1].Rows)
+		assert.NoError(t, results[1].Err)
+	}
+	{
+		// Tables are not started once the context is cancelled
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results, err := Run(ctx, []Table{{Name: "foo", Run: func(ctx context.Context) (int, error) { panic("should not run") }}}, 1)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, results[0].Err, context.Canceled)
+	}
+}
@@ -0,0 +1,150 @@
+package offsetstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// saveSeq - Sequence number used for data that is stored with [DatabaseStore.Save], appended records start at 1.
+const saveSeq = 0
+
+type dialect struct {
+	textType    string
+	placeholder func(i int) string
+}
+
+func dialectForDriver(driverName string) dialect {
+	switch driverName {
+	case "pgx", "postgres":
+		return dialect{textType: "TEXT", placeholder: func(i int) string { return fmt.Sprintf("$%d", i) }}
+	case "mysql":
+		return dialect{textType: "LONGTEXT", placeholder: func(_ int) string { return "?" }}
+	case "sqlite", "sqlite3":
+		return dialect{textType: "TEXT", placeholder: func(_ int) string { return "?" }}
+	default:
+		return dialect{textType: "TEXT", placeholder: func(_ int) string { return "?" }}
+	}
+}
+
+type queries struct {
+	createTable string
+	load        string
+	delete      string
+	insert      string
+	maxSeq      string
+	loadAll     string
+}
+
+func buildQueries(tableName string, d dialect) queries {
+	return queries{
+		createTable: fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (namespace VARCHAR(255) NOT NULL, seq BIGINT NOT NULL, data %s NOT NULL, PRIMARY KEY (namespace, seq))`, tableName, d.textType),
+		load:        fmt.Sprintf(`SELECT data FROM %s WHERE namespace = %s AND seq = %d`, tableName, d.placeholder(1), saveSeq),
+		delete:      fmt.Sprintf(`DELETE FROM %s WHERE namespace = %s AND seq = %d`, tableName, d.placeholder(1), saveSeq),
+		insert:      fmt.Sprintf(`INSERT INTO %s (namespace, seq, data) VALUES (%s, %s, %s)`, tableName, d.placeholder(1), d.placeholder(2), d.placeholder(3)),
+		maxSeq:      fmt.Sprintf(`SELECT COALESCE(MAX(seq), %d) FROM %s WHERE namespace = %s`, saveSeq, tableName, d.placeholder(1)),
+		loadAll:     fmt.Sprintf(`SELECT data FROM %s WHERE namespace = %s AND seq > %d ORDER BY seq`, tableName, d.placeholder(1), saveSeq),
+	}
+}
+
+// DatabaseStore - Stores offsets and schema history in a table on a database/sql database.
+type DatabaseStore struct {
+	db      *sql.DB
+	queries queries
+}
+
+// NewDatabaseStore creates [tableName] if it does not exist, [driverName] is used to pick the placeholder syntax.
+func NewDatabaseStore(db *sql.DB, driverName string, tableName string) (*DatabaseStore, error) {
+	store := &DatabaseStore{db: db, queries: buildQueries(tableName, dialectForDriver(driverName))}
+	if _, err := db.Exec(store.queries.createTable); err != nil {
+		return nil, fmt.Errorf("failed to create offsets table: %w", err)
+	}
+
+	return store, nil
+}
+
+func (d *DatabaseStore) Load(namespace string) ([]byte, error) {
+	var data string
+	if err := d.db.QueryRow(d.queries.load, namespace).Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to load offsets: %w", err)
+	}
+
+	return []byte(data), nil
+}
+
+func (d *DatabaseStore) Save(namespace string, data []byte) error {
+	return d.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(d.queries.delete, namespace); err != nil {
+			return fmt.Errorf("failed to delete offsets: %w", err)
+		}
+
+		if _, err := tx.Exec(d.queries.insert, namespace, saveSeq, string(data)); err != nil {
+			return fmt.Errorf("failed to insert offsets: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (d *DatabaseStore) Append(namespace string, record []byte) error {
+	return d.inTx(func(tx *sql.Tx) error {
+		var seq int64
+		if err := tx.QueryRow(d.queries.maxSeq, namespace).Scan(&seq); err != nil {
+			return fmt.Errorf("failed to retrieve sequence number: %w", err)
+		}
+
+		if _, err := tx.Exec(d.queries.insert, namespace, seq+1, string(record)); err != nil {
+			return fmt.Errorf("failed to insert record: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (d *DatabaseStore) LoadAll(namespace string) ([][]byte, error) {
+	rows, err := d.db.Query(d.queries.loadAll, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load records: %w", err)
+	}
+	defer rows.Close()
+
+	var records [][]byte
+	for rows.Next() {
+		var data string
+		if err = rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan record: %w", err)
+		}
+
+		records = append(records, []byte(data))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over records: %w", err)
+	}
+
+	return records, nil
+}
+
+func (d *DatabaseStore) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	if err = fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to rollback transaction: %w", rollbackErr))
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package offsetstore
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+func TestBuildQueries(t *testing.T) {
+	{
+		// PostgreSQL
+		q := buildQueries("artie_offsets", dialectForDriver("pgx"))
+		assert.Equal(t, "CREATE TABLE IF NOT EXISTS artie_offsets (namespace VARCHAR(255) NOT NULL, seq BIGINT NOT NULL, data TEXT NOT NULL, PRIMARY KEY (namespace, seq))", q.createTable)
+		assert.Equal(t, "SELECT data FROM artie_offsets WHERE namespace = $1 AND seq = 0", q.load)
+		assert.Equal(t, "DELETE FROM artie_offsets WHERE namespace = $1 AND seq = 0", q.delete)
+		assert.Equal(t, "INSERT INTO artie_offsets (namespace, seq, data) VALUES ($1, $2, $3)", q.insert)
+		assert.Equal(t, "SELECT COALESCE(MAX(seq), 0) FROM artie_offsets WHERE namespace = $1", q.maxSeq)
+		assert.Equal(t, "SELECT data FROM artie_offsets WHERE namespace = $1 AND seq > 0 ORDER BY seq", q.loadAll)
+	}
+	{
+		// MySQL
+		q := buildQueries("artie_offsets", dialectForDriver("mysql"))
+		assert.Equal(t, "CREATE TABLE IF NOT EXISTS artie_offsets (namespace VARCHAR(255) NOT NULL, seq BIGINT NOT NULL, data LONGTEXT NOT NULL, PRIMARY KEY (namespace, seq))", q.createTable)
+		assert.Equal(t, "INSERT INTO artie_offsets (namespace, seq, data) VALUES (?, ?, ?)", q.insert)
+	}
+	{
+		// SQLite
+		q := buildQueries("artie_offsets", dialectForDriver("sqlite"))
+		assert.Equal(t, "CREATE TABLE IF NOT EXISTS artie_offsets (namespace VARCHAR(255) NOT NULL, seq BIGINT NOT NULL, data TEXT NOT NULL, PRIMARY KEY (namespace, seq))", q.createTable)
+		assert.Equal(t, "SELECT data FROM artie_offsets WHERE namespace = ? AND seq > 0 ORDER BY seq", q.loadAll)
+	}
+}
+
+func TestDatabaseStore(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "offsets.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	store, err := NewDatabaseStore(db, "sqlite", "artie_offsets")
+	assert.NoError(t, err)
+	{
+		// Save and load
+		data, err := store.Load("offsets.yaml")
+		assert.NoError(t, err)
+		assert.Nil(t, data)
+
+		assert.NoError(t, store.Save("offsets.yaml", []byte("foo")))
+		assert.NoError(t, store.Save("offsets.yaml", []byte("bar")))
+		data, err = store.Load("offsets.yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("bar"), data)
+	}
+	{
+		// Append and load all
+		records, err := store.LoadAll("schema_history.json")
+		assert.NoError(t, err)
+		assert.Empty(t, records)
+
+		assert.NoError(t, store.Append("schema_history.json", []byte(`{"a":1}`)))
+		assert.NoError(t, store.Append("schema_history.json", []byte(`{"b":2}`)))
+		records, err = store.LoadAll("schema_history.json")
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`)}, records)
+	}
+	{
+		// Saved data and appended records are kept apart, even within the same namespace
+		assert.NoError(t, store.Save("schema_history.json", []byte("baz")))
+		assert.NoError(t, store.Append("offsets.yaml", []byte("qux")))
+
+		data, err := store.Load("schema_history.json")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("baz"), data)
+
+		records, err := store.LoadAll("schema_history.json")
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+
+		records, err = store.LoadAll("offsets.yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("qux")}, records)
+	}
+	{
+		// Table already exists
+		store, err = NewDatabaseStore(db, "sqlite", "artie_offsets")
+		assert.NoError(t, err)
+		data, err := store.Load("offsets.yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("bar"), data)
+	}
+}
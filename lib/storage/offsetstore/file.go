@@ -0,0 +1,259 @@
+package offsetstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// checksumHeaderFormat - Header that is written before data that is stored with [FileStore.Save]. It is a YAML comment so
+// that offset files can still be read by hand.
+const checksumHeaderFormat = "# checksum: %08x\n"
+
+var (
+	checksumHeaderRegex = regexp.MustCompile(`^# checksum: ([0-9a-f]{8})\n`)
+	recordRegex         = regexp.MustCompile(`^([0-9a-f]{8}):`)
+	crcTable            = crc32.MakeTable(crc32.Castagnoli)
+)
+
+func checksum(data []byte) uint32 {
+	return crc32.Checksum(data, crcTable)
+}
+
+// FileStore - Stores each namespace in a local file, the namespace is the file path.
+//
+// Saved data is written to a temporary file that is synced and then renamed over the previous file, which is kept as a
+// backup (`<namespace>.bak`). Appended records are prefixed with a checksum and a torn final record is ignored.
+type FileStore struct{}
+
+func NewFileStore() FileStore {
+	return FileStore{}
+}
+
+func backupPath(namespace string) string {
+	return namespace + ".bak"
+}
+
+func (FileStore) Load(namespace string) ([]byte, error) {
+	data, err := readChecksummedFile(namespace)
+	if err == nil {
+		return data, nil
+	}
+
+	backup, backupErr := readChecksummedFile(backupPath(namespace))
+	if backupErr != nil {
+		if errors.Is(err, os.ErrNotExist) && errors.Is(backupErr, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	slog.Warn("Offset file is missing or corrupt, falling back to the backup",
+		slog.String("namespace", namespace),
+		slog.Any("err", err),
+	)
+	return backup, nil
+}
+
+// readChecksummedFile reads a file that was written by [FileStore.Save], files without a checksum header are returned as is.
+func readChecksummedFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	matches := checksumHeaderRegex.FindSubmatch(data)
+	if matches == nil {
+		return data, nil
+	}
+
+	data = data[len(matches[0]):]
+	if expected := string(matches[1]); fmt.Sprintf("%08x", checksum(data)) != expected {
+		return nil, fmt.Errorf("checksum mismatch for %q", path)
+	}
+
+	return data, nil
+}
+
+func (FileStore) Save(namespace string, data []byte) error {
+	tmpPath := namespace + ".tmp"
+	contents := append([]byte(fmt.Sprintf(checksumHeaderFormat, checksum(data))), data...)
+	if err := writeFileSync(tmpPath, contents); err != nil {
+		return err
+	}
+
+	// Keep the previous version around in case the new file does not make it to disk.
+	if err := os.Rename(namespace, backupPath(namespace)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to rotate backup: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, namespace); err != nil {
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+
+	return syncDir(namespace)
+}
+
+func writeFileSync(path string, data []byte) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write to file: %w", err)
+	}
+
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
+	return file.Close()
+}
+
+// syncDir syncs the directory that contains [path] so that renames and newly created files are durable.
+func syncDir(path string) error {
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("failed to open directory: %w", err)
+	}
+
+	defer dir.Close()
+	if err = dir.Sync(); err != nil {
+		return fmt.Errorf("failed to sync directory: %w", err)
+	}
+
+	return nil
+}
+
+func (FileStore) Append(namespace string, record []byte) error {
+	if bytes.IndexByte(record, '\n') != -1 {
+		return fmt.Errorf("record cannot contain a newline")
+	}
+
+	_, statErr := os.Stat(namespace)
+	isNewFile := errors.Is(statErr, os.ErrNotExist)
+
+	// If the file doesn't exist, create it
+	file, err := os.OpenFile(namespace, os.O_RDWR|os.O_CREATE, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+
+	defer file.Close()
+	offset, err := truncateTornRecord(file)
+	if err != nil {
+		return err
+	}
+
+	line := append([]byte(fmt.Sprintf("%08x:", checksum(record))), record...)
+	if _, err = file.WriteAt(append(line, '\n'), offset); err != nil {
+		return fmt.Errorf("failed to write to file: %w", err)
+	}
+
+	if err = file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
+	if isNewFile {
+		return syncDir(namespace)
+	}
+
+	return nil
+}
+
+// tornRecordReadSize - Number of bytes that are read at a time from the end of a file when looking for a torn record.
+const tornRecordReadSize = 4096
+
+// truncateTornRecord removes a partially written final record and returns the offset to write the next record at.
+func truncateTornRecord(file *os.File) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	if info.Size() == 0 {
+		return 0, nil
+	}
+
+	// Only the end of the file is read, it goes back further than the last byte if the final record is torn.
+	var offset int64
+	for end := info.Size(); end > 0; {
+		start := max(end-tornRecordReadSize, 0)
+		data := make([]byte, end-start)
+		if _, err = file.ReadAt(data, start); err != nil {
+			return 0, fmt.Errorf("failed to read file: %w", err)
+		}
+
+		if end == info.Size() && data[len(data)-1] == '\n' {
+			return info.Size(), nil
+		}
+
+		if idx := bytes.LastIndexByte(data, '\n'); idx != -1 {
+			offset = start + int64(idx) + 1
+			break
+		}
+		end = start
+	}
+
+	slog.Warn("Dropping torn record", slog.String("file", file.Name()), slog.Int64("bytes", info.Size()-offset))
+	if err = file.Truncate(offset); err != nil {
+		return 0, fmt.Errorf("failed to truncate torn record: %w", err)
+	}
+
+	return offset, nil
+}
+
+func (FileStore) LoadAll(namespace string) ([][]byte, error) {
+	data, err := os.ReadFile(namespace)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	lines := bytes.Split(data, []byte("\n"))
+	// Every record ends with a newline, so the last element is either empty or a record that was not fully written.
+	if torn := lines[len(lines)-1]; len(torn) > 0 {
+		slog.Warn("Ignoring torn record", slog.String("namespace", namespace), slog.Int("bytes", len(torn)))
+	}
+	lines = lines[:len(lines)-1]
+
+	var records [][]byte
+	for idx, line := range lines {
+		record, err := parseRecord(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse record %d: %w", idx+1, err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// parseRecord verifies the checksum of a record that was written by [FileStore.Append], records that were written
+// before checksums were added are returned as is.
+func parseRecord(line []byte) ([]byte, error) {
+	matches := recordRegex.FindSubmatch(line)
+	if matches == nil {
+		return append([]byte(nil), line...), nil
+	}
+
+	record := line[len(matches[0]):]
+	if fmt.Sprintf("%08x", checksum(record)) != string(matches[1]) {
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+
+	return append([]byte(nil), record...), nil
+}
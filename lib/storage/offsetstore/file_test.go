@@ -0,0 +1,157 @@
+package offsetstore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	store := NewFileStore()
+	{
+		// Save and load
+		namespace := filepath.Join(t.TempDir(), "offsets.yaml")
+		data, err := store.Load(namespace)
+		assert.NoError(t, err)
+		assert.Nil(t, data)
+
+		assert.NoError(t, store.Save(namespace, []byte("foo")))
+		assert.NoError(t, store.Save(namespace, []byte("bar")))
+		data, err = store.Load(namespace)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("bar"), data)
+	}
+	{
+		// Append and load all
+		namespace := filepath.Join(t.TempDir(), "schema_history.json")
+		records, err := store.LoadAll(namespace)
+		assert.NoError(t, err)
+		assert.Empty(t, records)
+
+		assert.NoError(t, store.Append(namespace, []byte(`{"a":1}`)))
+		assert.NoError(t, store.Append(namespace, []byte(`{"b":2}`)))
+		records, err = store.LoadAll(namespace)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`)}, records)
+	}
+}
+
+func TestFileStore_Save_Recovery(t *testing.T) {
+	store := NewFileStore()
+	{
+		// Previous version is kept as a backup
+		namespace := filepath.Join(t.TempDir(), "offsets.yaml")
+		assert.NoError(t, store.Save(namespace, []byte("foo")))
+		assert.NoError(t, store.Save(namespace, []byte("bar")))
+
+		backup, err := os.ReadFile(backupPath(namespace))
+		assert.NoError(t, err)
+		assert.Equal(t, "# checksum: cfc4ae1d\nfoo", string(backup))
+		assert.NoFileExists(t, namespace+".tmp")
+	}
+	{
+		// Corrupt file falls back to the backup
+		namespace := filepath.Join(t.TempDir(), "offsets.yaml")
+		assert.NoError(t, store.Save(namespace, []byte("foo")))
+		assert.NoError(t, store.Save(namespace, []byte("bar")))
+		assert.NoError(t, os.WriteFile(namespace, []byte("# checksum: cfc4ae1d\nba"), 0644))
+
+		data, err := store.Load(namespace)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("foo"), data)
+	}
+	{
+		// Missing file (crash between renames) falls back to the backup
+		namespace := filepath.Join(t.TempDir(), "offsets.yaml")
+		assert.NoError(t, store.Save(namespace, []byte("foo")))
+		assert.NoError(t, os.Rename(namespace, backupPath(namespace)))
+
+		data, err := store.Load(namespace)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("foo"), data)
+	}
+	{
+		// Corrupt file without a backup
+		namespace := filepath.Join(t.TempDir(), "offsets.yaml")
+		assert.NoError(t, os.WriteFile(namespace, []byte("# checksum: 00000000\nfoo"), 0644))
+		_, err := store.Load(namespace)
+		assert.ErrorContains(t, err, "checksum mismatch")
+	}
+	{
+		// Files without a checksum are still supported
+		namespace := filepath.Join(t.TempDir(), "offsets.yaml")
+		assert.NoError(t, os.WriteFile(namespace, []byte("offset: foo\n"), 0644))
+		data, err := store.Load(namespace)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("offset: foo\n"), data)
+	}
+}
+
+func TestFileStore_Append_Recovery(t *testing.T) {
+	store := NewFileStore()
+	{
+		// Torn final record is ignored and then truncated on the next append
+		namespace := filepath.Join(t.TempDir(), "schema_history.json")
+		assert.NoError(t, store.Append(namespace, []byte(`{"a":1}`)))
+
+		file, err := os.OpenFile(namespace, os.O_APPEND|os.O_WRONLY, 0644)
+		assert.NoError(t, err)
+		_, err = file.WriteString(`1234abcd:{"b":`)
+		assert.NoError(t, err)
+		assert.NoError(t, file.Close())
+
+		records, err := store.LoadAll(namespace)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte(`{"a":1}`)}, records)
+
+		assert.NoError(t, store.Append(namespace, []byte(`{"c":3}`)))
+		records, err = store.LoadAll(namespace)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte(`{"a":1}`), []byte(`{"c":3}`)}, records)
+	}
+	{
+		// Torn final record that is larger than what is read at a time, or that is the only record
+		for _, existing := range []string{"", "00000000:{}\n"} {
+			namespace := filepath.Join(t.TempDir(), "schema_history.json")
+			torn := strings.Repeat("x", tornRecordReadSize*2+10)
+			assert.NoError(t, os.WriteFile(namespace, []byte(existing+torn), 0644))
+
+			offset, err := func() (int64, error) {
+				file, err := os.OpenFile(namespace, os.O_RDWR, 0644)
+				assert.NoError(t, err)
+				defer file.Close()
+				return truncateTornRecord(file)
+			}()
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(existing)), offset)
+
+			data, err := os.ReadFile(namespace)
+			assert.NoError(t, err)
+			assert.Equal(t, existing, string(data))
+		}
+	}
+	{
+		// Corrupt record in the middle of the file
+		namespace := filepath.Join(t.TempDir(), "schema_history.json")
+		assert.NoError(t, os.WriteFile(namespace, []byte("00000000:{\"a\":1}\n"), 0644))
+		_, err := store.LoadAll(namespace)
+		assert.ErrorContains(t, err, "failed to parse record 1: checksum mismatch")
+	}
+	{
+		// Records without a checksum are still supported
+		namespace := filepath.Join(t.TempDir(), "schema_history.json")
+		assert.NoError(t, os.WriteFile(namespace, []byte("{\"a\":1}\n"), 0644))
+		assert.NoError(t, store.Append(namespace, []byte(`{"b":2}`)))
+		records, err := store.LoadAll(namespace)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`)}, records)
+	}
+	{
+		// Records cannot contain newlines
+		namespace := filepath.Join(t.TempDir(), "schema_history.json")
+		assert.ErrorContains(t, store.Append(namespace, []byte("a\nb")), "record cannot contain a newline")
+	}
+}
@@ -0,0 +1,14 @@
+package offsetstore
+
+// OffsetStore - Persists offsets and schema history. Data is grouped by namespace, which is the offset or schema history
+// file that has been configured for the source.
+type OffsetStore interface {
+	// Load returns the data that was last saved for [namespace], nil if nothing has been saved yet.
+	Load(namespace string) ([]byte, error)
+	// Save replaces the data for [namespace].
+	Save(namespace string, data []byte) error
+	// Append adds a record to the end of [namespace].
+	Append(namespace string, record []byte) error
+	// LoadAll returns all the records that have been appended to [namespace], in order.
+	LoadAll(namespace string) ([][]byte, error)
+}
@@ -1,28 +1,21 @@
 package persistedlist
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"log/slog"
-	"os"
 
 	"github.com/artie-labs/reader/lib/logger"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 )
 
 type PersistedList[T any] struct {
-	file     *os.File
-	filePath string
+	store     offsetstore.OffsetStore
+	namespace string
 }
 
-func NewPersistedList[T any](filePath string) (PersistedList[T], error) {
-	// If the file doesn't exist, create it
-	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.ModePerm)
-	if err != nil {
-		return PersistedList[T]{}, fmt.Errorf("failed to open file")
-	}
-
-	return PersistedList[T]{file: file, filePath: filePath}, nil
+func NewPersistedList[T any](store offsetstore.OffsetStore, namespace string) (PersistedList[T], error) {
+	return PersistedList[T]{store: store, namespace: namespace}, nil
 }
 
 func (p PersistedList[T]) Push(item T) error {
@@ -31,9 +24,8 @@ func (p PersistedList[T]) Push(item T) error {
 		return fmt.Errorf("failed to marshal data")
 	}
 
-	bytes = append(bytes, '\n')
-	if _, err = p.file.Write(bytes); err != nil {
-		return fmt.Errorf("failed to write to file")
+	if err = p.store.Append(p.namespace, bytes); err != nil {
+		return fmt.Errorf("failed to append item: %w", err)
 	}
 
 	return nil
@@ -41,31 +33,24 @@ func (p PersistedList[T]) Push(item T) error {
 
 // GetData - This is a separate function since we don't need to keep the entire list in memory
 func (p PersistedList[T]) GetData() []T {
-	data, err := loadFromFile[T](p.filePath)
+	data, err := load[T](p.store, p.namespace)
 	if err != nil {
-		logger.Panic("Failed to load persisted map from filepath", slog.Any("err", err))
+		logger.Panic("Failed to load persisted list", slog.String("namespace", p.namespace), slog.Any("err", err))
 	}
 
 	return data
 }
 
-func loadFromFile[T any](filePath string) ([]T, error) {
-	file, err := os.Open(filePath)
+func load[T any](store offsetstore.OffsetStore, namespace string) ([]T, error) {
+	records, err := store.LoadAll(namespace)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, err
 	}
 
-	defer file.Close()
-
 	var data []T
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
+	for _, record := range records {
 		var t T
-		if err = json.Unmarshal(scanner.Bytes(), &t); err != nil {
+		if err = json.Unmarshal(record, &t); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal data: %w", err)
 		}
 
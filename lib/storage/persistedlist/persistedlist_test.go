@@ -5,6 +5,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"path/filepath"
 	"testing"
+
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 )
 
 type Dog struct {
@@ -13,7 +15,7 @@ type Dog struct {
 }
 
 func TestPersistedList(t *testing.T) {
-	pl, err := NewPersistedList[Dog](filepath.Join(t.TempDir(), "dogs.json"))
+	pl, err := NewPersistedList[Dog](offsetstore.NewFileStore(), filepath.Join(t.TempDir(), "dogs.json"))
 	assert.NoError(t, err)
 	// Now, let's load a bunch of dogs
 	dogs := []Dog{
@@ -32,7 +34,7 @@ func TestPersistedList(t *testing.T) {
 }
 
 func BenchmarkPersistedList(b *testing.B) {
-	pl, err := NewPersistedList[Dog](filepath.Join(b.TempDir(), "dogs.json"))
+	pl, err := NewPersistedList[Dog](offsetstore.NewFileStore(), filepath.Join(b.TempDir(), "dogs.json"))
 	assert.NoError(b, err)
 	for n := 0; n < b.N; n++ {
 		assert.NoError(b, pl.Push(Dog{Name: fmt.Sprintf("Buddy #%d", n), Breed: "Golden Retriever"}))
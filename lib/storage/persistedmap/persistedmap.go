@@ -2,28 +2,32 @@ package persistedmap
 
 import (
 	"fmt"
-	"gopkg.in/yaml.v3"
-	"io"
 	"log/slog"
-	"os"
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
 
 	"github.com/artie-labs/reader/lib/logger"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 )
 
 type PersistedMap[T any] struct {
-	filePath string
-	data     map[string]T
+	store     offsetstore.OffsetStore
+	namespace string
+	data      map[string]T
 }
 
-func NewPersistedMap[T any](filePath string) *PersistedMap[T] {
+func NewPersistedMap[T any](store offsetstore.OffsetStore, namespace string) *PersistedMap[T] {
 	persistedMap := &PersistedMap[T]{
-		filePath: filePath,
-		data:     make(map[string]T),
+		store:     store,
+		namespace: namespace,
+		data:      make(map[string]T),
 	}
 
-	data, err := loadFromFile[T](filePath)
+	data, err := load[T](store, namespace)
 	if err != nil {
-		logger.Panic("Failed to load persisted map from filepath", slog.Any("err", err))
+		logger.Panic("Failed to load persisted map", slog.String("namespace", namespace), slog.Any("err", err))
 	}
 
 	if len(data) > 0 {
@@ -35,22 +39,28 @@ func NewPersistedMap[T any](filePath string) *PersistedMap[T] {
 
 func (p *PersistedMap[T]) Set(key string, value T) error {
 	p.data[key] = value
+	return p.flush()
+}
 
-	file, err := os.Create(p.filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
+// Delete removes [key] and persists the map.
+func (p *PersistedMap[T]) Delete(key string) error {
+	delete(p.data, key)
+	return p.flush()
+}
 
+// Clear removes all the keys and persists the empty map.
+func (p *PersistedMap[T]) Clear() error {
+	p.data = make(map[string]T)
+	return p.flush()
+}
+
+func (p *PersistedMap[T]) flush() error {
 	yamlBytes, err := yaml.Marshal(p.data)
 	if err != nil {
 		return fmt.Errorf("failed to marshal data: %w", err)
 	}
 
-	if _, err = file.Write(yamlBytes); err != nil {
-		return fmt.Errorf("failed to write to file: %w", err)
-	}
-
-	return file.Close()
+	return p.store.Save(p.namespace, yamlBytes)
 }
 
 func (p *PersistedMap[T]) Get(key string) (T, bool) {
@@ -58,20 +68,20 @@ func (p *PersistedMap[T]) Get(key string) (T, bool) {
 	return value, isOk
 }
 
-func loadFromFile[T any](filePath string) (map[string]T, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
+// Keys returns the keys of the map in sorted order.
+func (p *PersistedMap[T]) Keys() []string {
+	return slices.Sorted(maps.Keys(p.data))
+}
 
-		return nil, fmt.Errorf("failed to open file: %w", err)
-	}
+// Len returns the number of keys in the map.
+func (p *PersistedMap[T]) Len() int {
+	return len(p.data)
+}
 
-	defer file.Close()
-	readBytes, err := io.ReadAll(file)
+func load[T any](store offsetstore.OffsetStore, namespace string) (map[string]T, error) {
+	readBytes, err := store.Load(namespace)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, err
 	}
 
 	var data map[string]T
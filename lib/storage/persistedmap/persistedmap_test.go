@@ -6,6 +6,8 @@ import (
 	"gopkg.in/yaml.v3"
 	"os"
 	"testing"
+
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 )
 
 func TestPersistedMap_LoadFromFile(t *testing.T) {
@@ -21,14 +23,14 @@ func TestPersistedMap_LoadFromFile(t *testing.T) {
 	tmpFile.Close()
 
 	// Load the data from the file into PersistedMap
-	pMap := NewPersistedMap[any](tmpFile.Name())
+	pMap := NewPersistedMap[any](offsetstore.NewFileStore(), tmpFile.Name())
 	assert.Equal(t, initialData, pMap.data)
 }
 
 func TestPersistedMap_Flush(t *testing.T) {
 	tmpFile := fmt.Sprintf("%s/persistedmap_test", t.TempDir())
 
-	pMap := NewPersistedMap[any](tmpFile)
+	pMap := NewPersistedMap[any](offsetstore.NewFileStore(), tmpFile)
 	assert.NoError(t, pMap.Set("key1", "value1"))
 	assert.NoError(t, pMap.Set("key2", 2))
 
@@ -42,7 +44,7 @@ func TestPersistedMap_Flush(t *testing.T) {
 	assert.True(t, isOk)
 
 	// If I load a new PersistedMap, does it come back?
-	pMap2 := NewPersistedMap[any](tmpFile)
+	pMap2 := NewPersistedMap[any](offsetstore.NewFileStore(), tmpFile)
 	val, isOk = pMap2.Get("key1")
 	assert.True(t, isOk)
 	assert.Equal(t, "value1", val)
@@ -52,9 +54,30 @@ func TestPersistedMap_Flush(t *testing.T) {
 	assert.True(t, isOk)
 }
 
+func TestPersistedMap_Delete(t *testing.T) {
+	tmpFile := fmt.Sprintf("%s/persistedmap_test", t.TempDir())
+
+	pMap := NewPersistedMap[any](offsetstore.NewFileStore(), tmpFile)
+	assert.NoError(t, pMap.Set("key1", "value1"))
+	assert.NoError(t, pMap.Set("key2", 2))
+	assert.NoError(t, pMap.Delete("key1"))
+
+	_, isOk := pMap.Get("key1")
+	assert.False(t, isOk)
+
+	// The deletion should be persisted
+	pMap2 := NewPersistedMap[any](offsetstore.NewFileStore(), tmpFile)
+	_, isOk = pMap2.Get("key1")
+	assert.False(t, isOk)
+
+	val, isOk := pMap2.Get("key2")
+	assert.True(t, isOk)
+	assert.Equal(t, 2, val)
+}
+
 func BenchmarkNewPersistedMap(b *testing.B) {
 	// Seed the persisted map with 100 values
-	pMap := NewPersistedMap[any](fmt.Sprintf("%s/persistedmap_test", b.TempDir()))
+	pMap := NewPersistedMap[any](offsetstore.NewFileStore(), fmt.Sprintf("%s/persistedmap_test", b.TempDir()))
 	for i := 0; i < 100; i++ {
 		assert.NoError(b, pMap.Set(fmt.Sprintf("key%d", i), i))
 	}
@@ -2,15 +2,14 @@ package ttlmap
 
 import (
 	"fmt"
-	"io"
 	"log/slog"
-	"os"
 	"sync"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/artie-labs/reader/lib/logger"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 )
 
 const (
@@ -28,20 +27,22 @@ type TTLMap struct {
 	shouldSave    bool
 	mu            sync.RWMutex
 	data          map[string]*ItemWrapper `yaml:"data"`
-	filePath      string
+	store         offsetstore.OffsetStore
+	namespace     string
 	closeChan     chan struct{}
 	cleanupTicker *time.Ticker
 	flushTicker   *time.Ticker
 }
 
-func NewMap(filePath string, cleanupInterval, flushInterval time.Duration) *TTLMap {
+func NewMap(store offsetstore.OffsetStore, namespace string, cleanupInterval, flushInterval time.Duration) *TTLMap {
 	t := &TTLMap{
 		data:      make(map[string]*ItemWrapper),
-		filePath:  filePath,
+		store:     store,
+		namespace: namespace,
 		closeChan: make(chan struct{}),
 	}
 
-	if err := t.loadFromFile(); err != nil {
+	if err := t.load(); err != nil {
 		slog.Warn("Failed to load ttlmap from memory, starting a new one...", slog.Any("err", err))
 	}
 
@@ -93,6 +94,14 @@ func (t *TTLMap) Get(key string) (any, bool) {
 	return item.Value, true
 }
 
+// Close stops the background routine and flushes the data to disk.
+func (t *TTLMap) Close() error {
+	t.cleanupTicker.Stop()
+	t.flushTicker.Stop()
+	close(t.closeChan)
+	return t.flush()
+}
+
 func (t *TTLMap) cleanUpAndFlushRoutine() {
 	for {
 		select {
@@ -129,13 +138,6 @@ func (t *TTLMap) flush() error {
 		return nil
 	}
 
-	file, err := os.Create(t.filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-
-	defer file.Close()
-
 	dataToSave := make(map[string]*ItemWrapper)
 	for key, val := range t.data {
 		if val.DoNotFlushToDisk {
@@ -150,27 +152,20 @@ func (t *TTLMap) flush() error {
 		return fmt.Errorf("failed to marshal data: %w", err)
 	}
 
-	if _, err = file.Write(yamlBytes); err != nil {
-		return fmt.Errorf("failed to write to file: %w", err)
+	if err = t.store.Save(t.namespace, yamlBytes); err != nil {
+		return fmt.Errorf("failed to save data: %w", err)
 	}
 
 	t.shouldSave = false
 	return nil
 }
 
-func (t *TTLMap) loadFromFile() error {
-	file, err := os.Open(t.filePath)
+func (t *TTLMap) load() error {
+	readBytes, err := t.store.Load(t.namespace)
 	if err != nil {
 		return err
 	}
 
-	defer file.Close()
-
-	readBytes, err := io.ReadAll(file)
-	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
-	}
-
 	var data map[string]*ItemWrapper
 	if err = yaml.Unmarshal(readBytes, &data); err != nil {
 		return fmt.Errorf("failed to unmarshal data: %w", err)
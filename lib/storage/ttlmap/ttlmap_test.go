@@ -9,12 +9,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
+
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 )
 
 func TestTTLMap_Complete(t *testing.T) {
 	fp := filepath.Join(t.TempDir(), "test.yaml")
 
-	store := NewMap(fp, 100*time.Millisecond, 120*time.Millisecond)
+	store := NewMap(offsetstore.NewFileStore(), fp, 100*time.Millisecond, 120*time.Millisecond)
 	keyToDuration := map[string]time.Duration{
 		"foo": 50 * time.Millisecond,
 		"bar": 100 * time.Millisecond,
@@ -76,7 +78,7 @@ func TestFlushing(t *testing.T) {
 	// Step 1: Create a TTLMap instance with a temporary file for storage
 	fp := filepath.Join(t.TempDir(), "test.yaml")
 
-	ttlMap := NewMap(fp, DefaultCleanUpInterval, DefaultFlushInterval)
+	ttlMap := NewMap(offsetstore.NewFileStore(), fp, DefaultCleanUpInterval, DefaultFlushInterval)
 
 	// Step 2: Add items to the map with varying DoNotFlushToDisk values
 	ttlMap.Set(SetArgs{Key: "key1", Value: "value1", DoNotFlushToDisk: true}, 1*time.Hour)
@@ -98,3 +100,21 @@ func TestFlushing(t *testing.T) {
 
 	ttlMap.closeChan <- struct{}{}
 }
+
+func TestTTLMap_Close(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "test.yaml")
+	store := NewMap(offsetstore.NewFileStore(), fp, time.Hour, time.Hour)
+	store.Set(SetArgs{Key: "foo", Value: "bar"}, time.Hour)
+	store.Set(SetArgs{Key: "in-memory", Value: "value", DoNotFlushToDisk: true}, time.Hour)
+
+	// Data should be flushed on close, even though the flush interval has not elapsed.
+	assert.NoError(t, store.Close())
+
+	reloaded := NewMap(offsetstore.NewFileStore(), fp, time.Hour, time.Hour)
+	val, isOk := reloaded.Get("foo")
+	assert.True(t, isOk)
+	assert.Equal(t, "bar", val)
+
+	_, isOk = reloaded.Get("in-memory")
+	assert.False(t, isOk)
+}
@@ -2,14 +2,21 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
+	"os/signal"
+	"syscall"
+
+	_ "modernc.org/sqlite"
 
 	"github.com/artie-labs/reader/config"
 	"github.com/artie-labs/reader/lib/kafkalib"
 	"github.com/artie-labs/reader/lib/logger"
 	"github.com/artie-labs/reader/lib/mtr"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 	"github.com/artie-labs/reader/sources"
 	"github.com/artie-labs/reader/sources/dynamodb"
 	"github.com/artie-labs/reader/sources/mongo"
@@ -34,27 +41,41 @@ func setUpMetrics(cfg *config.Metrics) (mtr.Client, error) {
 	return client, nil
 }
 
-func buildSource(ctx context.Context, cfg *config.Settings) (sources.Source, bool, error) {
-	var source sources.Source
-	var err error
+func buildOffsetStore(cfg *config.OffsetStorage) (offsetstore.OffsetStore, error) {
+	switch cfg.GetKind() {
+	case config.OffsetStorageFile:
+		return offsetstore.NewFileStore(), nil
+	case config.OffsetStorageDatabase:
+		slog.Info("Storing offsets in database", slog.String("driver", cfg.Driver), slog.String("tableName", cfg.GetTableName()))
+		db, err := sql.Open(cfg.Driver, cfg.DSN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to offset storage database: %w", err)
+		}
+
+		return offsetstore.NewDatabaseStore(db, cfg.Driver, cfg.GetTableName())
+	default:
+		panic(fmt.Sprintf("unknown offset storage kind %q", cfg.GetKind())) // should never happen
+	}
+}
+
+func buildSource(ctx context.Context, cfg *config.Settings, store offsetstore.OffsetStore) (sources.Source, bool, error) {
 	switch cfg.Source {
 	case config.SourceDynamo:
-		return dynamodb.Load(ctx, *cfg.DynamoDB)
+		return dynamodb.Load(ctx, *cfg.DynamoDB, store)
 	case config.SourceMongoDB:
-		return mongo.Load(ctx, *cfg.MongoDB)
+		return mongo.Load(ctx, *cfg.MongoDB, store)
 	case config.SourceMySQL:
-		return mysql.Load(ctx, *cfg.MySQL)
+		return mysql.Load(ctx, *cfg.MySQL, store)
 	case config.SourceMSSQL:
-		source, err = mssql.Load(*cfg.MSSQL)
+		return mssql.Load(ctx, *cfg.MSSQL, store)
 	case config.SourcePostgreSQL:
-		source, err = postgres.Load(*cfg.PostgreSQL)
+		return postgres.Load(ctx, *cfg.PostgreSQL, store)
 	default:
 		panic(fmt.Sprintf("unknown source %q", cfg.Source)) // should never happen
 	}
-	return source, false, err
 }
 
-func buildDestinationWriter(ctx context.Context, cfg *config.Settings, statsD mtr.Client) (writers.DestinationWriter, error) {
+func buildDestinationWriter(ctx context.Context, cfg *config.Settings, statsD mtr.Client, isStreamingMode bool) (writers.DestinationWriter, error) {
 	switch cfg.Destination {
 	case config.DestinationKafka:
 		kafkaCfg := cfg.Kafka
@@ -70,7 +91,7 @@ func buildDestinationWriter(ctx context.Context, cfg *config.Settings, statsD mt
 		)
 		return kafkalib.NewBatchWriter(ctx, *kafkaCfg, statsD)
 	case config.DestinationTransfer:
-		return transfer.NewWriter(*cfg.Transfer, statsD, cfg.BeforeBackfill)
+		return transfer.NewWriter(*cfg.Transfer, statsD, cfg.BeforeBackfill, cfg.GetUnavailableValuePlaceholder(), isStreamingMode)
 	default:
 		panic(fmt.Sprintf("unknown destination %q", cfg.Destination)) // should never happen
 	}
@@ -101,20 +122,31 @@ func main() {
 		}
 	}()
 
-	ctx := context.Background()
-	destinationWriter, err := buildDestinationWriter(ctx, cfg, statsD)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	store, err := buildOffsetStore(cfg.OffsetStorage)
 	if err != nil {
-		logger.Fatal(fmt.Sprintf("Failed to init %q destination writer", cfg.Destination), slog.Any("err", err))
+		logger.Fatal("Failed to init offset storage", slog.Any("err", err))
 	}
 
-	source, isStreamingMode, err := buildSource(ctx, cfg)
+	source, isStreamingMode, err := buildSource(ctx, cfg, store)
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("Failed to init %q source", cfg.Source), slog.Any("err", err))
 	}
-	defer source.Close()
+	defer func() {
+		if err := source.Close(); err != nil {
+			slog.Warn("Failed to close source", slog.Any("err", err))
+		}
+	}()
+
+	destinationWriter, err := buildDestinationWriter(ctx, cfg, statsD, isStreamingMode)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to init %q destination writer", cfg.Destination), slog.Any("err", err))
+	}
 
 	logProgress := !isStreamingMode
-	writer := writers.New(destinationWriter, logProgress)
+	writer := writers.New(destinationWriter, logProgress, cfg.GetShutdownTimeout())
 
 	mode := "snapshot"
 	if isStreamingMode {
@@ -124,6 +156,11 @@ func main() {
 	slog.Info(fmt.Sprintf("Starting %s...", mode))
 
 	if err = source.Run(ctx, writer); err != nil {
+		if errors.Is(err, context.Canceled) {
+			slog.Info("Received shutdown signal, offsets have been committed, exiting...")
+			return
+		}
+
 		logger.Fatal(fmt.Sprintf("Failed to %s", mode),
 			slog.Any("err", err),
 			slog.String("source", string(cfg.Source)),
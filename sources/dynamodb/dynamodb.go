@@ -9,12 +9,13 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 
 	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 	"github.com/artie-labs/reader/sources"
 	"github.com/artie-labs/reader/sources/dynamodb/snapshot"
 	"github.com/artie-labs/reader/sources/dynamodb/stream"
 )
 
-func Load(ctx context.Context, cfg config.DynamoDB) (sources.Source, bool, error) {
+func Load(ctx context.Context, cfg config.DynamoDB, offsetStore offsetstore.OffsetStore) (sources.Source, bool, error) {
 	parsedArn, err := arn.Parse(cfg.StreamArn)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to parse stream ARN: %w", err)
@@ -37,6 +38,6 @@ func Load(ctx context.Context, cfg config.DynamoDB) (sources.Source, bool, error
 
 		return store, false, nil
 	} else {
-		return stream.NewStore(cfg, _awsCfg), true, nil
+		return stream.NewStore(cfg, _awsCfg, offsetStore), true, nil
 	}
 }
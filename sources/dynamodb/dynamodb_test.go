@@ -3,6 +3,7 @@ package dynamodb
 import (
 	"context"
 	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -19,7 +20,7 @@ func TestLoad(t *testing.T) {
 		SnapshotSettings:   nil,
 	}
 
-	_, _, err := Load(context.Background(), cfg)
+	_, _, err := Load(context.Background(), cfg, offsetstore.NewFileStore())
 	assert.NoError(t, err)
 
 	parsedArn, err := arn.Parse(cfg.StreamArn)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 	"github.com/artie-labs/reader/lib/storage/ttlmap"
 )
 
@@ -92,7 +93,12 @@ func (o *OffsetStorage) LastProcessedSequenceNumber(shardID string) (string, boo
 	return fmt.Sprint(sequenceNumber), true
 }
 
-func NewStorage(fp string, cleanUpIntervalOverride, flushIntervalOverride *time.Duration) *OffsetStorage {
+// Close flushes the offsets to disk.
+func (o *OffsetStorage) Close() error {
+	return o.ttlMap.Close()
+}
+
+func NewStorage(store offsetstore.OffsetStore, fp string, cleanUpIntervalOverride, flushIntervalOverride *time.Duration) *OffsetStorage {
 	cleanUpInterval := ttlmap.DefaultCleanUpInterval
 	if cleanUpIntervalOverride != nil {
 		cleanUpInterval = *cleanUpIntervalOverride
@@ -104,7 +110,7 @@ func NewStorage(fp string, cleanUpIntervalOverride, flushIntervalOverride *time.
 	}
 
 	offset := &OffsetStorage{
-		ttlMap: ttlmap.NewMap(fp, cleanUpInterval, flushInterval),
+		ttlMap: ttlmap.NewMap(store, fp, cleanUpInterval, flushInterval),
 	}
 	return offset
 }
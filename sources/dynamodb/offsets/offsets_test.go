@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 )
 
 func ptrDuration(d time.Duration) *time.Duration {
@@ -16,7 +18,7 @@ func ptrDuration(d time.Duration) *time.Duration {
 func TestOffsets_Complete(t *testing.T) {
 	offsetsFilePath := filepath.Join(t.TempDir(), "offsets-test")
 
-	storage := NewStorage(offsetsFilePath, ptrDuration(50*time.Millisecond), ptrDuration(50*time.Millisecond))
+	storage := NewStorage(offsetstore.NewFileStore(), offsetsFilePath, ptrDuration(50*time.Millisecond), ptrDuration(50*time.Millisecond))
 	processedShards := []string{"foo", "bar", "xyz"}
 
 	// It should all return `False` because the file doesn't exist and we didn't load anything yet.
@@ -40,7 +42,7 @@ func TestOffsets_Complete(t *testing.T) {
 
 	// Sleep, wait for the file to be committed to disk and then reload the storage.
 	time.Sleep(75 * time.Millisecond) // Wait for the file to be written.
-	storage = NewStorage(offsetsFilePath, ptrDuration(50*time.Millisecond), ptrDuration(50*time.Millisecond))
+	storage = NewStorage(offsetstore.NewFileStore(), offsetsFilePath, ptrDuration(50*time.Millisecond), ptrDuration(50*time.Millisecond))
 	for _, processedShard := range processedShards {
 		assert.True(t, storage.GetShardProcessed(processedShard),
 			fmt.Sprintf("shard: %s, value: %v", processedShard, storage.GetShardProcessed(processedShard)))
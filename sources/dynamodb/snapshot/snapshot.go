@@ -89,7 +89,7 @@ func (s *Store) Run(ctx context.Context, writer writers.Writer) error {
 
 	ch := make(chan map[string]types.AttributeValue)
 	go func() {
-		if err = s.s3Client.StreamJsonGzipFiles(ctx, s.cfg.SnapshotSettings.SpecifiedFiles, ch); err != nil {
+		if err := s.s3Client.StreamJsonGzipFiles(ctx, s.cfg.SnapshotSettings.SpecifiedFiles, ch); err != nil && ctx.Err() == nil {
 			logger.Panic("Failed to read file", slog.Any("err", err))
 		}
 	}()
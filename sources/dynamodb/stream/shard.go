@@ -2,8 +2,10 @@ package stream
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/artie-labs/transfer/lib/jitter"
@@ -20,13 +22,26 @@ import (
 
 const maxNumErrs = 25
 
+// ListenToChannel processes shards from [s.shardChan] until it is closed, it then waits for all the shards that are in-flight to return.
 func (s *Store) ListenToChannel(ctx context.Context, writer writers.Writer) {
+	var wg sync.WaitGroup
 	for shard := range s.shardChan {
-		go s.processShard(ctx, shard, writer, 0)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.processShard(ctx, shard, writer, 0)
+		}()
 	}
+
+	wg.Wait()
 }
 
 func (s *Store) reprocessShard(ctx context.Context, shard types.Shard, writer writers.Writer, numErrs int, err error) {
+	if ctx.Err() != nil {
+		// We are shutting down, the shard will be picked up again from the last processed sequence number.
+		return
+	}
+
 	if numErrs > maxNumErrs {
 		logger.Panic(fmt.Sprintf("Failed to process shard: %s and the max number of attempts have been reached", *shard.ShardId), err)
 	}
@@ -44,6 +59,10 @@ func (s *Store) reprocessShard(ctx context.Context, shard types.Shard, writer wr
 }
 
 func (s *Store) processShard(ctx context.Context, shard types.Shard, writer writers.Writer, numErrs int) {
+	if ctx.Err() != nil {
+		return
+	}
+
 	// Is there another go-routine processing this shard?
 	if s.storage.GetShardProcessing(*shard.ShardId) {
 		return
@@ -95,6 +114,11 @@ func (s *Store) processShard(ctx context.Context, shard types.Shard, writer writ
 	shardIterator := iteratorOutput.ShardIterator
 	// Get records from shard iterator
 	for shardIterator != nil {
+		if ctx.Err() != nil {
+			slog.Info("Stopping shard processing since the context is done", slog.String("shardId", *shard.ShardId))
+			return
+		}
+
 		getRecordsInput := &dynamodbstreams.GetRecordsInput{
 			ShardIterator: shardIterator,
 			Limit:         typing.ToPtr(int32(1000)),
@@ -121,6 +145,11 @@ func (s *Store) processShard(ctx context.Context, shard types.Shard, writer writ
 		}
 
 		if _, err = writer.Write(ctx, iterator.Once(messages)); err != nil {
+			if errors.Is(err, context.Canceled) {
+				// The records were not written, so we don't move the sequence number forward.
+				return
+			}
+
 			logger.Panic("Failed to publish messages, exiting...", slog.Any("err", err))
 		}
 
@@ -11,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
 
 	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 	"github.com/artie-labs/reader/sources/dynamodb/offsets"
 	"github.com/artie-labs/reader/writers"
 )
@@ -30,18 +31,22 @@ type Store struct {
 	shardChan chan types.Shard
 }
 
-func NewStore(cfg config.DynamoDB, awsCfg aws.Config) *Store {
+func NewStore(cfg config.DynamoDB, awsCfg aws.Config, offsetStore offsetstore.OffsetStore) *Store {
 	return &Store{
 		tableName: cfg.TableName,
 		streamArn: cfg.StreamArn,
 		cfg:       &cfg,
 		streams:   dynamodbstreams.NewFromConfig(awsCfg),
-		storage:   offsets.NewStorage(cfg.OffsetFile, nil, nil),
+		storage:   offsets.NewStorage(offsetStore, cfg.OffsetFile, nil, nil),
 		shardChan: make(chan types.Shard),
 	}
 }
 
 func (s *Store) Close() error {
+	if err := s.storage.Close(); err != nil {
+		return fmt.Errorf("failed to flush offsets: %w", err)
+	}
+
 	return nil
 }
 
@@ -49,7 +54,11 @@ func (s *Store) Run(ctx context.Context, writer writers.Writer) error {
 	ticker := time.NewTicker(shardScannerInterval)
 
 	// Start to subscribe to the channel
-	go s.ListenToChannel(ctx, writer)
+	listenerDone := make(chan struct{})
+	go func() {
+		s.ListenToChannel(ctx, writer)
+		close(listenerDone)
+	}()
 
 	// Scan it for the first time manually, so we don't have to wait 5 mins
 	if err := s.scanForNewShards(ctx); err != nil {
@@ -59,8 +68,9 @@ func (s *Store) Run(ctx context.Context, writer writers.Writer) error {
 		select {
 		case <-ctx.Done():
 			close(s.shardChan)
-			slog.Info("Terminating process...")
-			return nil
+			slog.Info("Terminating process, waiting for shards to finish processing...")
+			<-listenerDone
+			return ctx.Err()
 		case <-ticker.C:
 			slog.Info("Scanning for new shards...")
 			if err := s.scanForNewShards(ctx); err != nil {
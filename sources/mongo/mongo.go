@@ -10,16 +10,20 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 
 	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/checkpoint"
 	mongoLib "github.com/artie-labs/reader/lib/mongo"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+	"github.com/artie-labs/reader/lib/storage/persistedmap"
 	"github.com/artie-labs/reader/writers"
 )
 
 type Source struct {
-	cfg config.MongoDB
-	db  *mongo.Database
+	cfg   config.MongoDB
+	db    *mongo.Database
+	store offsetstore.OffsetStore
 }
 
-func Load(ctx context.Context, cfg config.MongoDB) (*Source, bool, error) {
+func Load(ctx context.Context, cfg config.MongoDB, store offsetstore.OffsetStore) (*Source, bool, error) {
 	opts, err := mongoLib.OptsFromConfig(cfg)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to build options for MongoDB: %w", err)
@@ -37,7 +41,7 @@ func Load(ctx context.Context, cfg config.MongoDB) (*Source, bool, error) {
 		return nil, false, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
-	return &Source{cfg: cfg, db: client.Database(cfg.Database)}, cfg.StreamingSettings.Enabled, nil
+	return &Source{cfg: cfg, db: client.Database(cfg.Database), store: store}, cfg.StreamingSettings.Enabled, nil
 }
 
 func (s *Source) Close() error {
@@ -46,34 +50,106 @@ func (s *Source) Close() error {
 }
 
 func (s *Source) Run(ctx context.Context, writer writers.Writer) error {
-	if s.cfg.StreamingSettings.Enabled {
-		iterator, err := newStreamingIterator(ctx, s.db, s.cfg, s.cfg.StreamingSettings.OffsetFile)
-		if err != nil {
+	if !s.cfg.StreamingSettings.Enabled {
+		return s.snapshot(ctx, writer)
+	}
+
+	if s.cfg.StreamingSettings.InitialSnapshot {
+		if err := s.initialSnapshot(ctx, writer); err != nil {
 			return err
 		}
+	}
 
-		if _, err = writer.Write(ctx, iterator); err != nil {
-			return fmt.Errorf("failed to stream: %w", err)
-		}
+	iterator, err := newStreamingIterator(ctx, s.db, s.cfg, s.store, s.cfg.StreamingSettings.OffsetFile)
+	if err != nil {
+		return err
+	}
+
+	if _, err = writer.Write(ctx, iterator); err != nil {
+		return fmt.Errorf("failed to stream: %w", err)
+	}
+
+	return nil
+}
+
+// initialSnapshot snapshots every collection if streaming has not started yet. The change stream position is captured
+// and persisted before the snapshot starts so that writes made during the snapshot are streamed afterwards, even if the
+// snapshot is interrupted and has to be resumed.
+func (s *Source) initialSnapshot(ctx context.Context, writer writers.Writer) error {
+	offsets := persistedmap.NewPersistedMap[string](s.store, s.cfg.StreamingSettings.OffsetFile)
+	return runInitialSnapshot(offsets,
+		func() (string, error) { return captureResumeToken(ctx, s.db) },
+		func() error { return s.snapshot(ctx, writer) },
+	)
+}
+
+// runInitialSnapshot persists the position from [captureToken] under [snapshotOffsetKey], runs [snapshot] and then hands
+// the position off to streaming by moving it to [offsetKey].
+func runInitialSnapshot(offsets *persistedmap.PersistedMap[string], captureToken func() (string, error), snapshot func() error) error {
+	if _, isOk := offsets.Get(offsetKey); isOk {
+		slog.Info("Found a streaming offset, skipping the initial snapshot")
+		return nil
+	}
+
+	token, isOk := offsets.Get(snapshotOffsetKey)
+	if isOk {
+		slog.Info("Resuming the initial snapshot, streaming will start from the position captured before it started")
 	} else {
-		for _, collection := range s.cfg.Collections {
-			snapshotStartTime := time.Now()
-
-			slog.Info("Scanning collection",
-				slog.String("collectionName", collection.Name),
-				slog.String("topicSuffix", collection.TopicSuffix(s.db.Name())),
-				slog.Any("batchSize", collection.GetBatchSize()),
-			)
-
-			iterator := NewSnapshotIterator(s.db, collection, s.cfg)
-			count, err := writer.Write(ctx, iterator)
-			if err != nil {
-				return fmt.Errorf("failed to snapshot collection %q: %w", collection.Name, err)
+		var err error
+		if token, err = captureToken(); err != nil {
+			return fmt.Errorf("failed to capture change stream position: %w", err)
+		}
+
+		if err = offsets.Set(snapshotOffsetKey, token); err != nil {
+			return fmt.Errorf("failed to save change stream position: %w", err)
+		}
+	}
+
+	if err := snapshot(); err != nil {
+		return err
+	}
+
+	if err := offsets.Set(offsetKey, token); err != nil {
+		return fmt.Errorf("failed to save offset: %w", err)
+	}
+
+	return offsets.Delete(snapshotOffsetKey)
+}
+
+func (s *Source) snapshot(ctx context.Context, writer writers.Writer) error {
+	checkpoints := checkpoint.NewStore(s.store, s.cfg.SnapshotCheckpointFile)
+	for _, collection := range s.cfg.Collections {
+		snapshotStartTime := time.Now()
+		if checkpoints.IsDone(collection.TopicSuffix(s.db.Name())) {
+			slog.Info("Skipping collection, it has already been snapshotted", slog.String("collectionName", collection.Name))
+			continue
+		}
+
+		slog.Info("Scanning collection",
+			slog.String("collectionName", collection.Name),
+			slog.String("topicSuffix", collection.TopicSuffix(s.db.Name())),
+			slog.Any("batchSize", collection.GetBatchSize()),
+		)
+
+		if checkpoints.IsResuming(collection.TopicSuffix(s.db.Name())) {
+			if err := writer.OnResume(collection.TopicSuffix(s.db.Name())); err != nil {
+				return err
 			}
+		}
 
-			slog.Info("Finished snapshotting", slog.Int("scannedTotal", count), slog.Duration("totalDuration", time.Since(snapshotStartTime)))
+		iterator := newSnapshotIterator(s.db, collection, s.cfg, checkpoints)
+		count, err := writer.Write(ctx, iterator)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot collection %q: %w", collection.Name, err)
 		}
+
+		if err = checkpoints.MarkDone(collection.TopicSuffix(s.db.Name())); err != nil {
+			return err
+		}
+
+		slog.Info("Finished snapshotting", slog.Int("scannedTotal", count), slog.Duration("totalDuration", time.Since(snapshotStartTime)))
 	}
 
-	return nil
+	// Every collection has been snapshotted, the next run should start from scratch.
+	return checkpoints.Clear()
 }
@@ -0,0 +1,111 @@
+package mongo
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+	"github.com/artie-labs/reader/lib/storage/persistedmap"
+)
+
+func TestRunInitialSnapshot(t *testing.T) {
+	store := offsetstore.NewFileStore()
+	newOffsets := func(namespace string) *persistedmap.PersistedMap[string] {
+		return persistedmap.NewPersistedMap[string](store, namespace)
+	}
+
+	{
+		// The position is persisted before the snapshot and handed off to streaming once it's done
+		namespace := filepath.Join(t.TempDir(), "offsets.yaml")
+		offsets := newOffsets(namespace)
+		var steps []string
+		err := runInitialSnapshot(offsets,
+			func() (string, error) {
+				steps = append(steps, "capture")
+				return "token", nil
+			},
+			func() error {
+				// The position has been persisted, but streaming has not been handed off yet.
+				persisted := newOffsets(namespace)
+				token, isOk := persisted.Get(snapshotOffsetKey)
+				assert.True(t, isOk)
+				assert.Equal(t, "token", token)
+				_, isOk = persisted.Get(offsetKey)
+				assert.False(t, isOk)
+
+				steps = append(steps, "snapshot")
+				return nil
+			},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"capture", "snapshot"}, steps)
+
+		persisted := newOffsets(namespace)
+		token, isOk := persisted.Get(offsetKey)
+		assert.True(t, isOk)
+		assert.Equal(t, "token", token)
+		_, isOk = persisted.Get(snapshotOffsetKey)
+		assert.False(t, isOk)
+	}
+	{
+		// Snapshot fails, the position is kept so that the next run resumes from it
+		namespace := filepath.Join(t.TempDir(), "offsets.yaml")
+		err := runInitialSnapshot(newOffsets(namespace),
+			func() (string, error) { return "token", nil },
+			func() error { return fmt.Errorf("snapshot failed") },
+		)
+		assert.ErrorContains(t, err, "snapshot failed")
+
+		persisted := newOffsets(namespace)
+		_, isOk := persisted.Get(offsetKey)
+		assert.False(t, isOk)
+		token, isOk := persisted.Get(snapshotOffsetKey)
+		assert.True(t, isOk)
+		assert.Equal(t, "token", token)
+
+		// Resumed snapshot uses the position that was captured by the first run
+		var snapshotted bool
+		err = runInitialSnapshot(persisted,
+			func() (string, error) { return "", fmt.Errorf("position should not be captured again") },
+			func() error {
+				snapshotted = true
+				return nil
+			},
+		)
+		assert.NoError(t, err)
+		assert.True(t, snapshotted)
+
+		persisted = newOffsets(namespace)
+		token, isOk = persisted.Get(offsetKey)
+		assert.True(t, isOk)
+		assert.Equal(t, "token", token)
+		_, isOk = persisted.Get(snapshotOffsetKey)
+		assert.False(t, isOk)
+	}
+	{
+		// Streaming has already started, the snapshot is skipped
+		namespace := filepath.Join(t.TempDir(), "offsets.yaml")
+		offsets := newOffsets(namespace)
+		assert.NoError(t, offsets.Set(offsetKey, "streaming"))
+		err := runInitialSnapshot(offsets,
+			func() (string, error) { return "", fmt.Errorf("position should not be captured") },
+			func() error { return fmt.Errorf("snapshot should not run") },
+		)
+		assert.NoError(t, err)
+
+		token, isOk := newOffsets(namespace).Get(offsetKey)
+		assert.True(t, isOk)
+		assert.Equal(t, "streaming", token)
+	}
+	{
+		// Position cannot be captured
+		err := runInitialSnapshot(newOffsets(filepath.Join(t.TempDir(), "offsets.yaml")),
+			func() (string, error) { return "", fmt.Errorf("failed to watch") },
+			func() error { return fmt.Errorf("snapshot should not run") },
+		)
+		assert.ErrorContains(t, err, "failed to capture change stream position: failed to watch")
+	}
+}
@@ -3,34 +3,129 @@ package mongo
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"slices"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/checkpoint"
 	"github.com/artie-labs/reader/lib/iterator"
 	"github.com/artie-labs/reader/lib/kafkalib"
 	mongoLib "github.com/artie-labs/reader/lib/mongo"
 )
 
+// typeBrackets - BSON types in the order that MongoDB sorts them, types in the same bracket are compared by value.
+// Comparison operators only match values of the same bracket, see https://www.mongodb.com/docs/manual/reference/bson-type-comparison-order/
+var typeBrackets = [][]bsontype.Type{
+	{bsontype.MinKey},
+	{bsontype.Null, bsontype.Undefined},
+	{bsontype.Int32, bsontype.Int64, bsontype.Double, bsontype.Decimal128},
+	{bsontype.String, bsontype.Symbol},
+	{bsontype.EmbeddedDocument},
+	{bsontype.Array},
+	{bsontype.Binary},
+	{bsontype.ObjectID},
+	{bsontype.Boolean},
+	{bsontype.DateTime},
+	{bsontype.Timestamp},
+	{bsontype.Regex},
+	{bsontype.DBPointer},
+	{bsontype.JavaScript},
+	{bsontype.CodeWithScope},
+	{bsontype.MaxKey},
+}
+
+// resumeFilter returns the filter of the documents that are sorted after [lastID]. Since `$gt` only matches IDs of
+// the same type bracket, IDs of the brackets that are sorted after it are matched by their type.
+func resumeFilter(lastID any) (bson.E, error) {
+	idType, _, err := bson.MarshalValue(lastID)
+	if err != nil {
+		return bson.E{}, fmt.Errorf("failed to marshal last id: %w", err)
+	}
+
+	bracketIdx := slices.IndexFunc(typeBrackets, func(bracket []bsontype.Type) bool { return slices.Contains(bracket, idType) })
+	if bracketIdx == -1 {
+		return bson.E{}, fmt.Errorf("unsupported last id type %q", idType)
+	}
+
+	greaterThan := bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: lastID}}}}
+	var laterTypes bson.A
+	for _, bracket := range typeBrackets[bracketIdx+1:] {
+		for _, laterType := range bracket {
+			laterTypes = append(laterTypes, int32(laterType))
+		}
+	}
+
+	if len(laterTypes) == 0 {
+		return greaterThan[0], nil
+	}
+
+	return bson.E{Key: "$or", Value: bson.A{greaterThan, bson.D{{Key: "_id", Value: bson.D{{Key: "$type", Value: laterTypes}}}}}}, nil
+}
+
 type snapshotIterator struct {
 	db         *mongo.Database
 	cfg        config.MongoDB
 	collection config.Collection
+	// checkpoints - Snapshot progress, this is nil if checkpointing is disabled.
+	checkpoints *checkpoint.Store
 
 	// mutable
 	cursor *mongo.Cursor
 	done   bool
+	// lastID - ID of the last document that has been read.
+	lastID any
 }
 
 func NewSnapshotIterator(db *mongo.Database, collection config.Collection, cfg config.MongoDB) iterator.Iterator[[]kafkalib.Message] {
+	return newSnapshotIterator(db, collection, cfg, nil)
+}
+
+func newSnapshotIterator(db *mongo.Database, collection config.Collection, cfg config.MongoDB, checkpoints *checkpoint.Store) iterator.StreamingIterator[[]kafkalib.Message] {
 	return &snapshotIterator{
-		db:         db,
-		cfg:        cfg,
-		collection: collection,
+		db:          db,
+		cfg:         cfg,
+		collection:  collection,
+		checkpoints: checkpoints,
+	}
+}
+
+func (s *snapshotIterator) topicSuffix() string {
+	return s.collection.TopicSuffix(s.db.Name())
+}
+
+// lastCheckpointedID returns the ID of the last document that was written before the snapshot was interrupted. The
+// checkpoint is canonical Extended JSON, so the ID has the same BSON type as it had in the collection.
+func (s *snapshotIterator) lastCheckpointedID() (any, bool, error) {
+	lastCheckpoint, isOk := s.checkpoints.Get(s.topicSuffix())
+	if !isOk || len(lastCheckpoint.LastKey) != 1 || lastCheckpoint.LastKey[0].Type != checkpoint.ExtJSON {
+		return nil, false, nil
+	}
+
+	var doc bson.M
+	if err := bson.UnmarshalExtJSON([]byte(lastCheckpoint.LastKey[0].Value), true, &doc); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+	}
+
+	return doc["_id"], true, nil
+}
+
+func (s *snapshotIterator) CommitOffset() error {
+	if s.checkpoints == nil || s.lastID == nil {
+		return nil
+	}
+
+	bytes, err := bson.MarshalExtJSON(bson.D{{Key: "_id", Value: s.lastID}}, true, false)
+	if err != nil {
+		return fmt.Errorf("failed to marshal last id: %w", err)
 	}
+
+	return s.checkpoints.SaveLastKey(s.topicSuffix(), []checkpoint.Value{{Type: checkpoint.ExtJSON, Value: string(bytes)}})
 }
 
 func (s *snapshotIterator) HasNext() bool {
@@ -67,6 +162,25 @@ func (s *snapshotIterator) Next() ([]kafkalib.Message, error) {
 		// Find options
 		findOptions := options.Find()
 		findOptions.SetBatchSize(s.collection.GetBatchSize())
+		if s.checkpoints != nil {
+			// Documents need to be read in order so that the snapshot can be resumed from the last one.
+			findOptions.SetSort(bson.D{{Key: "_id", Value: 1}})
+
+			lastID, isOk, err := s.lastCheckpointedID()
+			if err != nil {
+				return nil, err
+			}
+
+			if isOk {
+				slog.Info("Resuming snapshot from checkpoint", slog.String("collection", s.collection.Name), slog.Any("lastID", lastID))
+				resumeFrom, err := resumeFilter(lastID)
+				if err != nil {
+					return nil, err
+				}
+				filter = append(filter, resumeFrom)
+			}
+		}
+
 		cursor, err := s.db.Collection(s.collection.Name).Find(ctx, filter, findOptions)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find documents: %w", err)
@@ -93,6 +207,7 @@ func (s *snapshotIterator) Next() ([]kafkalib.Message, error) {
 		}
 
 		rawMsgs = append(rawMsgs, rawMsg)
+		s.lastID = result["_id"]
 	}
 
 	if err := s.cursor.Err(); err != nil {
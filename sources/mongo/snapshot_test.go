@@ -0,0 +1,92 @@
+package mongo
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/checkpoint"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+)
+
+func TestResumeFilter(t *testing.T) {
+	{
+		// Numbers, every type that is sorted after numbers is included
+		filter, err := resumeFilter(int32(5))
+		assert.NoError(t, err)
+		assert.Equal(t, "$or", filter.Key)
+
+		conditions, ok := filter.Value.(bson.A)
+		assert.True(t, ok)
+		assert.Len(t, conditions, 2)
+		assert.Equal(t, bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: int32(5)}}}}, conditions[0])
+		assert.Equal(t, bson.D{{Key: "_id", Value: bson.D{{Key: "$type", Value: bson.A{
+			int32(bsontype.String), int32(bsontype.Symbol), int32(bsontype.EmbeddedDocument), int32(bsontype.Array),
+			int32(bsontype.Binary), int32(bsontype.ObjectID), int32(bsontype.Boolean), int32(bsontype.DateTime),
+			int32(bsontype.Timestamp), int32(bsontype.Regex), int32(bsontype.DBPointer), int32(bsontype.JavaScript),
+			int32(bsontype.CodeWithScope), int32(bsontype.MaxKey),
+		}}}}}, conditions[1])
+	}
+	{
+		// Other numeric types are in the same bracket
+		intFilter, err := resumeFilter(int32(5))
+		assert.NoError(t, err)
+		doubleFilter, err := resumeFilter(float64(5.5))
+		assert.NoError(t, err)
+		assert.Equal(t, intFilter.Value.(bson.A)[1], doubleFilter.Value.(bson.A)[1])
+	}
+	{
+		// Object IDs
+		objectID, err := primitive.ObjectIDFromHex("65d5f3e1e3b5a1b2c3d4e5f6")
+		assert.NoError(t, err)
+
+		filter, err := resumeFilter(objectID)
+		assert.NoError(t, err)
+		assert.Equal(t, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: objectID}}}},
+			bson.D{{Key: "_id", Value: bson.D{{Key: "$type", Value: bson.A{
+				int32(bsontype.Boolean), int32(bsontype.DateTime), int32(bsontype.Timestamp), int32(bsontype.Regex),
+				int32(bsontype.DBPointer), int32(bsontype.JavaScript), int32(bsontype.CodeWithScope), int32(bsontype.MaxKey),
+			}}}}},
+		}}, filter)
+	}
+	{
+		// Nothing is sorted after MaxKey
+		filter, err := resumeFilter(primitive.MaxKey{})
+		assert.NoError(t, err)
+		assert.Equal(t, bson.E{Key: "_id", Value: bson.D{{Key: "$gt", Value: primitive.MaxKey{}}}}, filter)
+	}
+}
+
+func TestSnapshotIterator_ResumeAcrossTypes(t *testing.T) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://localhost:27017"))
+	assert.NoError(t, err)
+	defer client.Disconnect(context.Background())
+
+	collection := config.Collection{Name: "foo"}
+	checkpoints := checkpoint.NewStore(offsetstore.NewFileStore(), filepath.Join(t.TempDir(), "checkpoints.yaml"))
+	for _, lastID := range []any{int32(5), int64(5), "5", primitive.NewObjectID()} {
+		iter := &snapshotIterator{db: client.Database("db"), collection: collection, checkpoints: checkpoints, lastID: lastID}
+		assert.NoError(t, iter.CommitOffset())
+
+		// The ID keeps its type, so that the documents of the brackets after it are still read
+		resumeID, isOk, err := iter.lastCheckpointedID()
+		assert.NoError(t, err)
+		assert.True(t, isOk)
+		assert.Equal(t, lastID, resumeID)
+
+		expected, err := resumeFilter(lastID)
+		assert.NoError(t, err)
+		actual, err := resumeFilter(resumeID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+}
@@ -15,36 +15,58 @@ import (
 	"github.com/artie-labs/reader/lib/iterator"
 	"github.com/artie-labs/reader/lib/kafkalib"
 	mongoLib "github.com/artie-labs/reader/lib/mongo"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 	"github.com/artie-labs/reader/lib/storage/persistedmap"
 )
 
-const offsetKey = "offset"
+const (
+	offsetKey = "offset"
+	// snapshotOffsetKey - Change stream position that was captured before the initial snapshot started, streaming will
+	// start from here once the snapshot has completed.
+	snapshotOffsetKey = "snapshotOffset"
+)
+
+// We only care about DMLs, the full list can be found here: https://www.mongodb.com/docs/manual/reference/change-events/
+var pipeline = mongo.Pipeline{
+	{{Key: "$match", Value: bson.D{
+		{Key: "operationType", Value: bson.D{
+			{Key: "$in", Value: bson.A{"insert", "update", "delete", "replace"}},
+		}},
+	}}},
+}
+
+// captureResumeToken opens a change stream to capture the current position, streaming from the returned token will
+// include every change that happens afterwards.
+func captureResumeToken(ctx context.Context, db *mongo.Database) (string, error) {
+	cs, err := db.Watch(ctx, pipeline)
+	if err != nil {
+		return "", fmt.Errorf("failed to start change stream: %w", err)
+	}
+	defer cs.Close(ctx)
+
+	token := cs.ResumeToken()
+	if token == nil {
+		return "", fmt.Errorf("change stream did not return a resume token, MongoDB 4.0.7 or later is required")
+	}
+
+	return base64.StdEncoding.EncodeToString(token), nil
+}
 
 type streaming struct {
 	db                    *mongo.Database
 	cfg                   config.MongoDB
 	changeStream          *mongo.ChangeStream
-	ctx                   context.Context
 	collectionsToWatchMap map[string]config.Collection
 	offsets               *persistedmap.PersistedMap[string]
 	batchSize             int32
 }
 
-func newStreamingIterator(ctx context.Context, db *mongo.Database, cfg config.MongoDB, filePath string) (iterator.StreamingIterator[[]kafkalib.Message], error) {
+func newStreamingIterator(ctx context.Context, db *mongo.Database, cfg config.MongoDB, store offsetstore.OffsetStore, filePath string) (iterator.StreamingIterator[[]kafkalib.Message], error) {
 	collectionsToWatchMap := make(map[string]config.Collection)
 	for _, collection := range cfg.Collections {
 		collectionsToWatchMap[collection.Name] = collection
 	}
 
-	// We only care about DMLs, the full list can be found here: https://www.mongodb.com/docs/manual/reference/change-events/
-	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.D{
-			{Key: "operationType", Value: bson.D{
-				{Key: "$in", Value: bson.A{"insert", "update", "delete", "replace"}},
-			}},
-		}}},
-	}
-
 	opts := options.ChangeStream().
 		// Setting `updateLookup` will emit the whole document for updates
 		// Ref: https://www.mongodb.com/docs/manual/reference/change-events/update/#description
@@ -55,7 +77,7 @@ func newStreamingIterator(ctx context.Context, db *mongo.Database, cfg config.Mo
 		opts = opts.SetFullDocumentBeforeChange(options.WhenAvailable)
 	}
 
-	storage := persistedmap.NewPersistedMap[string](filePath)
+	storage := persistedmap.NewPersistedMap[string](store, filePath)
 	if encodedResumeToken, exists := storage.Get(offsetKey); exists {
 		decodedBytes, err := base64.StdEncoding.DecodeString(encodedResumeToken)
 		if err != nil {
@@ -80,7 +102,6 @@ func newStreamingIterator(ctx context.Context, db *mongo.Database, cfg config.Mo
 		db:                    db,
 		cfg:                   cfg,
 		changeStream:          cs,
-		ctx:                   ctx,
 		collectionsToWatchMap: collectionsToWatchMap,
 		offsets:               storage,
 	}, nil
@@ -97,8 +118,12 @@ func (s *streaming) CommitOffset() error {
 }
 
 func (s *streaming) Next() ([]kafkalib.Message, error) {
+	return s.NextContext(context.Background())
+}
+
+func (s *streaming) NextContext(ctx context.Context) ([]kafkalib.Message, error) {
 	var rawMsgs []kafkalib.Message
-	for s.batchSize > int32(len(rawMsgs)) && s.changeStream.TryNext(s.ctx) {
+	for s.batchSize > int32(len(rawMsgs)) && s.changeStream.TryNext(ctx) {
 		var rawChangeEvent bson.M
 		if err := s.changeStream.Decode(&rawChangeEvent); err != nil {
 			return nil, fmt.Errorf("failed to decode change event: %w", err)
@@ -129,7 +154,7 @@ func (s *streaming) Next() ([]kafkalib.Message, error) {
 
 	if len(rawMsgs) == 0 {
 		// If there are no messages, let's sleep a bit before we try again
-		time.Sleep(2 * time.Second)
+		iterator.Sleep(ctx, 2*time.Second)
 	}
 
 	return rawMsgs, nil
@@ -3,6 +3,7 @@ package adapter
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/artie-labs/reader/config"
 	"github.com/artie-labs/reader/lib/debezium/converters"
@@ -26,7 +27,7 @@ type MSSQLAdapter struct {
 }
 
 func NewMSSQLAdapter(db *sql.DB, dbName string, tableCfg config.MSSQLTable) (MSSQLAdapter, error) {
-	table, err := mssql.LoadTable(db, tableCfg.Schema, tableCfg.Name)
+	table, err := mssql.LoadTable(db, tableCfg.Schema, tableCfg.Name, tableCfg.PrimaryKeysOverride)
 	if err != nil {
 		return MSSQLAdapter{}, fmt.Errorf("failed to load metadata for table %s.%s: %w", tableCfg.Schema, tableCfg.Name, err)
 	}
@@ -70,18 +71,63 @@ func (m MSSQLAdapter) TopicSuffix() string {
 	return fmt.Sprintf("%s.%s.%s", m.dbName, m.table.Schema, m.table.Name)
 }
 
+func (m MSSQLAdapter) Columns() []schema.Column {
+	return m.columns
+}
+
 func (m MSSQLAdapter) FieldConverters() []transformer.FieldConverter {
 	return m.fieldConverters
 }
 
 func (m MSSQLAdapter) NewIterator() (transformer.RowsIterator, error) {
-	return mssql.NewScanner(m.db, m.table, m.columns, m.scannerCfg)
+	if len(m.table.PrimaryKeys()) == 0 {
+		// Tables without a key are read in full, see [transformer.RowHashKey] for how they are partitioned.
+		keylessScanner, err := mssql.NewKeylessScanner(m.db, m.table, m.columns, m.scannerCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return keylessScanner, nil
+	}
+
+	tableScanner, err := mssql.NewScanner(m.db, m.table, m.columns, m.scannerCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return tableScanner.Parallelize()
 }
 
 func (m MSSQLAdapter) PartitionKeys() []string {
+	if len(m.table.PrimaryKeys()) == 0 {
+		return []string{transformer.RowHashKey}
+	}
 	return m.table.PrimaryKeys()
 }
 
+func (m MSSQLAdapter) NewPoller(cursorColumn string, lag time.Duration) (*scan.Poller, error) {
+	cfg := scan.PollerConfig{BatchSize: m.scannerCfg.BatchSize, ErrorRetries: m.scannerCfg.ErrorRetries, Lag: lag}
+	return scan.NewPoller(m.db, cursorColumn, m.table.PrimaryKeys(), cfg, mssql.NewPollAdapter(m.table, m.columns))
+}
+
+func (m MSSQLAdapter) NewPrimaryKeysIterator() (transformer.RowsIterator, error) {
+	columns, err := column.FilterForIncludedColumns(m.columns, m.table.PrimaryKeys(), m.table.PrimaryKeys())
+	if err != nil {
+		return nil, err
+	}
+
+	// Primary key overrides are not applied, otherwise rows outside of them would look like they have been deleted.
+	tableScanner, err := mssql.NewScanner(m.db, m.table, columns, scan.ScannerConfig{
+		BatchSize:    m.scannerCfg.BatchSize,
+		ErrorRetries: m.scannerCfg.ErrorRetries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tableScanner, nil
+}
+
 func valueConverterForType(dataType schema.DataType, opts *schema.Opts) (converters.ValueConverter, error) {
 	switch dataType {
 	case schema.Bit:
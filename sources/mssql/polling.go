@@ -0,0 +1,53 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/artie-labs/transfer/lib/cdc/util"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/rdbms/poll"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+	"github.com/artie-labs/reader/sources/mssql/adapter"
+	"github.com/artie-labs/reader/writers"
+)
+
+// Polling - Streams changes by polling each table with its cursor column, this is used when CDC tables cannot be read.
+type Polling struct {
+	iterator *poll.Iterator
+	db       *sql.DB
+}
+
+func buildPolling(db *sql.DB, cfg config.MSSQL, store offsetstore.OffsetStore) (Polling, error) {
+	var tables []poll.Table
+	for _, tableCfg := range cfg.Tables {
+		dbzAdapter, err := adapter.NewMSSQLAdapter(db, cfg.Database, *tableCfg)
+		if err != nil {
+			return Polling{}, fmt.Errorf("failed to create MSSQL adapter: %w", err)
+		}
+
+		tables = append(tables, poll.Table{
+			Adapter:      dbzAdapter,
+			CursorColumn: tableCfg.CursorColumn,
+			Source:       util.Source{Connector: "sqlserver", Database: cfg.Database, Schema: tableCfg.Schema},
+		})
+	}
+
+	iter, err := poll.NewIterator(tables, cfg.PollingSettings, store)
+	if err != nil {
+		return Polling{}, err
+	}
+
+	return Polling{iterator: iter, db: db}, nil
+}
+
+func (p Polling) Close() error {
+	return p.db.Close()
+}
+
+func (p Polling) Run(ctx context.Context, writer writers.Writer) error {
+	_, err := writer.Write(ctx, p.iterator)
+	return err
+}
@@ -11,9 +11,13 @@ import (
 	_ "github.com/microsoft/go-mssqldb"
 
 	"github.com/artie-labs/reader/config"
-	"github.com/artie-labs/reader/lib/debezium/transformer"
+	"github.com/artie-labs/reader/lib/checkpoint"
+	"github.com/artie-labs/reader/lib/iterator"
 	"github.com/artie-labs/reader/lib/rdbms"
+	"github.com/artie-labs/reader/lib/snapshot"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 	"github.com/artie-labs/reader/lib/transfer"
+	"github.com/artie-labs/reader/sources"
 	"github.com/artie-labs/reader/sources/mssql/adapter"
 	"github.com/artie-labs/reader/writers"
 )
@@ -21,18 +25,39 @@ import (
 type Snapshot struct {
 	cfg config.MSSQL
 	db  *sql.DB
+	// checkpoints - Snapshot progress, this is nil if checkpointing is disabled.
+	checkpoints *checkpoint.Store
 }
 
-func Load(cfg config.MSSQL) (*Snapshot, error) {
+func Load(ctx context.Context, cfg config.MSSQL, store offsetstore.OffsetStore) (sources.Source, bool, error) {
 	db, err := sql.Open("mssql", cfg.ToDSN())
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to MSSQL: %w", err)
+		return nil, false, fmt.Errorf("failed to connect to MSSQL: %w", err)
+	}
+
+	if cfg.StreamingSettings.Enabled {
+		stream, err := buildStreaming(ctx, db, cfg, store)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to build streaming config: %w", err)
+		}
+
+		return stream, true, nil
+	}
+
+	if cfg.PollingSettings.Enabled {
+		polling, err := buildPolling(db, cfg, store)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to build polling config: %w", err)
+		}
+
+		return polling, true, nil
 	}
 
 	return &Snapshot{
-		cfg: cfg,
-		db:  db,
-	}, nil
+		cfg:         cfg,
+		db:          db,
+		checkpoints: checkpoint.NewStore(store, cfg.SnapshotCheckpointFile),
+	}, false, nil
 }
 
 func (s *Snapshot) Close() error {
@@ -40,45 +65,81 @@ func (s *Snapshot) Close() error {
 }
 
 func (s *Snapshot) Run(ctx context.Context, writer writers.Writer) error {
-	for _, tableCfg := range s.cfg.Tables {
-		logger := slog.With(slog.String("schema", tableCfg.Schema), slog.String("table", tableCfg.Name))
-		snapshotStartTime := time.Now()
-
-		dbzAdapter, err := adapter.NewMSSQLAdapter(s.db, s.cfg.Database, *tableCfg)
-		if err != nil {
-			return fmt.Errorf("failed to create MSSQL adapter: %w", err)
+	tables := make([]snapshot.Table, len(s.cfg.Tables))
+	for i, tableCfg := range s.cfg.Tables {
+		tables[i] = snapshot.Table{
+			Name: fmt.Sprintf("%s.%s", tableCfg.Schema, tableCfg.Name),
+			Run: func(ctx context.Context) (int, error) {
+				return s.snapshotTable(ctx, writer, *tableCfg)
+			},
 		}
+	}
 
-		dbzTransformer, err := transformer.NewDebeziumTransformer(dbzAdapter)
-		if err != nil {
-			if errors.Is(err, rdbms.ErrNoPkValuesForEmptyTable) {
-				cols, err := transfer.BuildTransferColumns(dbzAdapter)
-				if err != nil {
-					return fmt.Errorf("failed to build transfer columns: %w", err)
-				}
-
-				if err = writer.CreateTable(ctx, dbzAdapter.TableName(), cols); err != nil {
-					return fmt.Errorf("failed to create table: %w", err)
-				}
-
-				logger.Info("Table has been created, it does not contain any rows")
-				continue
-			} else {
-				return fmt.Errorf("failed to build Debezium transformer for table %q: %w", tableCfg.Name, err)
+	if _, err := snapshot.Run(ctx, tables, s.cfg.MaxConcurrentTables); err != nil {
+		return err
+	}
+
+	// Every table has been snapshotted, the next run should start from scratch.
+	return s.checkpoints.Clear()
+}
+
+func (s *Snapshot) snapshotTable(ctx context.Context, writer writers.Writer, tableCfg config.MSSQLTable) (int, error) {
+	logger := slog.With(slog.String("schema", tableCfg.Schema), slog.String("table", tableCfg.Name))
+	snapshotStartTime := time.Now()
+
+	dbzAdapter, err := adapter.NewMSSQLAdapter(s.db, s.cfg.Database, tableCfg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create MSSQL adapter: %w", err)
+	}
+
+	if s.checkpoints.IsDone(dbzAdapter.TopicSuffix()) {
+		logger.Info("Skipping table, it has already been snapshotted")
+		return 0, nil
+	}
+
+	dbzTransformer, err := s.checkpoints.NewTransformer(dbzAdapter)
+	if err != nil {
+		if errors.Is(err, rdbms.ErrNoPkValuesForEmptyTable) {
+			cols, err := transfer.BuildTransferColumns(dbzAdapter)
+			if err != nil {
+				return 0, fmt.Errorf("failed to build transfer columns: %w", err)
+			}
+
+			if err = writer.CreateTable(ctx, dbzAdapter.TopicSuffix(), dbzAdapter.TableName(), cols); err != nil {
+				return 0, fmt.Errorf("failed to create table: %w", err)
 			}
+
+			if err = s.checkpoints.MarkDone(dbzAdapter.TopicSuffix()); err != nil {
+				return 0, err
+			}
+
+			logger.Info("Table has been created, it does not contain any rows")
+			return 0, nil
+		} else {
+			return 0, fmt.Errorf("failed to build Debezium transformer for table %q: %w", tableCfg.Name, err)
 		}
+	}
+	defer iterator.Close(dbzTransformer)
 
-		logger.Info("Scanning table...", slog.Any("batchSize", tableCfg.GetBatchSize()))
-		count, err := writer.Write(ctx, dbzTransformer)
-		if err != nil {
-			return fmt.Errorf("failed to snapshot table %q: %w", tableCfg.Name, err)
+	logger.Info("Scanning table...", slog.Any("batchSize", tableCfg.GetBatchSize()))
+	if s.checkpoints.IsResuming(dbzAdapter.TopicSuffix()) {
+		if err = writer.OnResume(dbzAdapter.TopicSuffix()); err != nil {
+			return 0, err
 		}
+	}
+
+	count, err := writer.Write(ctx, dbzTransformer)
+	if err != nil {
+		return 0, fmt.Errorf("failed to snapshot table %q: %w", tableCfg.Name, err)
+	}
 
-		logger.Info("Finished snapshotting",
-			slog.Int("scannedTotal", count),
-			slog.Duration("totalDuration", time.Since(snapshotStartTime)),
-		)
+	if err = s.checkpoints.MarkDone(dbzAdapter.TopicSuffix()); err != nil {
+		return 0, err
 	}
 
-	return nil
+	logger.Info("Finished snapshotting",
+		slog.Int("scannedTotal", count),
+		slog.Duration("totalDuration", time.Since(snapshotStartTime)),
+	)
+	return count, nil
 }
@@ -0,0 +1,42 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+	"github.com/artie-labs/reader/sources/mssql/streaming"
+	"github.com/artie-labs/reader/writers"
+)
+
+type Streaming struct {
+	iterator *streaming.Iterator
+	db       *sql.DB
+}
+
+func buildStreaming(ctx context.Context, db *sql.DB, cfg config.MSSQL, store offsetstore.OffsetStore) (Streaming, error) {
+	iter, err := streaming.BuildStreamingIterator(ctx, db, cfg, store)
+	if err != nil {
+		return Streaming{}, err
+	}
+
+	return Streaming{
+		db:       db,
+		iterator: iter,
+	}, nil
+}
+
+func (s Streaming) Close() error {
+	if err := s.iterator.Close(); err != nil {
+		return fmt.Errorf("failed to close iterator: %w", err)
+	}
+
+	return s.db.Close()
+}
+
+func (s Streaming) Run(ctx context.Context, writer writers.Writer) error {
+	_, err := writer.Write(ctx, s.iterator)
+	return err
+}
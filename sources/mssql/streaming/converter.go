@@ -0,0 +1,90 @@
+package streaming
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Values for `__$operation`, see https://learn.microsoft.com/en-us/sql/relational-databases/system-functions/cdc-fn-cdc-get-all-changes-capture-instance-transact-sql
+const (
+	operationDelete       = 1
+	operationInsert       = 2
+	operationUpdateBefore = 3
+	operationUpdateAfter  = 4
+)
+
+type changeRow struct {
+	startLSN   []byte
+	seqVal     []byte
+	operation  int64
+	commitTime time.Time
+	values     map[string]any
+}
+
+type changeEvent struct {
+	position   Position
+	operation  string
+	commitTime time.Time
+	before     map[string]any
+	after      map[string]any
+}
+
+// buildChangeEvents pairs update before and after images and maps `__$operation` to a Debezium operation. Both images
+// of an update have the same position, so they are always read in the same batch, see [buildChangesQuery].
+func buildChangeEvents(rows []changeRow) ([]changeEvent, error) {
+	// Rows are only ordered by position, the before image of an update has to come first.
+	slices.SortStableFunc(rows, func(a, b changeRow) int {
+		return cmp.Or(bytes.Compare(a.startLSN, b.startLSN), bytes.Compare(a.seqVal, b.seqVal), cmp.Compare(a.operation, b.operation))
+	})
+
+	var events []changeEvent
+	var pendingBefore *changeRow
+	for idx, row := range rows {
+		if pendingBefore != nil && row.operation != operationUpdateAfter {
+			skipUpdateBefore(*pendingBefore)
+			pendingBefore = nil
+		}
+
+		event := changeEvent{
+			position:   NewPosition(row.startLSN, row.seqVal),
+			commitTime: row.commitTime,
+		}
+
+		switch row.operation {
+		case operationDelete:
+			event.operation = "d"
+			event.before = row.values
+		case operationInsert:
+			event.operation = "c"
+			event.after = row.values
+		case operationUpdateBefore:
+			pendingBefore = &rows[idx]
+			continue
+		case operationUpdateAfter:
+			event.operation = "u"
+			event.after = row.values
+			if pendingBefore != nil && bytes.Equal(pendingBefore.startLSN, row.startLSN) && bytes.Equal(pendingBefore.seqVal, row.seqVal) {
+				event.before = pendingBefore.values
+			}
+		default:
+			return nil, fmt.Errorf("unexpected operation: %d", row.operation)
+		}
+
+		pendingBefore = nil
+		events = append(events, event)
+	}
+
+	if pendingBefore != nil {
+		skipUpdateBefore(*pendingBefore)
+	}
+
+	return events, nil
+}
+
+func skipUpdateBefore(row changeRow) {
+	slog.Warn("Skipping update before image without an after image", slog.String("position", NewPosition(row.startLSN, row.seqVal).String()))
+}
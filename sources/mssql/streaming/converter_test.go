@@ -0,0 +1,71 @@
+package streaming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/lib/mssql/schema"
+)
+
+func lsn(b byte) []byte {
+	return []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, b}
+}
+
+func TestBuildChangeEvents(t *testing.T) {
+	{
+		// No rows
+		events, err := buildChangeEvents(nil)
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	}
+	{
+		// Insert, update and delete
+		events, err := buildChangeEvents([]changeRow{
+			{startLSN: lsn(1), seqVal: lsn(1), operation: operationInsert, values: map[string]any{"id": int64(1), "name": "a"}},
+			{startLSN: lsn(2), seqVal: lsn(1), operation: operationUpdateBefore, values: map[string]any{"id": int64(1), "name": "a"}},
+			{startLSN: lsn(2), seqVal: lsn(1), operation: operationUpdateAfter, values: map[string]any{"id": int64(1), "name": "b"}},
+			{startLSN: lsn(3), seqVal: lsn(1), operation: operationDelete, values: map[string]any{"id": int64(1), "name": "b"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []changeEvent{
+			{position: NewPosition(lsn(1), lsn(1)), operation: "c", after: map[string]any{"id": int64(1), "name": "a"}},
+			{position: NewPosition(lsn(2), lsn(1)), operation: "u", before: map[string]any{"id": int64(1), "name": "a"}, after: map[string]any{"id": int64(1), "name": "b"}},
+			{position: NewPosition(lsn(3), lsn(1)), operation: "d", before: map[string]any{"id": int64(1), "name": "b"}},
+		}, events)
+	}
+	{
+		// Update after image is read before the before image
+		events, err := buildChangeEvents([]changeRow{
+			{startLSN: lsn(2), seqVal: lsn(1), operation: operationUpdateAfter, values: map[string]any{"id": int64(1), "name": "b"}},
+			{startLSN: lsn(2), seqVal: lsn(1), operation: operationUpdateBefore, values: map[string]any{"id": int64(1), "name": "a"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []changeEvent{
+			{position: NewPosition(lsn(2), lsn(1)), operation: "u", before: map[string]any{"id": int64(1), "name": "a"}, after: map[string]any{"id": int64(1), "name": "b"}},
+		}, events)
+	}
+	{
+		// Update before image without an after image is skipped
+		events, err := buildChangeEvents([]changeRow{
+			{startLSN: lsn(1), seqVal: lsn(1), operation: operationInsert, values: map[string]any{"id": int64(1)}},
+			{startLSN: lsn(2), seqVal: lsn(1), operation: operationUpdateBefore, values: map[string]any{"id": int64(1)}},
+			{startLSN: lsn(3), seqVal: lsn(1), operation: operationDelete, values: map[string]any{"id": int64(1)}},
+			{startLSN: lsn(4), seqVal: lsn(1), operation: operationUpdateBefore, values: map[string]any{"id": int64(1)}},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, events, 2)
+		assert.Equal(t, NewPosition(lsn(1), lsn(1)), events[0].position)
+		assert.Equal(t, NewPosition(lsn(3), lsn(1)), events[1].position)
+	}
+	{
+		// Unknown operation
+		_, err := buildChangeEvents([]changeRow{{startLSN: lsn(1), seqVal: lsn(1), operation: 5}})
+		assert.ErrorContains(t, err, "unexpected operation: 5")
+	}
+}
+
+func TestBuildChangesQuery(t *testing.T) {
+	query := buildChangesQuery("dbo_orders", []schema.Column{{Name: "id"}, {Name: "name"}}, 100)
+	assert.Equal(t, `SELECT TOP 100 WITH TIES __$start_lsn, __$seqval, __$operation, sys.fn_cdc_map_lsn_to_time(__$start_lsn), "id","name" FROM cdc."fn_cdc_get_all_changes_dbo_orders"(?, ?, N'all update old') WHERE __$start_lsn > ? OR (__$start_lsn = ? AND __$seqval > ?) ORDER BY __$start_lsn, __$seqval`, query)
+}
@@ -0,0 +1,268 @@
+package streaming
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/artie-labs/transfer/lib/cdc/util"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/debezium/transformer"
+	"github.com/artie-labs/reader/lib/iterator"
+	"github.com/artie-labs/reader/lib/kafkalib"
+	"github.com/artie-labs/reader/lib/mssql/parse"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+	"github.com/artie-labs/reader/lib/storage/persistedmap"
+	"github.com/artie-labs/reader/sources/mssql/adapter"
+)
+
+type captureInstance struct {
+	name     string
+	tableCfg config.MSSQLTable
+	adapter  adapter.MSSQLAdapter
+	query    string
+	stmt     *sql.Stmt
+}
+
+type Iterator struct {
+	db               *sql.DB
+	cfg              config.MSSQL
+	batchSize        int32
+	captureInstances []captureInstance
+	offsets          *persistedmap.PersistedMap[Position]
+	// positions - Positions that have been read but not yet committed, keyed by capture instance.
+	positions map[string]Position
+}
+
+func BuildStreamingIterator(ctx context.Context, db *sql.DB, cfg config.MSSQL, store offsetstore.OffsetStore) (*Iterator, error) {
+	var isCDCEnabled bool
+	if err := db.QueryRowContext(ctx, "SELECT is_cdc_enabled FROM sys.databases WHERE name = DB_NAME()").Scan(&isCDCEnabled); err != nil {
+		return nil, fmt.Errorf("failed to check if CDC is enabled: %w", err)
+	}
+
+	if !isCDCEnabled {
+		return nil, fmt.Errorf("CDC is not enabled for database %q", cfg.Database)
+	}
+
+	var captureInstances []captureInstance
+	for _, tableCfg := range cfg.Tables {
+		dbzAdapter, err := adapter.NewMSSQLAdapter(db, cfg.Database, *tableCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create MSSQL adapter: %w", err)
+		}
+
+		name := tableCfg.GetCaptureInstance()
+		query := buildChangesQuery(name, dbzAdapter.Columns(), cfg.GetStreamingBatchSize())
+		stmt, err := db.PrepareContext(ctx, query)
+		if err != nil {
+			closeStatements(captureInstances)
+			return nil, fmt.Errorf("failed to prepare changes query for capture instance %q: %w", name, err)
+		}
+
+		captureInstances = append(captureInstances, captureInstance{
+			name:     name,
+			tableCfg: *tableCfg,
+			adapter:  dbzAdapter,
+			query:    query,
+			stmt:     stmt,
+		})
+	}
+
+	offsets := persistedmap.NewPersistedMap[Position](store, cfg.StreamingSettings.OffsetFile)
+	positions := make(map[string]Position)
+	for _, ci := range captureInstances {
+		if pos, isOk := offsets.Get(ci.name); isOk {
+			slog.Info("Found offsets", slog.String("captureInstance", ci.name), slog.String("offset", pos.String()))
+			positions[ci.name] = pos
+		}
+	}
+
+	return &Iterator{
+		db:               db,
+		cfg:              cfg,
+		batchSize:        cfg.GetStreamingBatchSize(),
+		captureInstances: captureInstances,
+		offsets:          offsets,
+		positions:        positions,
+	}, nil
+}
+
+func closeStatements(captureInstances []captureInstance) error {
+	var errs []error
+	for _, ci := range captureInstances {
+		if err := ci.stmt.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close changes query for capture instance %q: %w", ci.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close closes the prepared queries, the database is closed by the caller.
+func (i *Iterator) Close() error {
+	return closeStatements(i.captureInstances)
+}
+
+func (i *Iterator) HasNext() bool {
+	return true
+}
+
+func (i *Iterator) CommitOffset() error {
+	for name, pos := range i.positions {
+		slog.Info("Committing offset", slog.String("captureInstance", name), slog.String("position", pos.String()))
+		if err := i.offsets.Set(name, pos); err != nil {
+			return fmt.Errorf("failed to persist offset for capture instance %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (i *Iterator) Next() ([]kafkalib.Message, error) {
+	return i.NextContext(context.Background())
+}
+
+// NextContext reads the changes of each capture instance, it returns what has been read so far once [ctx] is done.
+func (i *Iterator) NextContext(ctx context.Context) ([]kafkalib.Message, error) {
+	var rawMsgs []kafkalib.Message
+	for _, ci := range i.captureInstances {
+		msgs, err := i.readChanges(ctx, ci)
+		if err != nil {
+			if ctx.Err() != nil {
+				// The position of [ci] has not moved, its changes will be read again.
+				return rawMsgs, nil
+			}
+
+			return nil, fmt.Errorf("failed to read changes for capture instance %q: %w", ci.name, err)
+		}
+
+		rawMsgs = append(rawMsgs, msgs...)
+	}
+
+	if len(rawMsgs) == 0 {
+		// If there are no messages, let's sleep a bit before we try again
+		iterator.Sleep(ctx, 2*time.Second)
+	}
+
+	return rawMsgs, nil
+}
+
+func (i *Iterator) readChanges(ctx context.Context, ci captureInstance) ([]kafkalib.Message, error) {
+	lastStartLSN, lastSeqVal, err := i.positions[ci.name].Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	var minLSN, maxLSN []byte
+	if err = i.db.QueryRowContext(ctx, lsnBoundsQuery, ci.name).Scan(&minLSN, &maxLSN); err != nil {
+		return nil, fmt.Errorf("failed to retrieve LSN bounds: %w", err)
+	}
+
+	if minLSN == nil {
+		return nil, fmt.Errorf("capture instance does not exist")
+	}
+
+	fromLSN := lastStartLSN
+	if bytes.Compare(fromLSN, minLSN) < 0 {
+		if _, hasPosition := i.positions[ci.name]; hasPosition {
+			slog.Warn("Last position is before the minimum LSN of the capture instance, changes may have been removed by the cleanup job",
+				slog.String("captureInstance", ci.name),
+			)
+		}
+		fromLSN = minLSN
+	}
+
+	if maxLSN == nil || bytes.Compare(fromLSN, maxLSN) > 0 {
+		// There are no new changes.
+		return nil, nil
+	}
+
+	rows, err := ci.stmt.QueryContext(ctx, fromLSN, maxLSN, lastStartLSN, lastStartLSN, lastSeqVal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run query: %s: %w", ci.query, err)
+	}
+	defer rows.Close()
+
+	columns := ci.adapter.Columns()
+	var changeRows []changeRow
+	for rows.Next() {
+		values := make([]any, metadataColumnCount+len(columns))
+		valuePtrs := make([]any, len(values))
+		for idx := range values {
+			valuePtrs[idx] = &values[idx]
+		}
+
+		if err = rows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		row := changeRow{values: make(map[string]any)}
+		var isOk bool
+		if row.startLSN, isOk = values[0].([]byte); !isOk {
+			return nil, fmt.Errorf("expected []byte for __$start_lsn, got %T", values[0])
+		}
+		if row.seqVal, isOk = values[1].([]byte); !isOk {
+			return nil, fmt.Errorf("expected []byte for __$seqval, got %T", values[1])
+		}
+		if row.operation, isOk = values[2].(int64); !isOk {
+			return nil, fmt.Errorf("expected int64 for __$operation, got %T", values[2])
+		}
+		if row.commitTime, isOk = values[3].(time.Time); !isOk {
+			return nil, fmt.Errorf("expected time.Time for commit time, got %T", values[3])
+		}
+
+		for idx, col := range columns {
+			if row.values[col.Name], err = parse.ParseValue(col.Type, values[metadataColumnCount+idx]); err != nil {
+				return nil, fmt.Errorf("failed to parse column: %q: %w", col.Name, err)
+			}
+		}
+
+		changeRows = append(changeRows, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+
+	events, err := buildChangeEvents(changeRows)
+	if err != nil {
+		return nil, err
+	}
+
+	dbz := transformer.NewLightDebeziumTransformer(ci.tableCfg.Name, ci.adapter.PartitionKeys(), ci.adapter.FieldConverters())
+	var rawMsgs []kafkalib.Message
+	for _, event := range events {
+		sourcePayload := util.Source{
+			Connector: "sqlserver",
+			TsMs:      event.commitTime.UnixMilli(),
+			Database:  i.cfg.Database,
+			Schema:    ci.tableCfg.Schema,
+			Table:     ci.tableCfg.Name,
+		}
+
+		dbzMessage, err := dbz.BuildEventPayload(sourcePayload, event.before, event.after, event.operation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build event payload: %w", err)
+		}
+
+		primaryKeyPayload, err := dbz.BuildPartitionKey(event.before, event.after)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build partition key: %w", err)
+		}
+
+		rawMsgs = append(rawMsgs, kafkalib.NewMessage(ci.adapter.TopicSuffix(), primaryKeyPayload.Schema, primaryKeyPayload.Payload, &dbzMessage))
+	}
+
+	if len(changeRows) > 0 {
+		// Rows that did not produce an event are skipped as well, otherwise they would be read again.
+		lastRow := changeRows[len(changeRows)-1]
+		i.positions[ci.name] = NewPosition(lastRow.startLSN, lastRow.seqVal)
+	}
+
+	return rawMsgs, nil
+}
@@ -0,0 +1,58 @@
+package streaming
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// Position is the last change that was read from a capture instance, both values are hex encoded.
+type Position struct {
+	StartLSN string `yaml:"startLSN"`
+	SeqVal   string `yaml:"seqVal"`
+}
+
+func NewPosition(startLSN []byte, seqVal []byte) Position {
+	return Position{
+		StartLSN: hex.EncodeToString(startLSN),
+		SeqVal:   hex.EncodeToString(seqVal),
+	}
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("startLSN: %s, seqVal: %s", p.StartLSN, p.SeqVal)
+}
+
+// Decode returns the binary `__$start_lsn` and `__$seqval` values, an empty position decodes to zero values.
+func (p Position) Decode() ([]byte, []byte, error) {
+	startLSN, err := decodeLSN(p.StartLSN)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode start LSN: %w", err)
+	}
+
+	seqVal, err := decodeLSN(p.SeqVal)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode seqval: %w", err)
+	}
+
+	return startLSN, seqVal, nil
+}
+
+// lsnLength - LSNs in SQL Server are binary(10).
+const lsnLength = 10
+
+func decodeLSN(value string) ([]byte, error) {
+	if value == "" {
+		return make([]byte, lsnLength), nil
+	}
+
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decoded) != lsnLength {
+		return nil, fmt.Errorf("expected %d bytes, got %d", lsnLength, len(decoded))
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,37 @@
+package streaming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPosition_Decode(t *testing.T) {
+	{
+		// Empty position
+		startLSN, seqVal, err := Position{}.Decode()
+		assert.NoError(t, err)
+		assert.Equal(t, make([]byte, 10), startLSN)
+		assert.Equal(t, make([]byte, 10), seqVal)
+	}
+	{
+		// Round trip
+		pos := NewPosition([]byte{0, 0, 0, 0x2a, 0, 0, 0x01, 0x10, 0, 0x03}, []byte{0, 0, 0, 0x2a, 0, 0, 0x01, 0x10, 0, 0x02})
+		assert.Equal(t, Position{StartLSN: "0000002a000001100003", SeqVal: "0000002a000001100002"}, pos)
+
+		startLSN, seqVal, err := pos.Decode()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0, 0, 0, 0x2a, 0, 0, 0x01, 0x10, 0, 0x03}, startLSN)
+		assert.Equal(t, []byte{0, 0, 0, 0x2a, 0, 0, 0x01, 0x10, 0, 0x02}, seqVal)
+	}
+	{
+		// Invalid hex
+		_, _, err := Position{StartLSN: "zz"}.Decode()
+		assert.ErrorContains(t, err, "failed to decode start LSN")
+	}
+	{
+		// Wrong length
+		_, _, err := Position{StartLSN: "0000002a000001100003", SeqVal: "00"}.Decode()
+		assert.ErrorContains(t, err, "failed to decode seqval: expected 10 bytes, got 1")
+	}
+}
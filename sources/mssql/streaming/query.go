@@ -0,0 +1,35 @@
+package streaming
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/artie-labs/transfer/clients/mssql/dialect"
+
+	"github.com/artie-labs/reader/lib/mssql/schema"
+)
+
+const lsnBoundsQuery = `SELECT sys.fn_cdc_get_min_lsn(?), sys.fn_cdc_get_max_lsn()`
+
+// metadataColumnCount - Number of CDC metadata columns selected before the table's columns, see [buildChangesQuery].
+const metadataColumnCount = 4
+
+// buildChangesQuery returns a query that reads changes from a capture instance that come after the last position.
+// Arguments are: from LSN, to LSN, last start LSN, last start LSN, last seqval. Rows that have the same position as the
+// last row are included past the batch size, so that the before and after images of an update are never split.
+func buildChangesQuery(captureInstance string, columns []schema.Column, batchSize int32) string {
+	mssqlDialect := dialect.MSSQLDialect{}
+	colNames := make([]string, len(columns))
+	for idx, col := range columns {
+		colNames[idx] = mssqlDialect.QuoteIdentifier(col.Name)
+	}
+
+	return fmt.Sprintf(`SELECT TOP %d WITH TIES __$start_lsn, __$seqval, __$operation, sys.fn_cdc_map_lsn_to_time(__$start_lsn), %s FROM cdc.%s(?, ?, N'all update old') WHERE __$start_lsn > ? OR (__$start_lsn = ? AND __$seqval > ?) ORDER BY __$start_lsn, __$seqval`,
+		// TOP
+		batchSize,
+		// SELECT
+		strings.Join(colNames, ","),
+		// FROM
+		mssqlDialect.QuoteIdentifier("fn_cdc_get_all_changes_"+captureInstance),
+	)
+}
@@ -1,9 +1,9 @@
 package adapter
 
 import (
-	"database/sql"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/artie-labs/reader/config"
 	"github.com/artie-labs/reader/lib/debezium/transformer"
@@ -11,6 +11,7 @@ import (
 	"github.com/artie-labs/reader/lib/mysql/converters"
 	"github.com/artie-labs/reader/lib/mysql/scanner"
 	"github.com/artie-labs/reader/lib/mysql/schema"
+	"github.com/artie-labs/reader/lib/rdbms"
 	"github.com/artie-labs/reader/lib/rdbms/column"
 	"github.com/artie-labs/reader/lib/rdbms/scan"
 )
@@ -18,7 +19,7 @@ import (
 const defaultErrorRetries = 10
 
 type MySQLAdapter struct {
-	db              *sql.DB
+	db              rdbms.Querier
 	dbName          string
 	table           mysql.Table
 	columns         []schema.Column
@@ -26,9 +27,9 @@ type MySQLAdapter struct {
 	scannerCfg      scan.ScannerConfig
 }
 
-func NewMySQLAdapter(db *sql.DB, dbName string, tableCfg config.MySQLTable) (MySQLAdapter, error) {
+func NewMySQLAdapter(db rdbms.Querier, dbName string, tableCfg config.MySQLTable) (MySQLAdapter, error) {
 	slog.Info("Loading metadata for table")
-	table, err := mysql.LoadTable(db, tableCfg.Name)
+	table, err := mysql.LoadTable(db, tableCfg.Name, tableCfg.PrimaryKeysOverride)
 	if err != nil {
 		return MySQLAdapter{}, fmt.Errorf("failed to load metadata for table %q: %w", tableCfg.Name, err)
 	}
@@ -48,7 +49,7 @@ func NewMySQLAdapter(db *sql.DB, dbName string, tableCfg config.MySQLTable) (MyS
 	return newMySQLAdapter(db, dbName, *table, columns, tableCfg.ToScannerConfig(defaultErrorRetries))
 }
 
-func newMySQLAdapter(db *sql.DB, dbName string, table mysql.Table, columns []schema.Column, scannerCfg scan.ScannerConfig) (MySQLAdapter, error) {
+func newMySQLAdapter(db rdbms.Querier, dbName string, table mysql.Table, columns []schema.Column, scannerCfg scan.ScannerConfig) (MySQLAdapter, error) {
 	fieldConverters := make([]transformer.FieldConverter, len(columns))
 	for i, col := range columns {
 		converter, err := converters.ValueConverterForType(col.Type, col.Opts)
@@ -81,9 +82,50 @@ func (m MySQLAdapter) FieldConverters() []transformer.FieldConverter {
 }
 
 func (m MySQLAdapter) NewIterator() (transformer.RowsIterator, error) {
-	return scanner.NewScanner(m.db, m.table, m.columns, m.scannerCfg)
+	if len(m.table.PrimaryKeys) == 0 {
+		// Tables without a key are read in full, see [transformer.RowHashKey] for how they are partitioned.
+		keylessScanner, err := scanner.NewKeylessScanner(m.db, m.table, m.columns, m.scannerCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return keylessScanner, nil
+	}
+
+	tableScanner, err := scanner.NewScanner(m.db, m.table, m.columns, m.scannerCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return tableScanner.Parallelize()
 }
 
 func (m MySQLAdapter) PartitionKeys() []string {
+	if len(m.table.PrimaryKeys) == 0 {
+		return []string{transformer.RowHashKey}
+	}
 	return m.table.PrimaryKeys
 }
+
+func (m MySQLAdapter) NewPoller(cursorColumn string, lag time.Duration) (*scan.Poller, error) {
+	cfg := scan.PollerConfig{BatchSize: m.scannerCfg.BatchSize, ErrorRetries: m.scannerCfg.ErrorRetries, Lag: lag}
+	return scan.NewPoller(m.db, cursorColumn, m.table.PrimaryKeys, cfg, scanner.NewPollAdapter(m.table, m.columns))
+}
+
+func (m MySQLAdapter) NewPrimaryKeysIterator() (transformer.RowsIterator, error) {
+	columns, err := column.FilterForIncludedColumns(m.columns, m.table.PrimaryKeys, m.table.PrimaryKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	// Primary key overrides are not applied, otherwise rows outside of them would look like they have been deleted.
+	tableScanner, err := scanner.NewScanner(m.db, m.table, columns, scan.ScannerConfig{
+		BatchSize:    m.scannerCfg.BatchSize,
+		ErrorRetries: m.scannerCfg.ErrorRetries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tableScanner, nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/artie-labs/reader/lib/debezium/transformer"
 	"github.com/artie-labs/reader/lib/mysql"
 	"github.com/artie-labs/reader/lib/mysql/schema"
 	"github.com/artie-labs/reader/lib/rdbms/scan"
@@ -46,3 +47,18 @@ func TestMySQLAdapter_TopicSuffix(t *testing.T) {
 		assert.Equal(t, tc.expected, adapter.TopicSuffix())
 	}
 }
+
+func TestMySQLAdapter_PartitionKeys(t *testing.T) {
+	{
+		// Primary key or unique key
+		adapter, err := newMySQLAdapter(nil, "foo", mysql.Table{Name: "table1", PrimaryKeys: []string{"a", "b"}}, []schema.Column{}, scan.ScannerConfig{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, adapter.PartitionKeys())
+	}
+	{
+		// No key
+		adapter, err := newMySQLAdapter(nil, "foo", mysql.Table{Name: "table1"}, []schema.Column{}, scan.ScannerConfig{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{transformer.RowHashKey}, adapter.PartitionKeys())
+	}
+}
@@ -0,0 +1,226 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+	"github.com/artie-labs/reader/sources/mysql/streaming"
+	"github.com/artie-labs/reader/writers"
+)
+
+// SnapshotThenStream - Snapshots all the tables at a consistent binlog position and then starts streaming from that
+// position, so that no changes are missed in between. This is used when there is no streaming offset yet.
+type SnapshotThenStream struct {
+	cfg      config.MySQL
+	db       *sql.DB
+	settings Settings
+	store    offsetstore.OffsetStore
+
+	// iterator - Streaming iterator, this is set once the snapshot has completed.
+	iterator *streaming.Iterator
+}
+
+func (s *SnapshotThenStream) Close() error {
+	if s.iterator != nil {
+		if err := s.iterator.Close(); err != nil {
+			return fmt.Errorf("failed to close iterator: %w", err)
+		}
+	}
+
+	return s.db.Close()
+}
+
+func (s *SnapshotThenStream) Run(ctx context.Context, writer writers.Writer) error {
+	conn, pos, err := beginConsistentSnapshot(ctx, s.db, s.settings.Flavor)
+	if err != nil {
+		return fmt.Errorf("failed to start consistent snapshot: %w", err)
+	}
+	defer conn.Close()
+
+	slog.Info("Snapshotting tables at a consistent position", slog.String("position", pos.String()))
+	tableSnapshot := Snapshot{cfg: sequentialSnapshotConfig(s.cfg), db: s.db, conn: conn}
+	if err = tableSnapshot.Run(ctx, writer); err != nil {
+		return err
+	}
+
+	if _, err = conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("failed to commit snapshot transaction: %w", err)
+	}
+
+	if err = streaming.SaveOffset(s.store, s.cfg.StreamingSettings.OffsetFile, pos); err != nil {
+		return fmt.Errorf("failed to save offset: %w", err)
+	}
+
+	iter, err := streaming.BuildStreamingIterator(s.db, s.cfg, s.settings.Flavor, s.settings.SQLMode, s.settings.GTIDEnabled, s.store)
+	if err != nil {
+		return fmt.Errorf("failed to build streaming iterator: %w", err)
+	}
+	s.iterator = &iter
+
+	slog.Info("Snapshot has completed, streaming from the snapshot position")
+	_, err = writer.Write(ctx, s.iterator)
+	return err
+}
+
+// sequentialSnapshotConfig - A connection can only run one query at a time, so tables and their chunks have to be read
+// one after the other.
+func sequentialSnapshotConfig(cfg config.MySQL) config.MySQL {
+	if cfg.MaxConcurrentTables > 1 {
+		slog.Warn("Ignoring maxConcurrentTables, tables are snapshotted one at a time before streaming")
+	}
+	cfg.MaxConcurrentTables = 1
+
+	tables := make([]*config.MySQLTable, len(cfg.Tables))
+	for i, table := range cfg.Tables {
+		tableCfg := *table
+		if tableCfg.SnapshotParallelism > 1 {
+			slog.Warn("Ignoring snapshotParallelism, the table is scanned sequentially before streaming", slog.String("table", tableCfg.Name))
+		}
+		tableCfg.SnapshotParallelism = 0
+		tables[i] = &tableCfg
+	}
+	cfg.Tables = tables
+	return cfg
+}
+
+// beginConsistentSnapshot opens a connection with a transaction that reads every table as of the returned binlog position.
+func beginConsistentSnapshot(ctx context.Context, db *sql.DB, flavor string) (*sql.Conn, streaming.Position, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, streaming.Position{}, fmt.Errorf("failed to open connection: %w", err)
+	}
+
+	pos, err := startConsistentSnapshot(ctx, conn, flavor)
+	if err != nil {
+		// Closing the connection also releases the global read lock if we are still holding it.
+		conn.Close()
+		return nil, streaming.Position{}, err
+	}
+
+	return conn, pos, nil
+}
+
+func startConsistentSnapshot(ctx context.Context, conn *sql.Conn, flavor string) (streaming.Position, error) {
+	if _, err := conn.ExecContext(ctx, "SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ"); err != nil {
+		return streaming.Position{}, fmt.Errorf("failed to set isolation level: %w", err)
+	}
+
+	// Holding a global read lock while the transaction starts guarantees that the position matches the snapshot exactly.
+	locked := true
+	if _, err := conn.ExecContext(ctx, "FLUSH TABLES WITH READ LOCK"); err != nil {
+		// Managed databases (e.g. RDS) do not grant the privileges needed for this. Reading the position before the
+		// transaction starts means that changes made in between will be streamed again, which may cause duplicates but
+		// never gaps.
+		slog.Warn("Unable to acquire a global read lock, changes made while the snapshot starts may be streamed twice", slog.Any("err", err))
+		locked = false
+	}
+
+	var pos streaming.Position
+	var err error
+	if !locked {
+		if pos, err = fetchBinlogPosition(ctx, conn, flavor); err != nil {
+			return streaming.Position{}, err
+		}
+	}
+
+	if _, err = conn.ExecContext(ctx, "START TRANSACTION WITH CONSISTENT SNAPSHOT"); err != nil {
+		return streaming.Position{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	if locked {
+		if pos, err = fetchBinlogPosition(ctx, conn, flavor); err != nil {
+			return streaming.Position{}, err
+		}
+
+		if _, err = conn.ExecContext(ctx, "UNLOCK TABLES"); err != nil {
+			return streaming.Position{}, fmt.Errorf("failed to release global read lock: %w", err)
+		}
+	}
+
+	return pos, nil
+}
+
+func fetchBinlogPosition(ctx context.Context, conn *sql.Conn, flavor string) (streaming.Position, error) {
+	pos, err := fetchBinlogStatus(ctx, conn)
+	if err != nil {
+		return streaming.Position{}, err
+	}
+
+	if flavor == gomysql.MariaDBFlavor {
+		// MariaDB does not include the GTID position in the binlog status.
+		if err = conn.QueryRowContext(ctx, "SELECT @@GLOBAL.gtid_current_pos").Scan(&pos.GTIDSet); err != nil {
+			return streaming.Position{}, fmt.Errorf("failed to query gtid_current_pos: %w", err)
+		}
+	}
+
+	return pos, nil
+}
+
+func fetchBinlogStatus(ctx context.Context, conn *sql.Conn) (streaming.Position, error) {
+	rows, err := conn.QueryContext(ctx, "SHOW MASTER STATUS")
+	if err != nil {
+		// MySQL 8.4 replaced SHOW MASTER STATUS with SHOW BINARY LOG STATUS.
+		if rows, err = conn.QueryContext(ctx, "SHOW BINARY LOG STATUS"); err != nil {
+			return streaming.Position{}, fmt.Errorf("failed to query binlog status: %w", err)
+		}
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return streaming.Position{}, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return streaming.Position{}, fmt.Errorf("failed to read binlog status: %w", err)
+		}
+
+		return streaming.Position{}, fmt.Errorf("binlog status is empty, binary logging must be enabled")
+	}
+
+	values := make([]sql.NullString, len(columns))
+	valuePtrs := make([]any, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	if err = rows.Scan(valuePtrs...); err != nil {
+		return streaming.Position{}, fmt.Errorf("failed to scan binlog status: %w", err)
+	}
+
+	return parseBinlogStatus(columns, values)
+}
+
+func parseBinlogStatus(columns []string, values []sql.NullString) (streaming.Position, error) {
+	var pos streaming.Position
+	for i, column := range columns {
+		switch column {
+		case "File":
+			pos.File = values[i].String
+		case "Position":
+			offset, err := strconv.ParseUint(values[i].String, 10, 32)
+			if err != nil {
+				return streaming.Position{}, fmt.Errorf("failed to parse binlog position %q: %w", values[i].String, err)
+			}
+			pos.Pos = uint32(offset)
+		case "Executed_Gtid_Set":
+			// GTID sets that span multiple servers are split over multiple lines.
+			pos.GTIDSet = strings.ReplaceAll(values[i].String, "\n", "")
+		}
+	}
+
+	if pos.File == "" {
+		return streaming.Position{}, fmt.Errorf("binlog status does not contain a file")
+	}
+
+	return pos, nil
+}
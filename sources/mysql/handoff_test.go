@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/sources/mysql/streaming"
+)
+
+func TestParseBinlogStatus(t *testing.T) {
+	columns := []string{"File", "Position", "Binlog_Do_DB", "Binlog_Ignore_DB", "Executed_Gtid_Set"}
+	{
+		// Without GTIDs
+		pos, err := parseBinlogStatus(columns, []sql.NullString{
+			{String: "mysql-bin.000003", Valid: true},
+			{String: "157", Valid: true},
+			{},
+			{},
+			{},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, streaming.Position{File: "mysql-bin.000003", Pos: 157}, pos)
+	}
+	{
+		// With a GTID set that spans multiple lines
+		pos, err := parseBinlogStatus(columns, []sql.NullString{
+			{String: "mysql-bin.000003", Valid: true},
+			{String: "157", Valid: true},
+			{},
+			{},
+			{String: "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5,\n4e11fa47-71ca-11e1-9e33-c80aa9429562:1-2", Valid: true},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5,4e11fa47-71ca-11e1-9e33-c80aa9429562:1-2", pos.GTIDSet)
+	}
+	{
+		// Invalid position
+		_, err := parseBinlogStatus(columns, []sql.NullString{{String: "mysql-bin.000003", Valid: true}, {String: "abc", Valid: true}, {}, {}, {}})
+		assert.ErrorContains(t, err, `failed to parse binlog position "abc"`)
+	}
+	{
+		// Missing file
+		_, err := parseBinlogStatus([]string{"Position"}, []sql.NullString{{String: "157", Valid: true}})
+		assert.ErrorContains(t, err, "binlog status does not contain a file")
+	}
+}
+
+func TestSequentialSnapshotConfig(t *testing.T) {
+	cfg := config.MySQL{
+		MaxConcurrentTables: 4,
+		Tables: []*config.MySQLTable{
+			{Name: "foo", SnapshotParallelism: 8},
+			{Name: "bar"},
+		},
+	}
+
+	sequentialCfg := sequentialSnapshotConfig(cfg)
+	assert.Equal(t, 1, sequentialCfg.MaxConcurrentTables)
+	assert.Equal(t, []*config.MySQLTable{{Name: "foo"}, {Name: "bar"}}, sequentialCfg.Tables)
+
+	// The original config should not be modified.
+	assert.Equal(t, 4, cfg.MaxConcurrentTables)
+	assert.Equal(t, uint(8), cfg.Tables[0].SnapshotParallelism)
+}
@@ -7,10 +7,13 @@ import (
 	"log/slog"
 
 	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/checkpoint"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 	"github.com/artie-labs/reader/sources"
+	"github.com/artie-labs/reader/sources/mysql/streaming"
 )
 
-func Load(ctx context.Context, cfg config.MySQL) (sources.Source, bool, error) {
+func Load(ctx context.Context, cfg config.MySQL, store offsetstore.OffsetStore) (sources.Source, bool, error) {
 	db, err := sql.Open("mysql", cfg.ToDSN())
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to connect to MySQL: %w", err)
@@ -23,12 +26,26 @@ func Load(ctx context.Context, cfg config.MySQL) (sources.Source, bool, error) {
 
 	slog.Info("Loading MySQL connector",
 		slog.String("version", settings.Version),
+		slog.String("flavor", settings.Flavor),
 		slog.Any("sqlMode", settings.SQLMode),
 		slog.Bool("gtidEnabled", settings.GTIDEnabled),
 	)
 
 	if cfg.StreamingSettings.Enabled {
-		stream, err := buildStreamingConfig(ctx, db, cfg, settings.SQLMode, settings.GTIDEnabled)
+		if cfg.StreamingSettings.InitialSnapshot {
+			if _, isOk := streaming.LoadOffset(store, cfg.StreamingSettings.OffsetFile); !isOk {
+				// Validate up front so that we do not snapshot every table only to find out that we cannot stream.
+				if err = ValidateMySQL(ctx, db, true); err != nil {
+					return nil, false, fmt.Errorf("failed validation: %w", err)
+				}
+
+				return &SnapshotThenStream{cfg: cfg, db: db, settings: settings, store: store}, true, nil
+			}
+
+			slog.Info("Found a streaming offset, skipping the initial snapshot")
+		}
+
+		stream, err := buildStreamingConfig(ctx, db, cfg, settings, store)
 		if err != nil {
 			return nil, false, fmt.Errorf("failed to build streaming config: %w", err)
 		}
@@ -36,5 +53,14 @@ func Load(ctx context.Context, cfg config.MySQL) (sources.Source, bool, error) {
 		return stream, true, nil
 	}
 
-	return &Snapshot{cfg: cfg, db: db}, false, nil
+	if cfg.PollingSettings.Enabled {
+		polling, err := buildPolling(db, cfg, store)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to build polling config: %w", err)
+		}
+
+		return polling, true, nil
+	}
+
+	return &Snapshot{cfg: cfg, db: db, checkpoints: checkpoint.NewStore(store, cfg.SnapshotCheckpointFile)}, false, nil
 }
@@ -5,10 +5,14 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
 )
 
 type Settings struct {
-	Version     string
+	Version string
+	// Flavor - Either [gomysql.MySQLFlavor] or [gomysql.MariaDBFlavor], this is detected from the version.
+	Flavor      string
 	SQLMode     []string
 	GTIDEnabled bool
 }
@@ -24,13 +28,15 @@ func retrieveSettings(ctx context.Context, db *sql.DB) (Settings, error) {
 		return Settings{}, fmt.Errorf("failed to retrieve MySQL session sql_mode: %w", err)
 	}
 
-	gtidEnabled, err := hasGTIDEnabled(ctx, db)
+	flavor := parseFlavor(version)
+	gtidEnabled, err := hasGTIDEnabled(ctx, db, flavor)
 	if err != nil {
 		return Settings{}, fmt.Errorf("failed to check if GTID is enabled: %w", err)
 	}
 
 	return Settings{
 		Version:     version,
+		Flavor:      flavor,
 		SQLMode:     sqlMode,
 		GTIDEnabled: gtidEnabled,
 	}, nil
@@ -45,6 +51,15 @@ func retrieveVersion(db *sql.DB) (string, error) {
 	return version, nil
 }
 
+// parseFlavor - MariaDB reports versions such as `10.11.6-MariaDB-log`.
+func parseFlavor(version string) string {
+	if strings.Contains(strings.ToLower(version), "mariadb") {
+		return gomysql.MariaDBFlavor
+	}
+
+	return gomysql.MySQLFlavor
+}
+
 func retrieveSessionSQLMode(db *sql.DB) ([]string, error) {
 	var sqlMode string
 	if err := db.QueryRow(`SELECT @@SESSION.sql_mode;`).Scan(&sqlMode); err != nil {
@@ -54,7 +69,12 @@ func retrieveSessionSQLMode(db *sql.DB) ([]string, error) {
 	return strings.Split(sqlMode, ","), nil
 }
 
-func hasGTIDEnabled(ctx context.Context, db *sql.DB) (bool, error) {
+func hasGTIDEnabled(ctx context.Context, db *sql.DB, flavor string) (bool, error) {
+	if flavor == gomysql.MariaDBFlavor {
+		// MariaDB always assigns GTIDs and writes them to the binlog, it does not have the variables below.
+		return true, nil
+	}
+
 	requiredVariables := []string{"gtid_mode", "enforce_gtid_consistency"}
 	for _, requiredVariable := range requiredVariables {
 		value, err := fetchVariable(ctx, db, requiredVariable)
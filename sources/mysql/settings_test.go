@@ -0,0 +1,15 @@
+package mysql
+
+import (
+	"testing"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFlavor(t *testing.T) {
+	assert.Equal(t, gomysql.MySQLFlavor, parseFlavor("8.0.36"))
+	assert.Equal(t, gomysql.MySQLFlavor, parseFlavor("5.7.44-log"))
+	assert.Equal(t, gomysql.MariaDBFlavor, parseFlavor("10.11.6-MariaDB-log"))
+	assert.Equal(t, gomysql.MariaDBFlavor, parseFlavor("5.5.5-10.6.16-MariaDB-1:10.6.16+maria~ubu2004"))
+}
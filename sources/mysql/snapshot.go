@@ -11,8 +11,10 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 
 	"github.com/artie-labs/reader/config"
-	"github.com/artie-labs/reader/lib/debezium/transformer"
+	"github.com/artie-labs/reader/lib/checkpoint"
+	"github.com/artie-labs/reader/lib/iterator"
 	"github.com/artie-labs/reader/lib/rdbms"
+	"github.com/artie-labs/reader/lib/snapshot"
 	"github.com/artie-labs/reader/lib/transfer"
 	"github.com/artie-labs/reader/sources/mysql/adapter"
 	"github.com/artie-labs/reader/writers"
@@ -21,58 +23,102 @@ import (
 type Snapshot struct {
 	cfg config.MySQL
 	db  *sql.DB
+	// conn - If set, tables are read from this connection instead of [db], this is used to read every table within the
+	// same consistent snapshot.
+	conn *sql.Conn
+	// checkpoints - Snapshot progress, this is nil if checkpointing is disabled.
+	checkpoints *checkpoint.Store
 }
 
 func (s Snapshot) Close() error {
 	return s.db.Close()
 }
 
+// querier returns the connection that tables are read from.
+func (s Snapshot) querier() rdbms.Querier {
+	if s.conn != nil {
+		return s.conn
+	}
+
+	return s.db
+}
+
 func (s *Snapshot) Run(ctx context.Context, writer writers.Writer) error {
-	for _, tableCfg := range s.cfg.Tables {
-		if err := s.snapshotTable(ctx, writer, *tableCfg); err != nil {
-			return err
+	tables := make([]snapshot.Table, len(s.cfg.Tables))
+	for i, tableCfg := range s.cfg.Tables {
+		tables[i] = snapshot.Table{
+			Name: tableCfg.Name,
+			Run: func(ctx context.Context) (int, error) {
+				return s.snapshotTable(ctx, writer, *tableCfg)
+			},
 		}
 	}
-	return nil
+
+	if _, err := snapshot.Run(ctx, tables, s.cfg.MaxConcurrentTables); err != nil {
+		return err
+	}
+
+	// Every table has been snapshotted, the next run should start from scratch.
+	return s.checkpoints.Clear()
 }
 
-func (s Snapshot) snapshotTable(ctx context.Context, writer writers.Writer, tableCfg config.MySQLTable) error {
+func (s Snapshot) snapshotTable(ctx context.Context, writer writers.Writer, tableCfg config.MySQLTable) (int, error) {
 	logger := slog.With(slog.String("table", tableCfg.Name), slog.String("database", s.cfg.Database))
 	snapshotStartTime := time.Now()
 
-	dbzAdapter, err := adapter.NewMySQLAdapter(s.db, s.cfg.Database, tableCfg)
+	dbzAdapter, err := adapter.NewMySQLAdapter(s.querier(), s.cfg.Database, tableCfg)
 	if err != nil {
-		return fmt.Errorf("failed to create MySQL adapter: %w", err)
+		return 0, fmt.Errorf("failed to create MySQL adapter: %w", err)
+	}
+
+	if s.checkpoints.IsDone(dbzAdapter.TopicSuffix()) {
+		logger.Info("Skipping table, it has already been snapshotted")
+		return 0, nil
 	}
 
-	dbzTransformer, err := transformer.NewDebeziumTransformer(dbzAdapter)
+	dbzTransformer, err := s.checkpoints.NewTransformer(dbzAdapter)
 	if err != nil {
 		if errors.Is(err, rdbms.ErrNoPkValuesForEmptyTable) {
 			cols, err := transfer.BuildTransferColumns(dbzAdapter)
 			if err != nil {
-				return fmt.Errorf("failed to build transfer columns: %w", err)
+				return 0, fmt.Errorf("failed to build transfer columns: %w", err)
+			}
+
+			if err = writer.CreateTable(ctx, dbzAdapter.TopicSuffix(), dbzAdapter.TableName(), cols); err != nil {
+				return 0, fmt.Errorf("failed to create table: %w", err)
 			}
 
-			if err = writer.CreateTable(ctx, dbzAdapter.TableName(), cols); err != nil {
-				return fmt.Errorf("failed to create table: %w", err)
+			if err = s.checkpoints.MarkDone(dbzAdapter.TopicSuffix()); err != nil {
+				return 0, err
 			}
 
 			logger.Info("Table has been created, it does not contain any rows")
-			return nil
+			return 0, nil
 		} else {
-			return fmt.Errorf("failed to build Debezium transformer for table %q: %w", tableCfg.Name, err)
+			return 0, fmt.Errorf("failed to build Debezium transformer for table %q: %w", tableCfg.Name, err)
 		}
 	}
+	defer iterator.Close(dbzTransformer)
 
 	logger.Info("Scanning table...", slog.Any("batchSize", tableCfg.GetBatchSize()))
+	if s.checkpoints.IsResuming(dbzAdapter.TopicSuffix()) {
+		if err = writer.OnResume(dbzAdapter.TopicSuffix()); err != nil {
+			return 0, err
+		}
+	}
+
 	count, err := writer.Write(ctx, dbzTransformer)
 	if err != nil {
-		return fmt.Errorf("failed to snapshot table %q: %w", tableCfg.Name, err)
+		return 0, fmt.Errorf("failed to snapshot table %q: %w", tableCfg.Name, err)
+	}
+
+	if err = s.checkpoints.MarkDone(dbzAdapter.TopicSuffix()); err != nil {
+		return 0, err
 	}
 
 	logger.Info("Finished snapshotting",
 		slog.Int("scannedTotal", count),
 		slog.Duration("totalDuration", time.Since(snapshotStartTime)),
 	)
-	return nil
+	return count, nil
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+
 	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 	"github.com/artie-labs/reader/sources/mysql/streaming"
 	"github.com/artie-labs/reader/writers"
 )
@@ -14,13 +16,13 @@ type Streaming struct {
 	db       *sql.DB
 }
 
-func buildStreamingConfig(ctx context.Context, db *sql.DB, cfg config.MySQL, sqlMode []string, gtidEnabled bool) (Streaming, error) {
+func buildStreamingConfig(ctx context.Context, db *sql.DB, cfg config.MySQL, settings Settings, store offsetstore.OffsetStore) (Streaming, error) {
 	// Validate to ensure that we can use streaming.
 	if err := ValidateMySQL(ctx, db, true); err != nil {
 		return Streaming{}, fmt.Errorf("failed validation: %w", err)
 	}
 
-	iter, err := streaming.BuildStreamingIterator(db, cfg, sqlMode, gtidEnabled)
+	iter, err := streaming.BuildStreamingIterator(db, cfg, settings.Flavor, settings.SQLMode, settings.GTIDEnabled, store)
 	if err != nil {
 		return Streaming{}, err
 	}
@@ -39,6 +39,25 @@ func shouldSkipDDL(query string) bool {
 	return false
 }
 
+// completeAfterRow fills in the columns that are not in the after image of an update from the before image, since they
+// did not change. It returns the columns that are in neither image, their values are unavailable.
+func completeAfterRow(beforeRow map[string]any, afterRow map[string]any, parsedColumns []schema.Column) []string {
+	var unavailableColumns []string
+	for _, col := range parsedColumns {
+		if _, ok := afterRow[col.Name]; ok {
+			continue
+		}
+
+		if val, ok := beforeRow[col.Name]; ok {
+			afterRow[col.Name] = val
+		} else {
+			unavailableColumns = append(unavailableColumns, col.Name)
+		}
+	}
+
+	return unavailableColumns
+}
+
 func preprocessRow(row map[string]any, parsedColumns []schema.Column) (map[string]any, error) {
 	out := make(map[string]any)
 	if len(row) == 0 {
@@ -48,7 +67,8 @@ func preprocessRow(row map[string]any, parsedColumns []schema.Column) (map[strin
 	for _, col := range parsedColumns {
 		val, ok := row[col.Name]
 		if !ok {
-			return nil, fmt.Errorf("column %q not found in row", col.Name)
+			// The column is not in the row image, see [zipRow].
+			continue
 		}
 
 		parsedValue, err := schema.ConvertValue(val, col.Type, col.Opts)
@@ -64,11 +84,11 @@ func preprocessRow(row map[string]any, parsedColumns []schema.Column) (map[strin
 
 func convertHeaderToOperation(evtType replication.EventType) (string, error) {
 	switch evtType {
-	case replication.WRITE_ROWS_EVENTv2:
+	case replication.WRITE_ROWS_EVENTv2, replication.WRITE_ROWS_EVENTv1, replication.MARIADB_WRITE_ROWS_COMPRESSED_EVENT_V1:
 		return "c", nil
-	case replication.UPDATE_ROWS_EVENTv2:
+	case replication.UPDATE_ROWS_EVENTv2, replication.UPDATE_ROWS_EVENTv1, replication.MARIADB_UPDATE_ROWS_COMPRESSED_EVENT_V1, replication.PARTIAL_UPDATE_ROWS_EVENT:
 		return "u", nil
-	case replication.DELETE_ROWS_EVENTv2:
+	case replication.DELETE_ROWS_EVENTv2, replication.DELETE_ROWS_EVENTv1, replication.MARIADB_DELETE_ROWS_COMPRESSED_EVENT_V1:
 		return "d", nil
 	default:
 		return "", fmt.Errorf("unexpected event type %T", evtType)
@@ -97,12 +117,28 @@ func zipSlicesToMap[K comparable, V any](keys []K, values []V) (map[K]V, error)
 	return out, nil
 }
 
-func splitIntoBeforeAndAfter(operation string, rows [][]any) (iter.Seq2[[]any, []any], error) {
+// zipRow creates a row from the values of a rows event. Columns that are not in the row image, which happens when
+// `binlog_row_image` is set to MINIMAL or NOBLOB, are left out so that they can be told apart from NULL values.
+func zipRow(columnNames []string, values []any, skippedColumns []int) (map[string]any, error) {
+	row, err := zipSlicesToMap(columnNames, values)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, idx := range skippedColumns {
+		delete(row, columnNames[idx])
+	}
+
+	return row, nil
+}
+
+func splitIntoBeforeAndAfter[T any](operation string, rows []T) (iter.Seq2[T, T], error) {
+	var empty T
 	switch operation {
 	case "c":
-		return func(yield func([]any, []any) bool) {
+		return func(yield func(T, T) bool) {
 			for _, row := range rows {
-				if !yield(nil, row) {
+				if !yield(empty, row) {
 					return
 				}
 			}
@@ -115,7 +151,7 @@ func splitIntoBeforeAndAfter(operation string, rows [][]any) (iter.Seq2[[]any, [
 			return nil, fmt.Errorf("update row count is not divisible by two: %d", len(rows))
 		}
 
-		return func(yield func([]any, []any) bool) {
+		return func(yield func(T, T) bool) {
 			for group := range slices.Chunk(rows, 2) {
 				if !yield(group[0], group[1]) {
 					return
@@ -123,9 +159,9 @@ func splitIntoBeforeAndAfter(operation string, rows [][]any) (iter.Seq2[[]any, [
 			}
 		}, nil
 	case "d":
-		return func(yield func([]any, []any) bool) {
+		return func(yield func(T, T) bool) {
 			for _, row := range rows {
-				if !yield(row, nil) {
+				if !yield(row, empty) {
 					return
 				}
 			}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/go-mysql-org/go-mysql/replication"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/lib/mysql/schema"
 )
 
 func TestShouldSkipDDL(t *testing.T) {
@@ -41,7 +43,22 @@ func TestConvertHeaderToOperation(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "d", op)
 	}
+	{
+		// MariaDB rows events
+		op, err := convertHeaderToOperation(replication.WRITE_ROWS_EVENTv1)
+		assert.NoError(t, err)
+		assert.Equal(t, "c", op)
 
+		op, err = convertHeaderToOperation(replication.MARIADB_DELETE_ROWS_COMPRESSED_EVENT_V1)
+		assert.NoError(t, err)
+		assert.Equal(t, "d", op)
+	}
+	{
+		// Partial JSON updates
+		op, err := convertHeaderToOperation(replication.PARTIAL_UPDATE_ROWS_EVENT)
+		assert.NoError(t, err)
+		assert.Equal(t, "u", op)
+	}
 	{
 		// Random
 		_, err := convertHeaderToOperation(replication.UNKNOWN_EVENT)
@@ -182,3 +199,44 @@ func TestSplitIntoBeforeAndAfter(t *testing.T) {
 		assert.Equal(t, []any{456, "Bella", "The Full Size Aussie"}, beforeList[1])
 	}
 }
+
+func TestZipRow(t *testing.T) {
+	{
+		// Full row image, NULL values are kept
+		row, err := zipRow([]string{"id", "name"}, []any{1, nil}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": 1, "name": nil}, row)
+	}
+	{
+		// Columns that are not in the row image are left out
+		row, err := zipRow([]string{"id", "name", "bio"}, []any{1, nil, nil}, []int{1, 2})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": 1}, row)
+	}
+	{
+		// Mismatched lengths
+		_, err := zipRow([]string{"id"}, []any{1, "foo"}, nil)
+		assert.ErrorContains(t, err, "keys length (1) is different from values length (2)")
+	}
+}
+
+func TestCompleteAfterRow(t *testing.T) {
+	parsedColumns := []schema.Column{{Name: "id", Type: schema.Int}, {Name: "name", Type: schema.Varchar}, {Name: "bio", Type: schema.Text}}
+	{
+		// Full row image
+		afterRow := map[string]any{"id": 1, "name": "foo", "bio": nil}
+		assert.Empty(t, completeAfterRow(map[string]any{"id": 1, "name": "bar", "bio": nil}, afterRow, parsedColumns))
+		assert.Equal(t, map[string]any{"id": 1, "name": "foo", "bio": nil}, afterRow)
+	}
+	{
+		// MINIMAL, the before image only has the key and the after image only has the changed columns
+		afterRow := map[string]any{"name": "foo"}
+		assert.Equal(t, []string{"bio"}, completeAfterRow(map[string]any{"id": 1}, afterRow, parsedColumns))
+		assert.Equal(t, map[string]any{"id": 1, "name": "foo"}, afterRow)
+	}
+	{
+		// NOBLOB, unchanged blob columns are in neither image
+		afterRow := map[string]any{"id": 1, "name": "foo"}
+		assert.Equal(t, []string{"bio"}, completeAfterRow(map[string]any{"id": 1, "name": "bar"}, afterRow, parsedColumns))
+	}
+}
@@ -0,0 +1,191 @@
+package ddl
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/artie-labs/reader/lib/antlr"
+)
+
+// TableLoader returns the `CREATE TABLE` statement of a table, e.g. by running `SHOW CREATE TABLE`.
+type TableLoader func(database string, tableName string) (string, error)
+
+// Catalog - Schema of every database that is streamed, see [TableSelector.IncludesDatabase].
+type Catalog struct {
+	selector TableSelector
+	flavor   string
+	sqlMode  []string
+	// loadTable - Used for tables that are copied or moved from a database that is not streamed, this can be nil.
+	loadTable TableLoader
+	// schemas - Keyed by the lowercased database name.
+	schemas map[string]*SchemaAdapter
+}
+
+func NewCatalog(selector TableSelector, flavor string, sqlMode []string, loadTable TableLoader) *Catalog {
+	return &Catalog{
+		selector:  selector,
+		flavor:    flavor,
+		sqlMode:   sqlMode,
+		loadTable: loadTable,
+		schemas:   make(map[string]*SchemaAdapter),
+	}
+}
+
+// IncludesDatabase returns whether DDL that is run against [database] should be applied.
+func (c *Catalog) IncludesDatabase(database string) bool {
+	return c.selector.IncludesDatabase(database)
+}
+
+// IsDefaultDatabase returns whether [database] is [config.MySQL.Database].
+func (c *Catalog) IsDefaultDatabase(database string) bool {
+	return c.selector.IsDefaultDatabase(database)
+}
+
+func (c *Catalog) GetTableAdapter(database string, tableName string) (TableAdapter, bool) {
+	schema, ok := c.schemas[strings.ToLower(database)]
+	if !ok {
+		return TableAdapter{}, false
+	}
+
+	return schema.GetTableAdapter(tableName)
+}
+
+// ApplyDDL applies [query] which was run against [database], tables that are qualified with a database belong to that
+// database instead. Changes to databases that are not streamed are skipped. It returns the tables that were truncated.
+func (c *Catalog) ApplyDDL(database string, unixTs int64, query string) ([]TableAdapter, error) {
+	results, conditional, err := parse(c.flavor, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query %q: %w", query, err)
+	}
+
+	var truncated []TableAdapter
+	err = applyEvents(query, results, conditional, func(result antlr.Event) error {
+		tableDatabase := cmp.Or(result.GetDatabase(), database)
+		if _, ok := result.(antlr.TruncateTableEvent); ok {
+			if tblAdapter, ok := c.GetTableAdapter(tableDatabase, result.GetTable()); ok {
+				truncated = append(truncated, tblAdapter)
+			}
+			return nil
+		}
+
+		return c.applyDDL(tableDatabase, unixTs, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return truncated, nil
+}
+
+func (c *Catalog) applyDDL(database string, unixTs int64, result antlr.Event) error {
+	switch castedResult := result.(type) {
+	case antlr.RenameTableEvent:
+		if newDatabase := cmp.Or(castedResult.GetNewDatabase(), database); !strings.EqualFold(newDatabase, database) {
+			return c.moveTable(unixTs, database, castedResult.GetTable(), newDatabase, castedResult.GetNewTableName())
+		}
+	case antlr.CopyTableEvent:
+		if copyFromDatabase := cmp.Or(castedResult.GetCopyFromDatabase(), database); !strings.EqualFold(copyFromDatabase, database) {
+			return c.copyTable(unixTs, copyFromDatabase, castedResult.GetCopyFromTableName(), database, castedResult.GetTable())
+		}
+	}
+
+	schema, ok := c.getSchema(database)
+	if !ok {
+		return nil
+	}
+
+	return schema.applyDDL(unixTs, result)
+}
+
+// moveTable handles a table that is renamed into another database.
+func (c *Catalog) moveTable(unixTs int64, database string, tableName string, newDatabase string, newTableName string) error {
+	schema, ok := c.getSchema(database)
+	newSchema, newOk := c.getSchema(newDatabase)
+	if !newOk {
+		if ok {
+			delete(schema.adapters, tableName)
+		}
+		return nil
+	}
+
+	if !ok {
+		return c.addTableFromLoader(unixTs, newDatabase, newTableName)
+	}
+
+	tblAdapter, ok := schema.adapters[tableName]
+	if !ok {
+		return fmt.Errorf("%w: %q", errTableNotFound, tableName)
+	}
+
+	if err := newSchema.addTable(unixTs, newTableName, tblAdapter.columns, tblAdapter.uniqueKeys); err != nil {
+		return err
+	}
+
+	delete(schema.adapters, tableName)
+	return nil
+}
+
+// copyTable handles `CREATE TABLE ... LIKE` where the existing table is in another database.
+func (c *Catalog) copyTable(unixTs int64, copyFromDatabase string, copyFromTableName string, database string, tableName string) error {
+	schema, ok := c.getSchema(database)
+	if !ok {
+		return nil
+	}
+
+	copyFromSchema, ok := c.getSchema(copyFromDatabase)
+	if !ok {
+		return c.addTableFromLoader(unixTs, database, tableName)
+	}
+
+	tblAdapter, ok := copyFromSchema.adapters[copyFromTableName]
+	if !ok {
+		return fmt.Errorf("%w: %q", errTableNotFound, copyFromTableName)
+	}
+
+	return schema.addTable(unixTs, tableName, tblAdapter.columns, tblAdapter.uniqueKeys)
+}
+
+// addTableFromLoader adds a table whose schema is unknown because it comes from a database that is not streamed. The
+// table is skipped if it cannot be loaded, so that the rest of the binlog can still be streamed.
+func (c *Catalog) addTableFromLoader(unixTs int64, database string, tableName string) error {
+	logger := slog.With(slog.String("database", database), slog.String("table", tableName))
+	if c.loadTable == nil {
+		logger.Warn("Skipping table, it comes from a database that is not streamed")
+		return nil
+	}
+
+	query, err := c.loadTable(database, tableName)
+	if err != nil {
+		logger.Warn("Skipping table, it comes from a database that is not streamed and its schema cannot be loaded", slog.Any("err", err))
+		return nil
+	}
+
+	if _, err = c.ApplyDDL(database, unixTs, query); err != nil {
+		return fmt.Errorf("failed to apply DDL of table %q: %w", tableName, err)
+	}
+
+	return nil
+}
+
+// getSchema returns the schema of [database], creating it if the database is streamed.
+func (c *Catalog) getSchema(database string) (*SchemaAdapter, bool) {
+	key := strings.ToLower(database)
+	if schema, ok := c.schemas[key]; ok {
+		return schema, true
+	}
+
+	if !c.selector.IncludesDatabase(database) {
+		return nil, false
+	}
+
+	if c.selector.IsDefaultDatabase(database) {
+		// Keep the spelling from the config since it is used in the source metadata.
+		database = c.selector.database
+	}
+
+	schema := NewSchemaAdapter(database, c.selector, c.flavor, c.sqlMode)
+	c.schemas[key] = &schema
+	return &schema, true
+}
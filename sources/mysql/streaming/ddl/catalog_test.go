@@ -0,0 +1,132 @@
+package ddl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/config"
+)
+
+func TestCatalog_ApplyDDL(t *testing.T) {
+	selector, err := NewTableSelector(config.MySQL{Database: "Foo", Tables: []*config.MySQLTable{{Name: "bar"}}, StreamingSettings: config.MySQLStreamingSettings{
+		TableSelection: &config.MySQLTableSelection{IncludeDatabases: []string{"tenant_.*"}},
+	}})
+	assert.NoError(t, err)
+
+	catalog := NewCatalog(selector, mysql.MySQLFlavor, nil, nil)
+	applyDDL := func(database string, unixTs int64, query string) error {
+		truncated, err := catalog.ApplyDDL(database, unixTs, query)
+		assert.Empty(t, truncated)
+		return err
+	}
+	{
+		// Tables belong to the database the statement ran in unless they are qualified
+		assert.NoError(t, applyDDL("foo", 1, "CREATE TABLE bar (id INT PRIMARY KEY)"))
+		assert.NoError(t, applyDDL("foo", 1, "CREATE TABLE tenant_1.orders (id INT PRIMARY KEY)"))
+		assert.NoError(t, applyDDL("foo", 1, "CREATE TABLE other.orders (id INT PRIMARY KEY)"))
+
+		tblAdapter, ok := catalog.GetTableAdapter("FOO", "bar")
+		assert.True(t, ok)
+		assert.Equal(t, "Foo", tblAdapter.DatabaseName())
+		assert.Equal(t, "Foo.bar", tblAdapter.TopicSuffix())
+
+		tblAdapter, ok = catalog.GetTableAdapter("tenant_1", "orders")
+		assert.True(t, ok)
+		assert.True(t, tblAdapter.ShouldReplicate())
+		assert.Equal(t, "tenant_1.orders", tblAdapter.TopicSuffix())
+
+		_, ok = catalog.GetTableAdapter("other", "orders")
+		assert.False(t, ok)
+		assert.Len(t, catalog.schemas, 2)
+	}
+	{
+		// Copying and moving tables between databases
+		assert.NoError(t, applyDDL("tenant_2", 2, "CREATE TABLE orders LIKE tenant_1.orders"))
+		tblAdapter, ok := catalog.GetTableAdapter("tenant_2", "orders")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"id"}, tblAdapter.ColumnNames())
+
+		assert.NoError(t, applyDDL("foo", 3, "RENAME TABLE tenant_2.orders TO tenant_3.orders_v2"))
+		_, ok = catalog.GetTableAdapter("tenant_2", "orders")
+		assert.False(t, ok)
+		tblAdapter, ok = catalog.GetTableAdapter("tenant_3", "orders_v2")
+		assert.True(t, ok)
+		assert.Equal(t, int64(3), tblAdapter.GetUnixTs())
+
+		// Moving a table out of the streamed databases drops it
+		assert.NoError(t, applyDDL("tenant_3", 4, "RENAME TABLE orders_v2 TO other.orders_v2"))
+		_, ok = catalog.GetTableAdapter("tenant_3", "orders_v2")
+		assert.False(t, ok)
+
+		// The schema of tables in databases that are not streamed is unknown, they are skipped without a table loader
+		assert.NoError(t, applyDDL("foo", 5, "RENAME TABLE other.orders TO tenant_1.other_orders"))
+		_, ok = catalog.GetTableAdapter("tenant_1", "other_orders")
+		assert.False(t, ok)
+	}
+	{
+		// Truncated tables are returned
+		assert.NoError(t, applyDDL("foo", 6, "CREATE TABLE baz (id INT PRIMARY KEY)"))
+		truncated, err := catalog.ApplyDDL("foo", 7, "TRUNCATE TABLE bar")
+		assert.NoError(t, err)
+		assert.Len(t, truncated, 1)
+		assert.Equal(t, "bar", truncated[0].TableName())
+
+		truncated, err = catalog.ApplyDDL("tenant_1", 7, "TRUNCATE TABLE Foo.baz")
+		assert.NoError(t, err)
+		assert.Len(t, truncated, 1)
+		assert.Equal(t, "Foo.baz", truncated[0].TopicSuffix())
+
+		// Tables that we don't know about are skipped
+		truncated, err = catalog.ApplyDDL("foo", 7, "TRUNCATE TABLE other.orders")
+		assert.NoError(t, err)
+		assert.Empty(t, truncated)
+	}
+}
+
+func TestCatalog_ApplyDDL_TableFromDatabaseThatIsNotStreamed(t *testing.T) {
+	selector, err := NewTableSelector(config.MySQL{Database: "foo", StreamingSettings: config.MySQLStreamingSettings{
+		TableSelection: &config.MySQLTableSelection{IncludeDatabases: []string{"tenant_.*"}},
+	}})
+	assert.NoError(t, err)
+
+	var loaded []string
+	catalog := NewCatalog(selector, mysql.MySQLFlavor, nil, func(database string, tableName string) (string, error) {
+		loaded = append(loaded, database+"."+tableName)
+		if tableName == "missing" {
+			return "", fmt.Errorf("table does not exist")
+		}
+		return fmt.Sprintf("CREATE TABLE `%s` (`id` int NOT NULL, `name` text, PRIMARY KEY (`id`))", tableName), nil
+	})
+	{
+		// Copying a table from a database that is not streamed
+		truncated, err := catalog.ApplyDDL("tenant_a", 1, "CREATE TABLE tenant_a.t LIKE excluded_db.s")
+		assert.NoError(t, err)
+		assert.Empty(t, truncated)
+
+		tblAdapter, ok := catalog.GetTableAdapter("tenant_a", "t")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"id", "name"}, tblAdapter.ColumnNames())
+		assert.Equal(t, int64(1), tblAdapter.GetUnixTs())
+	}
+	{
+		// Moving a table from a database that is not streamed
+		truncated, err := catalog.ApplyDDL("excluded_db", 2, "RENAME TABLE excluded_db.x TO tenant_a.x")
+		assert.NoError(t, err)
+		assert.Empty(t, truncated)
+
+		tblAdapter, ok := catalog.GetTableAdapter("tenant_a", "x")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"id", "name"}, tblAdapter.ColumnNames())
+	}
+	{
+		// Tables that cannot be loaded are skipped
+		_, err := catalog.ApplyDDL("foo", 3, "RENAME TABLE excluded_db.missing TO tenant_a.missing")
+		assert.NoError(t, err)
+		_, ok := catalog.GetTableAdapter("tenant_a", "missing")
+		assert.False(t, ok)
+	}
+	assert.Equal(t, []string{"tenant_a.t", "tenant_a.x", "tenant_a.missing"}, loaded)
+}
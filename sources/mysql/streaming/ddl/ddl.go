@@ -1,32 +1,40 @@
 package ddl
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
+	"strings"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
 
-	"github.com/artie-labs/reader/config"
 	"github.com/artie-labs/reader/lib/antlr"
 )
 
+var (
+	errTableNotFound       = errors.New("table not found")
+	errColumnNotFound      = errors.New("column not found")
+	errColumnAlreadyExists = errors.New("column already exists")
+)
+
+// SchemaAdapter - Schema of the tables of a single database.
 type SchemaAdapter struct {
-	adapters    map[string]TableAdapter
-	tableCfgMap map[string]*config.MySQLTable
-	dbName      string
-	sqlMode     []string
+	adapters map[string]TableAdapter
+	selector TableSelector
+	dbName   string
+	// flavor - Either [mysql.MySQLFlavor] or [mysql.MariaDBFlavor], this determines how DDL is parsed.
+	flavor  string
+	sqlMode []string
 }
 
-func NewSchemaAdapter(cfg config.MySQL, sqlMode []string) SchemaAdapter {
-	tableCfgMap := make(map[string]*config.MySQLTable)
-	for _, tbl := range cfg.Tables {
-		tableCfgMap[tbl.Name] = tbl
-	}
-
+func NewSchemaAdapter(dbName string, selector TableSelector, flavor string, sqlMode []string) SchemaAdapter {
 	return SchemaAdapter{
-		adapters:    make(map[string]TableAdapter),
-		tableCfgMap: tableCfgMap,
-		dbName:      cfg.Database,
-		sqlMode:     sqlMode,
+		adapters: make(map[string]TableAdapter),
+		selector: selector,
+		dbName:   dbName,
+		flavor:   flavor,
+		sqlMode:  sqlMode,
 	}
 }
 
@@ -39,14 +47,38 @@ func (s *SchemaAdapter) GetTableAdapter(tableName string) (TableAdapter, bool) {
 	return tblAdapter, ok
 }
 
+// ApplyDDL applies [query] to this database, tables that are qualified with a database are assumed to belong to it.
+// Use [Catalog.ApplyDDL] to apply DDL that may span several databases.
 func (s *SchemaAdapter) ApplyDDL(unixTs int64, query string) error {
-	results, err := antlr.Parse(query)
+	results, conditional, err := parse(s.flavor, query)
 	if err != nil {
 		return fmt.Errorf("failed to parse query %q: %w", query, err)
 	}
 
+	return applyEvents(query, results, conditional, func(result antlr.Event) error {
+		return s.applyDDL(unixTs, result)
+	})
+}
+
+func parse(flavor string, query string) ([]antlr.Event, bool, error) {
+	if flavor == mysql.MariaDBFlavor {
+		return antlr.ParseMariaDB(query)
+	}
+
+	results, err := antlr.Parse(query)
+	return results, false, err
+}
+
+// applyEvents calls [apply] for each of the events that [query] was parsed into.
+func applyEvents(query string, results []antlr.Event, conditional bool, apply func(result antlr.Event) error) error {
 	for _, result := range results {
-		if err = s.applyDDL(unixTs, result); err != nil {
+		if err := apply(result); err != nil {
+			if conditional && isSchemaMismatch(err) {
+				// MariaDB skips the parts of `IF [NOT] EXISTS` statements that do not apply and still writes them to the binlog.
+				slog.Info("Skipping DDL that does not apply to the current schema", slog.String("query", query), slog.Any("err", err))
+				continue
+			}
+
 			return fmt.Errorf("failed to apply ddl %q: %w", query, err)
 		}
 	}
@@ -54,11 +86,18 @@ func (s *SchemaAdapter) ApplyDDL(unixTs int64, query string) error {
 	return nil
 }
 
+func isSchemaMismatch(err error) bool {
+	return errors.Is(err, errTableNotFound) || errors.Is(err, errColumnNotFound) || errors.Is(err, errColumnAlreadyExists)
+}
+
 func (s *SchemaAdapter) applyDDL(unixTs int64, result antlr.Event) error {
 	switch castedResult := result.(type) {
 	case antlr.DropTableEvent:
 		delete(s.adapters, result.GetTable())
 		return nil
+	case antlr.TruncateTableEvent:
+		// Truncating a table does not change its schema.
+		return nil
 	case antlr.CreateTableEvent:
 		var cols []Column
 		for _, col := range result.GetColumns() {
@@ -66,49 +105,36 @@ func (s *SchemaAdapter) applyDDL(unixTs int64, result antlr.Event) error {
 				Name:       col.Name,
 				PrimaryKey: col.PrimaryKey,
 				DataType:   col.DataType,
+				NotNull:    col.NotNull,
 			})
 		}
 
-		tblAdapter, err := NewTableAdapter(s.dbName, s.tableCfgMap[result.GetTable()], cols, unixTs, s.sqlMode)
-		if err != nil {
-			return err
-		}
-
-		s.adapters[result.GetTable()] = tblAdapter
-		return nil
+		return s.addTable(unixTs, result.GetTable(), cols, castedResult.GetUniqueKeys())
 	case antlr.CopyTableEvent:
 		existingTableAdapter, ok := s.adapters[castedResult.GetCopyFromTableName()]
 		if !ok {
-			return fmt.Errorf("table not found: %q", castedResult.GetTable())
-		}
-
-		tblAdapter, err := NewTableAdapter(s.dbName, s.tableCfgMap[result.GetTable()], existingTableAdapter.columns, unixTs, s.sqlMode)
-		if err != nil {
-			return err
+			return fmt.Errorf("%w: %q", errTableNotFound, castedResult.GetTable())
 		}
 
-		s.adapters[result.GetTable()] = tblAdapter
-		return nil
+		return s.addTable(unixTs, result.GetTable(), existingTableAdapter.columns, existingTableAdapter.uniqueKeys)
 	case antlr.RenameTableEvent:
 		tblAdapter, ok := s.adapters[castedResult.GetTable()]
 		if !ok {
-			return fmt.Errorf("table not found: %q", result.GetTable())
+			return fmt.Errorf("%w: %q", errTableNotFound, result.GetTable())
 		}
 
-		newTableAdapter, err := NewTableAdapter(s.dbName, s.tableCfgMap[castedResult.GetNewTableName()], tblAdapter.columns, unixTs, s.sqlMode)
-		if err != nil {
+		// Create a new table adapter and delete the old one
+		if err := s.addTable(unixTs, castedResult.GetNewTableName(), tblAdapter.columns, tblAdapter.uniqueKeys); err != nil {
 			return err
 		}
 
-		// Delete the old table adapter and create a new one
 		delete(s.adapters, result.GetTable())
-		s.adapters[castedResult.GetNewTableName()] = newTableAdapter
 		return nil
 	}
 
 	tblAdapter, ok := s.adapters[result.GetTable()]
 	if !ok {
-		return fmt.Errorf("table not found: %q", result.GetTable())
+		return fmt.Errorf("%w: %q", errTableNotFound, result.GetTable())
 	}
 
 	switch castedResult := result.(type) {
@@ -116,17 +142,18 @@ func (s *SchemaAdapter) applyDDL(unixTs int64, result antlr.Event) error {
 		for _, col := range castedResult.GetColumns() {
 			columnIdx := slices.IndexFunc(tblAdapter.columns, func(x Column) bool { return x.Name == col.PreviousName })
 			if columnIdx == -1 {
-				return fmt.Errorf("column not found: %q", col.PreviousName)
+				return fmt.Errorf("%w: %q", errColumnNotFound, col.PreviousName)
 			}
 
 			// Apply the new name
 			tblAdapter.columns[columnIdx].Name = col.Name
+			tblAdapter.uniqueKeys = updateUniqueKeyColumns(tblAdapter.uniqueKeys, col.PreviousName, col.Name)
 		}
 	case antlr.AddPrimaryKeyEvent:
 		for _, col := range castedResult.GetColumns() {
 			columnIdx := slices.IndexFunc(tblAdapter.columns, func(x Column) bool { return x.Name == col.Name })
 			if columnIdx == -1 {
-				return fmt.Errorf("column not found: %q", col.Name)
+				return fmt.Errorf("%w: %q", errColumnNotFound, col.Name)
 			}
 
 			tblAdapter.columns[columnIdx].PrimaryKey = true
@@ -135,10 +162,11 @@ func (s *SchemaAdapter) applyDDL(unixTs int64, result antlr.Event) error {
 		for _, col := range castedResult.GetColumns() {
 			columnIdx := slices.IndexFunc(tblAdapter.columns, func(x Column) bool { return x.Name == col.Name })
 			if columnIdx == -1 {
-				return fmt.Errorf("column not found: %q", col.Name)
+				return fmt.Errorf("%w: %q", errColumnNotFound, col.Name)
 			}
 
 			tblAdapter.columns = slices.Delete(tblAdapter.columns, columnIdx, columnIdx+1)
+			tblAdapter.uniqueKeys = updateUniqueKeyColumns(tblAdapter.uniqueKeys, col.Name, "")
 		}
 
 		s.adapters[castedResult.GetTable()] = tblAdapter
@@ -146,22 +174,30 @@ func (s *SchemaAdapter) applyDDL(unixTs int64, result antlr.Event) error {
 		for _, col := range castedResult.GetColumns() {
 			columnIdx := slices.IndexFunc(tblAdapter.columns, func(x Column) bool { return x.Name == col.Name })
 			if columnIdx == -1 {
-				return fmt.Errorf("column not found: %q", col.Name)
+				return fmt.Errorf("%w: %q", errColumnNotFound, col.Name)
 			}
 
 			tblAdapter.columns[columnIdx].DataType = col.DataType
+			tblAdapter.columns[columnIdx].NotNull = col.NotNull
 		}
 	case antlr.AddColumnsEvent:
 		for _, col := range castedResult.GetColumns() {
 			if slices.ContainsFunc(tblAdapter.columns, func(x Column) bool { return x.Name == col.Name }) {
-				return fmt.Errorf("column already exists: %q", col.Name)
+				return fmt.Errorf("%w: %q", errColumnAlreadyExists, col.Name)
 			}
 
 			tblAdapter.columns = append(tblAdapter.columns, Column{
 				Name:     col.Name,
 				DataType: col.DataType,
+				NotNull:  col.NotNull,
 			})
 		}
+	case antlr.AddUniqueKeyEvent:
+		tblAdapter.uniqueKeys = append(slices.Clone(tblAdapter.uniqueKeys), castedResult.GetUniqueKey())
+	case antlr.DropIndexEvent:
+		tblAdapter.uniqueKeys = slices.DeleteFunc(slices.Clone(tblAdapter.uniqueKeys), func(x antlr.UniqueKey) bool {
+			return strings.EqualFold(x.Name, castedResult.GetIndexName())
+		})
 	default:
 		slog.Info("Skipping event type", slog.Any("eventType", fmt.Sprintf("%T", result)))
 	}
@@ -173,7 +209,7 @@ func (s *SchemaAdapter) applyDDL(unixTs int64, result antlr.Event) error {
 				// Find the current position, delete it and insert it at the first position
 				columnIdx := slices.IndexFunc(tblAdapter.columns, func(x Column) bool { return x.Name == col.Name })
 				if columnIdx == -1 {
-					return fmt.Errorf("column not found: %q", col.Name)
+					return fmt.Errorf("%w: %q", errColumnNotFound, col.Name)
 				}
 
 				_col := tblAdapter.columns[columnIdx]
@@ -185,7 +221,7 @@ func (s *SchemaAdapter) applyDDL(unixTs int64, result antlr.Event) error {
 				// Find the current position, delete it and insert it after the specified column
 				columnIdx := slices.IndexFunc(tblAdapter.columns, func(x Column) bool { return x.Name == col.Name })
 				if columnIdx == -1 {
-					return fmt.Errorf("column not found: %q", col.Name)
+					return fmt.Errorf("%w: %q", errColumnNotFound, col.Name)
 				}
 
 				_col := tblAdapter.columns[columnIdx]
@@ -195,7 +231,7 @@ func (s *SchemaAdapter) applyDDL(unixTs int64, result antlr.Event) error {
 				// Find the column to insert after
 				afterColumnIdx := slices.IndexFunc(tblAdapter.columns, func(x Column) bool { return x.Name == castedPosition.Column() })
 				if afterColumnIdx == -1 {
-					return fmt.Errorf("column not found: %q", castedPosition.Column())
+					return fmt.Errorf("%w: %q", errColumnNotFound, castedPosition.Column())
 				}
 
 				// Insert the column after the specified column
@@ -215,3 +251,36 @@ func (s *SchemaAdapter) applyDDL(unixTs int64, result antlr.Event) error {
 	s.adapters[result.GetTable()] = tblAdapter
 	return nil
 }
+
+// addTable adds a table or replaces the schema of an existing one, the table is replicated if it is selected.
+func (s *SchemaAdapter) addTable(unixTs int64, tableName string, columns []Column, uniqueKeys []antlr.UniqueKey) error {
+	tblAdapter, err := NewTableAdapter(s.dbName, s.selector.TableConfig(s.dbName, tableName), columns, uniqueKeys, unixTs, s.sqlMode)
+	if err != nil {
+		return err
+	}
+
+	s.adapters[tableName] = tblAdapter
+	return nil
+}
+
+// updateUniqueKeyColumns renames [name] to [newName] in every unique key, or removes it if [newName] is empty. MySQL
+// drops an index once all of its columns have been dropped.
+func updateUniqueKeyColumns(uniqueKeys []antlr.UniqueKey, name string, newName string) []antlr.UniqueKey {
+	var result []antlr.UniqueKey
+	for _, uniqueKey := range uniqueKeys {
+		var columns []string
+		for _, col := range uniqueKey.Columns {
+			if col != name {
+				columns = append(columns, col)
+			} else if newName != "" {
+				columns = append(columns, newName)
+			}
+		}
+
+		if len(columns) > 0 {
+			result = append(result, antlr.UniqueKey{Name: uniqueKey.Name, Columns: columns})
+		}
+	}
+
+	return result
+}
@@ -1,14 +1,23 @@
 package ddl
 
 import (
+	"github.com/go-mysql-org/go-mysql/mysql"
+
 	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/debezium/transformer"
 	"github.com/artie-labs/reader/lib/mysql/schema"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
 
+func newSchemaAdapter(t *testing.T, cfg config.MySQL, flavor string, sqlMode []string) SchemaAdapter {
+	selector, err := NewTableSelector(cfg)
+	assert.NoError(t, err)
+	return NewSchemaAdapter(cfg.Database, selector, flavor, sqlMode)
+}
+
 func initializeAdapter(t *testing.T) SchemaAdapter {
-	adapter := NewSchemaAdapter(config.MySQL{Database: "foo"}, nil)
+	adapter := newSchemaAdapter(t, config.MySQL{Database: "foo"}, mysql.MySQLFlavor, nil)
 	assert.Equal(t, "foo", adapter.dbName)
 	// Create a table first
 	assert.NoError(t, adapter.ApplyDDL(99, "CREATE TABLE test_table (id INT PRIMARY KEY, name VARCHAR(255), email VARCHAR(255));"))
@@ -17,7 +26,7 @@ func initializeAdapter(t *testing.T) SchemaAdapter {
 	assert.Len(t, adapter.adapters, 1)
 	assert.Len(t, adapter.adapters["test_table"].columns, 3)
 	assert.Equal(t, int64(99), adapter.adapters["test_table"].unixTs)
-	assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true}, adapter.adapters["test_table"].columns[0])
+	assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true, NotNull: true}, adapter.adapters["test_table"].columns[0])
 	assert.Equal(t, Column{Name: "name", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[1])
 	assert.Equal(t, Column{Name: "email", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[2])
 	return adapter
@@ -26,7 +35,7 @@ func initializeAdapter(t *testing.T) SchemaAdapter {
 func TestSchemaAdapter_SQLMode(t *testing.T) {
 	{
 		// SQL mode for `REAL_AS_FLOAT` is configured
-		adapter := NewSchemaAdapter(config.MySQL{Database: "foo"}, []string{"REAL_AS_FLOAT"})
+		adapter := newSchemaAdapter(t, config.MySQL{Database: "foo"}, mysql.MySQLFlavor, []string{"REAL_AS_FLOAT"})
 		assert.Equal(t, "foo", adapter.dbName)
 
 		assert.NoError(t, adapter.ApplyDDL(99, "CREATE TABLE foo (real_test REAL);"))
@@ -42,7 +51,7 @@ func TestSchemaAdapter_SQLMode(t *testing.T) {
 	}
 	{
 		// No SQL mode
-		adapter := NewSchemaAdapter(config.MySQL{Database: "foo"}, []string{""})
+		adapter := newSchemaAdapter(t, config.MySQL{Database: "foo"}, mysql.MySQLFlavor, []string{""})
 		assert.Equal(t, "foo", adapter.dbName)
 
 		assert.NoError(t, adapter.ApplyDDL(99, "CREATE TABLE foo (real_test REAL);"))
@@ -61,7 +70,7 @@ func TestSchemaAdapter_SQLMode(t *testing.T) {
 func TestSchemaAdapter_ColumnFiltering(t *testing.T) {
 	{
 		// Excluding column [exclude_me]
-		adapter := NewSchemaAdapter(config.MySQL{Database: "foo", Tables: []*config.MySQLTable{{Name: "test_table", ExcludeColumns: []string{"exclude_me"}}}}, nil)
+		adapter := newSchemaAdapter(t, config.MySQL{Database: "foo", Tables: []*config.MySQLTable{{Name: "test_table", ExcludeColumns: []string{"exclude_me"}}}}, mysql.MySQLFlavor, nil)
 		assert.Equal(t, "foo", adapter.dbName)
 
 		assert.NoError(t, adapter.ApplyDDL(99, "CREATE TABLE test_table (id INT PRIMARY KEY, exclude_me VARCHAR(255));"))
@@ -79,7 +88,7 @@ func TestSchemaAdapter_ColumnFiltering(t *testing.T) {
 	}
 	{
 		// Not excluding
-		adapter := NewSchemaAdapter(config.MySQL{Database: "foo", Tables: []*config.MySQLTable{{Name: "test_table"}}}, nil)
+		adapter := newSchemaAdapter(t, config.MySQL{Database: "foo", Tables: []*config.MySQLTable{{Name: "test_table"}}}, mysql.MySQLFlavor, nil)
 		assert.Equal(t, "foo", adapter.dbName)
 
 		assert.NoError(t, adapter.ApplyDDL(99, "CREATE TABLE test_table (id INT PRIMARY KEY, name VARCHAR(255));"))
@@ -115,7 +124,7 @@ func TestSchemaAdapter_ApplyDDL(t *testing.T) {
 			assert.NoError(t, adapter.ApplyDDL(123, "ALTER TABLE test_table RENAME COLUMN id TO new_id;"))
 			assert.Equal(t, int64(123), adapter.adapters["test_table"].unixTs)
 			assert.Len(t, adapter.adapters["test_table"].columns, 3)
-			assert.Equal(t, Column{Name: "new_id", DataType: "INT", PrimaryKey: true}, adapter.adapters["test_table"].columns[0])
+			assert.Equal(t, Column{Name: "new_id", DataType: "INT", PrimaryKey: true, NotNull: true}, adapter.adapters["test_table"].columns[0])
 		}
 	}
 	{
@@ -136,7 +145,7 @@ func TestSchemaAdapter_ApplyDDL(t *testing.T) {
 			assert.NoError(t, adapter.ApplyDDL(56, "ALTER TABLE test_table ADD PRIMARY KEY (name);"))
 			assert.Equal(t, int64(56), adapter.adapters["test_table"].unixTs)
 			assert.Len(t, adapter.adapters["test_table"].columns, 3)
-			assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true}, adapter.adapters["test_table"].columns[0])
+			assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true, NotNull: true}, adapter.adapters["test_table"].columns[0])
 			assert.Equal(t, Column{Name: "name", DataType: "VARCHAR(255)", PrimaryKey: true}, adapter.adapters["test_table"].columns[1])
 			assert.Equal(t, Column{Name: "email", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[2])
 		}
@@ -227,7 +236,7 @@ func TestSchemaAdapter_ApplyDDL(t *testing.T) {
 			assert.NoError(t, adapter.ApplyDDL(9, "ALTER TABLE test_table DROP COLUMN name;"))
 			assert.Equal(t, int64(9), adapter.adapters["test_table"].unixTs)
 			assert.Len(t, adapter.adapters["test_table"].columns, 2)
-			assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true}, adapter.adapters["test_table"].columns[0])
+			assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true, NotNull: true}, adapter.adapters["test_table"].columns[0])
 			assert.Equal(t, Column{Name: "email", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[1])
 		}
 		{
@@ -274,7 +283,7 @@ func TestSchemaAdapter_ApplyDDL(t *testing.T) {
 				assert.Equal(t, int64(123), adapter.adapters["test_table"].unixTs)
 				assert.Len(t, adapter.adapters["test_table"].columns, 4)
 				assert.Equal(t, Column{Name: "new_column1", DataType: "INT"}, adapter.adapters["test_table"].columns[0])
-				assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true}, adapter.adapters["test_table"].columns[1])
+				assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true, NotNull: true}, adapter.adapters["test_table"].columns[1])
 				assert.Equal(t, Column{Name: "name", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[2])
 				assert.Equal(t, Column{Name: "email", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[3])
 			}
@@ -286,7 +295,7 @@ func TestSchemaAdapter_ApplyDDL(t *testing.T) {
 				assert.Len(t, adapter.adapters["test_table"].columns, 5)
 				assert.Equal(t, Column{Name: "new_column3", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[0])
 				assert.Equal(t, Column{Name: "new_column2", DataType: "INT"}, adapter.adapters["test_table"].columns[1])
-				assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true}, adapter.adapters["test_table"].columns[2])
+				assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true, NotNull: true}, adapter.adapters["test_table"].columns[2])
 				assert.Equal(t, Column{Name: "name", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[3])
 			}
 			{
@@ -295,7 +304,7 @@ func TestSchemaAdapter_ApplyDDL(t *testing.T) {
 				assert.NoError(t, adapter.ApplyDDL(345, "ALTER TABLE test_table ADD COLUMN new_column1 INT AFTER name;"))
 				assert.Equal(t, int64(345), adapter.adapters["test_table"].unixTs)
 				assert.Len(t, adapter.adapters["test_table"].columns, 4)
-				assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true}, adapter.adapters["test_table"].columns[0])
+				assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true, NotNull: true}, adapter.adapters["test_table"].columns[0])
 				assert.Equal(t, Column{Name: "name", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[1])
 				assert.Equal(t, Column{Name: "new_column1", DataType: "INT"}, adapter.adapters["test_table"].columns[2])
 				assert.Equal(t, Column{Name: "email", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[3])
@@ -307,7 +316,7 @@ func TestSchemaAdapter_ApplyDDL(t *testing.T) {
 				assert.Equal(t, int64(456), adapter.adapters["test_table"].unixTs)
 				assert.Len(t, adapter.adapters["test_table"].columns, 5)
 				assert.Equal(t, Column{Name: "new_column2", DataType: "INT"}, adapter.adapters["test_table"].columns[0])
-				assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true}, adapter.adapters["test_table"].columns[1])
+				assert.Equal(t, Column{Name: "id", DataType: "INT", PrimaryKey: true, NotNull: true}, adapter.adapters["test_table"].columns[1])
 				assert.Equal(t, Column{Name: "name", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[2])
 				assert.Equal(t, Column{Name: "new_column3", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[3])
 				assert.Equal(t, Column{Name: "email", DataType: "VARCHAR(255)"}, adapter.adapters["test_table"].columns[4])
@@ -315,3 +324,55 @@ func TestSchemaAdapter_ApplyDDL(t *testing.T) {
 		}
 	}
 }
+
+func TestSchemaAdapter_PartitionKeys(t *testing.T) {
+	tableCfg := &config.MySQLTable{Name: "test_table"}
+	adapter := newSchemaAdapter(t, config.MySQL{Database: "foo", Tables: []*config.MySQLTable{tableCfg}}, mysql.MySQLFlavor, nil)
+	{
+		// Primary key
+		assert.NoError(t, adapter.ApplyDDL(0, "CREATE TABLE test_table (id INT PRIMARY KEY, a INT NOT NULL, b INT, UNIQUE KEY a_idx (a));"))
+		assert.Equal(t, []string{"id"}, adapter.adapters["test_table"].PartitionKeys())
+	}
+	{
+		// First unique key whose columns are all NOT NULL
+		assert.NoError(t, adapter.ApplyDDL(0, "CREATE TABLE test_table (id INT, a INT NOT NULL, b INT, UNIQUE KEY a_b_idx (a, b), UNIQUE KEY B_idx (a));"))
+		assert.Equal(t, []string{"a"}, adapter.adapters["test_table"].PartitionKeys())
+
+		// Renaming the column renames the key, dropping the index falls back to the row hash
+		assert.NoError(t, adapter.ApplyDDL(0, "ALTER TABLE test_table RENAME COLUMN a TO c;"))
+		assert.Equal(t, []string{"c"}, adapter.adapters["test_table"].PartitionKeys())
+		assert.NoError(t, adapter.ApplyDDL(0, "ALTER TABLE test_table DROP INDEX b_idx;"))
+		assert.Equal(t, []string{transformer.RowHashKey}, adapter.adapters["test_table"].PartitionKeys())
+
+		assert.NoError(t, adapter.ApplyDDL(0, "ALTER TABLE test_table ADD UNIQUE KEY id_idx (id), MODIFY COLUMN id INT NOT NULL;"))
+		assert.Equal(t, []string{"id"}, adapter.adapters["test_table"].PartitionKeys())
+	}
+	{
+		// Primary key override
+		tableCfg.PrimaryKeysOverride = []string{"b"}
+		assert.NoError(t, adapter.ApplyDDL(0, "CREATE TABLE test_table (id INT PRIMARY KEY, b INT);"))
+		assert.Equal(t, []string{"b"}, adapter.adapters["test_table"].PartitionKeys())
+	}
+}
+
+func TestSchemaAdapter_MariaDB(t *testing.T) {
+	adapter := newSchemaAdapter(t, config.MySQL{Database: "foo"}, mysql.MariaDBFlavor, nil)
+	assert.NoError(t, adapter.ApplyDDL(0, "CREATE TABLE test_table (id INT PRIMARY KEY, name VARCHAR(255));"))
+	{
+		// CREATE OR REPLACE TABLE
+		assert.NoError(t, adapter.ApplyDDL(0, "CREATE OR REPLACE TABLE test_table (id INT PRIMARY KEY, email VARCHAR(255));"))
+		assert.Equal(t, []string{"id", "email"}, adapter.adapters["test_table"].ColumnNames())
+	}
+	{
+		// Clauses that MariaDB skipped are skipped as well
+		assert.NoError(t, adapter.ApplyDDL(0, "ALTER TABLE test_table ADD COLUMN IF NOT EXISTS email VARCHAR(255);"))
+		assert.NoError(t, adapter.ApplyDDL(0, "ALTER TABLE test_table DROP COLUMN IF EXISTS name;"))
+		assert.NoError(t, adapter.ApplyDDL(0, "ALTER TABLE test_table ADD COLUMN IF NOT EXISTS name VARCHAR(255);"))
+		assert.Equal(t, []string{"id", "email", "name"}, adapter.adapters["test_table"].ColumnNames())
+	}
+	{
+		// Unconditional statements still have to match the schema
+		err := adapter.ApplyDDL(0, "ALTER TABLE test_table DROP COLUMN non_existing_column;")
+		assert.ErrorContains(t, err, `column not found: "non_existing_column"`)
+	}
+}
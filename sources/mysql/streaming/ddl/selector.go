@@ -0,0 +1,124 @@
+package ddl
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/artie-labs/reader/config"
+)
+
+// systemDatabases - Databases that are not selected unless they match [config.MySQLTableSelection.IncludeDatabases].
+var systemDatabases = []string{"information_schema", "mysql", "performance_schema", "sys"}
+
+// TableSelector - Decides which tables are streamed, these are [config.MySQL.Tables] and the tables that match
+// [config.MySQLStreamingSettings.TableSelection].
+type TableSelector struct {
+	database string
+	tables   map[string]*config.MySQLTable
+
+	// selection - Whether [config.MySQLStreamingSettings.TableSelection] is set.
+	selection        bool
+	includeDatabases []*regexp.Regexp
+	excludeDatabases []*regexp.Regexp
+	includeTables    []*regexp.Regexp
+	excludeTables    []*regexp.Regexp
+}
+
+func NewTableSelector(cfg config.MySQL) (TableSelector, error) {
+	tables := make(map[string]*config.MySQLTable)
+	for _, tbl := range cfg.Tables {
+		tables[tbl.Name] = tbl
+	}
+
+	selection := cfg.StreamingSettings.TableSelection
+	if selection == nil {
+		return TableSelector{database: cfg.Database, tables: tables}, nil
+	}
+
+	var patterns [4][]*regexp.Regexp
+	for i, x := range [][]string{selection.IncludeDatabases, selection.ExcludeDatabases, selection.IncludeTables, selection.ExcludeTables} {
+		var err error
+		if patterns[i], err = compilePatterns(x); err != nil {
+			return TableSelector{}, err
+		}
+	}
+
+	return TableSelector{
+		database:         cfg.Database,
+		tables:           tables,
+		selection:        true,
+		includeDatabases: patterns[0],
+		excludeDatabases: patterns[1],
+		includeTables:    patterns[2],
+		excludeTables:    patterns[3],
+	}, nil
+}
+
+// compilePatterns - Patterns have to match the whole name.
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var out []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile pattern %q: %w", pattern, err)
+		}
+
+		out = append(out, re)
+	}
+
+	return out, nil
+}
+
+// IsDefaultDatabase returns whether [database] is [config.MySQL.Database].
+func (t TableSelector) IsDefaultDatabase(database string) bool {
+	return strings.EqualFold(t.database, database)
+}
+
+// HasSelection returns whether tables are also selected by pattern, in which case databases other than the default
+// database may be streamed.
+func (t TableSelector) HasSelection() bool {
+	return t.selection
+}
+
+// IncludesDatabase returns whether tables of [database] may be streamed.
+func (t TableSelector) IncludesDatabase(database string) bool {
+	if t.IsDefaultDatabase(database) {
+		return true
+	}
+
+	if !t.selection || matchesAny(t.excludeDatabases, database) {
+		return false
+	}
+
+	if len(t.includeDatabases) == 0 {
+		return !slices.Contains(systemDatabases, strings.ToLower(database))
+	}
+
+	return matchesAny(t.includeDatabases, database)
+}
+
+// TableConfig returns the config of the table, this is nil if the table should not be streamed.
+func (t TableSelector) TableConfig(database string, table string) *config.MySQLTable {
+	if t.IsDefaultDatabase(database) {
+		if tableCfg, ok := t.tables[table]; ok {
+			return tableCfg
+		}
+	}
+
+	if !t.selection || !t.IncludesDatabase(database) {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s.%s", database, table)
+	if matchesAny(t.excludeTables, name) || (len(t.includeTables) > 0 && !matchesAny(t.includeTables, name)) {
+		return nil
+	}
+
+	return &config.MySQLTable{Name: table}
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	return slices.ContainsFunc(patterns, func(pattern *regexp.Regexp) bool { return pattern.MatchString(s) })
+}
@@ -0,0 +1,68 @@
+package ddl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/config"
+)
+
+func TestTableSelector(t *testing.T) {
+	{
+		// Without a selection only the configured tables are streamed
+		selector, err := NewTableSelector(config.MySQL{Database: "foo", Tables: []*config.MySQLTable{{Name: "bar"}}})
+		assert.NoError(t, err)
+		assert.False(t, selector.HasSelection())
+		assert.True(t, selector.IncludesDatabase("FOO"))
+		assert.False(t, selector.IncludesDatabase("other"))
+		assert.Equal(t, &config.MySQLTable{Name: "bar"}, selector.TableConfig("foo", "bar"))
+		assert.Nil(t, selector.TableConfig("foo", "baz"))
+		assert.Nil(t, selector.TableConfig("other", "bar"))
+	}
+	{
+		// Every database except system databases
+		selector, err := NewTableSelector(config.MySQL{Database: "foo", StreamingSettings: config.MySQLStreamingSettings{
+			TableSelection: &config.MySQLTableSelection{ExcludeDatabases: []string{"tmp_.*"}},
+		}})
+		assert.NoError(t, err)
+		assert.True(t, selector.HasSelection())
+		assert.True(t, selector.IncludesDatabase("tenant_1"))
+		assert.True(t, selector.IncludesDatabase("my_tmp_db"))
+		assert.False(t, selector.IncludesDatabase("tmp_db"))
+		assert.False(t, selector.IncludesDatabase("mysql"))
+		assert.Equal(t, &config.MySQLTable{Name: "bar"}, selector.TableConfig("tenant_1", "bar"))
+		assert.Equal(t, &config.MySQLTable{Name: "bar"}, selector.TableConfig("foo", "bar"))
+		assert.Nil(t, selector.TableConfig("tmp_db", "bar"))
+	}
+	{
+		// Include and exclude patterns for databases and tables
+		selector, err := NewTableSelector(config.MySQL{Database: "foo", Tables: []*config.MySQLTable{{Name: "audit", ExcludeColumns: []string{"a"}}}, StreamingSettings: config.MySQLStreamingSettings{
+			TableSelection: &config.MySQLTableSelection{
+				IncludeDatabases: []string{"tenant_[0-9]+"},
+				ExcludeDatabases: []string{"tenant_0"},
+				IncludeTables:    []string{`tenant_\d+\.orders.*`, `foo\.audit`},
+				ExcludeTables:    []string{`.*\.orders_archive`},
+			},
+		}})
+		assert.NoError(t, err)
+		assert.True(t, selector.IncludesDatabase("foo"))
+		assert.True(t, selector.IncludesDatabase("tenant_12"))
+		assert.False(t, selector.IncludesDatabase("tenant_0"))
+		assert.False(t, selector.IncludesDatabase("tenant_1_old"))
+		assert.Equal(t, &config.MySQLTable{Name: "orders_2024"}, selector.TableConfig("tenant_1", "orders_2024"))
+		assert.Nil(t, selector.TableConfig("tenant_1", "orders_archive"))
+		assert.Nil(t, selector.TableConfig("tenant_1", "customers"))
+		assert.Nil(t, selector.TableConfig("tenant_0", "orders"))
+		// Configured tables keep their settings
+		assert.Equal(t, []string{"a"}, selector.TableConfig("foo", "audit").ExcludeColumns)
+		assert.Nil(t, selector.TableConfig("foo", "orders"))
+	}
+	{
+		// Invalid pattern
+		_, err := NewTableSelector(config.MySQL{Database: "foo", StreamingSettings: config.MySQLStreamingSettings{
+			TableSelection: &config.MySQLTableSelection{IncludeTables: []string{"foo("}},
+		}})
+		assert.ErrorContains(t, err, `failed to compile pattern "foo("`)
+	}
+}
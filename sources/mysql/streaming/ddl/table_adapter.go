@@ -2,7 +2,11 @@ package ddl
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/antlr"
 	"github.com/artie-labs/reader/lib/debezium/transformer"
 	"github.com/artie-labs/reader/lib/mysql/converters"
 	"github.com/artie-labs/reader/lib/mysql/schema"
@@ -13,14 +17,16 @@ type Column struct {
 	Name       string
 	DataType   string
 	PrimaryKey bool
+	NotNull    bool
 }
 
 type TableAdapter struct {
-	dbName   string
-	tableCfg *config.MySQLTable
-	columns  []Column
-	unixTs   int64
-	sqlMode  []string
+	dbName     string
+	tableCfg   *config.MySQLTable
+	columns    []Column
+	uniqueKeys []antlr.UniqueKey
+	unixTs     int64
+	sqlMode    []string
 
 	// Generated by helper functions
 	fieldConverters []transformer.FieldConverter
@@ -35,13 +41,14 @@ func (t TableAdapter) GetUnixTs() int64 {
 	return t.unixTs
 }
 
-func NewTableAdapter(dbName string, tableCfg *config.MySQLTable, columns []Column, unixTs int64, sqlMode []string) (TableAdapter, error) {
+func NewTableAdapter(dbName string, tableCfg *config.MySQLTable, columns []Column, uniqueKeys []antlr.UniqueKey, unixTs int64, sqlMode []string) (TableAdapter, error) {
 	tblAdapter := TableAdapter{
-		dbName:   dbName,
-		tableCfg: tableCfg,
-		columns:  columns,
-		unixTs:   unixTs,
-		sqlMode:  sqlMode,
+		dbName:     dbName,
+		tableCfg:   tableCfg,
+		columns:    columns,
+		uniqueKeys: uniqueKeys,
+		unixTs:     unixTs,
+		sqlMode:    sqlMode,
 	}
 
 	return tblAdapter.buildGeneratedFields()
@@ -100,13 +107,13 @@ func (t TableAdapter) buildParsedColumns() ([]schema.Column, error) {
 	}
 
 	// Exclude columns (if any) from the table metadata
-	cols, err := column.FilterOutExcludedColumns(parsedColumns, t.tableCfg.ExcludeColumns, t.PartitionKeys())
+	cols, err := column.FilterOutExcludedColumns(parsedColumns, t.tableCfg.ExcludeColumns, t.keyColumns())
 	if err != nil {
 		return nil, err
 	}
 
 	// Include columns (if any) from the table metadata
-	cols, err = column.FilterForIncludedColumns(cols, t.tableCfg.IncludeColumns, t.PartitionKeys())
+	cols, err = column.FilterForIncludedColumns(cols, t.tableCfg.IncludeColumns, t.keyColumns())
 	if err != nil {
 		return nil, err
 	}
@@ -114,6 +121,15 @@ func (t TableAdapter) buildParsedColumns() ([]schema.Column, error) {
 	return cols, nil
 }
 
+func (t TableAdapter) DatabaseName() string {
+	return t.dbName
+}
+
+// TableName - This should only be called for tables that are replicated.
+func (t TableAdapter) TableName() string {
+	return t.tableCfg.Name
+}
+
 func (t TableAdapter) TopicSuffix() string {
 	return fmt.Sprintf("%s.%s", t.dbName, t.tableCfg.Name)
 }
@@ -127,7 +143,22 @@ func (t TableAdapter) ColumnNames() []string {
 	return colNames
 }
 
+// PartitionKeys - Returns the same keys as the snapshot adapter: [keyColumns] or [transformer.RowHashKey] if there are none.
 func (t TableAdapter) PartitionKeys() []string {
+	if keys := t.keyColumns(); len(keys) > 0 {
+		return keys
+	}
+
+	return []string{transformer.RowHashKey}
+}
+
+// keyColumns returns the primary key override if it is set, otherwise the primary key and then the first unique index
+// (by name, index names are case-insensitive) whose columns are all NOT NULL.
+func (t TableAdapter) keyColumns() []string {
+	if t.tableCfg != nil && len(t.tableCfg.PrimaryKeysOverride) > 0 {
+		return t.tableCfg.PrimaryKeysOverride
+	}
+
 	var keys []string
 	for _, col := range t.columns {
 		if col.PrimaryKey {
@@ -135,7 +166,27 @@ func (t TableAdapter) PartitionKeys() []string {
 		}
 	}
 
-	return keys
+	if len(keys) > 0 {
+		return keys
+	}
+
+	uniqueKeys := slices.Clone(t.uniqueKeys)
+	slices.SortStableFunc(uniqueKeys, func(a, b antlr.UniqueKey) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	for _, uniqueKey := range uniqueKeys {
+		notNull := len(uniqueKey.Columns) > 0
+		for _, name := range uniqueKey.Columns {
+			idx := slices.IndexFunc(t.columns, func(x Column) bool { return x.Name == name })
+			notNull = notNull && idx >= 0 && t.columns[idx].NotNull
+		}
+
+		if notNull {
+			return uniqueKey.Columns
+		}
+	}
+
+	return nil
 }
 
 func (t TableAdapter) GetParsedColumns() []schema.Column {
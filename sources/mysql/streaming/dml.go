@@ -3,14 +3,17 @@ package streaming
 import (
 	"fmt"
 	"log/slog"
-	"strings"
+	"slices"
 	"time"
 
+	"github.com/artie-labs/transfer/lib/cdc/util"
+	"github.com/artie-labs/transfer/lib/debezium"
 	"github.com/artie-labs/transfer/lib/typing"
 	"github.com/go-mysql-org/go-mysql/replication"
 
 	"github.com/artie-labs/reader/lib/debezium/transformer"
 	"github.com/artie-labs/reader/lib/kafkalib"
+	"github.com/artie-labs/reader/sources/mysql/streaming/ddl"
 )
 
 func (i *Iterator) processDML(ts time.Time, event *replication.BinlogEvent, currentGTID *string) ([]kafkalib.Message, error) {
@@ -19,21 +22,29 @@ func (i *Iterator) processDML(ts time.Time, event *replication.BinlogEvent, curr
 		return nil, fmt.Errorf("failed to assert a rows event: %w", err)
 	}
 
-	if !strings.EqualFold(i.cfg.Database, string(rowsEvent.Table.Schema)) {
-		slog.Debug("Skipping this event since the database does not match the configured database",
-			slog.String("config_db", i.cfg.Database),
-			slog.String("event_db", string(rowsEvent.Table.Schema)),
-		)
-
-		return nil, nil
-	}
-
+	database := string(rowsEvent.Table.Schema)
 	tableName := string(rowsEvent.Table.Table)
-	tblAdapter, ok := i.schemaAdapter.GetTableAdapter(tableName)
+	tblAdapter, ok := i.catalog.GetTableAdapter(database, tableName)
 	if !ok {
 		return nil, nil
 	}
 
+	// The signal table and incremental snapshots only apply to the configured database.
+	isDefaultDatabase := i.catalog.IsDefaultDatabase(database)
+
+	operation, err := convertHeaderToOperation(event.Header.EventType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert header to operation: %w", err)
+	}
+
+	if isDefaultDatabase && i.incrementalSnapshot.isSignalTable(tableName) {
+		if operation != "c" {
+			return nil, nil
+		}
+
+		return i.incrementalSnapshot.processSignals(tblAdapter.ColumnNames(), rowsEvent.Rows)
+	}
+
 	if !tblAdapter.ShouldReplicate() {
 		return nil, nil
 	}
@@ -47,12 +58,20 @@ func (i *Iterator) processDML(ts time.Time, event *replication.BinlogEvent, curr
 		return nil, nil
 	}
 
-	operation, err := convertHeaderToOperation(event.Header.EventType)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert header to operation: %w", err)
+	rows := make([]map[string]any, len(rowsEvent.Rows))
+	for idx, row := range rowsEvent.Rows {
+		var skippedColumns []int
+		if idx < len(rowsEvent.SkippedColumns) {
+			skippedColumns = rowsEvent.SkippedColumns[idx]
+		}
+
+		rows[idx], err = zipRow(tblAdapter.ColumnNames(), row, skippedColumns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert row to map for table %q: %w", tableName, err)
+		}
 	}
 
-	beforeAndAfters, err := splitIntoBeforeAndAfter(operation, rowsEvent.Rows)
+	beforeAndAfters, err := splitIntoBeforeAndAfter(operation, rows)
 	if err != nil {
 		return nil, err
 	}
@@ -63,52 +82,106 @@ func (i *Iterator) processDML(ts time.Time, event *replication.BinlogEvent, curr
 		return nil, fmt.Errorf("failed to get parsed columns: %w", err)
 	}
 
-	sourcePayload := buildDebeziumSourcePayload(i.cfg.Database, tableName, ts, i.position, currentGTID)
+	sourcePayload := buildDebeziumSourcePayload(tblAdapter.DatabaseName(), tableName, ts, i.position, currentGTID)
 	dbz := transformer.NewLightDebeziumTransformer(tableName, tblAdapter.PartitionKeys(), tblAdapter.GetFieldConverters())
+	isKeyless := slices.Equal(tblAdapter.PartitionKeys(), []string{transformer.RowHashKey})
 	for before, after := range beforeAndAfters {
-		var beforeRow map[string]any
-		if len(before) > 0 {
-			beforeRow, err = zipSlicesToMap[string](tblAdapter.ColumnNames(), before)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert before row to map:%w", err)
-			}
-		}
-
-		var afterRow map[string]any
-		if len(after) > 0 {
-			afterRow, err = zipSlicesToMap[string](tblAdapter.ColumnNames(), after)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert row to map for table %q: %w", tableName, err)
+		if operation == "u" {
+			if err = rebuildJSONValues(before, after); err != nil {
+				return nil, err
 			}
 		}
 
 		// Preprocess
-		beforeRow, err = preprocessRow(beforeRow, parsedColumns)
+		beforeRow, err := preprocessRow(before, parsedColumns)
 		if err != nil {
 			return nil, fmt.Errorf("failed to preprocess before row: %w", err)
 		}
 
-		afterRow, err = preprocessRow(afterRow, parsedColumns)
+		afterRow, err := preprocessRow(after, parsedColumns)
 		if err != nil {
 			return nil, fmt.Errorf("failed to preprocess after row: %w", err)
 		}
 
-		dbzMessage, err := dbz.BuildEventPayload(sourcePayload, beforeRow, afterRow, operation)
-		if err != nil {
-			return nil, fmt.Errorf("failed to build event payload: %w", err)
+		var unavailableColumns []string
+		if operation == "u" {
+			unavailableColumns = completeAfterRow(beforeRow, afterRow, parsedColumns)
 		}
 
-		primaryKeyPayload, err := dbz.BuildPartitionKey(beforeRow, afterRow)
-		if err != nil {
-			return nil, fmt.Errorf("failed to build partition key: %w", err)
+		if isDefaultDatabase {
+			i.incrementalSnapshot.observeChange(tableName, beforeRow, afterRow)
+		}
+
+		if operation == "u" && isKeyless {
+			// Rows without a key are identified by their values, so the old row is deleted and the new one is created.
+			deleteMsg, err := i.buildMessage(dbz, tblAdapter, sourcePayload, beforeRow, nil, "d", nil)
+			if err != nil {
+				return nil, err
+			}
+
+			createMsg, err := i.buildMessage(dbz, tblAdapter, sourcePayload, nil, afterRow, "c", unavailableColumns)
+			if err != nil {
+				return nil, err
+			}
+
+			rawMsgs = append(rawMsgs, deleteMsg, createMsg)
+			continue
 		}
 
-		if len(primaryKeyPayload.Payload) == 0 {
-			return nil, fmt.Errorf("partition key is not set for table: %q", tableName)
+		msg, err := i.buildMessage(dbz, tblAdapter, sourcePayload, beforeRow, afterRow, operation, unavailableColumns)
+		if err != nil {
+			return nil, err
 		}
 
-		rawMsgs = append(rawMsgs, kafkalib.NewMessage(tblAdapter.TopicSuffix(), primaryKeyPayload.Schema, primaryKeyPayload.Payload, &dbzMessage))
+		rawMsgs = append(rawMsgs, msg)
 	}
 
 	return rawMsgs, nil
 }
+
+// buildTruncateMessage returns a truncate message for the table, this is false if the table is not replicated.
+func (i *Iterator) buildTruncateMessage(ts time.Time, tblAdapter ddl.TableAdapter, currentGTID *string) (kafkalib.Message, bool, error) {
+	if !tblAdapter.ShouldReplicate() || tblAdapter.GetUnixTs() > ts.Unix() {
+		return kafkalib.Message{}, false, nil
+	}
+
+	tableName := tblAdapter.TableName()
+	sourcePayload := buildDebeziumSourcePayload(tblAdapter.DatabaseName(), tableName, ts, i.position, currentGTID)
+	dbz := transformer.NewLightDebeziumTransformer(tableName, tblAdapter.PartitionKeys(), tblAdapter.GetFieldConverters())
+	dbzMessage, err := dbz.BuildEventPayload(sourcePayload, nil, nil, "t")
+	if err != nil {
+		return kafkalib.Message{}, false, fmt.Errorf("failed to build event payload: %w", err)
+	}
+
+	// Truncates do not have a row, so there is no partition key.
+	return kafkalib.NewMessage(tblAdapter.TopicSuffix(), debezium.FieldsObject{}, nil, &dbzMessage), true, nil
+}
+
+// buildMessage builds the message for a row, [unavailableColumns] of a string type are set to the unavailable value
+// placeholder in the after image. Other unavailable columns are left out, since the placeholder does not fit their type.
+func (i *Iterator) buildMessage(dbz transformer.LightDebeziumTransformer, tblAdapter ddl.TableAdapter, source util.Source, beforeRow, afterRow map[string]any, operation string, unavailableColumns []string) (kafkalib.Message, error) {
+	dbzMessage, err := dbz.BuildEventPayload(source, beforeRow, afterRow, operation)
+	if err != nil {
+		return kafkalib.Message{}, fmt.Errorf("failed to build event payload: %w", err)
+	}
+
+	if afterFields := dbzMessage.Schema.GetSchemaFromLabel(debezium.After); afterFields != nil {
+		for _, field := range afterFields.Fields {
+			// The placeholder is set after the values have been converted, since it is not a value of the column.
+			if field.Type == debezium.String && slices.Contains(unavailableColumns, field.FieldName) {
+				dbzMessage.Payload.After[field.FieldName] = i.cfg.GetUnavailableValuePlaceholder()
+			}
+		}
+	}
+
+	primaryKeyPayload, err := dbz.BuildPartitionKey(beforeRow, afterRow)
+	if err != nil {
+		return kafkalib.Message{}, fmt.Errorf("failed to build partition key: %w", err)
+	}
+
+	if len(primaryKeyPayload.Payload) == 0 {
+		return kafkalib.Message{}, fmt.Errorf("partition key is not set for table: %q", source.Table)
+	}
+
+	return kafkalib.NewMessage(tblAdapter.TopicSuffix(), primaryKeyPayload.Schema, primaryKeyPayload.Payload, &dbzMessage), nil
+}
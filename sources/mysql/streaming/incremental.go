@@ -0,0 +1,422 @@
+package streaming
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/checkpoint"
+	"github.com/artie-labs/reader/lib/debezium/transformer"
+	"github.com/artie-labs/reader/lib/iterator"
+	"github.com/artie-labs/reader/lib/kafkalib"
+	"github.com/artie-labs/reader/lib/mysql/schema"
+	"github.com/artie-labs/reader/lib/rdbms"
+	"github.com/artie-labs/reader/lib/rdbms/scan"
+	"github.com/artie-labs/reader/lib/signal"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+	"github.com/artie-labs/reader/sources/mysql/adapter"
+)
+
+const (
+	signalWindowOpen  = "snapshot-window-open"
+	signalWindowClose = "snapshot-window-close"
+)
+
+// signalPollInterval - How often the signal directory is checked for new files.
+const signalPollInterval = 5 * time.Second
+
+// incrementalSnapshot - Snapshots tables while streaming using the DBLog watermark algorithm
+// (https://arxiv.org/abs/2010.12597). Each chunk is read in between a low and a high watermark that are written to the
+// signal table. Rows of the chunk that change in the binlog in between the two watermarks are dropped since the binlog
+// has a newer version of them, the rest are emitted as reads when the high watermark is reached.
+type incrementalSnapshot struct {
+	db          *sql.DB
+	dbName      string
+	tables      []*config.MySQLTable
+	signalTable string
+	progress    *checkpoint.Store
+
+	// signals - Signals that have been received through the signal table or the signal directory.
+	signals *signal.Store
+	// signalDirectory - Reads the signals from the signal directory, this is nil if it hasn't been configured.
+	signalDirectory *signal.DirectoryReader
+	lastSignalPoll  time.Time
+	// pauses - IDs of the pause signals that have not been resumed yet.
+	pauses []string
+
+	// pending - Snapshots that still need to run, the first one is the snapshot that is currently running.
+	pending []*pendingSnapshot
+	// window - Chunk that has been read but whose high watermark has not been seen in the binlog yet.
+	window *snapshotWindow
+	// uncommitted - Progress of chunks that have been emitted but not committed yet.
+	uncommitted []chunkProgress
+}
+
+type pendingSnapshot struct {
+	tableCfg *config.MySQLTable
+	// signalID - ID of the signal that requested the snapshot, this is empty for tables that were added to the config.
+	signalID string
+
+	// These are set once the first chunk is read.
+	adapter adapter.MySQLAdapter
+	scanner *scan.Scanner
+}
+
+type snapshotWindow struct {
+	snapshot      *pendingSnapshot
+	adapter       transformer.Adapter
+	lowWatermark  string
+	highWatermark string
+	// open - Whether the low watermark has been seen, changes are only tracked from that point on.
+	open bool
+	rows []transformer.Row
+	// changedKeys - Keys of the rows that changed in the binlog while the window was open.
+	changedKeys map[string]bool
+	progress    chunkProgress
+}
+
+type chunkProgress struct {
+	table string
+	// signalID - Progress of snapshots that were requested with a signal is not persisted, they start over after a
+	// restart and the signal is only marked as done once they have completed.
+	signalID string
+	lastKey  []any
+	done     bool
+}
+
+func newIncrementalSnapshot(db *sql.DB, cfg config.MySQL, store offsetstore.OffsetStore) (*incrementalSnapshot, error) {
+	settings := cfg.StreamingSettings.IncrementalSnapshot
+	if settings == nil {
+		return nil, nil
+	}
+
+	snapshot := &incrementalSnapshot{
+		db:          db,
+		dbName:      cfg.Database,
+		tables:      cfg.Tables,
+		signalTable: settings.SignalTable,
+		progress:    checkpoint.NewStore(store, settings.ProgressFile),
+		signals:     signal.NewStore(store, settings.SignalsFile),
+	}
+
+	if settings.SignalDirectory != "" {
+		snapshot.signalDirectory = signal.NewDirectoryReader(settings.SignalDirectory)
+	}
+
+	if snapshot.progress.IsEmpty() {
+		// Tables that are configured when incremental snapshots are first enabled are already being streamed.
+		for _, table := range cfg.Tables {
+			if err := snapshot.progress.MarkDone(snapshot.tableKey(table.Name)); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for _, table := range cfg.Tables {
+			if !snapshot.progress.IsDone(snapshot.tableKey(table.Name)) {
+				slog.Info("Table will be snapshotted incrementally", slog.String("table", table.Name))
+				snapshot.pending = append(snapshot.pending, &pendingSnapshot{tableCfg: table})
+			}
+		}
+	}
+
+	if err := snapshot.restoreSignals(); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func (s *incrementalSnapshot) tableKey(tableName string) string {
+	return fmt.Sprintf("%s.%s", s.dbName, tableName)
+}
+
+func (s *incrementalSnapshot) isSignalTable(tableName string) bool {
+	return s != nil && strings.EqualFold(s.signalTable, tableName)
+}
+
+// openWindow reads the next chunk in between two watermarks, unless a chunk is already in flight, snapshots have been
+// paused or there is nothing left to snapshot.
+func (s *incrementalSnapshot) openWindow() error {
+	if s == nil {
+		return nil
+	}
+
+	if err := s.pollSignalDirectory(); err != nil {
+		return err
+	}
+
+	if s.window != nil || len(s.pauses) > 0 {
+		return nil
+	}
+
+	for len(s.pending) > 0 && s.pending[0].scanner == nil {
+		skip, err := s.loadScanner(s.pending[0])
+		if err != nil {
+			return err
+		}
+
+		if skip {
+			s.uncommitted = append(s.uncommitted, chunkProgress{table: s.tableKey(s.pending[0].tableCfg.Name), signalID: s.pending[0].signalID, done: true})
+			s.pending = s.pending[1:]
+		}
+	}
+
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	snapshot := s.pending[0]
+	lowWatermark, err := s.writeWatermark(snapshot, signalWindowOpen)
+	if err != nil {
+		return err
+	}
+
+	rows, err := snapshot.scanner.Next()
+	if err != nil {
+		return fmt.Errorf("failed to read chunk for table %q: %w", snapshot.tableCfg.Name, err)
+	}
+
+	highWatermark, err := s.writeWatermark(snapshot, signalWindowClose)
+	if err != nil {
+		return err
+	}
+
+	s.window = &snapshotWindow{
+		snapshot:      snapshot,
+		adapter:       snapshot.adapter,
+		lowWatermark:  lowWatermark,
+		highWatermark: highWatermark,
+		rows:          rows,
+		changedKeys:   make(map[string]bool),
+		progress: chunkProgress{
+			table:    snapshot.adapter.TopicSuffix(),
+			signalID: snapshot.signalID,
+			lastKey:  snapshot.scanner.LastKey(),
+			done:     !snapshot.scanner.HasNext(),
+		},
+	}
+
+	if s.window.progress.done {
+		s.pending = s.pending[1:]
+	}
+
+	return nil
+}
+
+// loadScanner builds the scanner for a snapshot, resuming from its progress. This returns true if the snapshot should be
+// skipped because the table is empty or because it does not have a key that chunks can be read by.
+func (s *incrementalSnapshot) loadScanner(snapshot *pendingSnapshot) (bool, error) {
+	// Chunks are read one at a time in between watermarks.
+	sequentialCfg := *snapshot.tableCfg
+	sequentialCfg.SnapshotParallelism = 0
+
+	dbzAdapter, err := adapter.NewMySQLAdapter(s.db, s.dbName, sequentialCfg)
+	if err != nil {
+		return false, fmt.Errorf("failed to create MySQL adapter: %w", err)
+	}
+
+	if slices.Equal(dbzAdapter.PartitionKeys(), []string{transformer.RowHashKey}) {
+		slog.Warn("Table does not have a primary key or a unique index whose columns are all NOT NULL, skipping snapshot",
+			slog.String("table", snapshot.tableCfg.Name))
+		return true, nil
+	}
+
+	iter, err := dbzAdapter.NewIterator()
+	if err != nil {
+		if errors.Is(err, rdbms.ErrNoPkValuesForEmptyTable) {
+			slog.Info("Table does not contain any rows, skipping snapshot", slog.String("table", snapshot.tableCfg.Name))
+			return true, nil
+		}
+
+		return false, fmt.Errorf("failed to create scanner for table %q: %w", snapshot.tableCfg.Name, err)
+	}
+
+	scanner, isOk := iter.(*scan.Scanner)
+	if !isOk {
+		return false, fmt.Errorf("expected a scanner for table %q, got %T", snapshot.tableCfg.Name, iter)
+	}
+
+	if progress, isOk := s.progress.Get(dbzAdapter.TopicSuffix()); isOk && len(progress.LastKey) > 0 && snapshot.signalID == "" {
+		lastKey, err := checkpoint.DecodeKey(progress.LastKey)
+		if err != nil {
+			return false, fmt.Errorf("failed to decode progress for table %q: %w", snapshot.tableCfg.Name, err)
+		}
+
+		if err = scanner.Resume(lastKey); err != nil {
+			return false, fmt.Errorf("failed to resume table %q: %w", snapshot.tableCfg.Name, err)
+		}
+
+		slog.Info("Resuming incremental snapshot", slog.String("table", snapshot.tableCfg.Name), slog.Any("lastKey", lastKey))
+	}
+
+	snapshot.adapter = dbzAdapter
+	snapshot.scanner = scanner
+	return false, nil
+}
+
+func (s *incrementalSnapshot) writeWatermark(snapshot *pendingSnapshot, signalType string) (string, error) {
+	id := uuid.NewString()
+	query := fmt.Sprintf("INSERT INTO %s (id, type, data) VALUES (?, ?, ?)", schema.QuoteIdentifier(s.signalTable))
+	if _, err := s.db.Exec(query, id, signalType, snapshot.tableCfg.Name); err != nil {
+		return "", fmt.Errorf("failed to write %q watermark: %w", signalType, err)
+	}
+
+	return id, nil
+}
+
+// processSignals handles rows that were inserted into the signal table. Watermarks of the current window are tracked
+// and the chunk is emitted once the high watermark has been reached, other rows are signals sent by users.
+func (s *incrementalSnapshot) processSignals(columnNames []string, rows [][]any) ([]kafkalib.Message, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	var msgs []kafkalib.Message
+	for _, row := range rows {
+		values, err := zipSlicesToMap(columnNames, row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert signal row to map: %w", err)
+		}
+
+		id := toString(values["id"])
+		switch signalType := toString(values["type"]); signalType {
+		case signalWindowOpen:
+			if s.window != nil && id == s.window.lowWatermark {
+				s.window.open = true
+			}
+		case signalWindowClose:
+			if s.window != nil && id == s.window.highWatermark {
+				chunk, err := s.closeWindow()
+				if err != nil {
+					return nil, err
+				}
+
+				msgs = append(msgs, chunk...)
+			}
+		default:
+			var data string
+			if values["data"] != nil {
+				data = toString(values["data"])
+			}
+
+			if err = s.handleSignal(signal.New(id, signalType, data)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return msgs, nil
+}
+
+// observeChange drops the row that changed from the current chunk, the binlog event has a newer version of it.
+func (s *incrementalSnapshot) observeChange(tableName string, beforeRow map[string]any, afterRow map[string]any) {
+	if s == nil || s.window == nil || !s.window.open || s.window.adapter.TableName() != tableName {
+		return
+	}
+
+	for _, row := range []map[string]any{beforeRow, afterRow} {
+		if len(row) > 0 {
+			s.window.changedKeys[rowKey(row, s.window.adapter.PartitionKeys())] = true
+		}
+	}
+}
+
+func (s *incrementalSnapshot) closeWindow() ([]kafkalib.Message, error) {
+	window := s.window
+	s.window = nil
+
+	var rows []transformer.Row
+	for _, row := range window.rows {
+		if !window.changedKeys[rowKey(row, window.adapter.PartitionKeys())] {
+			rows = append(rows, row)
+		}
+	}
+
+	slog.Info("Emitting incremental snapshot chunk",
+		slog.String("table", window.adapter.TableName()),
+		slog.Int("rows", len(rows)),
+		slog.Int("droppedRows", len(window.rows)-len(rows)),
+	)
+
+	dbzTransformer := transformer.NewDebeziumTransformerWithIterator(window.adapter, iterator.ForSlice([][]transformer.Row{rows}))
+	batches, err := iterator.Collect[[]kafkalib.Message](dbzTransformer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build messages for table %q: %w", window.adapter.TableName(), err)
+	}
+
+	var msgs []kafkalib.Message
+	for _, batch := range batches {
+		msgs = append(msgs, batch...)
+	}
+
+	s.uncommitted = append(s.uncommitted, window.progress)
+	return msgs, nil
+}
+
+// commit persists the progress of the chunks that have been emitted, this is called once they have been written.
+func (s *incrementalSnapshot) commit() error {
+	if s == nil {
+		return nil
+	}
+
+	for _, progress := range s.uncommitted {
+		if progress.signalID != "" {
+			if progress.done {
+				if err := s.signals.MarkDone(progress.signalID); err != nil {
+					return err
+				}
+
+				slog.Info("Finished snapshot requested by signal", slog.String("table", progress.table), slog.String("signal", progress.signalID))
+			}
+			continue
+		}
+
+		if progress.done {
+			if err := s.progress.MarkDone(progress.table); err != nil {
+				return err
+			}
+
+			slog.Info("Finished incremental snapshot", slog.String("table", progress.table))
+			continue
+		}
+
+		lastKey, err := checkpoint.EncodeKey(progress.lastKey)
+		if err != nil {
+			slog.Warn("Unable to save incremental snapshot progress, key cannot be persisted", slog.String("table", progress.table), slog.Any("err", err))
+			continue
+		}
+
+		if err = s.progress.SaveLastKey(progress.table, lastKey); err != nil {
+			return err
+		}
+	}
+
+	s.uncommitted = nil
+	return nil
+}
+
+// rowKey returns a key that identifies a row by its primary keys, rows from the binlog and from the chunk are both
+// parsed with [schema.ConvertValue] so their values have the same types.
+func rowKey(row map[string]any, primaryKeys []string) string {
+	values := make([]string, len(primaryKeys))
+	for i, key := range primaryKeys {
+		values[i] = toString(row[key])
+	}
+
+	return strings.Join(values, "\x00")
+}
+
+func toString(value any) string {
+	if bytes, isOk := value.([]byte); isOk {
+		return string(bytes)
+	}
+
+	return fmt.Sprint(value)
+}
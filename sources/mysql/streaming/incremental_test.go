@@ -0,0 +1,133 @@
+package streaming
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/checkpoint"
+	"github.com/artie-labs/reader/lib/debezium/converters"
+	"github.com/artie-labs/reader/lib/debezium/transformer"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+)
+
+type mockAdapter struct{}
+
+func (mockAdapter) TableName() string {
+	return "foo"
+}
+
+func (mockAdapter) TopicSuffix() string {
+	return "db.foo"
+}
+
+func (mockAdapter) PartitionKeys() []string {
+	return []string{"id"}
+}
+
+func (mockAdapter) FieldConverters() []transformer.FieldConverter {
+	return []transformer.FieldConverter{
+		{Name: "id", ValueConverter: converters.Int64Passthrough{}},
+		{Name: "name", ValueConverter: converters.StringPassthrough{}},
+	}
+}
+
+func (mockAdapter) NewIterator() (transformer.RowsIterator, error) {
+	panic("not implemented")
+}
+
+func TestNewIncrementalSnapshot(t *testing.T) {
+	cfg := config.MySQL{
+		Database: "db",
+		Tables:   []*config.MySQLTable{{Name: "foo"}},
+		StreamingSettings: config.MySQLStreamingSettings{
+			IncrementalSnapshot: &config.MySQLIncrementalSnapshot{
+				SignalTable:  "signals",
+				ProgressFile: filepath.Join(t.TempDir(), "progress.yaml"),
+				SignalsFile:  filepath.Join(t.TempDir(), "signals.yaml"),
+			},
+		},
+	}
+	store := offsetstore.NewFileStore()
+	{
+		// Disabled
+		snapshot, err := newIncrementalSnapshot(nil, config.MySQL{}, store)
+		assert.NoError(t, err)
+		assert.Nil(t, snapshot)
+		assert.False(t, snapshot.isSignalTable("signals"))
+		assert.NoError(t, snapshot.openWindow())
+		assert.NoError(t, snapshot.commit())
+	}
+	{
+		// Tables that are configured when incremental snapshots are first enabled are not snapshotted
+		snapshot, err := newIncrementalSnapshot(nil, cfg, store)
+		assert.NoError(t, err)
+		assert.Empty(t, snapshot.pending)
+		assert.True(t, snapshot.isSignalTable("SIGNALS"))
+	}
+	{
+		// Tables that are added afterwards are snapshotted
+		cfg.Tables = append(cfg.Tables, &config.MySQLTable{Name: "bar"})
+		snapshot, err := newIncrementalSnapshot(nil, cfg, store)
+		assert.NoError(t, err)
+		assert.Equal(t, []*pendingSnapshot{{tableCfg: &config.MySQLTable{Name: "bar"}}}, snapshot.pending)
+	}
+}
+
+func TestIncrementalSnapshot_Window(t *testing.T) {
+	progressFile := filepath.Join(t.TempDir(), "progress.yaml")
+	snapshot := &incrementalSnapshot{
+		dbName:   "db",
+		progress: checkpoint.NewStore(offsetstore.NewFileStore(), progressFile),
+		window: &snapshotWindow{
+			adapter:       mockAdapter{},
+			lowWatermark:  "low",
+			highWatermark: "high",
+			rows: []transformer.Row{
+				{"id": int64(1), "name": "a"},
+				{"id": int64(2), "name": "b"},
+				{"id": int64(3), "name": "c"},
+			},
+			changedKeys: make(map[string]bool),
+			progress:    chunkProgress{table: "db.foo", lastKey: []any{int64(3)}},
+		},
+	}
+	columnNames := []string{"id", "type", "data"}
+
+	// Changes before the low watermark are older than the chunk.
+	snapshot.observeChange("foo", nil, map[string]any{"id": int64(1), "name": "z"})
+
+	msgs, err := snapshot.processSignals(columnNames, [][]any{{[]byte("low"), signalWindowOpen, "foo"}})
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+	assert.True(t, snapshot.window.open)
+
+	// Changes in between the watermarks drop the row from the chunk, changes to other tables are ignored.
+	snapshot.observeChange("foo", map[string]any{"id": int64(2), "name": "b"}, map[string]any{"id": int64(2), "name": "y"})
+	snapshot.observeChange("bar", nil, map[string]any{"id": int64(3)})
+
+	msgs, err = snapshot.processSignals(columnNames, [][]any{{"high", signalWindowClose, "foo"}})
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot.window)
+	assert.Len(t, msgs, 2)
+	for i, expectedID := range []int64{1, 3} {
+		assert.Equal(t, "prefix.db.foo", msgs[i].Topic("prefix"))
+		assert.Equal(t, map[string]any{"id": expectedID}, msgs[i].PartitionKeyValues())
+	}
+
+	// Progress is only persisted once the chunk has been committed.
+	_, isOk := snapshot.progress.Get("db.foo")
+	assert.False(t, isOk)
+
+	assert.NoError(t, snapshot.commit())
+	progress, isOk := checkpoint.NewStore(offsetstore.NewFileStore(), progressFile).Get("db.foo")
+	assert.True(t, isOk)
+	assert.Equal(t, checkpoint.Checkpoint{LastKey: []checkpoint.Value{{Type: checkpoint.Int64, Value: "3"}}}, progress)
+}
+
+func TestRowKey(t *testing.T) {
+	assert.Equal(t, "1\x00foo", rowKey(map[string]any{"a": int32(1), "b": []byte("foo"), "c": "bar"}, []string{"a", "b"}))
+	assert.Equal(t, rowKey(map[string]any{"a": "foo"}, []string{"a"}), rowKey(map[string]any{"a": []byte("foo")}, []string{"a"}))
+}
@@ -1,6 +1,7 @@
 package streaming
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"errors"
@@ -13,9 +14,11 @@ import (
 	"github.com/go-mysql-org/go-mysql/replication"
 
 	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/iterator"
 	"github.com/artie-labs/reader/lib/kafkalib"
 	"github.com/artie-labs/reader/lib/mysql"
 	"github.com/artie-labs/reader/lib/mysql/schema"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
 	"github.com/artie-labs/reader/lib/storage/persistedlist"
 	"github.com/artie-labs/reader/lib/storage/persistedmap"
 	"github.com/artie-labs/reader/sources/mysql/streaming/ddl"
@@ -23,12 +26,29 @@ import (
 
 const offsetKey = "offset"
 
-func buildSchemaAdapter(db *sql.DB, cfg config.MySQL, schemaHistoryList persistedlist.PersistedList[SchemaHistory], pos Position, sqlMode []string) (ddl.SchemaAdapter, error) {
+// LoadOffset returns the binlog position that streaming will resume from, if one has been persisted.
+func LoadOffset(store offsetstore.OffsetStore, offsetFile string) (Position, bool) {
+	return persistedmap.NewPersistedMap[Position](store, offsetFile).Get(offsetKey)
+}
+
+// SaveOffset persists [pos] so that streaming starts from it.
+func SaveOffset(store offsetstore.OffsetStore, offsetFile string, pos Position) error {
+	return persistedmap.NewPersistedMap[Position](store, offsetFile).Set(offsetKey, pos)
+}
+
+func buildCatalog(db *sql.DB, cfg config.MySQL, schemaHistoryList persistedlist.PersistedList[SchemaHistory], pos Position, flavor string, sqlMode []string) (*ddl.Catalog, error) {
+	selector, err := ddl.NewTableSelector(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build table selector: %w", err)
+	}
+
 	var latestSchemaUnixTs int64
-	schemaAdapter := ddl.NewSchemaAdapter(cfg, sqlMode)
+	catalog := ddl.NewCatalog(selector, flavor, sqlMode, func(database string, tableName string) (string, error) {
+		return schema.GetCreateTableDDL(db, database, tableName)
+	})
 	for _, schemaHistory := range schemaHistoryList.GetData() {
-		if err := schemaAdapter.ApplyDDL(schemaHistory.UnixTs, schemaHistory.Query); err != nil {
-			return ddl.SchemaAdapter{}, fmt.Errorf("failed to apply DDL: %w", err)
+		if _, err := catalog.ApplyDDL(cmp.Or(schemaHistory.Database, cfg.Database), schemaHistory.UnixTs, schemaHistory.Query); err != nil {
+			return nil, fmt.Errorf("failed to apply DDL: %w", err)
 		}
 
 		latestSchemaUnixTs = schemaHistory.UnixTs
@@ -36,71 +56,105 @@ func buildSchemaAdapter(db *sql.DB, cfg config.MySQL, schemaHistoryList persiste
 
 	// If [pos.UnixTs] is set, it should be greater than the latest schema timestamp
 	if latestSchemaUnixTs > pos.UnixTs && pos.UnixTs > 0 {
-		return ddl.SchemaAdapter{}, fmt.Errorf("latest schema timestamp %d is greater than the current position's timestamp %d", latestSchemaUnixTs, pos.UnixTs)
+		return nil, fmt.Errorf("latest schema timestamp %d is greater than the current position's timestamp %d", latestSchemaUnixTs, pos.UnixTs)
 	}
 
-	// Find all the tables in the schema, check if they are already in the schema adapter
-	// If not, then call [GetCreateTableDDL] to get the DDL and apply it to the schema adapter
-	tables, err := schema.ListTables(db, cfg.Database)
-	if err != nil {
-		return ddl.SchemaAdapter{}, fmt.Errorf("failed to list tables: %w", err)
-	}
+	databases := []string{cfg.Database}
+	if selector.HasSelection() {
+		allDatabases, err := schema.ListDatabases(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list databases: %w", err)
+		}
 
-	for _, tbl := range tables {
-		if _, ok := schemaAdapter.GetTableAdapter(tbl); !ok {
-			now := time.Now().Unix()
-			ddlQuery, err := schema.GetCreateTableDDL(db, tbl)
-			if err != nil {
-				return ddl.SchemaAdapter{}, fmt.Errorf("failed to get columns: %w", err)
+		for _, database := range allDatabases {
+			if !selector.IsDefaultDatabase(database) && selector.IncludesDatabase(database) {
+				databases = append(databases, database)
 			}
+		}
+	}
 
-			// Persist the DDL
-			if err = schemaHistoryList.Push(SchemaHistory{Query: ddlQuery, UnixTs: now}); err != nil {
-				return ddl.SchemaAdapter{}, fmt.Errorf("failed to push schema history: %w", err)
-			}
+	// Find all the tables in the schema, check if they are already in the catalog
+	// If not, then call [GetCreateTableDDL] to get the DDL and apply it to the catalog
+	for _, database := range databases {
+		tables, err := schema.ListTables(db, database)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tables: %w", err)
+		}
+
+		for _, tbl := range tables {
+			if _, ok := catalog.GetTableAdapter(database, tbl); !ok {
+				now := time.Now().Unix()
+				ddlQuery, err := schema.GetCreateTableDDL(db, database, tbl)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get columns: %w", err)
+				}
+
+				// Persist the DDL
+				if err = schemaHistoryList.Push(newSchemaHistory(cfg, database, ddlQuery, now)); err != nil {
+					return nil, fmt.Errorf("failed to push schema history: %w", err)
+				}
 
-			// Apply the DDL
-			if err = schemaAdapter.ApplyDDL(now, ddlQuery); err != nil {
-				return ddl.SchemaAdapter{}, fmt.Errorf("failed to apply DDL: %w", err)
+				// Apply the DDL
+				if _, err = catalog.ApplyDDL(database, now, ddlQuery); err != nil {
+					return nil, fmt.Errorf("failed to apply DDL: %w", err)
+				}
 			}
 		}
 	}
 
-	return schemaAdapter, nil
+	return catalog, nil
+}
+
+// newSchemaHistory leaves [SchemaHistory.Database] empty for the configured database, which is what older schema
+// history files contain.
+func newSchemaHistory(cfg config.MySQL, database string, query string, unixTs int64) SchemaHistory {
+	if strings.EqualFold(cfg.Database, database) {
+		database = ""
+	}
+
+	return SchemaHistory{Database: database, Query: query, UnixTs: unixTs}
 }
 
-func BuildStreamingIterator(db *sql.DB, cfg config.MySQL, sqlMode []string, gtidEnabled bool) (Iterator, error) {
+// BuildStreamingIterator returns an iterator that streams the binlog, [flavor] is either "mysql" or "mariadb".
+func BuildStreamingIterator(db *sql.DB, cfg config.MySQL, flavor string, sqlMode []string, gtidEnabled bool, store offsetstore.OffsetStore) (Iterator, error) {
 	var pos Position
-	offsets := persistedmap.NewPersistedMap[Position](cfg.StreamingSettings.OffsetFile)
+	offsets := persistedmap.NewPersistedMap[Position](store, cfg.StreamingSettings.OffsetFile)
 	if _pos, isOk := offsets.Get(offsetKey); isOk {
 		slog.Info("Found offsets", slog.String("offset", _pos.String()))
 		pos = _pos
 	}
 
-	schemaHistoryList, err := persistedlist.NewPersistedList[SchemaHistory](cfg.StreamingSettings.SchemaHistoryFile)
+	schemaHistoryList, err := persistedlist.NewPersistedList[SchemaHistory](store, cfg.StreamingSettings.SchemaHistoryFile)
 	if err != nil {
 		return Iterator{}, fmt.Errorf("failed to create persisted list: %w", err)
 	}
 
-	schemaAdapter, err := buildSchemaAdapter(db, cfg, schemaHistoryList, pos, sqlMode)
+	catalog, err := buildCatalog(db, cfg, schemaHistoryList, pos, flavor, sqlMode)
+	if err != nil {
+		return Iterator{}, fmt.Errorf("failed to build catalog: %w", err)
+	}
+
+	snapshot, err := newIncrementalSnapshot(db, cfg, store)
 	if err != nil {
-		return Iterator{}, fmt.Errorf("failed to build schema adapter: %w", err)
+		return Iterator{}, fmt.Errorf("failed to set up incremental snapshot: %w", err)
 	}
 
 	syncer := replication.NewBinlogSyncer(
 		replication.BinlogSyncerConfig{
 			ServerID: cfg.StreamingSettings.ServerID,
-			Flavor:   "mysql",
+			Flavor:   flavor,
 			Host:     cfg.Host,
 			Port:     uint16(cfg.Port),
 			User:     cfg.Username,
 			Password: cfg.Password,
+			// Decodes every diff of partial JSON values, see [decodeRowsEvent].
+			RowsEventDecodeFunc: decodeRowsEvent,
 		},
 	)
 
 	var streamer *replication.BinlogStreamer
 	if gtidEnabled {
-		gtidSet, err := pos.ToGTIDSet()
+		gtidSet, err := pos.ToGTIDSet(flavor)
 		if err != nil {
 			return Iterator{}, fmt.Errorf("failed to parse GTID: %w", err)
 		}
@@ -120,11 +174,15 @@ func BuildStreamingIterator(db *sql.DB, cfg config.MySQL, sqlMode []string, gtid
 		batchSize:         cfg.GetStreamingBatchSize(),
 		cfg:               cfg,
 		position:          pos,
+		committedPosition: pos,
+		rowsToSkip:        pos.TransactionRowIndex,
 		syncer:            syncer,
 		streamer:          streamer,
 		offsets:           offsets,
 		schemaHistoryList: &schemaHistoryList,
-		schemaAdapter:     &schemaAdapter,
+		catalog:           catalog,
+
+		incrementalSnapshot: snapshot,
 	}, nil
 }
 
@@ -132,13 +190,45 @@ func (i *Iterator) HasNext() bool {
 	return true
 }
 
+// CommitOffset persists the position of the last fully processed transaction along with the number of rows that have
+// already been emitted from the transaction that is currently in flight.
 func (i *Iterator) CommitOffset() error {
+	pos := i.committedPosition
+	pos.TransactionRowIndex = max(i.transactionRowIndex, i.rowsToSkip)
 	slog.Info("Committing offset",
-		slog.String("position", i.position.String()),
-		slog.Int64("unixTs", i.position.UnixTs),
+		slog.String("position", pos.String()),
+		slog.Int64("unixTs", pos.UnixTs),
+		slog.Int64("transactionRowIndex", pos.TransactionRowIndex),
 	)
 
-	return i.offsets.Set(offsetKey, i.position)
+	if err := i.offsets.Set(offsetKey, pos); err != nil {
+		return err
+	}
+
+	return i.incrementalSnapshot.commit()
+}
+
+// completeTransaction is called once a transaction has been fully processed, resuming from here will start at the next transaction.
+func (i *Iterator) completeTransaction() {
+	i.inTransaction = false
+	i.transactionRowIndex = 0
+	i.rowsToSkip = 0
+	i.committedPosition = i.position
+}
+
+// skipDeliveredRows drops rows that were already emitted before we restarted in the middle of a transaction.
+func (i *Iterator) skipDeliveredRows(msgs []kafkalib.Message) []kafkalib.Message {
+	var out []kafkalib.Message
+	for _, msg := range msgs {
+		i.transactionRowIndex++
+		if i.transactionRowIndex <= i.rowsToSkip {
+			continue
+		}
+
+		out = append(out, msg)
+	}
+
+	return out
 }
 
 func (i *Iterator) Close() error {
@@ -147,7 +237,16 @@ func (i *Iterator) Close() error {
 }
 
 func (i *Iterator) Next() ([]kafkalib.Message, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	return i.NextContext(context.Background())
+}
+
+// NextContext reads up to a batch of binlog events, it returns what has been read so far once [ctx] is done.
+func (i *Iterator) NextContext(ctx context.Context) ([]kafkalib.Message, error) {
+	if err := i.incrementalSnapshot.openWindow(); err != nil {
+		return nil, fmt.Errorf("failed to read incremental snapshot chunk: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	var rawMsgs []kafkalib.Message
@@ -159,14 +258,14 @@ func (i *Iterator) Next() ([]kafkalib.Message, error) {
 		default:
 			event, err := i.streamer.GetEvent(ctx)
 			if err != nil {
-				if errors.Is(err, context.DeadlineExceeded) {
+				if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
 					return rawMsgs, nil
 				}
 
 				return nil, fmt.Errorf("failed to get binlog event: %w", err)
 			}
 
-			if gtidEvent, ok := event.Event.(*replication.GTIDEvent); ok {
+			if gtidEvent, ok := event.Event.(gtidEvent); ok {
 				next, err := gtidEvent.GTIDNext()
 				if err != nil {
 					return nil, fmt.Errorf("failed to retrieve next GTID set: %w", err)
@@ -189,77 +288,168 @@ func (i *Iterator) Next() ([]kafkalib.Message, error) {
 				return nil, fmt.Errorf("failed to update position: %w", err)
 			}
 
-			switch event.Header.EventType {
-			case
-				// We don't need these events, [GTID_EVENT] will contain the offsets via GTID sets, which is handled in [UpdatePosition]
-				replication.GTID_EVENT,
-				replication.PREVIOUS_GTIDS_EVENT,
-				replication.FORMAT_DESCRIPTION_EVENT,
-				replication.ANONYMOUS_GTID_EVENT,
-				replication.TABLE_MAP_EVENT,
-				// We don't need TableMapEvent because we are handling it by consuming DDL queries, applying it to our schema adapter
-				// RotateEvent is handled by [UpdatePosition]
-				replication.ROTATE_EVENT,
-				replication.XID_EVENT:
-				continue
-			case replication.QUERY_EVENT:
-				query, err := typing.AssertType[*replication.QueryEvent](event.Event)
-				if err != nil {
-					return nil, fmt.Errorf("failed to assert a query event: %w", err)
-				}
-
-				if err = i.persistAndProcessDDL(query, ts); err != nil {
-					return nil, fmt.Errorf("failed to persist DDL: %w", err)
-				}
-			case replication.WRITE_ROWS_EVENTv2, replication.UPDATE_ROWS_EVENTv2, replication.DELETE_ROWS_EVENTv2:
-				rows, err := i.processDML(ts, event, currentGTID)
-				if err != nil {
-					return nil, fmt.Errorf("failed to process DML: %w", err)
-				}
-
-				rawMsgs = append(rawMsgs, rows...)
-			default:
-				slog.Info("Skipping event", slog.Any("eventType", event.Header.EventType))
+			msgs, err := i.processEvent(ts, event, currentGTID)
+			if err != nil {
+				return nil, err
 			}
+
+			rawMsgs = append(rawMsgs, msgs...)
 		}
 	}
 
 	if len(rawMsgs) == 0 {
 		// If there are no messages, let's sleep a bit before we try again
-		time.Sleep(2 * time.Second)
+		iterator.Sleep(ctx, 2*time.Second)
 	}
 
 	return rawMsgs, nil
 }
 
-func (i *Iterator) persistAndProcessDDL(evt *replication.QueryEvent, ts time.Time) error {
+func (i *Iterator) processEvent(ts time.Time, event *replication.BinlogEvent, currentGTID *string) ([]kafkalib.Message, error) {
+	switch event.Header.EventType {
+	case
+		// We don't need these events, [GTID_EVENT] will contain the offsets via GTID sets, which is handled in [UpdatePosition]
+		replication.GTID_EVENT,
+		replication.PREVIOUS_GTIDS_EVENT,
+		replication.MARIADB_GTID_EVENT,
+		replication.MARIADB_GTID_LIST_EVENT,
+		replication.MARIADB_BINLOG_CHECKPOINT_EVENT,
+		// MariaDB writes the statement that produced the rows events that follow when `binlog_annotate_row_events` is enabled.
+		replication.MARIADB_ANNOTATE_ROWS_EVENT,
+		replication.FORMAT_DESCRIPTION_EVENT,
+		replication.ANONYMOUS_GTID_EVENT,
+		replication.TABLE_MAP_EVENT,
+		// We don't need TableMapEvent because we are handling it by consuming DDL queries, applying it to our schema adapter
+		// RotateEvent is handled by [UpdatePosition]
+		replication.ROTATE_EVENT:
+		return nil, nil
+	case replication.XID_EVENT:
+		i.completeTransaction()
+		return nil, nil
+	case replication.QUERY_EVENT, replication.MARIADB_QUERY_COMPRESSED_EVENT:
+		query, err := typing.AssertType[*replication.QueryEvent](event.Event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to assert a query event: %w", err)
+		}
+
+		switch strings.ToUpper(strings.TrimSpace(string(query.Query))) {
+		case "BEGIN":
+			i.inTransaction = true
+			return nil, nil
+		case "COMMIT":
+			// Transactions on non-transactional engines end with a COMMIT query instead of a XID event.
+			i.completeTransaction()
+			return nil, nil
+		}
+
+		msgs, err := i.persistAndProcessDDL(query, ts, currentGTID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to persist DDL: %w", err)
+		}
+
+		msgs = i.skipDeliveredRows(msgs)
+		if !i.inTransaction {
+			// DDLs are implicitly committed.
+			i.completeTransaction()
+		}
+
+		return msgs, nil
+	case
+		replication.WRITE_ROWS_EVENTv2, replication.UPDATE_ROWS_EVENTv2, replication.DELETE_ROWS_EVENTv2,
+		// Written instead of UPDATE_ROWS_EVENTv2 when `binlog_row_value_options` is set to PARTIAL_JSON.
+		replication.PARTIAL_UPDATE_ROWS_EVENT,
+		// MariaDB writes v1 rows events, which may be compressed when `log_bin_compress` is enabled.
+		replication.WRITE_ROWS_EVENTv1, replication.UPDATE_ROWS_EVENTv1, replication.DELETE_ROWS_EVENTv1,
+		replication.MARIADB_WRITE_ROWS_COMPRESSED_EVENT_V1, replication.MARIADB_UPDATE_ROWS_COMPRESSED_EVENT_V1, replication.MARIADB_DELETE_ROWS_COMPRESSED_EVENT_V1:
+		rows, err := i.processDML(ts, event, currentGTID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process DML: %w", err)
+		}
+
+		return i.skipDeliveredRows(rows), nil
+	case replication.TRANSACTION_PAYLOAD_EVENT:
+		// With `binlog_transaction_compression` enabled, the whole transaction is compressed into a single event.
+		// The parser decompresses it for us, the position is tracked by the outer event since the inner events do not have a log position.
+		payload, err := typing.AssertType[*replication.TransactionPayloadEvent](event.Event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to assert a transaction payload event: %w", err)
+		}
+
+		var rawMsgs []kafkalib.Message
+		for _, innerEvent := range payload.Events {
+			if innerEvent.Header.EventType == replication.TRANSACTION_PAYLOAD_EVENT {
+				return nil, fmt.Errorf("unexpected nested transaction payload event")
+			}
+
+			if innerEvent.Header.EventType == replication.PARTIAL_UPDATE_ROWS_EVENT {
+				// Events in the payload are decoded without [decodeRowsEvent], they do not have a checksum.
+				rowsEvent, err := typing.AssertType[*replication.RowsEvent](innerEvent.Event)
+				if err != nil {
+					return nil, fmt.Errorf("failed to assert a rows event: %w", err)
+				}
+
+				if err = decodeRowsEvent(rowsEvent, innerEvent.RawData[replication.EventHeaderSize:]); err != nil {
+					return nil, fmt.Errorf("failed to decode rows event from transaction payload: %w", err)
+				}
+			}
+
+			msgs, err := i.processEvent(getTimeFromEvent(innerEvent), innerEvent, currentGTID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to process event from transaction payload: %w", err)
+			}
+
+			rawMsgs = append(rawMsgs, msgs...)
+		}
+
+		return rawMsgs, nil
+	default:
+		slog.Info("Skipping event", slog.Any("eventType", event.Header.EventType))
+		return nil, nil
+	}
+}
+
+// persistAndProcessDDL applies the DDL to our schema, it returns truncate messages for the tables that were truncated.
+func (i *Iterator) persistAndProcessDDL(evt *replication.QueryEvent, ts time.Time, currentGTID *string) ([]kafkalib.Message, error) {
 	if evt.ErrorCode != 0 {
 		// Don't process a non-zero error code DDL.
-		return nil
+		return nil, nil
 	}
 
-	if !strings.EqualFold(i.cfg.Database, string(evt.Schema)) {
-		slog.Debug("Skipping this event since the database does not match the configured database",
+	database := string(evt.Schema)
+	if !i.catalog.IncludesDatabase(database) {
+		slog.Debug("Skipping this event since the database is not streamed",
 			slog.String("config_db", i.cfg.Database),
-			slog.String("event_db", string(evt.Schema)),
+			slog.String("event_db", database),
 		)
 
-		return nil
+		return nil, nil
 	}
 
 	query := string(evt.Query)
 	if shouldSkipDDL(query) {
-		return nil
+		return nil, nil
 	}
 
-	schemaHistory := SchemaHistory{
-		Query:  query,
-		UnixTs: ts.Unix(),
+	if err := i.schemaHistoryList.Push(newSchemaHistory(i.cfg, database, query, ts.Unix())); err != nil {
+		return nil, fmt.Errorf("failed to push schema history: %w", err)
 	}
 
-	if err := i.schemaHistoryList.Push(schemaHistory); err != nil {
-		return fmt.Errorf("failed to push schema history: %w", err)
+	truncatedTables, err := i.catalog.ApplyDDL(database, ts.Unix(), query)
+	if err != nil {
+		return nil, err
 	}
 
-	return i.schemaAdapter.ApplyDDL(ts.Unix(), query)
+	var rawMsgs []kafkalib.Message
+	for _, tblAdapter := range truncatedTables {
+		msg, ok, err := i.buildTruncateMessage(ts, tblAdapter, currentGTID)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			rawMsgs = append(rawMsgs, msg)
+		}
+	}
+
+	return rawMsgs, nil
 }
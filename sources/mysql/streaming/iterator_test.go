@@ -0,0 +1,244 @@
+package streaming
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/artie-labs/transfer/lib/cdc/util"
+	"github.com/artie-labs/transfer/lib/debezium"
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/kafkalib"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+	"github.com/artie-labs/reader/lib/storage/persistedlist"
+	"github.com/artie-labs/reader/lib/storage/persistedmap"
+	"github.com/artie-labs/reader/sources/mysql/streaming/ddl"
+)
+
+func TestIterator_ProcessEvent_TransactionPayload(t *testing.T) {
+	iter := Iterator{cfg: config.MySQL{Database: "db"}}
+	{
+		// Inner events that we don't need are skipped
+		event := &replication.BinlogEvent{
+			Header: &replication.EventHeader{EventType: replication.TRANSACTION_PAYLOAD_EVENT},
+			Event: &replication.TransactionPayloadEvent{
+				Events: []*replication.BinlogEvent{
+					{
+						Header: &replication.EventHeader{EventType: replication.QUERY_EVENT},
+						Event:  &replication.QueryEvent{Schema: []byte("other_db"), Query: []byte("BEGIN")},
+					},
+					{Header: &replication.EventHeader{EventType: replication.TABLE_MAP_EVENT}},
+					{Header: &replication.EventHeader{EventType: replication.XID_EVENT}},
+				},
+			},
+		}
+
+		msgs, err := iter.processEvent(time.Time{}, event, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, msgs)
+	}
+	{
+		// Wrong event type
+		event := &replication.BinlogEvent{
+			Header: &replication.EventHeader{EventType: replication.TRANSACTION_PAYLOAD_EVENT},
+			Event:  &replication.XIDEvent{},
+		}
+
+		_, err := iter.processEvent(time.Time{}, event, nil)
+		assert.ErrorContains(t, err, "failed to assert a transaction payload event")
+	}
+	{
+		// Nested transaction payloads are not expected
+		event := &replication.BinlogEvent{
+			Header: &replication.EventHeader{EventType: replication.TRANSACTION_PAYLOAD_EVENT},
+			Event: &replication.TransactionPayloadEvent{
+				Events: []*replication.BinlogEvent{
+					{Header: &replication.EventHeader{EventType: replication.TRANSACTION_PAYLOAD_EVENT}},
+				},
+			},
+		}
+
+		_, err := iter.processEvent(time.Time{}, event, nil)
+		assert.ErrorContains(t, err, "unexpected nested transaction payload event")
+	}
+}
+
+func TestIterator_TransactionBoundaries(t *testing.T) {
+	queryEvent := func(query string) *replication.BinlogEvent {
+		return &replication.BinlogEvent{
+			Header: &replication.EventHeader{EventType: replication.QUERY_EVENT},
+			Event:  &replication.QueryEvent{Schema: []byte("other_db"), Query: []byte(query)},
+		}
+	}
+	xidEvent := &replication.BinlogEvent{Header: &replication.EventHeader{EventType: replication.XID_EVENT}}
+
+	cfg := config.MySQL{Database: "db"}
+	selector, err := ddl.NewTableSelector(cfg)
+	assert.NoError(t, err)
+
+	iter := Iterator{cfg: cfg, catalog: ddl.NewCatalog(selector, mysql.MySQLFlavor, nil, nil), position: Position{File: "file", Pos: 4}, committedPosition: Position{File: "file", Pos: 4}}
+	{
+		// Position does not get committed in the middle of a transaction
+		_, err := iter.processEvent(time.Time{}, queryEvent("BEGIN"), nil)
+		assert.NoError(t, err)
+		assert.True(t, iter.inTransaction)
+
+		iter.position.Pos = 100
+		assert.Len(t, iter.skipDeliveredRows(make([]kafkalib.Message, 3)), 3)
+		assert.Equal(t, uint32(4), iter.committedPosition.Pos)
+		assert.Equal(t, int64(3), iter.transactionRowIndex)
+	}
+	{
+		// XID event completes the transaction
+		iter.position.Pos = 150
+		_, err := iter.processEvent(time.Time{}, xidEvent, nil)
+		assert.NoError(t, err)
+		assert.False(t, iter.inTransaction)
+		assert.Equal(t, uint32(150), iter.committedPosition.Pos)
+		assert.Equal(t, int64(0), iter.transactionRowIndex)
+	}
+	{
+		// COMMIT query completes the transaction
+		_, err := iter.processEvent(time.Time{}, queryEvent("BEGIN"), nil)
+		assert.NoError(t, err)
+		iter.position.Pos = 200
+		_, err = iter.processEvent(time.Time{}, queryEvent("COMMIT"), nil)
+		assert.NoError(t, err)
+		assert.False(t, iter.inTransaction)
+		assert.Equal(t, uint32(200), iter.committedPosition.Pos)
+	}
+	{
+		// DDL outside of a transaction is implicitly committed
+		iter.position.Pos = 300
+		_, err := iter.processEvent(time.Time{}, queryEvent("CREATE TABLE foo (id int)"), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(300), iter.committedPosition.Pos)
+	}
+}
+
+func TestIterator_SkipDeliveredRows(t *testing.T) {
+	msgs := []kafkalib.Message{
+		kafkalib.NewMessage("topic", debezium.FieldsObject{}, map[string]any{"id": 1}, nil),
+		kafkalib.NewMessage("topic", debezium.FieldsObject{}, map[string]any{"id": 2}, nil),
+		kafkalib.NewMessage("topic", debezium.FieldsObject{}, map[string]any{"id": 3}, nil),
+	}
+
+	iter := Iterator{rowsToSkip: 2}
+	assert.Empty(t, iter.skipDeliveredRows(msgs[:1]))
+	assert.Equal(t, msgs[2:], iter.skipDeliveredRows(msgs[1:]))
+	assert.Equal(t, int64(3), iter.transactionRowIndex)
+	assert.Len(t, iter.skipDeliveredRows(msgs), 3)
+
+	// Row index should never go backwards when we commit before we have caught up
+	iter = Iterator{rowsToSkip: 5, offsets: persistedmap.NewPersistedMap[Position](offsetstore.NewFileStore(), filepath.Join(t.TempDir(), "offsets.yaml"))}
+	assert.NoError(t, iter.CommitOffset())
+	pos, isOk := iter.offsets.Get(offsetKey)
+	assert.True(t, isOk)
+	assert.Equal(t, int64(5), pos.TransactionRowIndex)
+}
+
+func TestIterator_Truncate(t *testing.T) {
+	cfg := config.MySQL{Database: "db", Tables: []*config.MySQLTable{{Name: "foo"}}}
+	selector, err := ddl.NewTableSelector(cfg)
+	assert.NoError(t, err)
+
+	schemaHistoryList, err := persistedlist.NewPersistedList[SchemaHistory](offsetstore.NewFileStore(), filepath.Join(t.TempDir(), "schema_history.yaml"))
+	assert.NoError(t, err)
+
+	iter := Iterator{cfg: cfg, catalog: ddl.NewCatalog(selector, mysql.MySQLFlavor, nil, nil), schemaHistoryList: &schemaHistoryList}
+	queryEvent := func(query string) *replication.BinlogEvent {
+		return &replication.BinlogEvent{
+			Header: &replication.EventHeader{EventType: replication.QUERY_EVENT},
+			Event:  &replication.QueryEvent{Schema: []byte("db"), Query: []byte(query)},
+		}
+	}
+
+	ts := time.Unix(100, 0)
+	for _, query := range []string{"CREATE TABLE foo (id INT PRIMARY KEY)", "CREATE TABLE bar (id INT PRIMARY KEY)"} {
+		msgs, err := iter.processEvent(ts, queryEvent(query), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, msgs)
+	}
+	{
+		// Replicated table
+		msgs, err := iter.processEvent(ts, queryEvent("TRUNCATE TABLE foo"), nil)
+		assert.NoError(t, err)
+		assert.Len(t, msgs, 1)
+		assert.Equal(t, "db.foo", msgs[0].Topic(""))
+		assert.Equal(t, "t", msgs[0].Event().Operation())
+		assert.Equal(t, "foo", msgs[0].Event().GetTableName())
+		assert.Empty(t, msgs[0].PartitionKeyValues())
+	}
+	{
+		// Table that is not replicated
+		msgs, err := iter.processEvent(ts, queryEvent("TRUNCATE TABLE bar"), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, msgs)
+	}
+	{
+		// Truncates that happened before the table was created are skipped
+		msgs, err := iter.processEvent(time.Unix(99, 0), queryEvent("TRUNCATE TABLE foo"), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, msgs)
+	}
+}
+
+func TestIterator_ProcessDML_MinimalRowImage(t *testing.T) {
+	cfg := config.MySQL{Database: "db", Tables: []*config.MySQLTable{{Name: "foo"}}}
+	selector, err := ddl.NewTableSelector(cfg)
+	assert.NoError(t, err)
+
+	catalog := ddl.NewCatalog(selector, mysql.MySQLFlavor, nil, nil)
+	_, err = catalog.ApplyDDL("db", 0, "CREATE TABLE foo (id INT PRIMARY KEY, name VARCHAR(255), age INT, bio TEXT)")
+	assert.NoError(t, err)
+
+	iter := Iterator{cfg: cfg, catalog: catalog}
+	event := &replication.BinlogEvent{
+		Header: &replication.EventHeader{EventType: replication.UPDATE_ROWS_EVENTv2},
+		Event: &replication.RowsEvent{
+			Table:          &replication.TableMapEvent{Schema: []byte("db"), Table: []byte("foo")},
+			Rows:           [][]any{{int32(1), nil, nil, nil}, {nil, "bar", nil, nil}},
+			SkippedColumns: [][]int{{1, 2, 3}, {0, 2, 3}},
+		},
+	}
+
+	msgs, err := iter.processEvent(time.Unix(1, 0), event, nil)
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+
+	payload, ok := msgs[0].Event().(*util.SchemaEventPayload)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]any{"id": int32(1)}, payload.Payload.Before)
+	// The placeholder is only used for string columns, other columns are left out
+	assert.Equal(t, map[string]any{"id": int32(1), "name": "bar", "bio": "__debezium_unavailable_value"}, payload.Payload.After)
+	assert.Equal(t, map[string]any{"id": int32(1)}, msgs[0].PartitionKeyValues())
+}
+
+func TestIterator_ProcessDML_PartialJSONUpdate(t *testing.T) {
+	cfg := config.MySQL{Database: "db", Tables: []*config.MySQLTable{{Name: "foo"}}}
+	selector, err := ddl.NewTableSelector(cfg)
+	assert.NoError(t, err)
+
+	catalog := ddl.NewCatalog(selector, mysql.MySQLFlavor, nil, nil)
+	_, err = catalog.ApplyDDL("db", 0, "CREATE TABLE foo (id INT PRIMARY KEY, doc JSON)")
+	assert.NoError(t, err)
+
+	iter := Iterator{cfg: cfg, catalog: catalog}
+	msgs, err := iter.processEvent(time.Unix(1, 0), parsePartialUpdate(t, decodeRowsEvent), nil)
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 2)
+
+	payload, ok := msgs[0].Event().(*util.SchemaEventPayload)
+	assert.True(t, ok)
+	assert.Equal(t, "u", payload.Payload.Operation)
+	assert.Equal(t, map[string]any{"id": int32(1), "doc": `{"a":1,"c":true}`}, payload.Payload.Before)
+	assert.Equal(t, map[string]any{"id": int32(1), "doc": `{"a":2,"b":"x"}`}, payload.Payload.After)
+
+	payload, ok = msgs[1].Event().(*util.SchemaEventPayload)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]any{"id": int32(2), "doc": "3"}, payload.Payload.After)
+}
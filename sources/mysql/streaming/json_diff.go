@@ -0,0 +1,244 @@
+package streaming
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// jsonPathLeg - A leg of a JSON path, this is either an object member or an array index.
+type jsonPathLeg struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath parses the paths that MySQL writes into partial JSON updates, such as `$.foo[0]."bar baz"`.
+func parseJSONPath(path string) ([]jsonPathLeg, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("path %q does not start with '$'", path)
+	}
+
+	var legs []jsonPathLeg
+	for rest := path[1:]; len(rest) > 0; {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if strings.HasPrefix(rest, `"`) {
+				// Quoted keys are escaped the same way as JSON strings.
+				decoder := json.NewDecoder(strings.NewReader(rest))
+				var key string
+				if err := decoder.Decode(&key); err != nil {
+					return nil, fmt.Errorf("failed to parse key in path %q: %w", path, err)
+				}
+
+				legs = append(legs, jsonPathLeg{key: key})
+				rest = rest[decoder.InputOffset():]
+			} else {
+				end := strings.IndexAny(rest, ".[")
+				if end == -1 {
+					end = len(rest)
+				}
+
+				legs = append(legs, jsonPathLeg{key: rest[:end]})
+				rest = rest[end:]
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("path %q has an unterminated array index", path)
+			}
+
+			index, err := strconv.Atoi(strings.TrimSpace(rest[1:end]))
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("path %q has an invalid array index %q", path, rest[1:end])
+			}
+
+			legs = append(legs, jsonPathLeg{index: index, isIndex: true})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("path %q has an unexpected character %q", path, rest[0])
+		}
+	}
+
+	return legs, nil
+}
+
+func decodeJSON(value string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	// Keep numbers as they are, so that large integers do not lose precision.
+	decoder.UseNumber()
+	var out any
+	if err := decoder.Decode(&out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// applyJSONDiffs applies the diffs of a partial JSON update (PARTIAL_UPDATE_ROWS_EVENT) to the JSON document [value],
+// which is the value of the column in the before image.
+func applyJSONDiffs(value any, diffs jsonDiffs) (string, error) {
+	var doc string
+	switch castedValue := value.(type) {
+	case string:
+		doc = castedValue
+	case []byte:
+		doc = string(castedValue)
+	default:
+		return "", fmt.Errorf("expected string or []byte got %T for value: %v", value, value)
+	}
+
+	decoded, err := decodeJSON(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode JSON document: %w", err)
+	}
+
+	for _, diff := range diffs {
+		if decoded, err = applyJSONDiff(decoded, diff); err != nil {
+			return "", fmt.Errorf("failed to apply diff %q: %w", diff.String(), err)
+		}
+	}
+
+	// Full JSON values are encoded with [json.Marshal] by [replication.RowsEvent] as well.
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON document: %w", err)
+	}
+
+	return string(encoded), nil
+}
+
+func applyJSONDiff(doc any, diff *replication.JsonDiff) (any, error) {
+	legs, err := parseJSONPath(diff.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var newValue any
+	if diff.Op != replication.JsonDiffOperationRemove {
+		if newValue, err = decodeJSON(diff.Value); err != nil {
+			return nil, fmt.Errorf("failed to decode value: %w", err)
+		}
+	}
+
+	if len(legs) == 0 {
+		if diff.Op != replication.JsonDiffOperationReplace {
+			return nil, fmt.Errorf("cannot apply %s to the root of the document", diff.Op)
+		}
+
+		return newValue, nil
+	}
+
+	return applyJSONDiffAt(doc, legs, diff.Op, newValue)
+}
+
+// applyJSONDiffAt applies [op] at [legs] relative to [doc] and returns the updated document.
+func applyJSONDiffAt(doc any, legs []jsonPathLeg, op replication.JsonDiffOperation, value any) (any, error) {
+	leg := legs[0]
+	switch castedDoc := doc.(type) {
+	case map[string]any:
+		if leg.isIndex {
+			return nil, fmt.Errorf("cannot index object with [%d]", leg.index)
+		}
+
+		child, ok := castedDoc[leg.key]
+		if len(legs) > 1 {
+			if !ok {
+				return nil, fmt.Errorf("key %q does not exist", leg.key)
+			}
+
+			newChild, err := applyJSONDiffAt(child, legs[1:], op, value)
+			if err != nil {
+				return nil, err
+			}
+
+			castedDoc[leg.key] = newChild
+			return castedDoc, nil
+		}
+
+		switch op {
+		case replication.JsonDiffOperationReplace, replication.JsonDiffOperationInsert:
+			castedDoc[leg.key] = value
+		case replication.JsonDiffOperationRemove:
+			delete(castedDoc, leg.key)
+		default:
+			return nil, fmt.Errorf("unexpected operation %s", op)
+		}
+
+		return castedDoc, nil
+	case []any:
+		if !leg.isIndex {
+			return nil, fmt.Errorf("cannot get key %q of an array", leg.key)
+		}
+
+		if len(legs) > 1 {
+			if leg.index >= len(castedDoc) {
+				return nil, fmt.Errorf("index %d is out of range", leg.index)
+			}
+
+			newChild, err := applyJSONDiffAt(castedDoc[leg.index], legs[1:], op, value)
+			if err != nil {
+				return nil, err
+			}
+
+			castedDoc[leg.index] = newChild
+			return castedDoc, nil
+		}
+
+		switch op {
+		case replication.JsonDiffOperationReplace:
+			if leg.index >= len(castedDoc) {
+				return nil, fmt.Errorf("index %d is out of range", leg.index)
+			}
+
+			castedDoc[leg.index] = value
+			return castedDoc, nil
+		case replication.JsonDiffOperationInsert:
+			// Same as JSON_ARRAY_INSERT, indexes past the end append to the array.
+			return slices.Insert(castedDoc, min(leg.index, len(castedDoc)), value), nil
+		case replication.JsonDiffOperationRemove:
+			if leg.index >= len(castedDoc) {
+				return castedDoc, nil
+			}
+
+			return slices.Delete(castedDoc, leg.index, leg.index+1), nil
+		default:
+			return nil, fmt.Errorf("unexpected operation %s", op)
+		}
+	default:
+		return nil, fmt.Errorf("expected an object or an array got %T", doc)
+	}
+}
+
+// rebuildJSONValues replaces the partial JSON values in the after image of an update with the full values, these are
+// rebuilt from the before image. Partial values of columns that are not in the before image are left out of the after
+// image, since they cannot be rebuilt.
+func rebuildJSONValues(before map[string]any, after map[string]any) error {
+	for col, val := range after {
+		switch castedValue := val.(type) {
+		case jsonDiffs:
+			beforeValue, ok := before[col]
+			if !ok {
+				delete(after, col)
+				continue
+			}
+
+			newValue, err := applyJSONDiffs(beforeValue, castedValue)
+			if err != nil {
+				return fmt.Errorf("failed to apply JSON diffs to column %q: %w", col, err)
+			}
+
+			after[col] = newValue
+		case *replication.JsonDiff:
+			// This is only the first diff of the value, see [decodeRowsEvent].
+			return fmt.Errorf("partial JSON value of column %q was not fully decoded", col)
+		}
+	}
+
+	return nil
+}
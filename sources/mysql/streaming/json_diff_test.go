@@ -0,0 +1,125 @@
+package streaming
+
+import (
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	{
+		// Root
+		legs, err := parseJSONPath("$")
+		assert.NoError(t, err)
+		assert.Empty(t, legs)
+	}
+	{
+		// Members and indexes
+		legs, err := parseJSONPath(`$.foo[1]."bar \"baz\"".qux[20]`)
+		assert.NoError(t, err)
+		assert.Equal(t, []jsonPathLeg{
+			{key: "foo"},
+			{index: 1, isIndex: true},
+			{key: `bar "baz"`},
+			{key: "qux"},
+			{index: 20, isIndex: true},
+		}, legs)
+	}
+	{
+		// Invalid paths
+		for _, path := range []string{"foo", "$[1", "$[last]", "$*", `$."foo`} {
+			_, err := parseJSONPath(path)
+			assert.Error(t, err, path)
+		}
+	}
+}
+
+func TestApplyJSONDiff(t *testing.T) {
+	doc := `{"a":1,"b":[1,2,3],"c":{"d":"e"},"big":12345678901234567890}`
+	{
+		// Replace
+		out, err := applyJSONDiffs(doc, jsonDiffs{{Op: replication.JsonDiffOperationReplace, Path: "$.c.d", Value: `"f"`}})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":1,"b":[1,2,3],"big":12345678901234567890,"c":{"d":"f"}}`, out)
+
+		out, err = applyJSONDiffs([]byte(doc), jsonDiffs{{Op: replication.JsonDiffOperationReplace, Path: "$.b[1]", Value: `{"x":null}`}})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":1,"b":[1,{"x":null},3],"big":12345678901234567890,"c":{"d":"e"}}`, out)
+
+		out, err = applyJSONDiffs(doc, jsonDiffs{{Op: replication.JsonDiffOperationReplace, Path: "$", Value: `[]`}})
+		assert.NoError(t, err)
+		assert.Equal(t, `[]`, out)
+	}
+	{
+		// Insert
+		out, err := applyJSONDiffs(doc, jsonDiffs{{Op: replication.JsonDiffOperationInsert, Path: "$.c.g", Value: `true`}})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":1,"b":[1,2,3],"big":12345678901234567890,"c":{"d":"e","g":true}}`, out)
+
+		out, err = applyJSONDiffs(doc, jsonDiffs{{Op: replication.JsonDiffOperationInsert, Path: "$.b[0]", Value: `0`}})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":1,"b":[0,1,2,3],"big":12345678901234567890,"c":{"d":"e"}}`, out)
+
+		out, err = applyJSONDiffs(doc, jsonDiffs{{Op: replication.JsonDiffOperationInsert, Path: "$.b[10]", Value: `4`}})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":1,"b":[1,2,3,4],"big":12345678901234567890,"c":{"d":"e"}}`, out)
+	}
+	{
+		// Remove
+		out, err := applyJSONDiffs(doc, jsonDiffs{{Op: replication.JsonDiffOperationRemove, Path: "$.a"}})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"b":[1,2,3],"big":12345678901234567890,"c":{"d":"e"}}`, out)
+
+		out, err = applyJSONDiffs(doc, jsonDiffs{{Op: replication.JsonDiffOperationRemove, Path: "$.b[2]"}})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":1,"b":[1,2],"big":12345678901234567890,"c":{"d":"e"}}`, out)
+	}
+	{
+		// Multiple diffs are applied in order
+		out, err := applyJSONDiffs(doc, jsonDiffs{
+			{Op: replication.JsonDiffOperationReplace, Path: "$.a", Value: `{"x":1}`},
+			{Op: replication.JsonDiffOperationInsert, Path: "$.a.y", Value: `2`},
+			{Op: replication.JsonDiffOperationRemove, Path: "$.b[0]"},
+			{Op: replication.JsonDiffOperationRemove, Path: "$.c"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":{"x":1,"y":2},"b":[2,3],"big":12345678901234567890}`, out)
+	}
+	{
+		// Paths that do not exist
+		_, err := applyJSONDiffs(doc, jsonDiffs{{Op: replication.JsonDiffOperationReplace, Path: "$.x.y", Value: `1`}})
+		assert.ErrorContains(t, err, `key "x" does not exist`)
+
+		_, err = applyJSONDiffs(doc, jsonDiffs{{Op: replication.JsonDiffOperationReplace, Path: "$.b[3]", Value: `1`}})
+		assert.ErrorContains(t, err, "index 3 is out of range")
+
+		_, err = applyJSONDiffs(doc, jsonDiffs{{Op: replication.JsonDiffOperationReplace, Path: "$.a.b", Value: `1`}})
+		assert.ErrorContains(t, err, "expected an object or an array got json.Number")
+	}
+	{
+		// Invalid before value
+		_, err := applyJSONDiffs(nil, jsonDiffs{{Op: replication.JsonDiffOperationRemove, Path: "$.a"}})
+		assert.ErrorContains(t, err, "expected string or []byte got <nil>")
+	}
+}
+
+func TestRebuildJSONValues(t *testing.T) {
+	{
+		before := map[string]any{"id": 1, "doc": `{"a":1}`}
+		after := map[string]any{
+			"id":    1,
+			"doc":   jsonDiffs{{Op: replication.JsonDiffOperationReplace, Path: "$.a", Value: `2`}},
+			"other": jsonDiffs{{Op: replication.JsonDiffOperationReplace, Path: "$.a", Value: `2`}},
+		}
+		assert.NoError(t, rebuildJSONValues(before, after))
+		// [other] is not in the before image, so it is left out of the after image.
+		assert.Equal(t, map[string]any{"id": 1, "doc": `{"a":2}`}, after)
+	}
+	{
+		// A diff that was decoded by [replication.RowsEvent] may not be the only diff of the value
+		before := map[string]any{"id": 1, "doc": `{"a":1}`}
+		after := map[string]any{"id": 1, "doc": &replication.JsonDiff{Op: replication.JsonDiffOperationReplace, Path: "$.a", Value: `2`}}
+		assert.ErrorContains(t, rebuildJSONValues(before, after), `partial JSON value of column "doc" was not fully decoded`)
+	}
+}
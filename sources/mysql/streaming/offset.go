@@ -9,6 +9,11 @@ import (
 	"github.com/go-mysql-org/go-mysql/replication"
 )
 
+// gtidEvent - Implemented by both [replication.GTIDEvent] and [replication.MariadbGTIDEvent].
+type gtidEvent interface {
+	GTIDNext() (mysql.GTIDSet, error)
+}
+
 type Position struct {
 	// Binlog position
 	File string `yaml:"file"`
@@ -20,14 +25,17 @@ type Position struct {
 	_gtidSet mysql.GTIDSet `yaml:"-"`
 
 	UnixTs int64 `yaml:"unixTs"`
+	// TransactionRowIndex - Number of rows that were already emitted from the transaction that starts at this position.
+	TransactionRowIndex int64 `yaml:"transactionRowIndex"`
 }
 
 func (p Position) String() string {
 	return fmt.Sprintf("File: %q, Pos: %d, GTIDSet (if enabled): %q", p.File, p.Pos, p.GTIDSet)
 }
 
-func (p *Position) ToGTIDSet() (mysql.GTIDSet, error) {
-	_gtidSet, err := mysql.ParseGTIDSet(mysql.MySQLFlavor, p.GTIDSet)
+// ToGTIDSet parses the GTID set, [flavor] is either [mysql.MySQLFlavor] or [mysql.MariaDBFlavor].
+func (p *Position) ToGTIDSet(flavor string) (mysql.GTIDSet, error) {
+	_gtidSet, err := mysql.ParseGTIDSet(flavor, p.GTIDSet)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse GTID set: %w", err)
 	}
@@ -44,8 +52,8 @@ func (p *Position) UpdatePosition(ts time.Time, evt *replication.BinlogEvent) er
 	// We should always update the log position
 	p.Pos = evt.Header.LogPos
 	p.UnixTs = ts.Unix()
-	if evt.Header.EventType == replication.GTID_EVENT {
-		gtidEvent, err := typing.AssertType[*replication.GTIDEvent](evt.Event)
+	if evt.Header.EventType == replication.GTID_EVENT || evt.Header.EventType == replication.MARIADB_GTID_EVENT {
+		gtidEvent, err := typing.AssertType[gtidEvent](evt.Event)
 		if err != nil {
 			return err
 		}
@@ -55,6 +63,16 @@ func (p *Position) UpdatePosition(ts time.Time, evt *replication.BinlogEvent) er
 			return fmt.Errorf("failed to retrieve next GTID set: %w", err)
 		}
 
+		if mariadbGTIDSet, ok := p._gtidSet.(*mysql.MariadbGTIDSet); ok {
+			// MariaDB can only resume from a single GTID per replication domain, so the last one replaces the others.
+			gtid, err := mysql.ParseMariadbGTID(set.String())
+			if err != nil {
+				return fmt.Errorf("failed to parse GTID: %w", err)
+			}
+
+			delete(mariadbGTIDSet.Sets, gtid.DomainID)
+		}
+
 		if err = p._gtidSet.Update(set.String()); err != nil {
 			return fmt.Errorf("failed to update GTID set: %w", err)
 		}
@@ -0,0 +1,193 @@
+package streaming
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// jsonDiffs - Every diff of a partial JSON value in a PARTIAL_UPDATE_ROWS_EVENT, [replication.RowsEvent] only decodes the
+// first diff of a value, see [decodeRowsEvent].
+type jsonDiffs []*replication.JsonDiff
+
+// decodeRowsEvent decodes a rows event, it is used as [replication.BinlogSyncerConfig.RowsEventDecodeFunc]. Partial JSON
+// values are replaced with [jsonDiffs] that hold all of their diffs.
+func decodeRowsEvent(e *replication.RowsEvent, data []byte) error {
+	// Copy the event before it is decoded, so that [data] can be decoded again with the state of the parser.
+	rawEvent := *e
+	if err := e.Decode(data); err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(e.Rows, func(row []any) bool { return slices.ContainsFunc(row, isJSONDiff) }) {
+		return nil
+	}
+
+	if err := decodePartialJSONValues(e, rawEvent, data); err != nil {
+		return fmt.Errorf("failed to decode partial JSON values: %w", err)
+	}
+
+	return nil
+}
+
+func isJSONDiff(value any) bool {
+	_, ok := value.(*replication.JsonDiff)
+	return ok
+}
+
+// decodePartialJSONValues decodes every diff of the partial JSON values in [e]. The event is decoded again with JSON
+// columns as blobs to get the binary values, which hold the diffs one after the other.
+//
+// go-mysql does not export its binary JSON decoder, so this relies on how go-mysql v1.9.1 decodes rows events: blobs
+// are returned as []byte and the bitmap of partial values is sized by [replication.TableMapEvent.JsonColumnCount].
+// TestDecodeRowsEvent_MinimalRowImage needs to pass before go-mysql is upgraded.
+func decodePartialJSONValues(e *replication.RowsEvent, rawEvent replication.RowsEvent, data []byte) error {
+	pos, err := rawEvent.DecodeHeader(data)
+	if err != nil {
+		return err
+	}
+
+	tableMap := *rawEvent.Table
+	tableMap.ColumnType = slices.Clone(tableMap.ColumnType)
+	for idx, columnType := range rawEvent.Table.ColumnType {
+		if columnType == mysql.MYSQL_TYPE_JSON {
+			tableMap.ColumnType[idx] = mysql.MYSQL_TYPE_BLOB
+			// The bitmap of partial values has a bit for every JSON column, keep the count the same so that it is still read.
+			tableMap.ColumnType = append(tableMap.ColumnType, mysql.MYSQL_TYPE_JSON)
+		}
+	}
+
+	jsonEvent := rawEvent
+	jsonEvent.Table = &tableMap
+	if err = jsonEvent.DecodeData(pos, data); err != nil {
+		return err
+	}
+
+	for rowIdx, row := range e.Rows {
+		for colIdx, value := range row {
+			if !isJSONDiff(value) {
+				continue
+			}
+
+			binaryValue, ok := jsonEvent.Rows[rowIdx][colIdx].([]byte)
+			if !ok {
+				return fmt.Errorf("expected []byte got %T for the binary value of column %d", jsonEvent.Rows[rowIdx][colIdx], colIdx)
+			}
+
+			diffs, err := parseJSONDiffs(binaryValue, func(value []byte) (string, error) {
+				return decodeJSONValue(rawEvent, data[:pos], colIdx, value)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to parse diffs of column %d: %w", colIdx, err)
+			}
+
+			row[colIdx] = diffs
+		}
+	}
+
+	return nil
+}
+
+// parseJSONDiffs parses a binary partial JSON value, see `Json_diff_vector::read_binary` in MySQL.
+func parseJSONDiffs(data []byte, decodeValue func([]byte) (string, error)) (jsonDiffs, error) {
+	var diffs jsonDiffs
+	for len(data) > 0 {
+		diff := &replication.JsonDiff{Op: replication.JsonDiffOperation(data[0])}
+		if diff.Op > replication.JsonDiffOperationRemove {
+			return nil, fmt.Errorf("unexpected operation %s", diff.Op)
+		}
+
+		path, rest, err := readLengthEncodedString(data[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read path: %w", err)
+		}
+
+		diff.Path = string(path)
+		data = rest
+		if diff.Op != replication.JsonDiffOperationRemove {
+			var value []byte
+			if value, data, err = readLengthEncodedString(data); err != nil {
+				return nil, fmt.Errorf("failed to read value of path %q: %w", diff.Path, err)
+			}
+
+			if diff.Value, err = decodeValue(value); err != nil {
+				return nil, fmt.Errorf("failed to decode value of path %q: %w", diff.Path, err)
+			}
+		}
+
+		diffs = append(diffs, diff)
+	}
+
+	return diffs, nil
+}
+
+func readLengthEncodedString(data []byte) ([]byte, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	// Lengths that start with these bytes are followed by 2, 3 or 8 bytes.
+	var size int
+	switch data[0] {
+	case 0xfc:
+		size = 3
+	case 0xfd:
+		size = 4
+	case 0xfe:
+		size = 9
+	}
+
+	if len(data) < size {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	value, _, n, err := mysql.LengthEncodedString(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return value, data[n:], nil
+}
+
+// decodeJSONValue decodes the binary JSON [value] of column [colIdx], by decoding an update of only that column with
+// the header of [rawEvent]. Like [decodePartialJSONValues] this relies on the rows event layout of go-mysql v1.9.1,
+// which ends the header with the column bitmaps.
+func decodeJSONValue(rawEvent replication.RowsEvent, header []byte, colIdx int, value []byte) (string, error) {
+	meta := int(rawEvent.Table.ColumnMeta[colIdx])
+	if meta < 1 || meta > 4 || (meta < 4 && len(value) >= 1<<(8*meta)) {
+		return "", fmt.Errorf("value of %d bytes does not fit a length of %d bytes", len(value), meta)
+	}
+
+	// The header ends with the column bitmaps of the before and after images.
+	bitmapSize := (int(rawEvent.ColumnCount) + 7) / 8
+	data := slices.Clone(header[:len(header)-2*bitmapSize])
+	data = append(data, make([]byte, bitmapSize)...)
+	afterBitmap := make([]byte, bitmapSize)
+	afterBitmap[colIdx/8] = 1 << (colIdx % 8)
+	data = append(data, afterBitmap...)
+
+	// The before image is empty, the after image has no `binlog_row_value_options` and an empty null bitmap.
+	data = append(data, 0, 0)
+	data = append(data, binary.LittleEndian.AppendUint32(nil, uint32(len(value)))[:meta]...)
+	data = append(data, value...)
+
+	valueEvent := rawEvent
+	if err := valueEvent.Decode(data); err != nil {
+		return "", err
+	}
+
+	if len(valueEvent.Rows) != 2 {
+		return "", fmt.Errorf("expected 2 rows got %d", len(valueEvent.Rows))
+	}
+
+	decoded, ok := valueEvent.Rows[1][colIdx].(string)
+	if !ok {
+		return "", fmt.Errorf("expected string got %T", valueEvent.Rows[1][colIdx])
+	}
+
+	return decoded, nil
+}
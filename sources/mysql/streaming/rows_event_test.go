@@ -0,0 +1,216 @@
+package streaming
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"slices"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/stretchr/testify/assert"
+)
+
+// buildBinlogEvent returns the raw data of a binlog event without a checksum.
+func buildBinlogEvent(eventType replication.EventType, body []byte) []byte {
+	data := binary.LittleEndian.AppendUint32(nil, 1)
+	data = append(data, byte(eventType))
+	data = binary.LittleEndian.AppendUint32(data, 1)
+	data = binary.LittleEndian.AppendUint32(data, uint32(replication.EventHeaderSize+len(body)))
+	data = binary.LittleEndian.AppendUint32(data, 0)
+	data = binary.LittleEndian.AppendUint16(data, 0)
+	return append(data, body...)
+}
+
+// withChecksum appends the CRC32 checksum to a binlog event, the way MySQL writes it with `binlog_checksum=CRC32`.
+func withChecksum(event []byte) []byte {
+	event = slices.Clone(event)
+	binary.LittleEndian.PutUint32(event[9:], uint32(len(event)+replication.BinlogChecksumLength))
+	return binary.LittleEndian.AppendUint32(event, crc32.ChecksumIEEE(event))
+}
+
+// buildFormatDescriptionEvent returns the body of a FORMAT_DESCRIPTION_EVENT of a MySQL 8.0 server.
+func buildFormatDescriptionEvent(checksumAlgorithm byte) []byte {
+	formatDescription := binary.LittleEndian.AppendUint16(nil, 4)
+	formatDescription = append(formatDescription, make([]byte, 50)...)
+	copy(formatDescription[2:], "8.0.36")
+	formatDescription = binary.LittleEndian.AppendUint32(formatDescription, 0)
+	formatDescription = append(formatDescription, replication.EventHeaderSize)
+	for range replication.PARTIAL_UPDATE_ROWS_EVENT {
+		formatDescription = append(formatDescription, 10)
+	}
+	// The checksum of the event itself, it is not verified.
+	return append(formatDescription, checksumAlgorithm, 0, 0, 0, 0)
+}
+
+func withLength(value []byte) []byte {
+	return append(binary.LittleEndian.AppendUint32(nil, uint32(len(value))), value...)
+}
+
+// parsePartialUpdate parses a PARTIAL_UPDATE_ROWS_EVENT of `foo (id INT PRIMARY KEY, doc JSON)` with two rows. The first
+// row updates `doc` from `{"a":1,"c":true}` with `JSON_REMOVE(JSON_SET(doc, '$.a', 2, '$.b', 'x'), '$.c')`, the second
+// row updates `doc` from `1` to `3` with a full value.
+func parsePartialUpdate(t *testing.T, decodeFunc func(*replication.RowsEvent, []byte) error) *replication.BinlogEvent {
+	// No checksum
+	formatDescription := buildFormatDescriptionEvent(replication.BINLOG_CHECKSUM_ALG_OFF)
+
+	tableMap := []byte{1, 0, 0, 0, 0, 0, 0, 0}
+	tableMap = append(tableMap, 2, 'd', 'b', 0, 3, 'f', 'o', 'o', 0)
+	tableMap = append(tableMap, 2, mysql.MYSQL_TYPE_LONG, mysql.MYSQL_TYPE_JSON)
+	tableMap = append(tableMap, 1, 4, 0b10)
+
+	// {"a":1,"c":true}
+	object := []byte{0x00, 2, 0, 20, 0, 18, 0, 1, 0, 19, 0, 1, 0, 0x05, 1, 0, 0x04, 1, 0, 'a', 'c'}
+	diffs := []byte{byte(replication.JsonDiffOperationReplace), 3, '$', '.', 'a', 3, 0x05, 2, 0}
+	diffs = append(diffs, byte(replication.JsonDiffOperationInsert), 3, '$', '.', 'b', 3, 0x0c, 1, 'x')
+	diffs = append(diffs, byte(replication.JsonDiffOperationRemove), 3, '$', '.', 'c')
+
+	rows := []byte{1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 2, 0b11, 0b11}
+	// Before image: null bitmap, id, doc
+	rows = append(rows, 0, 1, 0, 0, 0)
+	rows = append(rows, withLength(object)...)
+	// After image: `binlog_row_value_options`, bitmap of partial values, null bitmap, id, doc
+	rows = append(rows, 1, 0b1, 0, 1, 0, 0, 0)
+	rows = append(rows, withLength(diffs)...)
+	rows = append(rows, 0, 2, 0, 0, 0)
+	rows = append(rows, withLength([]byte{0x05, 1, 0})...)
+	rows = append(rows, 1, 0b0, 0, 2, 0, 0, 0)
+	rows = append(rows, withLength([]byte{0x05, 3, 0})...)
+
+	parser := replication.NewBinlogParser()
+	parser.SetRowsEventDecodeFunc(decodeFunc)
+	var event *replication.BinlogEvent
+	for _, data := range [][]byte{
+		buildBinlogEvent(replication.FORMAT_DESCRIPTION_EVENT, formatDescription),
+		buildBinlogEvent(replication.TABLE_MAP_EVENT, tableMap),
+		buildBinlogEvent(replication.PARTIAL_UPDATE_ROWS_EVENT, rows),
+	} {
+		var err error
+		event, err = parser.Parse(data)
+		assert.NoError(t, err)
+	}
+
+	return event
+}
+
+func TestDecodeRowsEvent(t *testing.T) {
+	expectedDiffs := jsonDiffs{
+		{Op: replication.JsonDiffOperationReplace, Path: "$.a", Value: "2"},
+		{Op: replication.JsonDiffOperationInsert, Path: "$.b", Value: `"x"`},
+		{Op: replication.JsonDiffOperationRemove, Path: "$.c"},
+	}
+	{
+		// Every diff is decoded
+		event := parsePartialUpdate(t, decodeRowsEvent)
+		rowsEvent, ok := event.Event.(*replication.RowsEvent)
+		assert.True(t, ok)
+		assert.Equal(t, [][]any{
+			{int32(1), `{"a":1,"c":true}`},
+			{int32(1), expectedDiffs},
+			{int32(2), "1"},
+			{int32(2), "3"},
+		}, rowsEvent.Rows)
+	}
+	{
+		// Without [decodeRowsEvent] only the first diff is decoded, the event can be decoded again from its raw data
+		event := parsePartialUpdate(t, nil)
+		rowsEvent, ok := event.Event.(*replication.RowsEvent)
+		assert.True(t, ok)
+		assert.Equal(t, &replication.JsonDiff{Op: replication.JsonDiffOperationReplace, Path: "$.a", Value: "2"}, rowsEvent.Rows[1][1])
+
+		assert.NoError(t, decodeRowsEvent(rowsEvent, event.RawData[replication.EventHeaderSize:]))
+		assert.Equal(t, expectedDiffs, rowsEvent.Rows[1][1])
+		assert.Equal(t, "3", rowsEvent.Rows[3][1])
+	}
+}
+
+func TestDecodeRowsEvent_MinimalRowImage(t *testing.T) {
+	// A PARTIAL_UPDATE_ROWS_EVENT of `bar (id INT PRIMARY KEY, a JSON, name VARCHAR(16), b JSON)` laid out the way MySQL
+	// 8.0 writes it with `binlog_row_image=MINIMAL` and `binlog_checksum=CRC32`, for:
+	// UPDATE bar SET a = JSON_ARRAY(1), b = JSON_SET(b, '$.d', JSON_OBJECT('e', JSON_ARRAY(1.5, 'y')), '$.s', REPEAT('x', 300))
+	// This pins how go-mysql lays out rows events, which [decodePartialJSONValues] and [decodeJSONValue] rely on.
+	tableMap := []byte{2, 0, 0, 0, 0, 0, 1, 0}
+	tableMap = append(tableMap, 2, 'd', 'b', 0, 3, 'b', 'a', 'r', 0)
+	tableMap = append(tableMap, 4, mysql.MYSQL_TYPE_LONG, mysql.MYSQL_TYPE_JSON, mysql.MYSQL_TYPE_VARCHAR, mysql.MYSQL_TYPE_JSON)
+	tableMap = append(tableMap, 4, 4, 64, 0, 4, 0b1110)
+	// Optional metadata: signedness and the default charset
+	tableMap = append(tableMap, 1, 1, 0, 2, 1, 255)
+
+	// [1]
+	array := []byte{0x02, 1, 0, 7, 0, 0x05, 1, 0}
+	// {"e":[1.5,"y"]}
+	object := []byte{0x00, 1, 0, 32, 0, 11, 0, 1, 0, 0x02, 12, 0, 'e'}
+	object = append(object, 2, 0, 20, 0, 0x0b, 10, 0, 0x0c, 18, 0)
+	object = binary.LittleEndian.AppendUint64(object, 0x3ff8000000000000)
+	object = append(object, 1, 'y')
+	longString := append([]byte{0x0c, 0xac, 0x02}, bytes.Repeat([]byte{'x'}, 300)...)
+
+	diffs := []byte{byte(replication.JsonDiffOperationInsert), 3, '$', '.', 'd', byte(len(object))}
+	diffs = append(diffs, object...)
+	diffs = append(diffs, byte(replication.JsonDiffOperationReplace), 3, '$', '.', 's', 0xfc, 0x2f, 0x01)
+	diffs = append(diffs, longString...)
+
+	// The before image only has the primary key, the after image only has the columns that changed.
+	rows := []byte{2, 0, 0, 0, 0, 0, 1, 0, 2, 0, 4, 0b0001, 0b1010}
+	// Before image: null bitmap, id
+	rows = append(rows, 0, 1, 0, 0, 0)
+	// After image: `binlog_row_value_options`, bitmap of partial values that has a bit for every JSON column, null bitmap, a, b
+	rows = append(rows, 1, 0b10, 0)
+	rows = append(rows, withLength(array)...)
+	rows = append(rows, withLength(diffs)...)
+
+	parser := replication.NewBinlogParser()
+	parser.SetVerifyChecksum(true)
+	parser.SetRowsEventDecodeFunc(decodeRowsEvent)
+	var event *replication.BinlogEvent
+	for _, data := range [][]byte{
+		buildBinlogEvent(replication.FORMAT_DESCRIPTION_EVENT, buildFormatDescriptionEvent(replication.BINLOG_CHECKSUM_ALG_CRC32)),
+		withChecksum(buildBinlogEvent(replication.TABLE_MAP_EVENT, tableMap)),
+		withChecksum(buildBinlogEvent(replication.PARTIAL_UPDATE_ROWS_EVENT, rows)),
+	} {
+		var err error
+		event, err = parser.Parse(data)
+		assert.NoError(t, err)
+	}
+
+	rowsEvent, ok := event.Event.(*replication.RowsEvent)
+	assert.True(t, ok)
+	assert.Equal(t, [][]any{
+		{int32(1), nil, nil, nil},
+		{nil, "[1]", nil, jsonDiffs{
+			{Op: replication.JsonDiffOperationInsert, Path: "$.d", Value: `{"e":[1.5,"y"]}`},
+			{Op: replication.JsonDiffOperationReplace, Path: "$.s", Value: `"` + string(bytes.Repeat([]byte{'x'}, 300)) + `"`},
+		}},
+	}, rowsEvent.Rows)
+	assert.Equal(t, [][]int{{1, 2, 3}, {0, 2}}, rowsEvent.SkippedColumns)
+}
+
+func TestParseJSONDiffs(t *testing.T) {
+	decodeValue := func(value []byte) (string, error) { return string(value), nil }
+	{
+		// Long values
+		value := make([]byte, 300)
+		data := append([]byte{byte(replication.JsonDiffOperationReplace), 1, '$', 0xfc, 0x2c, 0x01}, value...)
+		diffs, err := parseJSONDiffs(data, decodeValue)
+		assert.NoError(t, err)
+		assert.Equal(t, jsonDiffs{{Op: replication.JsonDiffOperationReplace, Path: "$", Value: string(value)}}, diffs)
+	}
+	{
+		// Invalid operation
+		_, err := parseJSONDiffs([]byte{3, 1, '$'}, decodeValue)
+		assert.ErrorContains(t, err, "unexpected operation Unknown(3)")
+	}
+	{
+		// Truncated data
+		for _, data := range [][]byte{
+			{byte(replication.JsonDiffOperationRemove)},
+			{byte(replication.JsonDiffOperationRemove), 3, '$'},
+			{byte(replication.JsonDiffOperationInsert), 1, '$'},
+			{byte(replication.JsonDiffOperationInsert), 1, '$', 0xfc, 1},
+		} {
+			_, err := parseJSONDiffs(data, decodeValue)
+			assert.Error(t, err, data)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package streaming
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/signal"
+)
+
+// restoreSignals resumes the signals that were received before a restart but have not been fully processed.
+func (s *incrementalSnapshot) restoreSignals() error {
+	for _, sig := range s.signals.Pending() {
+		switch sig.Type {
+		case signal.ExecuteSnapshot:
+			slog.Info("Resuming snapshot requested by signal", slog.String("signal", sig.ID))
+			if err := s.executeSnapshot(sig); err != nil {
+				// The config may have changed since the signal was received.
+				slog.Warn("Unable to resume signal", slog.String("signal", sig.ID), slog.Any("err", err))
+				if err = s.signals.MarkDone(sig.ID); err != nil {
+					return err
+				}
+			}
+		case signal.Pause:
+			s.pauses = append(s.pauses, sig.ID)
+		}
+	}
+
+	if len(s.pauses) > 0 {
+		slog.Info("Snapshots are paused")
+	}
+
+	return nil
+}
+
+// pollSignalDirectory handles the signals of the files that have been added to the signal directory, the directory is
+// checked at most once every [signalPollInterval].
+func (s *incrementalSnapshot) pollSignalDirectory() error {
+	if s.signalDirectory == nil || time.Since(s.lastSignalPoll) < signalPollInterval {
+		return nil
+	}
+
+	s.lastSignalPoll = time.Now()
+	signals, err := s.signalDirectory.Read()
+	if err != nil {
+		// The directory may not have been created yet, we'll try again on the next poll.
+		slog.Warn("Failed to read signals", slog.Any("err", err))
+		return nil
+	}
+
+	for _, sig := range signals {
+		if err = s.handleSignal(sig); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleSignal dispatches a signal, signals are acknowledged so that they are not processed again after a restart.
+func (s *incrementalSnapshot) handleSignal(sig signal.Signal) error {
+	if s.signals.IsReceived(sig.ID) {
+		return nil
+	}
+
+	slog.Info("Received signal", slog.String("signal", sig.ID), slog.String("type", string(sig.Type)))
+	switch sig.Type {
+	case signal.ExecuteSnapshot:
+		if err := s.executeSnapshot(sig); err != nil {
+			slog.Warn("Ignoring invalid signal", slog.String("signal", sig.ID), slog.Any("err", err))
+			return s.signals.Receive(sig, true)
+		}
+
+		// The signal is done once the snapshot has completed.
+		return s.signals.Receive(sig, false)
+	case signal.StopSnapshot:
+		data, err := sig.SnapshotData()
+		if err != nil {
+			slog.Warn("Ignoring invalid signal", slog.String("signal", sig.ID), slog.Any("err", err))
+			break
+		}
+
+		s.stopSnapshots(data.Table)
+	case signal.Pause:
+		s.pauses = append(s.pauses, sig.ID)
+		slog.Info("Snapshots have been paused")
+		// The signal is done once snapshots are resumed.
+		return s.signals.Receive(sig, false)
+	case signal.Resume:
+		for _, id := range s.pauses {
+			if err := s.signals.MarkDone(id); err != nil {
+				return err
+			}
+		}
+		s.pauses = nil
+		slog.Info("Snapshots have been resumed")
+	default:
+		slog.Warn("Ignoring unknown signal", slog.String("signal", sig.ID), slog.String("type", string(sig.Type)))
+	}
+
+	return s.signals.Receive(sig, true)
+}
+
+// executeSnapshot queues a snapshot of the table in the signal, limited to its primary key range.
+func (s *incrementalSnapshot) executeSnapshot(sig signal.Signal) error {
+	data, err := sig.SnapshotData()
+	if err != nil {
+		return err
+	}
+
+	idx := slices.IndexFunc(s.tables, func(table *config.MySQLTable) bool { return table.Name == data.Table })
+	if idx == -1 {
+		return fmt.Errorf("table %q is not configured", data.Table)
+	}
+
+	tableCfg := *s.tables[idx]
+	tableCfg.OptionalPrimaryKeyValStart = data.PKStart
+	tableCfg.OptionalPrimaryKeyValEnd = data.PKEnd
+	s.pending = append(s.pending, &pendingSnapshot{tableCfg: &tableCfg, signalID: sig.ID})
+	return nil
+}
+
+// stopSnapshots cancels the snapshots of [table], or every snapshot if [table] is empty. Tables that were added to the
+// config are marked as done so that they are not snapshotted again.
+func (s *incrementalSnapshot) stopSnapshots(table string) {
+	isStopped := func(snapshot *pendingSnapshot) bool {
+		return table == "" || snapshot.tableCfg.Name == table
+	}
+
+	stop := func(snapshot *pendingSnapshot) {
+		slog.Info("Stopping snapshot", slog.String("table", snapshot.tableCfg.Name), slog.String("signal", snapshot.signalID))
+		// Progress of earlier chunks is replaced so that it does not override the snapshot being done.
+		s.uncommitted = slices.DeleteFunc(s.uncommitted, func(progress chunkProgress) bool {
+			return progress.table == s.tableKey(snapshot.tableCfg.Name) && progress.signalID == snapshot.signalID
+		})
+		s.uncommitted = append(s.uncommitted, chunkProgress{table: s.tableKey(snapshot.tableCfg.Name), signalID: snapshot.signalID, done: true})
+	}
+
+	if s.window != nil && isStopped(s.window.snapshot) {
+		// The snapshot of the last chunk has already been removed from [s.pending].
+		if !slices.Contains(s.pending, s.window.snapshot) {
+			stop(s.window.snapshot)
+		}
+		s.window = nil
+	}
+
+	var pending []*pendingSnapshot
+	for _, snapshot := range s.pending {
+		if isStopped(snapshot) {
+			stop(snapshot)
+		} else {
+			pending = append(pending, snapshot)
+		}
+	}
+	s.pending = pending
+}
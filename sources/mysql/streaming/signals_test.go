@@ -0,0 +1,114 @@
+package streaming
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/lib/signal"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+)
+
+func TestIncrementalSnapshot_HandleSignal(t *testing.T) {
+	cfg := config.MySQL{
+		Database: "db",
+		Tables:   []*config.MySQLTable{{Name: "foo"}, {Name: "bar"}},
+		StreamingSettings: config.MySQLStreamingSettings{
+			IncrementalSnapshot: &config.MySQLIncrementalSnapshot{
+				SignalTable:  "signals",
+				ProgressFile: filepath.Join(t.TempDir(), "progress.yaml"),
+				SignalsFile:  filepath.Join(t.TempDir(), "signals.yaml"),
+			},
+		},
+	}
+	store := offsetstore.NewFileStore()
+
+	snapshot, err := newIncrementalSnapshot(nil, cfg, store)
+	assert.NoError(t, err)
+
+	// Signals are read from the signal table.
+	columnNames := []string{"id", "type", "data"}
+	msgs, err := snapshot.processSignals(columnNames, [][]any{
+		{"1", "execute-snapshot", `{"table": "foo", "pkStart": "1", "pkEnd": "10"}`},
+		{"2", "execute-snapshot", `{"table": "missing"}`},
+		{"3", "execute-snapshot", `{"table": "bar"}`},
+		{"4", "pause", nil},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+	assert.Equal(t, []*pendingSnapshot{
+		{tableCfg: &config.MySQLTable{Name: "foo", OptionalPrimaryKeyValStart: "1", OptionalPrimaryKeyValEnd: "10"}, signalID: "1"},
+		{tableCfg: &config.MySQLTable{Name: "bar"}, signalID: "3"},
+	}, snapshot.pending)
+	assert.Equal(t, []string{"4"}, snapshot.pauses)
+	assert.NoError(t, snapshot.openWindow())
+	assert.Nil(t, snapshot.window)
+
+	// Signals that have already been received are not processed again.
+	assert.NoError(t, snapshot.handleSignal(signal.New("1", "execute-snapshot", `{"table": "foo"}`)))
+	assert.Len(t, snapshot.pending, 2)
+
+	// Pending snapshots and pauses are restored after a restart, invalid signals are not.
+	restored, err := newIncrementalSnapshot(nil, cfg, store)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot.pending, restored.pending)
+	assert.Equal(t, []string{"4"}, restored.pauses)
+
+	// Stopping a table only cancels its snapshots.
+	assert.NoError(t, snapshot.handleSignal(signal.New("5", "stop-snapshot", `{"table": "foo"}`)))
+	assert.Equal(t, []*pendingSnapshot{{tableCfg: &config.MySQLTable{Name: "bar"}, signalID: "3"}}, snapshot.pending)
+	assert.Equal(t, []chunkProgress{{table: "db.foo", signalID: "1", done: true}}, snapshot.uncommitted)
+	assert.NoError(t, snapshot.commit())
+
+	// Stopping a snapshot whose last chunk is in flight, it has already been removed from the pending snapshots.
+	snapshot.window = &snapshotWindow{snapshot: snapshot.pending[0]}
+	snapshot.pending = nil
+	assert.NoError(t, snapshot.handleSignal(signal.New("6", "stop-snapshot", `{"table": "bar"}`)))
+	assert.Nil(t, snapshot.window)
+	assert.Equal(t, []chunkProgress{{table: "db.bar", signalID: "3", done: true}}, snapshot.uncommitted)
+	assert.NoError(t, snapshot.commit())
+
+	assert.NoError(t, snapshot.handleSignal(signal.New("7", "resume", "")))
+	assert.Empty(t, snapshot.pauses)
+
+	restored, err = newIncrementalSnapshot(nil, cfg, store)
+	assert.NoError(t, err)
+	assert.Empty(t, restored.pending)
+	assert.Empty(t, restored.pauses)
+}
+
+func TestIncrementalSnapshot_PollSignalDirectory(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.MySQL{
+		Database: "db",
+		Tables:   []*config.MySQLTable{{Name: "foo"}},
+		StreamingSettings: config.MySQLStreamingSettings{
+			IncrementalSnapshot: &config.MySQLIncrementalSnapshot{
+				SignalTable:     "signals",
+				SignalDirectory: dir,
+				ProgressFile:    filepath.Join(t.TempDir(), "progress.yaml"),
+				SignalsFile:     filepath.Join(t.TempDir(), "signals.yaml"),
+			},
+		},
+	}
+
+	snapshot, err := newIncrementalSnapshot(nil, cfg, offsetstore.NewFileStore())
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "1.json"), []byte(`{"type": "pause"}`), 0o644))
+	assert.NoError(t, snapshot.pollSignalDirectory())
+	assert.Equal(t, []string{"1"}, snapshot.pauses)
+
+	// The directory is not checked again until the poll interval has elapsed.
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2.json"), []byte(`{"type": "resume"}`), 0o644))
+	assert.NoError(t, snapshot.pollSignalDirectory())
+	assert.Equal(t, []string{"1"}, snapshot.pauses)
+
+	snapshot.lastSignalPoll = time.Now().Add(-signalPollInterval)
+	assert.NoError(t, snapshot.pollSignalDirectory())
+	assert.Empty(t, snapshot.pauses)
+}
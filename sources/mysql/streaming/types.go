@@ -12,17 +12,30 @@ import (
 type Iterator struct {
 	cfg       config.MySQL
 	batchSize int32
-	position  Position
+	// position - Position of the last event that was read.
+	position Position
+	// committedPosition - Position right after the last transaction that was fully processed, this is what we persist.
+	committedPosition Position
+	inTransaction     bool
+	// transactionRowIndex - Number of rows that have been read from the transaction that is currently being processed.
+	transactionRowIndex int64
+	// rowsToSkip - Number of rows from the first transaction that were already emitted before we restarted.
+	rowsToSkip int64
 
 	offsets           *persistedmap.PersistedMap[Position]
 	schemaHistoryList *persistedlist.PersistedList[SchemaHistory]
 
-	schemaAdapter *ddl.SchemaAdapter
-	streamer      *replication.BinlogStreamer
-	syncer        *replication.BinlogSyncer
+	catalog  *ddl.Catalog
+	streamer *replication.BinlogStreamer
+	syncer   *replication.BinlogSyncer
+
+	// incrementalSnapshot - This is nil if incremental snapshots are not enabled.
+	incrementalSnapshot *incrementalSnapshot
 }
 
 type SchemaHistory struct {
-	Query  string `json:"query"`
-	UnixTs int64  `json:"unixTs"`
+	// Database - Database that the query was run against, this is empty for [config.MySQL.Database].
+	Database string `json:"database,omitempty"`
+	Query    string `json:"query"`
+	UnixTs   int64  `json:"unixTs"`
 }
@@ -1,6 +1,7 @@
 package streaming
 
 import (
+	"github.com/go-mysql-org/go-mysql/mysql"
 	"github.com/go-mysql-org/go-mysql/replication"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -39,3 +40,23 @@ func TestPosition_UpdatePosition(t *testing.T) {
 		assert.Equal(t, "new_file", pos.File)
 	}
 }
+
+func TestPosition_UpdatePosition_MariaDB(t *testing.T) {
+	pos := Position{File: "file", GTIDSet: "0-1-5,1-2-3"}
+	_, err := pos.ToGTIDSet(mysql.MariaDBFlavor)
+	assert.NoError(t, err)
+
+	event := &replication.BinlogEvent{
+		Header: &replication.EventHeader{
+			LogPos:    1234,
+			EventType: replication.MARIADB_GTID_EVENT,
+		},
+		Event: &replication.MariadbGTIDEvent{
+			GTID: mysql.MariadbGTID{DomainID: 0, ServerID: 2, SequenceNumber: 6},
+		},
+	}
+
+	assert.NoError(t, pos.UpdatePosition(time.Time{}, event))
+	assert.Equal(t, uint32(1234), pos.Pos)
+	assert.Equal(t, "0-2-6,1-2-3", pos.GTIDSet)
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/artie-labs/reader/config"
 	"github.com/artie-labs/reader/lib/debezium/converters"
@@ -69,18 +70,63 @@ func (p PostgresAdapter) TopicSuffix() string {
 	return fmt.Sprintf("%s.%s", p.table.Schema, p.table.Name)
 }
 
+func (p PostgresAdapter) Columns() []schema.Column {
+	return p.columns
+}
+
 func (p PostgresAdapter) FieldConverters() []transformer.FieldConverter {
 	return p.fieldConverters
 }
 
 func (p PostgresAdapter) NewIterator() (transformer.RowsIterator, error) {
-	return postgres.NewScanner(p.db, p.table, p.columns, p.scannerCfg)
+	if len(p.table.PrimaryKeys) == 0 {
+		// Tables without a key are read in full, see [transformer.RowHashKey] for how they are partitioned.
+		keylessScanner, err := postgres.NewKeylessScanner(p.db, p.table, p.columns, p.scannerCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return keylessScanner, nil
+	}
+
+	tableScanner, err := postgres.NewScanner(p.db, p.table, p.columns, p.scannerCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return tableScanner.Parallelize()
 }
 
 func (p PostgresAdapter) PartitionKeys() []string {
+	if len(p.table.PrimaryKeys) == 0 {
+		return []string{transformer.RowHashKey}
+	}
 	return p.table.PrimaryKeys
 }
 
+func (p PostgresAdapter) NewPoller(cursorColumn string, lag time.Duration) (*scan.Poller, error) {
+	cfg := scan.PollerConfig{BatchSize: p.scannerCfg.BatchSize, ErrorRetries: p.scannerCfg.ErrorRetries, Lag: lag}
+	return scan.NewPoller(p.db, cursorColumn, p.table.PrimaryKeys, cfg, postgres.NewPollAdapter(p.table, p.columns))
+}
+
+func (p PostgresAdapter) NewPrimaryKeysIterator() (transformer.RowsIterator, error) {
+	columns, err := column.FilterForIncludedColumns(p.columns, p.table.PrimaryKeys, p.table.PrimaryKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	// Primary key overrides are not applied, otherwise rows outside of them would look like they have been deleted.
+	tableScanner, err := postgres.NewScanner(p.db, p.table, columns, scan.ScannerConfig{
+		BatchSize:    p.scannerCfg.BatchSize,
+		ErrorRetries: p.scannerCfg.ErrorRetries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tableScanner, nil
+}
+
 func valueConverterForType(dataType schema.DataType, opts *schema.Opts) (converters.ValueConverter, error) {
 	switch dataType {
 	case schema.Bit:
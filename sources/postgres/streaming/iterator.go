@@ -0,0 +1,329 @@
+package streaming
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/artie-labs/transfer/lib/cdc/util"
+	"github.com/artie-labs/transfer/lib/debezium"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+
+	"github.com/artie-labs/reader/config"
+	"github.com/artie-labs/reader/constants"
+	"github.com/artie-labs/reader/lib/debezium/transformer"
+	"github.com/artie-labs/reader/lib/iterator"
+	"github.com/artie-labs/reader/lib/kafkalib"
+	"github.com/artie-labs/reader/lib/postgres/schema"
+	"github.com/artie-labs/reader/lib/storage/offsetstore"
+	"github.com/artie-labs/reader/lib/storage/persistedmap"
+	"github.com/artie-labs/reader/sources/postgres/adapter"
+)
+
+const (
+	offsetKey = "offset"
+	// standbyStatusInterval - How often we report our position to the server, this needs to be less than `wal_sender_timeout`.
+	standbyStatusInterval = 10 * time.Second
+)
+
+type tableAdapter struct {
+	adapter transformer.Adapter
+	schema  string
+	columns map[string]schema.Column
+}
+
+type Iterator struct {
+	cfg       config.PostgreSQL
+	batchSize int32
+	conn      *pgconn.PgConn
+	offsets   *persistedmap.PersistedMap[string]
+	adapters  map[string]tableAdapter
+	relations map[uint32]RelationMessage
+
+	// position - End LSN of the last transaction that was fully processed.
+	position LSN
+	// committedPosition - Last LSN that was persisted to the offset file and reported to the server.
+	committedPosition LSN
+	// commitTime - Commit time of the transaction that is currently being processed.
+	commitTime       time.Time
+	lastStatusUpdate time.Time
+}
+
+func tableKey(schemaName string, tableName string) string {
+	return fmt.Sprintf("%s.%s", schemaName, tableName)
+}
+
+func BuildStreamingIterator(ctx context.Context, db *sql.DB, cfg config.PostgreSQL, store offsetstore.OffsetStore) (*Iterator, error) {
+	adapters := make(map[string]tableAdapter)
+	for _, tableCfg := range cfg.Tables {
+		dbzAdapter, err := adapter.NewPostgresAdapter(db, *tableCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PostgreSQL adapter: %w", err)
+		}
+
+		columns := make(map[string]schema.Column)
+		for _, col := range dbzAdapter.Columns() {
+			columns[col.Name] = col
+		}
+
+		adapters[tableKey(tableCfg.Schema, tableCfg.Name)] = tableAdapter{adapter: dbzAdapter, schema: tableCfg.Schema, columns: columns}
+	}
+
+	if err := ensurePublication(ctx, db, cfg); err != nil {
+		return nil, err
+	}
+
+	slotLSN, err := ensureReplicationSlot(ctx, db, cfg.StreamingSettings.SlotName)
+	if err != nil {
+		return nil, err
+	}
+
+	position := slotLSN
+	offsets := persistedmap.NewPersistedMap[string](store, cfg.StreamingSettings.OffsetFile)
+	if encodedLSN, isOk := offsets.Get(offsetKey); isOk {
+		slog.Info("Found offsets", slog.String("offset", encodedLSN))
+		if position, err = ParseLSN(encodedLSN); err != nil {
+			return nil, fmt.Errorf("failed to parse offset: %w", err)
+		}
+	}
+
+	conn, err := pgconn.Connect(ctx, cfg.ToReplicationDSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replication connection: %w", err)
+	}
+
+	slog.Info("Starting replication", slog.String("slot", cfg.StreamingSettings.SlotName), slog.String("lsn", position.String()))
+	if err = startReplication(ctx, conn, cfg.StreamingSettings.SlotName, cfg.StreamingSettings.PublicationName, position); err != nil {
+		conn.Close(ctx)
+		return nil, fmt.Errorf("failed to start replication: %w", err)
+	}
+
+	return &Iterator{
+		cfg:               cfg,
+		batchSize:         cfg.GetStreamingBatchSize(),
+		conn:              conn,
+		offsets:           offsets,
+		adapters:          adapters,
+		relations:         make(map[uint32]RelationMessage),
+		position:          position,
+		committedPosition: position,
+		lastStatusUpdate:  time.Now(),
+	}, nil
+}
+
+func (i *Iterator) HasNext() bool {
+	return true
+}
+
+func (i *Iterator) CommitOffset() error {
+	slog.Info("Committing offset", slog.String("lsn", i.position.String()))
+	if err := i.offsets.Set(offsetKey, i.position.String()); err != nil {
+		return fmt.Errorf("failed to persist offset: %w", err)
+	}
+
+	i.committedPosition = i.position
+	return i.sendStandbyStatus()
+}
+
+func (i *Iterator) Close() error {
+	return i.conn.Close(context.Background())
+}
+
+// sendStandbyStatus lets the server know that it can recycle WAL up until the committed position.
+func (i *Iterator) sendStandbyStatus() error {
+	if err := sendStandbyStatusUpdate(i.conn, i.committedPosition); err != nil {
+		return fmt.Errorf("failed to send standby status update: %w", err)
+	}
+
+	i.lastStatusUpdate = time.Now()
+	return nil
+}
+
+func (i *Iterator) Next() ([]kafkalib.Message, error) {
+	return i.NextContext(context.Background())
+}
+
+// NextContext reads up to a batch of replication messages, it returns what has been read so far once [ctx] is done.
+func (i *Iterator) NextContext(ctx context.Context) ([]kafkalib.Message, error) {
+	receiveCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var rawMsgs []kafkalib.Message
+	for i.batchSize > int32(len(rawMsgs)) {
+		if time.Since(i.lastStatusUpdate) > standbyStatusInterval {
+			if err := i.sendStandbyStatus(); err != nil {
+				return nil, err
+			}
+		}
+
+		msg, err := i.conn.ReceiveMessage(receiveCtx)
+		if err != nil {
+			if pgconn.Timeout(err) || errors.Is(err, context.Canceled) {
+				break
+			}
+
+			return nil, fmt.Errorf("failed to receive replication message: %w", err)
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.ErrorResponse:
+			return nil, fmt.Errorf("received error from server: %w", pgconn.ErrorResponseToPgError(msg))
+		case *pgproto3.CopyData:
+			msgs, err := i.processCopyData(msg.Data)
+			if err != nil {
+				return nil, err
+			}
+
+			rawMsgs = append(rawMsgs, msgs...)
+		default:
+			slog.Info("Skipping replication message", slog.String("type", fmt.Sprintf("%T", msg)))
+		}
+	}
+
+	if len(rawMsgs) == 0 {
+		// Transactions for tables we do not replicate still move our position forward, there's nothing in flight so we can commit it.
+		if i.position > i.committedPosition {
+			if err := i.CommitOffset(); err != nil {
+				return nil, err
+			}
+		}
+
+		// If there are no messages, let's sleep a bit before we try again
+		iterator.Sleep(ctx, 2*time.Second)
+	}
+
+	return rawMsgs, nil
+}
+
+func (i *Iterator) processCopyData(data []byte) ([]kafkalib.Message, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("received empty CopyData message")
+	}
+
+	switch data[0] {
+	case primaryKeepaliveMessageByteID:
+		keepalive, err := parsePrimaryKeepalive(data[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse primary keepalive: %w", err)
+		}
+
+		if keepalive.ReplyRequested {
+			if err = i.sendStandbyStatus(); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case xLogDataByteID:
+		xld, err := parseXLogData(data[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse XLogData: %w", err)
+		}
+
+		msg, err := parseMessage(xld.WALData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pgoutput message: %w", err)
+		}
+
+		return i.processMessage(msg)
+	default:
+		return nil, fmt.Errorf("unexpected CopyData message type %q", data[0])
+	}
+}
+
+func (i *Iterator) processMessage(msg any) ([]kafkalib.Message, error) {
+	switch msg := msg.(type) {
+	case BeginMessage:
+		i.commitTime = msg.CommitTime
+	case CommitMessage:
+		i.position = msg.TransactionEndLSN
+	case RelationMessage:
+		i.relations[msg.RelationID] = msg
+	case InsertMessage:
+		return i.processDML(msg.RelationID, "c", nil, msg.Tuple)
+	case UpdateMessage:
+		return i.processDML(msg.RelationID, "u", msg.OldTuple, msg.NewTuple)
+	case DeleteMessage:
+		return i.processDML(msg.RelationID, "d", msg.OldTuple, nil)
+	case TruncateMessage:
+		var rawMsgs []kafkalib.Message
+		for _, relationID := range msg.RelationIDs {
+			msgs, err := i.processDML(relationID, "t", nil, nil)
+			if err != nil {
+				return nil, err
+			}
+			rawMsgs = append(rawMsgs, msgs...)
+		}
+		return rawMsgs, nil
+	}
+
+	return nil, nil
+}
+
+func (i *Iterator) processDML(relationID uint32, operation string, beforeTuple []TupleColumn, afterTuple []TupleColumn) ([]kafkalib.Message, error) {
+	relation, ok := i.relations[relationID]
+	if !ok {
+		return nil, fmt.Errorf("received DML for unknown relation %d", relationID)
+	}
+
+	tblAdapter, ok := i.adapters[tableKey(relation.Namespace, relation.RelationName)]
+	if !ok {
+		return nil, nil
+	}
+
+	var beforeRow map[string]any
+	if beforeTuple != nil {
+		var err error
+		if beforeRow, _, err = convertTuple(relation.Columns, beforeTuple, tblAdapter.columns); err != nil {
+			return nil, fmt.Errorf("failed to convert before row: %w", err)
+		}
+	}
+
+	var afterRow map[string]any
+	var unchangedToastColumns []string
+	if afterTuple != nil {
+		var err error
+		if afterRow, unchangedToastColumns, err = convertTuple(relation.Columns, afterTuple, tblAdapter.columns); err != nil {
+			return nil, fmt.Errorf("failed to convert after row: %w", err)
+		}
+	}
+
+	sourcePayload := util.Source{
+		Connector: "postgresql",
+		TsMs:      i.commitTime.UnixMilli(),
+		Database:  i.cfg.Database,
+		Schema:    tblAdapter.schema,
+		Table:     relation.RelationName,
+	}
+
+	dbz := transformer.NewLightDebeziumTransformer(relation.RelationName, tblAdapter.adapter.PartitionKeys(), tblAdapter.adapter.FieldConverters())
+	dbzMessage, err := dbz.BuildEventPayload(sourcePayload, beforeRow, afterRow, operation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build event payload: %w", err)
+	}
+
+	if afterFields := dbzMessage.Schema.GetSchemaFromLabel(debezium.After); afterFields != nil {
+		for _, field := range afterFields.Fields {
+			// Like Debezium, only string columns get the placeholder since it does not fit the other types.
+			if field.Type == debezium.String && slices.Contains(unchangedToastColumns, field.FieldName) {
+				dbzMessage.Payload.After[field.FieldName] = constants.DefaultUnavailableValuePlaceholder
+			}
+		}
+	}
+
+	if operation == "t" {
+		// Truncates do not have a row, so there is no partition key.
+		return []kafkalib.Message{kafkalib.NewMessage(tblAdapter.adapter.TopicSuffix(), debezium.FieldsObject{}, nil, &dbzMessage)}, nil
+	}
+
+	primaryKeyPayload, err := dbz.BuildPartitionKey(beforeRow, afterRow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build partition key: %w", err)
+	}
+
+	return []kafkalib.Message{kafkalib.NewMessage(tblAdapter.adapter.TopicSuffix(), primaryKeyPayload.Schema, primaryKeyPayload.Payload, &dbzMessage)}, nil
+}
@@ -0,0 +1,92 @@
+package streaming
+
+import (
+	"testing"
+	"time"
+
+	"github.com/artie-labs/transfer/lib/cdc/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/lib/debezium/converters"
+	"github.com/artie-labs/reader/lib/debezium/transformer"
+	"github.com/artie-labs/reader/lib/postgres/schema"
+)
+
+type mockAdapter struct{}
+
+func (mockAdapter) TableName() string {
+	return "foo"
+}
+
+func (mockAdapter) TopicSuffix() string {
+	return "public.foo"
+}
+
+func (mockAdapter) PartitionKeys() []string {
+	return []string{"id"}
+}
+
+func (mockAdapter) FieldConverters() []transformer.FieldConverter {
+	return []transformer.FieldConverter{
+		{Name: "id", ValueConverter: converters.Int32Passthrough{}},
+		{Name: "name", ValueConverter: converters.StringPassthrough{}},
+		{Name: "bio", ValueConverter: converters.StringPassthrough{}},
+		{Name: "avatar", ValueConverter: converters.BytesPassthrough{}},
+	}
+}
+
+func (mockAdapter) NewIterator() (transformer.RowsIterator, error) {
+	panic("not implemented")
+}
+
+func TestIterator_ProcessMessage_UnchangedToast(t *testing.T) {
+	iter := &Iterator{
+		adapters: map[string]tableAdapter{
+			"public.foo": {
+				adapter: mockAdapter{},
+				schema:  "public",
+				columns: map[string]schema.Column{
+					"id":     {Name: "id", Type: schema.Int32},
+					"name":   {Name: "name", Type: schema.Text},
+					"bio":    {Name: "bio", Type: schema.Text},
+					"avatar": {Name: "avatar", Type: schema.Bytea},
+				},
+			},
+		},
+		relations:  make(map[uint32]RelationMessage),
+		commitTime: time.Now(),
+	}
+
+	_, err := iter.processMessage(RelationMessage{
+		RelationID:   1,
+		Namespace:    "public",
+		RelationName: "foo",
+		Columns: []RelationColumn{
+			{IsKey: true, Name: "id", TypeOID: pgtype.Int4OID},
+			{Name: "name", TypeOID: pgtype.TextOID},
+			{Name: "bio", TypeOID: pgtype.TextOID},
+			{Name: "avatar", TypeOID: pgtype.ByteaOID},
+		},
+	})
+	assert.NoError(t, err)
+
+	msgs, err := iter.processMessage(UpdateMessage{
+		RelationID: 1,
+		NewTuple: []TupleColumn{
+			{Kind: TupleDataText, Data: []byte("1")},
+			{Kind: TupleDataText, Data: []byte("bar")},
+			{Kind: TupleDataUnchangedToast},
+			{Kind: TupleDataUnchangedToast},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+
+	payload, ok := msgs[0].Event().(*util.SchemaEventPayload)
+	assert.True(t, ok)
+	assert.Equal(t, "u", payload.Payload.Operation)
+	// The placeholder is only used for string columns, other columns are left out
+	assert.Equal(t, map[string]any{"id": int32(1), "name": "bar", "bio": "__debezium_unavailable_value"}, payload.Payload.After)
+	assert.Equal(t, map[string]any{"id": int32(1)}, msgs[0].PartitionKeyValues())
+}
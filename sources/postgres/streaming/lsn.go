@@ -0,0 +1,34 @@
+package streaming
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LSN is a PostgreSQL log sequence number, a byte position within the write-ahead log.
+type LSN uint64
+
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint32(l>>32), uint32(l))
+}
+
+// ParseLSN parses the textual `XXX/XXX` representation of an [LSN].
+func ParseLSN(value string) (LSN, error) {
+	hi, lo, found := strings.Cut(value, "/")
+	if !found {
+		return 0, fmt.Errorf("invalid LSN %q", value)
+	}
+
+	upper, err := strconv.ParseUint(hi, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse upper half of LSN %q: %w", value, err)
+	}
+
+	lower, err := strconv.ParseUint(lo, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse lower half of LSN %q: %w", value, err)
+	}
+
+	return LSN(upper<<32 | lower), nil
+}
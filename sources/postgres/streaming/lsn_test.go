@@ -0,0 +1,33 @@
+package streaming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLSN(t *testing.T) {
+	{
+		// Valid
+		lsn, err := ParseLSN("16/B374D848")
+		assert.NoError(t, err)
+		assert.Equal(t, LSN(0x16B374D848), lsn)
+		assert.Equal(t, "16/B374D848", lsn.String())
+	}
+	{
+		// Zero
+		lsn, err := ParseLSN("0/0")
+		assert.NoError(t, err)
+		assert.Equal(t, LSN(0), lsn)
+	}
+	{
+		// Missing separator
+		_, err := ParseLSN("16B374D848")
+		assert.ErrorContains(t, err, `invalid LSN "16B374D848"`)
+	}
+	{
+		// Not hex
+		_, err := ParseLSN("16/XYZ")
+		assert.ErrorContains(t, err, `failed to parse lower half of LSN "16/XYZ"`)
+	}
+}
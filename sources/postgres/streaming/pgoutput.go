@@ -0,0 +1,265 @@
+package streaming
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// Decoding for the pgoutput logical replication protocol (version 1).
+// See https://www.postgresql.org/docs/current/protocol-logicalrep-message-formats.html
+
+// postgresEpoch is the epoch used by PostgreSQL for timestamps sent over the replication protocol.
+var postgresEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type TupleDataKind byte
+
+const (
+	TupleDataNull           TupleDataKind = 'n'
+	TupleDataUnchangedToast TupleDataKind = 'u'
+	TupleDataText           TupleDataKind = 't'
+)
+
+type TupleColumn struct {
+	Kind TupleDataKind
+	Data []byte
+}
+
+type RelationColumn struct {
+	// IsKey - Whether the column is part of the replica identity.
+	IsKey   bool
+	Name    string
+	TypeOID uint32
+	TypeMod int32
+}
+
+type BeginMessage struct {
+	FinalLSN   LSN
+	CommitTime time.Time
+	Xid        uint32
+}
+
+type CommitMessage struct {
+	CommitLSN         LSN
+	TransactionEndLSN LSN
+	CommitTime        time.Time
+}
+
+type RelationMessage struct {
+	RelationID      uint32
+	Namespace       string
+	RelationName    string
+	ReplicaIdentity byte
+	Columns         []RelationColumn
+}
+
+type InsertMessage struct {
+	RelationID uint32
+	Tuple      []TupleColumn
+}
+
+type UpdateMessage struct {
+	RelationID uint32
+	// OldTuple - Only set when the replica identity changed or the table uses REPLICA IDENTITY FULL.
+	OldTuple []TupleColumn
+	NewTuple []TupleColumn
+}
+
+type DeleteMessage struct {
+	RelationID uint32
+	OldTuple   []TupleColumn
+}
+
+type TruncateMessage struct {
+	RelationIDs []uint32
+}
+
+// messageReader is a cursor over a pgoutput message that records the first decoding error.
+type messageReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (m *messageReader) fail(format string, args ...any) {
+	if m.err == nil {
+		m.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (m *messageReader) bytes(n int) []byte {
+	if m.err != nil {
+		return nil
+	}
+
+	if n < 0 || m.pos+n > len(m.data) {
+		m.fail("message is too short: wanted %d bytes at offset %d, have %d", n, m.pos, len(m.data))
+		return nil
+	}
+
+	out := m.data[m.pos : m.pos+n]
+	m.pos += n
+	return out
+}
+
+func (m *messageReader) uint8() uint8 {
+	if b := m.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (m *messageReader) uint16() uint16 {
+	if b := m.bytes(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (m *messageReader) uint32() uint32 {
+	if b := m.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (m *messageReader) uint64() uint64 {
+	if b := m.bytes(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (m *messageReader) time() time.Time {
+	return postgresEpoch.Add(time.Duration(int64(m.uint64())) * time.Microsecond)
+}
+
+func (m *messageReader) string() string {
+	if m.err != nil {
+		return ""
+	}
+
+	for i := m.pos; i < len(m.data); i++ {
+		if m.data[i] == 0 {
+			out := string(m.data[m.pos:i])
+			m.pos = i + 1
+			return out
+		}
+	}
+
+	m.fail("string starting at offset %d is not null terminated", m.pos)
+	return ""
+}
+
+func (m *messageReader) tuple() []TupleColumn {
+	count := int(m.uint16())
+	if m.err != nil {
+		return nil
+	}
+
+	columns := make([]TupleColumn, count)
+	for i := range columns {
+		kind := TupleDataKind(m.uint8())
+		switch kind {
+		case TupleDataNull, TupleDataUnchangedToast:
+			columns[i] = TupleColumn{Kind: kind}
+		case TupleDataText:
+			length := int(int32(m.uint32()))
+			columns[i] = TupleColumn{Kind: kind, Data: m.bytes(length)}
+		default:
+			m.fail("unsupported tuple data kind %q", kind)
+		}
+	}
+	return columns
+}
+
+// parseMessage decodes a pgoutput message, it returns nil for message types that we do not need (origin, type, logical decoding messages).
+func parseMessage(data []byte) (any, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("message is empty")
+	}
+
+	reader := &messageReader{data: data, pos: 1}
+	var msg any
+	switch data[0] {
+	case 'B':
+		msg = BeginMessage{
+			FinalLSN:   LSN(reader.uint64()),
+			CommitTime: reader.time(),
+			Xid:        reader.uint32(),
+		}
+	case 'C':
+		// Flags are currently unused.
+		_ = reader.uint8()
+		msg = CommitMessage{
+			CommitLSN:         LSN(reader.uint64()),
+			TransactionEndLSN: LSN(reader.uint64()),
+			CommitTime:        reader.time(),
+		}
+	case 'R':
+		relation := RelationMessage{
+			RelationID:      reader.uint32(),
+			Namespace:       reader.string(),
+			RelationName:    reader.string(),
+			ReplicaIdentity: reader.uint8(),
+		}
+		relation.Columns = make([]RelationColumn, reader.uint16())
+		for i := range relation.Columns {
+			relation.Columns[i] = RelationColumn{
+				IsKey:   reader.uint8()&1 == 1,
+				Name:    reader.string(),
+				TypeOID: reader.uint32(),
+				TypeMod: int32(reader.uint32()),
+			}
+		}
+		msg = relation
+	case 'I':
+		insert := InsertMessage{RelationID: reader.uint32()}
+		if marker := reader.uint8(); marker != 'N' && reader.err == nil {
+			return nil, fmt.Errorf("unexpected insert tuple marker %q", marker)
+		}
+		insert.Tuple = reader.tuple()
+		msg = insert
+	case 'U':
+		update := UpdateMessage{RelationID: reader.uint32()}
+		marker := reader.uint8()
+		if marker == 'K' || marker == 'O' {
+			update.OldTuple = reader.tuple()
+			marker = reader.uint8()
+		}
+		if marker != 'N' && reader.err == nil {
+			return nil, fmt.Errorf("unexpected update tuple marker %q", marker)
+		}
+		update.NewTuple = reader.tuple()
+		msg = update
+	case 'D':
+		del := DeleteMessage{RelationID: reader.uint32()}
+		if marker := reader.uint8(); marker != 'K' && marker != 'O' && reader.err == nil {
+			return nil, fmt.Errorf("unexpected delete tuple marker %q", marker)
+		}
+		del.OldTuple = reader.tuple()
+		msg = del
+	case 'T':
+		count := reader.uint32()
+		// Options (CASCADE, RESTART IDENTITY) are not needed.
+		_ = reader.uint8()
+		truncate := TruncateMessage{}
+		for range count {
+			if reader.err != nil {
+				break
+			}
+			truncate.RelationIDs = append(truncate.RelationIDs, reader.uint32())
+		}
+		msg = truncate
+	case 'O', 'Y', 'M':
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported pgoutput message type %q", data[0])
+	}
+
+	if reader.err != nil {
+		return nil, fmt.Errorf("failed to decode %q message: %w", data[0], reader.err)
+	}
+
+	return msg, nil
+}
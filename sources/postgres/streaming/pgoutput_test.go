@@ -0,0 +1,162 @@
+package streaming
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type messageBuilder struct {
+	data []byte
+}
+
+func (m *messageBuilder) byte(b byte) *messageBuilder {
+	m.data = append(m.data, b)
+	return m
+}
+
+func (m *messageBuilder) uint16(v uint16) *messageBuilder {
+	m.data = binary.BigEndian.AppendUint16(m.data, v)
+	return m
+}
+
+func (m *messageBuilder) uint32(v uint32) *messageBuilder {
+	m.data = binary.BigEndian.AppendUint32(m.data, v)
+	return m
+}
+
+func (m *messageBuilder) uint64(v uint64) *messageBuilder {
+	m.data = binary.BigEndian.AppendUint64(m.data, v)
+	return m
+}
+
+func (m *messageBuilder) string(s string) *messageBuilder {
+	m.data = append(append(m.data, s...), 0)
+	return m
+}
+
+func (m *messageBuilder) text(s string) *messageBuilder {
+	return m.byte('t').uint32(uint32(len(s))).string(s).trim()
+}
+
+// trim removes the null terminator added by [string], tuple text values are length prefixed instead.
+func (m *messageBuilder) trim() *messageBuilder {
+	m.data = m.data[:len(m.data)-1]
+	return m
+}
+
+func TestParseMessage(t *testing.T) {
+	{
+		// Empty
+		_, err := parseMessage(nil)
+		assert.ErrorContains(t, err, "message is empty")
+	}
+	{
+		// Unsupported message type
+		_, err := parseMessage([]byte{'Z'})
+		assert.ErrorContains(t, err, `unsupported pgoutput message type 'Z'`)
+	}
+	{
+		// Ignored message types
+		msg, err := parseMessage([]byte{'Y'})
+		assert.NoError(t, err)
+		assert.Nil(t, msg)
+	}
+	{
+		// Begin
+		data := (&messageBuilder{}).byte('B').uint64(100).uint64(uint64(time.Second.Microseconds())).uint32(7).data
+		msg, err := parseMessage(data)
+		assert.NoError(t, err)
+		assert.Equal(t, BeginMessage{FinalLSN: 100, CommitTime: postgresEpoch.Add(time.Second), Xid: 7}, msg)
+	}
+	{
+		// Begin - truncated
+		_, err := parseMessage([]byte{'B', 0, 0})
+		assert.ErrorContains(t, err, `failed to decode 'B' message: message is too short`)
+	}
+	{
+		// Commit
+		data := (&messageBuilder{}).byte('C').byte(0).uint64(100).uint64(120).uint64(0).data
+		msg, err := parseMessage(data)
+		assert.NoError(t, err)
+		assert.Equal(t, CommitMessage{CommitLSN: 100, TransactionEndLSN: 120, CommitTime: postgresEpoch}, msg)
+	}
+	{
+		// Relation
+		data := (&messageBuilder{}).byte('R').uint32(16384).string("public").string("users").byte('d').uint16(2).
+			byte(1).string("id").uint32(23).uint32(0xFFFFFFFF).
+			byte(0).string("name").uint32(25).uint32(0xFFFFFFFF).data
+		msg, err := parseMessage(data)
+		assert.NoError(t, err)
+		assert.Equal(t, RelationMessage{
+			RelationID:      16384,
+			Namespace:       "public",
+			RelationName:    "users",
+			ReplicaIdentity: 'd',
+			Columns: []RelationColumn{
+				{IsKey: true, Name: "id", TypeOID: 23, TypeMod: -1},
+				{IsKey: false, Name: "name", TypeOID: 25, TypeMod: -1},
+			},
+		}, msg)
+	}
+	{
+		// Relation - string is not null terminated
+		data := (&messageBuilder{}).byte('R').uint32(16384).byte('p').data
+		_, err := parseMessage(data)
+		assert.ErrorContains(t, err, "string starting at offset 5 is not null terminated")
+	}
+	{
+		// Insert
+		data := (&messageBuilder{}).byte('I').uint32(16384).byte('N').uint16(3).text("1").byte('n').byte('u').data
+		msg, err := parseMessage(data)
+		assert.NoError(t, err)
+		assert.Equal(t, InsertMessage{
+			RelationID: 16384,
+			Tuple: []TupleColumn{
+				{Kind: TupleDataText, Data: []byte("1")},
+				{Kind: TupleDataNull},
+				{Kind: TupleDataUnchangedToast},
+			},
+		}, msg)
+	}
+	{
+		// Insert - bad marker
+		data := (&messageBuilder{}).byte('I').uint32(16384).byte('X').data
+		_, err := parseMessage(data)
+		assert.ErrorContains(t, err, `unexpected insert tuple marker 'X'`)
+	}
+	{
+		// Update without old tuple
+		data := (&messageBuilder{}).byte('U').uint32(16384).byte('N').uint16(1).text("2").data
+		msg, err := parseMessage(data)
+		assert.NoError(t, err)
+		assert.Equal(t, UpdateMessage{RelationID: 16384, NewTuple: []TupleColumn{{Kind: TupleDataText, Data: []byte("2")}}}, msg)
+	}
+	{
+		// Update with old tuple
+		data := (&messageBuilder{}).byte('U').uint32(16384).byte('O').uint16(1).text("1").byte('N').uint16(1).text("2").data
+		msg, err := parseMessage(data)
+		assert.NoError(t, err)
+		assert.Equal(t, UpdateMessage{
+			RelationID: 16384,
+			OldTuple:   []TupleColumn{{Kind: TupleDataText, Data: []byte("1")}},
+			NewTuple:   []TupleColumn{{Kind: TupleDataText, Data: []byte("2")}},
+		}, msg)
+	}
+	{
+		// Delete
+		data := (&messageBuilder{}).byte('D').uint32(16384).byte('K').uint16(1).text("1").data
+		msg, err := parseMessage(data)
+		assert.NoError(t, err)
+		assert.Equal(t, DeleteMessage{RelationID: 16384, OldTuple: []TupleColumn{{Kind: TupleDataText, Data: []byte("1")}}}, msg)
+	}
+	{
+		// Truncate
+		data := (&messageBuilder{}).byte('T').uint32(2).byte(0).uint32(16384).uint32(16385).data
+		msg, err := parseMessage(data)
+		assert.NoError(t, err)
+		assert.Equal(t, TruncateMessage{RelationIDs: []uint32{16384, 16385}}, msg)
+	}
+}
@@ -0,0 +1,161 @@
+package streaming
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+
+	"github.com/artie-labs/reader/config"
+)
+
+const (
+	xLogDataByteID                = 'w'
+	primaryKeepaliveMessageByteID = 'k'
+	standbyStatusUpdateByteID     = 'r'
+)
+
+type xLogData struct {
+	WALStart LSN
+	WALData  []byte
+}
+
+func parseXLogData(data []byte) (xLogData, error) {
+	if len(data) < 24 {
+		return xLogData{}, fmt.Errorf("XLogData is too short: %d bytes", len(data))
+	}
+
+	return xLogData{
+		WALStart: LSN(binary.BigEndian.Uint64(data)),
+		// Bytes 8 to 24 contain the server's WAL end and clock which we do not need.
+		WALData: data[24:],
+	}, nil
+}
+
+type primaryKeepalive struct {
+	ServerWALEnd   LSN
+	ReplyRequested bool
+}
+
+func parsePrimaryKeepalive(data []byte) (primaryKeepalive, error) {
+	if len(data) != 17 {
+		return primaryKeepalive{}, fmt.Errorf("primary keepalive message must be 17 bytes, got %d", len(data))
+	}
+
+	return primaryKeepalive{
+		ServerWALEnd:   LSN(binary.BigEndian.Uint64(data)),
+		ReplyRequested: data[16] != 0,
+	}, nil
+}
+
+func encodeStandbyStatusUpdate(lsn LSN, now time.Time) []byte {
+	data := make([]byte, 0, 34)
+	data = append(data, standbyStatusUpdateByteID)
+	// Written, flushed and applied positions are all reported as the last committed LSN.
+	data = binary.BigEndian.AppendUint64(data, uint64(lsn))
+	data = binary.BigEndian.AppendUint64(data, uint64(lsn))
+	data = binary.BigEndian.AppendUint64(data, uint64(lsn))
+	data = binary.BigEndian.AppendUint64(data, uint64(now.Sub(postgresEpoch).Microseconds()))
+	// Do not request a reply from the server.
+	return append(data, 0)
+}
+
+func sendStandbyStatusUpdate(conn *pgconn.PgConn, lsn LSN) error {
+	copyData := &pgproto3.CopyData{Data: encodeStandbyStatusUpdate(lsn, time.Now())}
+	buf, err := copyData.Encode(nil)
+	if err != nil {
+		return fmt.Errorf("failed to encode standby status update: %w", err)
+	}
+
+	return conn.Frontend().SendUnbufferedEncodedCopyData(buf)
+}
+
+func buildStartReplicationQuery(slotName string, publicationName string, lsn LSN) string {
+	return fmt.Sprintf(`START_REPLICATION SLOT %s LOGICAL %s (proto_version '1', publication_names '%s')`,
+		pgx.Identifier{slotName}.Sanitize(), lsn, strings.ReplaceAll(publicationName, "'", "''"))
+}
+
+func startReplication(ctx context.Context, conn *pgconn.PgConn, slotName string, publicationName string, lsn LSN) error {
+	conn.Frontend().SendQuery(&pgproto3.Query{String: buildStartReplicationQuery(slotName, publicationName, lsn)})
+	if err := conn.Frontend().Flush(); err != nil {
+		return fmt.Errorf("failed to send START_REPLICATION: %w", err)
+	}
+
+	for {
+		msg, err := conn.ReceiveMessage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to receive message: %w", err)
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.NoticeResponse:
+		case *pgproto3.ErrorResponse:
+			return pgconn.ErrorResponseToPgError(msg)
+		case *pgproto3.CopyBothResponse:
+			// Replication has started.
+			return nil
+		default:
+			return fmt.Errorf("unexpected response to START_REPLICATION: %T", msg)
+		}
+	}
+}
+
+// ensurePublication creates the publication for the configured tables if it does not exist yet.
+func ensurePublication(ctx context.Context, db *sql.DB, cfg config.PostgreSQL) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pg_publication WHERE pubname = $1)", cfg.StreamingSettings.PublicationName).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check if publication exists: %w", err)
+	}
+
+	if exists {
+		return nil
+	}
+
+	tableNames := make([]string, len(cfg.Tables))
+	for i, table := range cfg.Tables {
+		tableNames[i] = pgx.Identifier{table.Schema, table.Name}.Sanitize()
+	}
+
+	query := fmt.Sprintf("CREATE PUBLICATION %s FOR TABLE %s", pgx.Identifier{cfg.StreamingSettings.PublicationName}.Sanitize(), strings.Join(tableNames, ", "))
+	slog.Info("Creating publication", slog.String("query", query))
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create publication: %w", err)
+	}
+
+	return nil
+}
+
+// ensureReplicationSlot creates the logical replication slot if it does not exist yet and returns its confirmed flush LSN.
+func ensureReplicationSlot(ctx context.Context, db *sql.DB, slotName string) (LSN, error) {
+	var plugin string
+	var confirmedFlushLSN sql.NullString
+	err := db.QueryRowContext(ctx, "SELECT plugin, confirmed_flush_lsn::TEXT FROM pg_replication_slots WHERE slot_name = $1", slotName).Scan(&plugin, &confirmedFlushLSN)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Info("Creating replication slot", slog.String("slot", slotName))
+		var lsn string
+		if err = db.QueryRowContext(ctx, "SELECT lsn::TEXT FROM pg_create_logical_replication_slot($1, 'pgoutput')", slotName).Scan(&lsn); err != nil {
+			return 0, fmt.Errorf("failed to create replication slot: %w", err)
+		}
+		return ParseLSN(lsn)
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to look up replication slot: %w", err)
+	}
+
+	if plugin != "pgoutput" {
+		return 0, fmt.Errorf("replication slot %q uses the %q plugin, expected pgoutput", slotName, plugin)
+	}
+
+	if !confirmedFlushLSN.Valid {
+		return 0, nil
+	}
+
+	return ParseLSN(confirmedFlushLSN.String)
+}
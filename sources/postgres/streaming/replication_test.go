@@ -0,0 +1,62 @@
+package streaming
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePrimaryKeepalive(t *testing.T) {
+	{
+		// Invalid length
+		_, err := parsePrimaryKeepalive([]byte{1, 2, 3})
+		assert.ErrorContains(t, err, "primary keepalive message must be 17 bytes, got 3")
+	}
+	{
+		// Valid
+		data := binary.BigEndian.AppendUint64(nil, 500)
+		data = binary.BigEndian.AppendUint64(data, 0)
+		data = append(data, 1)
+		keepalive, err := parsePrimaryKeepalive(data)
+		assert.NoError(t, err)
+		assert.Equal(t, primaryKeepalive{ServerWALEnd: 500, ReplyRequested: true}, keepalive)
+	}
+}
+
+func TestParseXLogData(t *testing.T) {
+	{
+		// Too short
+		_, err := parseXLogData([]byte{1})
+		assert.ErrorContains(t, err, "XLogData is too short: 1 bytes")
+	}
+	{
+		// Valid
+		data := binary.BigEndian.AppendUint64(nil, 42)
+		data = binary.BigEndian.AppendUint64(data, 0)
+		data = binary.BigEndian.AppendUint64(data, 0)
+		data = append(data, 'B')
+		xld, err := parseXLogData(data)
+		assert.NoError(t, err)
+		assert.Equal(t, xLogData{WALStart: 42, WALData: []byte{'B'}}, xld)
+	}
+}
+
+func TestEncodeStandbyStatusUpdate(t *testing.T) {
+	data := encodeStandbyStatusUpdate(LSN(10), postgresEpoch.Add(time.Second))
+	assert.Len(t, data, 34)
+	assert.Equal(t, byte('r'), data[0])
+	assert.Equal(t, uint64(10), binary.BigEndian.Uint64(data[1:]))
+	assert.Equal(t, uint64(10), binary.BigEndian.Uint64(data[9:]))
+	assert.Equal(t, uint64(10), binary.BigEndian.Uint64(data[17:]))
+	assert.Equal(t, uint64(1_000_000), binary.BigEndian.Uint64(data[25:]))
+	assert.Equal(t, byte(0), data[33])
+}
+
+func TestBuildStartReplicationQuery(t *testing.T) {
+	assert.Equal(t,
+		`START_REPLICATION SLOT "artie" LOGICAL 0/2A (proto_version '1', publication_names 'dbz''s')`,
+		buildStartReplicationQuery("artie", "dbz's", LSN(42)),
+	)
+}
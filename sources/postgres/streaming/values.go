@@ -0,0 +1,115 @@
+package streaming
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/artie-labs/reader/lib/postgres/parse"
+	"github.com/artie-labs/reader/lib/postgres/schema"
+)
+
+var typeMap = pgtype.NewMap()
+
+// decodeTextValue decodes a text encoded value from pgoutput into the same Go type that `database/sql` returns for the
+// snapshot scanner, so that it can be passed through [parse.ParseValue] and the adapter's value converters.
+func decodeTextValue(oid uint32, data []byte) (any, error) {
+	switch oid {
+	case pgtype.BoolOID:
+		var value bool
+		err := typeMap.Scan(oid, pgtype.TextFormatCode, data, &value)
+		return value, err
+	case pgtype.ByteaOID:
+		var value []byte
+		err := typeMap.Scan(oid, pgtype.TextFormatCode, data, &value)
+		return value, err
+	case pgtype.Int2OID, pgtype.Int4OID, pgtype.Int8OID:
+		var value int64
+		err := typeMap.Scan(oid, pgtype.TextFormatCode, data, &value)
+		return value, err
+	case pgtype.Float4OID, pgtype.Float8OID:
+		var value float64
+		err := typeMap.Scan(oid, pgtype.TextFormatCode, data, &value)
+		return value, err
+	case pgtype.JSONOID, pgtype.JSONBOID:
+		return data, nil
+	case pgtype.DateOID:
+		var value pgtype.Date
+		if err := typeMap.Scan(oid, pgtype.TextFormatCode, data, &value); err != nil {
+			return nil, err
+		}
+		return value.Value()
+	case pgtype.TimestampOID:
+		var value pgtype.Timestamp
+		if err := typeMap.Scan(oid, pgtype.TextFormatCode, data, &value); err != nil {
+			return nil, err
+		}
+		return value.Value()
+	case pgtype.TimestamptzOID:
+		var value pgtype.Timestamptz
+		if err := typeMap.Scan(oid, pgtype.TextFormatCode, data, &value); err != nil {
+			return nil, err
+		}
+		return value.Value()
+	}
+
+	if dataType, ok := typeMap.TypeForOID(oid); ok {
+		if _, isArray := dataType.Codec.(*pgtype.ArrayCodec); isArray {
+			// The snapshot reads arrays with ARRAY_TO_JSON, so encode them as JSON here as well.
+			var value []any
+			if err := typeMap.Scan(oid, pgtype.TextFormatCode, data, &value); err != nil {
+				return nil, err
+			}
+
+			bytes, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal array: %w", err)
+			}
+			return string(bytes), nil
+		}
+	}
+
+	return string(data), nil
+}
+
+// convertTuple converts tuple data into a row, only the columns that are in [columns] are included.
+// Unchanged TOAST values are omitted since pgoutput does not send them, their columns are returned instead.
+func convertTuple(relationColumns []RelationColumn, tuple []TupleColumn, columns map[string]schema.Column) (map[string]any, []string, error) {
+	if len(tuple) != len(relationColumns) {
+		return nil, nil, fmt.Errorf("tuple has %d columns, relation has %d", len(tuple), len(relationColumns))
+	}
+
+	row := make(map[string]any)
+	var unchangedToastColumns []string
+	for i, value := range tuple {
+		relationColumn := relationColumns[i]
+		col, ok := columns[relationColumn.Name]
+		if !ok {
+			continue
+		}
+
+		switch value.Kind {
+		case TupleDataNull:
+			row[col.Name] = nil
+		case TupleDataUnchangedToast:
+			unchangedToastColumns = append(unchangedToastColumns, col.Name)
+		case TupleDataText:
+			decoded, err := decodeTextValue(relationColumn.TypeOID, value.Data)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to decode column %q: %w", col.Name, err)
+			}
+
+			parsed, err := parse.ParseValue(col.Type, decoded)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to parse column %q: %w", col.Name, err)
+			}
+
+			row[col.Name] = parsed
+		default:
+			return nil, nil, fmt.Errorf("unsupported tuple data kind %q for column %q", value.Kind, col.Name)
+		}
+	}
+
+	return row, unchangedToastColumns, nil
+}
@@ -0,0 +1,80 @@
+package streaming
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/artie-labs/reader/lib/postgres/schema"
+)
+
+func TestDecodeTextValue(t *testing.T) {
+	tcs := []struct {
+		name     string
+		oid      uint32
+		value    string
+		expected any
+	}{
+		{name: "bool", oid: pgtype.BoolOID, value: "t", expected: true},
+		{name: "int2", oid: pgtype.Int2OID, value: "12", expected: int64(12)},
+		{name: "int8", oid: pgtype.Int8OID, value: "-9000000000", expected: int64(-9000000000)},
+		{name: "float4", oid: pgtype.Float4OID, value: "1.5", expected: float64(1.5)},
+		{name: "bytea", oid: pgtype.ByteaOID, value: `\x0102`, expected: []byte{1, 2}},
+		{name: "jsonb", oid: pgtype.JSONBOID, value: `{"a": 1}`, expected: []byte(`{"a": 1}`)},
+		{name: "date", oid: pgtype.DateOID, value: "2024-01-02", expected: time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "timestamp", oid: pgtype.TimestampOID, value: "2024-01-02 03:04:05", expected: time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)},
+		{name: "int4 array", oid: pgtype.Int4ArrayOID, value: "{1,2,3}", expected: "[1,2,3]"},
+		{name: "text array", oid: pgtype.TextArrayOID, value: `{a,"b c"}`, expected: `["a","b c"]`},
+		{name: "numeric", oid: pgtype.NumericOID, value: "123.45", expected: "123.45"},
+		{name: "unknown oid", oid: 999_999, value: "foo", expected: "foo"},
+	}
+
+	for _, tc := range tcs {
+		value, err := decodeTextValue(tc.oid, []byte(tc.value))
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expected, value, tc.name)
+	}
+}
+
+func TestConvertTuple(t *testing.T) {
+	relationColumns := []RelationColumn{
+		{IsKey: true, Name: "id", TypeOID: pgtype.Int4OID},
+		{Name: "name", TypeOID: pgtype.TextOID},
+		{Name: "payload", TypeOID: pgtype.TextOID},
+		{Name: "excluded", TypeOID: pgtype.TextOID},
+	}
+	columns := map[string]schema.Column{
+		"id":      {Name: "id", Type: schema.Int32},
+		"name":    {Name: "name", Type: schema.Text},
+		"payload": {Name: "payload", Type: schema.Text},
+	}
+	{
+		// Column count mismatch
+		_, _, err := convertTuple(relationColumns, []TupleColumn{{Kind: TupleDataNull}}, columns)
+		assert.ErrorContains(t, err, "tuple has 1 columns, relation has 4")
+	}
+	{
+		// Nulls, unchanged TOAST values and excluded columns
+		row, unchangedToastColumns, err := convertTuple(relationColumns, []TupleColumn{
+			{Kind: TupleDataText, Data: []byte("1")},
+			{Kind: TupleDataNull},
+			{Kind: TupleDataUnchangedToast},
+			{Kind: TupleDataText, Data: []byte("skip me")},
+		}, columns)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": int64(1), "name": nil}, row)
+		assert.Equal(t, []string{"payload"}, unchangedToastColumns)
+	}
+	{
+		// Bad value
+		_, _, err := convertTuple(relationColumns, []TupleColumn{
+			{Kind: TupleDataText, Data: []byte("abc")},
+			{Kind: TupleDataNull},
+			{Kind: TupleDataNull},
+			{Kind: TupleDataNull},
+		}, columns)
+		assert.ErrorContains(t, err, `failed to decode column "id"`)
+	}
+}
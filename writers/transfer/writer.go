@@ -6,14 +6,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"time"
 
 	bqDialect "github.com/artie-labs/transfer/clients/bigquery/dialect"
 	"github.com/artie-labs/transfer/clients/mssql/dialect"
 	"github.com/artie-labs/transfer/lib/artie"
 	"github.com/artie-labs/transfer/lib/cdc/mongo"
+	"github.com/artie-labs/transfer/lib/cdc/util"
 	transferConfig "github.com/artie-labs/transfer/lib/config"
 	"github.com/artie-labs/transfer/lib/config/constants"
+	"github.com/artie-labs/transfer/lib/debezium"
 	"github.com/artie-labs/transfer/lib/destination"
 	"github.com/artie-labs/transfer/lib/destination/ddl"
 	"github.com/artie-labs/transfer/lib/destination/utils"
@@ -46,34 +50,70 @@ func buildColumns(cols []columns.Column, tc kafkalib.TopicConfig) []columns.Colu
 	return cols
 }
 
+// tableState - State that we keep for each table that we are writing to.
+type tableState struct {
+	tc kafkalib.TopicConfig
+	// fallback - Whether [tc] is the single topic config, used because there was no topic config for the table's topic.
+	fallback bool
+	// tableName - Name of the table that rows are written to, this is set once the first row has been written.
+	tableName string
+	// inMemDB - Rows that have not been flushed yet, this is kept per table so that tables with the same name in
+	// different schemas or databases don't share rows.
+	inMemDB     *models.DatabaseData
+	primaryKeys []string
+	// pendingDedupe - Whether rows have been written since the last time we ran dedupe.
+	pendingDedupe      bool
+	ranOnBackfillStart bool
+}
+
 type Writer struct {
 	cfg         transferConfig.Config
 	statsD      mtr.Client
-	inMemDB     *models.DatabaseData
-	tc          kafkalib.TopicConfig
 	destination destination.Baseline
 
-	primaryKeys []string
+	// topicConfigs - Topic configs keyed by topic, if there's only one topic config then it will be used for a single table.
+	topicConfigs map[string]kafkalib.TopicConfig
+	// tables - Per-table state keyed by the topic suffix of the source table (e.g. `public.users`).
+	tables map[string]*tableState
 
-	beforeBackfill     config.BeforeBackfill
-	ranOnBackfillStart bool
+	beforeBackfill config.BeforeBackfill
+	// unavailableValuePlaceholder - Value of the columns that were left out of an update by the source, see [withoutUnavailableValues].
+	unavailableValuePlaceholder string
+
+	// streaming - When set, rows are merged into the destination and buffered across calls to [Write] until a flush is due.
+	streaming bool
+	lastFlush time.Time
+	flushDue  bool
 }
 
-func NewWriter(cfg transferConfig.Config, statsD mtr.Client, beforeBackfill config.BeforeBackfill) (*Writer, error) {
+func NewWriter(cfg transferConfig.Config, statsD mtr.Client, beforeBackfill config.BeforeBackfill, unavailableValuePlaceholder string, streaming bool) (*Writer, error) {
 	if cfg.Kafka == nil {
 		return nil, fmt.Errorf("kafka config should not be nil")
 	}
 
-	if len(cfg.Kafka.TopicConfigs) != 1 {
-		return nil, fmt.Errorf("kafka config should have exactly one topic config")
+	if len(cfg.Kafka.TopicConfigs) == 0 {
+		return nil, fmt.Errorf("kafka config should have at least one topic config")
+	}
+
+	topicConfigs := make(map[string]kafkalib.TopicConfig)
+	for _, tc := range cfg.Kafka.TopicConfigs {
+		if _, ok := topicConfigs[tc.Topic]; ok {
+			return nil, fmt.Errorf("duplicate topic config for topic %q", tc.Topic)
+		}
+
+		topicConfigs[tc.Topic] = *tc
 	}
 
 	writer := &Writer{
 		cfg:            cfg,
 		statsD:         statsD,
-		inMemDB:        models.NewMemoryDB(),
-		tc:             *cfg.Kafka.TopicConfigs[0],
+		topicConfigs:   topicConfigs,
+		tables:         make(map[string]*tableState),
 		beforeBackfill: beforeBackfill,
+		streaming:      streaming,
+		lastFlush:      time.Now(),
+
+		unavailableValuePlaceholder: unavailableValuePlaceholder,
 	}
 
 	if utils.IsOutputBaseline(cfg) {
@@ -95,7 +135,38 @@ func NewWriter(cfg transferConfig.Config, statsD mtr.Client, beforeBackfill conf
 	return writer, nil
 }
 
-func (w *Writer) messageToEvent(message readerKafkaLib.Message) (event.Event, error) {
+// getTableState returns the state of the table for a topic, this is the topic suffix of the source table (e.g.
+// `public.users`). If there's only one topic config, it's used for a table without a topic config, as long as that is the
+// only table that is written to.
+func (w *Writer) getTableState(topic string) (*tableState, error) {
+	if state, ok := w.tables[topic]; ok {
+		return state, nil
+	}
+
+	for existingTopic, state := range w.tables {
+		if state.fallback {
+			return nil, fmt.Errorf("topic config %q is used for topic %q, a topic config is required for each table when writing more than one table", state.tc.Topic, existingTopic)
+		}
+	}
+
+	tc, ok := w.topicConfigs[topic]
+	fallback := !ok
+	if fallback {
+		if len(w.topicConfigs) != 1 || len(w.tables) > 0 {
+			return nil, fmt.Errorf("no topic config found for topic %q", topic)
+		}
+
+		for _, singleTopicConfig := range w.topicConfigs {
+			tc = singleTopicConfig
+		}
+	}
+
+	state := &tableState{tc: tc, fallback: fallback, inMemDB: models.NewMemoryDB()}
+	w.tables[topic] = state
+	return state, nil
+}
+
+func (w *Writer) messageToEvent(message readerKafkaLib.Message, tc kafkalib.TopicConfig) (event.Event, error) {
 	evt := message.Event()
 	if mongoEvt, ok := evt.(*mongo.SchemaEventPayload); ok {
 		bytes, err := json.Marshal(mongoEvt)
@@ -114,33 +185,83 @@ func (w *Writer) messageToEvent(message readerKafkaLib.Message) (event.Event, er
 			return event.Event{}, err
 		}
 
-		partitionKey, err := dbz.GetPrimaryKey(partitionKeyBytes, w.tc)
+		partitionKey, err := dbz.GetPrimaryKey(partitionKeyBytes, tc)
 		if err != nil {
 			return event.Event{}, err
 		}
 
-		return event.ToMemoryEvent(evt, partitionKey, w.tc, transferConfig.Replication)
+		return event.ToMemoryEvent(evt, partitionKey, tc, transferConfig.Replication)
+	}
+
+	payload, ok := evt.(*util.SchemaEventPayload)
+	if !ok {
+		return event.ToMemoryEvent(evt, message.PartitionKeyValues(), tc, transferConfig.Replication)
 	}
 
-	memoryEvent, err := event.ToMemoryEvent(evt, message.PartitionKeyValues(), w.tc, transferConfig.Replication)
+	// Unavailable values don't match the type of their column, so they are taken out while the row is parsed.
+	payload, unavailableColumns := withoutUnavailableValues(payload, w.unavailableValuePlaceholder)
+	memoryEvent, err := event.ToMemoryEvent(payload, message.PartitionKeyValues(), tc, transferConfig.Replication)
 	if err != nil {
 		return event.Event{}, err
 	}
 
-	// Setting the deleted column flag.
-	memoryEvent.Data[constants.DeleteColumnMarker] = false
+	for _, col := range unavailableColumns {
+		memoryEvent.Data[col] = constants.ToastUnavailableValuePlaceholder
+	}
+
 	return memoryEvent, nil
 }
 
-func (w *Writer) CreateTable(ctx context.Context, tableName string, cols []columns.Column) error {
+// withoutUnavailableValues returns a copy of [payload] without the columns of the after image of an update that are set
+// to [placeholder], along with the unavailable columns. Columns that are not of a string type are left out of the after
+// image by the source instead. The destination keeps the existing values of these columns.
+func withoutUnavailableValues(payload *util.SchemaEventPayload, placeholder string) (*util.SchemaEventPayload, []string) {
+	if payload.Payload.Operation != "u" {
+		return payload, nil
+	}
+
+	var unavailableColumns []string
+	for col, value := range payload.Payload.After {
+		if value == placeholder {
+			unavailableColumns = append(unavailableColumns, col)
+		}
+	}
+
+	if afterFields := payload.Schema.GetSchemaFromLabel(debezium.After); afterFields != nil {
+		for _, field := range afterFields.Fields {
+			if _, ok := payload.Payload.After[field.FieldName]; !ok {
+				unavailableColumns = append(unavailableColumns, field.FieldName)
+			}
+		}
+	}
+
+	if len(unavailableColumns) == 0 {
+		return payload, nil
+	}
+
+	copied := *payload
+	copied.Payload.After = maps.Clone(payload.Payload.After)
+	for _, col := range unavailableColumns {
+		delete(copied.Payload.After, col)
+	}
+
+	return &copied, unavailableColumns
+}
+
+func (w *Writer) CreateTable(ctx context.Context, topicSuffix string, tableName string, cols []columns.Column) error {
 	dwh, ok := w.destination.(destination.DataWarehouse)
 	if !ok {
 		// Don't create the table if it's not a data warehouse.
 		return nil
 	}
 
+	state, err := w.getTableState(topicSuffix)
+	if err != nil {
+		return err
+	}
+
 	// We should include additional columns based in the typing config
-	createTableSQL, err := ddl.BuildCreateTableSQL(w.cfg.SharedDestinationSettings.ColumnSettings, dwh.Dialect(), w.getTableID(tableName), false, w.cfg.Mode, buildColumns(cols, w.tc))
+	createTableSQL, err := ddl.BuildCreateTableSQL(w.cfg.SharedDestinationSettings.ColumnSettings, dwh.Dialect(), w.getTableID(state.tc, tableName), false, w.cfg.Mode, buildColumns(cols, state.tc))
 	if err != nil {
 		return fmt.Errorf("failed to build create table SQL: %w", err)
 	}
@@ -152,28 +273,36 @@ func (w *Writer) CreateTable(ctx context.Context, tableName string, cols []colum
 	return nil
 }
 
-func (w *Writer) dropTable(ctx context.Context, tableName string) error {
+// OnResume skips [config.BeforeBackfill] for a table, since the table already has the rows that have been written before
+// the snapshot was interrupted.
+func (w *Writer) OnResume(topicSuffix string) error {
+	state, err := w.getTableState(topicSuffix)
+	if err != nil {
+		return err
+	}
+
+	state.ranOnBackfillStart = true
+	return nil
+}
+
+func (w *Writer) dropTable(ctx context.Context, tableID sql.TableIdentifier) error {
 	dwh, ok := w.destination.(destination.DataWarehouse)
 	if !ok {
 		return nil
 	}
 
-	tableID := w.getTableID(tableName)
-
 	slog.Info("Dropping table before backfill...", slog.String("table", tableID.FullyQualifiedName()))
 	_, err := dwh.ExecContext(ctx, dwh.Dialect().BuildDropTableQuery(tableID))
 	return err
 }
 
-func (w *Writer) truncateTable(ctx context.Context, tableName string) error {
+func (w *Writer) truncateTable(ctx context.Context, tableID sql.TableIdentifier) error {
 	dwh, ok := w.destination.(destination.DataWarehouse)
 	if !ok {
 		return nil
 	}
 
-	tableID := w.getTableID(tableName)
-
-	slog.Info("Truncating table before backfill...", slog.String("table", tableID.FullyQualifiedName()))
+	slog.Info("Truncating table...", slog.String("table", tableID.FullyQualifiedName()))
 	_, err := dwh.ExecContext(ctx, dwh.Dialect().BuildTruncateTableQuery(tableID))
 	return err
 }
@@ -183,81 +312,155 @@ func (w *Writer) Write(ctx context.Context, messages []readerKafkaLib.Message) e
 		return nil
 	}
 
-	var events []event.Event
+	type tableOperation struct {
+		topic     string
+		operation string
+	}
+
+	eventCounts := make(map[tableOperation]int)
+	defer func() {
+		if w.statsD == nil {
+			return
+		}
+
+		for key, count := range eventCounts {
+			state := w.tables[key.topic]
+			w.statsD.Count("process.message", int64(count), map[string]string{
+				"mode":     w.cfg.Mode.String(),
+				"op":       key.operation,
+				"what":     "success",
+				"database": state.tc.Database,
+				"schema":   state.tc.Schema,
+				"table":    state.tableName,
+			})
+		}
+	}()
+
 	for _, message := range messages {
-		evt, err := w.messageToEvent(message)
+		state, err := w.getTableState(message.Topic(""))
 		if err != nil {
 			return err
 		}
-		events = append(events, evt)
-	}
 
-	if !w.ranOnBackfillStart {
-		w.ranOnBackfillStart = true
-		if err := w.onBackfillStart(ctx, events[0].Table); err != nil {
-			return fmt.Errorf("failed running onBackfillStart: %w", err)
+		if message.Event().Operation() == "t" {
+			if err = w.applyTruncate(ctx, state, message.Event().GetTableName()); err != nil {
+				return fmt.Errorf("failed to apply truncate: %w", err)
+			}
+			continue
 		}
-	}
 
-	tags := map[string]string{
-		"mode":     w.cfg.Mode.String(),
-		"op":       "r",
-		"what":     "success",
-		"database": w.tc.Database,
-		"schema":   w.tc.Schema,
-		"table":    events[0].Table,
-	}
-	defer func() {
-		if w.statsD != nil {
-			w.statsD.Count("process.message", int64(len(events)), tags)
+		evt, err := w.messageToEvent(message, state.tc)
+		if err != nil {
+			return err
+		}
+
+		state.tableName = evt.Table
+		if !w.streaming && !state.ranOnBackfillStart {
+			state.ranOnBackfillStart = true
+			if err = w.onBackfillStart(ctx, state.tc, evt.Table); err != nil {
+				return fmt.Errorf("failed running onBackfillStart: %w", err)
+			}
 		}
-	}()
 
-	for _, evt := range events {
 		// Set the primary keys if it's not set already.
-		if len(w.primaryKeys) == 0 {
+		if len(state.primaryKeys) == 0 {
 			var pks []string
 			for key := range evt.PrimaryKeyMap {
 				pks = append(pks, key)
 			}
 
-			w.primaryKeys = pks
+			state.primaryKeys = pks
 		}
 
-		shouldFlush, flushReason, err := evt.Save(w.cfg, w.inMemDB, w.tc, artie.Message{})
+		shouldFlush, flushReason, err := evt.Save(w.cfg, state.inMemDB, state.tc, artie.Message{})
 		if err != nil {
 			return fmt.Errorf("failed to save event: %w", err)
 		}
 
+		eventCounts[tableOperation{topic: message.Topic(""), operation: message.Event().Operation()}]++
+		if w.streaming {
+			// We can't flush in the middle of a batch, otherwise the source offsets would not line up with what has been merged.
+			w.flushDue = w.flushDue || shouldFlush
+			continue
+		}
+
+		state.pendingDedupe = true
 		if shouldFlush {
-			if err = w.flush(ctx, flushReason); err != nil {
+			if err = w.flush(ctx, state, flushReason); err != nil {
 				return err
 			}
 		}
 	}
 
-	return nil
+	if w.streaming && w.flushDue {
+		return w.Flush(ctx)
+	}
+
+	return w.FlushIfDue(ctx)
 }
 
-func (w *Writer) getTableData() (string, *models.TableData, error) {
-	tableData := w.inMemDB.TableData()
-	if len(tableData) != 1 {
-		return "", nil, fmt.Errorf("expected exactly one table")
+// applyTruncate drops the rows of the table that have not been flushed yet and truncates the destination table, rows that
+// come after the truncate are written as usual. History tables keep every change, so they are not truncated.
+func (w *Writer) applyTruncate(ctx context.Context, state *tableState, tableName string) error {
+	if w.cfg.Mode == transferConfig.History {
+		slog.Info("Skipping truncate for history table", slog.String("table", tableName))
+		return nil
 	}
 
-	for k, v := range tableData {
-		return k, v, nil
+	for bufferedTableName := range state.inMemDB.TableData() {
+		state.inMemDB.ClearTableConfig(bufferedTableName)
 	}
-	return "", nil, fmt.Errorf("expected exactly one table")
+
+	if _, ok := w.destination.(destination.DataWarehouse); !ok {
+		// Rows can only be deleted from data warehouses, the rows that have already been written are left as is.
+		slog.Warn("Destination does not support truncating tables, only the buffered rows have been dropped",
+			slog.String("table", tableName),
+			slog.String("destination", fmt.Sprintf("%T", w.destination)),
+		)
+		return nil
+	}
+
+	return w.truncateTable(ctx, w.getTableID(state.tc, tableName))
 }
 
-func (w *Writer) flush(ctx context.Context, reason string) error {
-	tableName, tableData, err := w.getTableData()
-	if err != nil {
-		return err
+// HasPendingWrites returns whether there are rows that have been buffered, but not flushed to the destination yet.
+func (w *Writer) HasPendingWrites() bool {
+	for _, state := range w.tables {
+		for _, tableData := range state.inMemDB.TableData() {
+			if !tableData.Empty() && tableData.NumberOfRows() > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// FlushIfDue flushes every table if we're streaming and the flush interval has elapsed since the last flush.
+func (w *Writer) FlushIfDue(ctx context.Context) error {
+	if !w.streaming || time.Since(w.lastFlush) < time.Duration(w.cfg.FlushIntervalSeconds)*time.Second {
+		return nil
+	}
+
+	return w.Flush(ctx)
+}
+
+// Flush flushes every table that has buffered rows.
+func (w *Writer) Flush(ctx context.Context) error {
+	for _, topic := range slices.Sorted(maps.Keys(w.tables)) {
+		if err := w.flush(ctx, w.tables[topic], "streaming"); err != nil {
+			return err
+		}
 	}
 
-	if tableData.ShouldSkipUpdate() {
+	w.flushDue = false
+	w.lastFlush = time.Now()
+	return nil
+}
+
+func (w *Writer) flush(ctx context.Context, state *tableState, reason string) error {
+	tableData, ok := state.inMemDB.TableData()[state.tableName]
+	if !ok || tableData.Empty() || tableData.ShouldSkipUpdate() {
 		return nil // No need to flush.
 	}
 
@@ -265,7 +468,7 @@ func (w *Writer) flush(ctx context.Context, reason string) error {
 	tags := map[string]string{
 		"what":     "success",
 		"mode":     tableData.Mode().String(),
-		"table":    tableName,
+		"table":    state.tableName,
 		"database": tableData.TopicConfig().Database,
 		"schema":   tableData.TopicConfig().Schema,
 		"reason":   reason,
@@ -277,9 +480,9 @@ func (w *Writer) flush(ctx context.Context, reason string) error {
 	}()
 
 	tableData.ResetTempTableSuffix()
-	if isMicrosoftSQLServer(w.destination) {
-		// Microsoft SQL Server uses MERGE not append
-		if err = w.destination.Merge(ctx, tableData.TableData); err != nil {
+	if w.streaming || isMicrosoftSQLServer(w.destination) {
+		// Streaming needs to apply updates and deletes and Microsoft SQL Server uses MERGE not append
+		if err := w.destination.Merge(ctx, tableData.TableData); err != nil {
 			tags["what"] = "merge_fail"
 			tags["retryable"] = fmt.Sprint(w.destination.IsRetryableError(err))
 			return fmt.Errorf("failed to merge data to destination: %w", err)
@@ -291,33 +494,33 @@ func (w *Writer) flush(ctx context.Context, reason string) error {
 		}
 
 		tableData.InMemoryColumns().DeleteColumn(constants.OnlySetDeleteColumnMarker)
-		if err = w.destination.Append(ctx, tableData.TableData, isBigQuery(w.destination)); err != nil {
+		if err := w.destination.Append(ctx, tableData.TableData, isBigQuery(w.destination)); err != nil {
 			tags["what"] = "merge_fail"
 			tags["retryable"] = fmt.Sprint(w.destination.IsRetryableError(err))
 			return fmt.Errorf("failed to append data to destination: %w", err)
 		}
 	}
 
-	w.inMemDB.ClearTableConfig(tableName)
+	state.inMemDB.ClearTableConfig(state.tableName)
 	return nil
 }
 
-func (w *Writer) getTableID(tableName string) sql.TableIdentifier {
-	// [w.tc.TableName] could be empty, in that case we'll fall back on [tableName]
-	return w.destination.IdentifierFor(w.tc, cmp.Or(w.tc.TableName, tableName))
+func (w *Writer) getTableID(tc kafkalib.TopicConfig, tableName string) sql.TableIdentifier {
+	// [tc.TableName] could be empty, in that case we'll fall back on [tableName]
+	return w.destination.IdentifierFor(tc, cmp.Or(tc.TableName, tableName))
 }
 
-func (w *Writer) onBackfillStart(ctx context.Context, tableName string) error {
+func (w *Writer) onBackfillStart(ctx context.Context, tc kafkalib.TopicConfig, tableName string) error {
 	switch w.beforeBackfill {
 	case config.BeforeBackfillDoNothing:
 		return nil
 	case config.BeforeBackfillTruncateTable:
-		if err := w.truncateTable(ctx, tableName); err != nil {
+		if err := w.truncateTable(ctx, w.getTableID(tc, tableName)); err != nil {
 			return fmt.Errorf("failed to truncate table: %w", err)
 		}
 		return nil
 	case config.BeforeBackfillDropTable:
-		if err := w.dropTable(ctx, tableName); err != nil {
+		if err := w.dropTable(ctx, w.getTableID(tc, tableName)); err != nil {
 			return fmt.Errorf("failed to drop table: %w", err)
 		}
 		return nil
@@ -326,21 +529,39 @@ func (w *Writer) onBackfillStart(ctx context.Context, tableName string) error {
 	}
 }
 
-func (w *Writer) OnComplete(ctx context.Context) error {
-	if len(w.primaryKeys) == 0 {
-		return fmt.Errorf("primary keys not set")
+// OnComplete flushes and dedupes the tables of [topicSuffixes] that have been written to since the last time it was called.
+// Other tables may still be snapshotted, so they are left as is. When streaming, rows have already been merged so we only need to flush.
+func (w *Writer) OnComplete(ctx context.Context, topicSuffixes []string) error {
+	if w.streaming {
+		return w.Flush(ctx)
 	}
 
-	if err := w.flush(ctx, "complete"); err != nil {
-		return fmt.Errorf("failed to flush: %w", err)
+	for _, topicSuffix := range topicSuffixes {
+		state, isOk := w.tables[topicSuffix]
+		if !isOk || !state.pendingDedupe {
+			continue
+		}
+
+		if err := w.completeTable(ctx, state); err != nil {
+			return fmt.Errorf("failed to complete table %q: %w", state.tableName, err)
+		}
+
+		state.pendingDedupe = false
 	}
 
-	tableName, _, err := w.getTableData()
-	if err != nil {
-		return err
+	return nil
+}
+
+func (w *Writer) completeTable(ctx context.Context, state *tableState) error {
+	if len(state.primaryKeys) == 0 {
+		return fmt.Errorf("primary keys not set")
+	}
+
+	if err := w.flush(ctx, state, "complete"); err != nil {
+		return fmt.Errorf("failed to flush: %w", err)
 	}
 
-	tableID := w.getTableID(tableName)
+	tableID := w.getTableID(state.tc, state.tableName)
 	if isMicrosoftSQLServer(w.destination) {
 		// We don't need to run dedupe because it's just merging.
 		return nil
@@ -354,7 +575,7 @@ func (w *Writer) OnComplete(ctx context.Context) error {
 		return nil
 	}
 
-	if err = dwh.Dedupe(tableID, w.primaryKeys, w.tc.IncludeArtieUpdatedAt); err != nil {
+	if err := dwh.Dedupe(tableID, state.primaryKeys, state.tc.IncludeArtieUpdatedAt); err != nil {
 		return err
 	}
 
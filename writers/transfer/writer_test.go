@@ -1,21 +1,58 @@
 package transfer
 
 import (
+	"context"
+	gosql "database/sql"
 	"fmt"
 	"testing"
+	"time"
 
+	snowflakeDialect "github.com/artie-labs/transfer/clients/snowflake/dialect"
+	"github.com/artie-labs/transfer/lib/cdc/util"
 	transferCfg "github.com/artie-labs/transfer/lib/config"
+	"github.com/artie-labs/transfer/lib/debezium"
+	"github.com/artie-labs/transfer/lib/destination"
 	"github.com/artie-labs/transfer/lib/kafkalib"
+	"github.com/artie-labs/transfer/lib/optimization"
+	"github.com/artie-labs/transfer/lib/sql"
 	"github.com/artie-labs/transfer/lib/typing"
 	"github.com/artie-labs/transfer/lib/typing/columns"
+	"github.com/artie-labs/transfer/models"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/artie-labs/reader/config"
+	readerKafkaLib "github.com/artie-labs/reader/lib/kafkalib"
 	"github.com/artie-labs/reader/lib/mongo"
 )
 
+// mockDataWarehouse records the queries that are executed and the tables that are deduped, methods that aren't overridden
+// are not implemented.
+type mockDataWarehouse struct {
+	destination.DataWarehouse
+	queries       []string
+	dedupedTables []string
+}
+
+func (m *mockDataWarehouse) Dedupe(tableID sql.TableIdentifier, _ []string, _ bool) error {
+	m.dedupedTables = append(m.dedupedTables, tableID.FullyQualifiedName())
+	return nil
+}
+
+func (m *mockDataWarehouse) Dialect() sql.Dialect {
+	return snowflakeDialect.SnowflakeDialect{}
+}
+
+func (m *mockDataWarehouse) IdentifierFor(tc kafkalib.TopicConfig, table string) sql.TableIdentifier {
+	return snowflakeDialect.NewTableIdentifier(tc.Database, tc.Schema, table)
+}
+
+func (m *mockDataWarehouse) ExecContext(_ context.Context, query string, _ ...any) (gosql.Result, error) {
+	m.queries = append(m.queries, query)
+	return nil, nil
+}
+
 func generateBasicColumns(n uint) []columns.Column {
 	cols := make([]columns.Column, n)
 	for i := range cols {
@@ -83,12 +120,8 @@ func TestWriter_MessageToEvent(t *testing.T) {
 	message, err := msg.ToRawMessage(config.Collection{Name: "collection"}, "database")
 	assert.NoError(t, err)
 
-	writer := Writer{
-		cfg: transferCfg.Config{},
-		tc:  kafkalib.TopicConfig{CDCKeyFormat: kafkalib.JSONKeyFmt},
-	}
-
-	evtOut, err := writer.messageToEvent(message)
+	writer := Writer{cfg: transferCfg.Config{}}
+	evtOut, err := writer.messageToEvent(message, kafkalib.TopicConfig{CDCKeyFormat: kafkalib.JSONKeyFmt})
 	assert.NoError(t, err)
 
 	for expectedKey, expectedValue := range map[string]any{
@@ -109,3 +142,273 @@ func TestWriter_MessageToEvent(t *testing.T) {
 	assert.Empty(t, evtOut.Data)
 	assert.Equal(t, map[string]any{"_id": objId.Hex()}, evtOut.PrimaryKeyMap)
 }
+
+func TestWriter_GetTableState(t *testing.T) {
+	{
+		// Single topic config is used for a single table
+		writer := Writer{
+			topicConfigs: map[string]kafkalib.TopicConfig{"unused": {Topic: "unused", Schema: "public"}},
+			tables:       make(map[string]*tableState),
+		}
+		state, err := writer.getTableState("public.users")
+		assert.NoError(t, err)
+		assert.Equal(t, "public", state.tc.Schema)
+		state.primaryKeys = []string{"id"}
+
+		// State should be kept for each table
+		sameState, err := writer.getTableState("public.users")
+		assert.NoError(t, err)
+		assert.Equal(t, state, sameState)
+
+		// A second table needs its own topic config
+		_, err = writer.getTableState("public.orders")
+		assert.ErrorContains(t, err, `topic config "unused" is used for topic "public.users", a topic config is required for each table when writing more than one table`)
+		_, err = writer.getTableState("unused")
+		assert.ErrorContains(t, err, `topic config "unused" is used for topic "public.users"`)
+		assert.Len(t, writer.tables, 1)
+	}
+	{
+		// Single topic config that matches the topic, other tables need their own topic config
+		writer := Writer{
+			topicConfigs: map[string]kafkalib.TopicConfig{"public.users": {Topic: "public.users", TableName: "users"}},
+			tables:       make(map[string]*tableState),
+		}
+		state, err := writer.getTableState("public.users")
+		assert.NoError(t, err)
+		assert.Equal(t, "users", state.tc.TableName)
+
+		_, err = writer.getTableState("public.orders")
+		assert.ErrorContains(t, err, `no topic config found for topic "public.orders"`)
+	}
+	{
+		// Multiple topic configs are looked up by topic
+		writer := Writer{
+			topicConfigs: map[string]kafkalib.TopicConfig{
+				"public.users":  {Topic: "public.users", TableName: "users"},
+				"public.orders": {Topic: "public.orders", TableName: "orders"},
+			},
+			tables: make(map[string]*tableState),
+		}
+
+		state, err := writer.getTableState("public.orders")
+		assert.NoError(t, err)
+		assert.Equal(t, "orders", state.tc.TableName)
+
+		_, err = writer.getTableState("public.products")
+		assert.ErrorContains(t, err, `no topic config found for topic "public.products"`)
+		assert.Len(t, writer.tables, 1)
+	}
+}
+
+func TestWriter_MessageToEvent_Delete(t *testing.T) {
+	payload := &util.SchemaEventPayload{
+		Payload: util.Payload{
+			Before:    map[string]any{"id": 1, "name": "foo"},
+			Operation: "d",
+			Source:    util.Source{Table: "users"},
+		},
+	}
+
+	message := readerKafkaLib.NewMessage("public.users", debezium.FieldsObject{}, map[string]any{"id": 1}, payload)
+	writer := Writer{cfg: transferCfg.Config{}}
+	evtOut, err := writer.messageToEvent(message, kafkalib.TopicConfig{CDCKeyFormat: kafkalib.JSONKeyFmt})
+	assert.NoError(t, err)
+	assert.True(t, evtOut.Deleted)
+	assert.Equal(t, "users", evtOut.Table)
+	assert.Equal(t, true, evtOut.Data["__artie_delete"])
+}
+
+func TestWriter_MessageToEvent_UnavailableValues(t *testing.T) {
+	newPayload := func(operation string, after map[string]any) *util.SchemaEventPayload {
+		return &util.SchemaEventPayload{
+			Schema: debezium.Schema{FieldsObject: []debezium.FieldsObject{{
+				FieldLabel: debezium.After,
+				Fields: []debezium.Field{
+					{FieldName: "id", Type: debezium.Int32},
+					{FieldName: "name", Type: debezium.String},
+					{FieldName: "age", Type: debezium.Int32},
+				},
+			}}},
+			Payload: util.Payload{After: after, Operation: operation, Source: util.Source{Table: "users"}},
+		}
+	}
+
+	writer := Writer{cfg: transferCfg.Config{}, unavailableValuePlaceholder: "__unavailable"}
+	tc := kafkalib.TopicConfig{CDCKeyFormat: kafkalib.JSONKeyFmt}
+	{
+		// String columns are set to the placeholder, other columns are left out
+		payload := newPayload("u", map[string]any{"id": 1, "name": "__unavailable"})
+		message := readerKafkaLib.NewMessage("public.users", debezium.FieldsObject{}, map[string]any{"id": 1}, payload)
+		evtOut, err := writer.messageToEvent(message, tc)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), evtOut.Data["id"])
+		assert.Equal(t, "__debezium_unavailable_value", evtOut.Data["name"])
+		assert.Equal(t, "__debezium_unavailable_value", evtOut.Data["age"])
+
+		// The message is left as is
+		assert.Equal(t, map[string]any{"id": 1, "name": "__unavailable"}, payload.Payload.After)
+	}
+	{
+		// Only updates have unavailable values
+		payload := newPayload("c", map[string]any{"id": 1, "name": "__unavailable"})
+		message := readerKafkaLib.NewMessage("public.users", debezium.FieldsObject{}, map[string]any{"id": 1}, payload)
+		evtOut, err := writer.messageToEvent(message, tc)
+		assert.NoError(t, err)
+		assert.Equal(t, "__unavailable", evtOut.Data["name"])
+		assert.NotContains(t, evtOut.Data, "age")
+	}
+}
+
+func TestWriter_FlushIfDue(t *testing.T) {
+	{
+		// Snapshots are flushed when the buffer is full or when the table is complete
+		writer := Writer{tables: map[string]*tableState{"public.users": {inMemDB: models.NewMemoryDB()}}, lastFlush: time.Now().Add(-time.Hour)}
+		assert.NoError(t, writer.FlushIfDue(context.Background()))
+		assert.False(t, writer.HasPendingWrites())
+	}
+	{
+		// Streaming, flush interval has not elapsed yet
+		lastFlush := time.Now()
+		writer := Writer{
+			cfg:       transferCfg.Config{FlushIntervalSeconds: 10},
+			streaming: true,
+			lastFlush: lastFlush,
+		}
+		assert.NoError(t, writer.FlushIfDue(context.Background()))
+		assert.Equal(t, lastFlush, writer.lastFlush)
+	}
+	{
+		// Streaming, flush interval has elapsed and there's nothing to flush
+		writer := Writer{
+			cfg:       transferCfg.Config{FlushIntervalSeconds: 10},
+			streaming: true,
+			lastFlush: time.Now().Add(-time.Minute),
+			flushDue:  true,
+		}
+		assert.NoError(t, writer.FlushIfDue(context.Background()))
+		assert.False(t, writer.flushDue)
+		assert.WithinDuration(t, time.Now(), writer.lastFlush, time.Second)
+	}
+}
+
+func TestWriter_Write_Truncate(t *testing.T) {
+	tc := kafkalib.TopicConfig{Topic: "public.users", TableName: "users"}
+	writer := Writer{
+		cfg:          transferCfg.Config{FlushIntervalSeconds: 10},
+		topicConfigs: map[string]kafkalib.TopicConfig{"public.users": tc},
+		tables:       make(map[string]*tableState),
+		streaming:    true,
+		lastFlush:    time.Now(),
+	}
+
+	tableData := optimization.NewTableData(&columns.Columns{}, transferCfg.Replication, []string{"id"}, tc, "users")
+	tableData.InsertRow("1", map[string]any{"id": 1}, false)
+	state, err := writer.getTableState("public.users")
+	assert.NoError(t, err)
+	state.tableName = "users"
+	state.inMemDB.GetOrCreateTableData("users").SetTableData(tableData)
+	assert.True(t, writer.HasPendingWrites())
+
+	// Rows that have not been flushed yet are dropped
+	payload := &util.SchemaEventPayload{Payload: util.Payload{Operation: "t", Source: util.Source{Table: "users"}}}
+	assert.NoError(t, writer.Write(context.Background(), []readerKafkaLib.Message{readerKafkaLib.NewMessage("public.users", debezium.FieldsObject{}, nil, payload)}))
+	assert.False(t, writer.HasPendingWrites())
+}
+
+func TestWriter_Write_SameTableNameInDifferentSchemas(t *testing.T) {
+	writer := Writer{
+		cfg: transferCfg.Config{FlushIntervalSeconds: 10, BufferRows: 10, FlushSizeKb: 1024},
+		topicConfigs: map[string]kafkalib.TopicConfig{
+			"foo.users": {Topic: "foo.users", Schema: "foo", CDCKeyFormat: kafkalib.JSONKeyFmt},
+			"bar.users": {Topic: "bar.users", Schema: "bar", CDCKeyFormat: kafkalib.JSONKeyFmt},
+		},
+		tables:    make(map[string]*tableState),
+		streaming: true,
+		lastFlush: time.Now(),
+	}
+
+	var messages []readerKafkaLib.Message
+	for _, topic := range []string{"foo.users", "bar.users", "bar.users"} {
+		payload := &util.SchemaEventPayload{
+			Schema: debezium.Schema{FieldsObject: []debezium.FieldsObject{{
+				FieldLabel: debezium.After,
+				Fields:     []debezium.Field{{FieldName: "id", Type: debezium.Int32}},
+			}}},
+			Payload: util.Payload{
+				After:     map[string]any{"id": len(messages)},
+				Operation: "c",
+				Source:    util.Source{Table: "users"},
+			},
+		}
+		messages = append(messages, readerKafkaLib.NewMessage(topic, debezium.FieldsObject{}, map[string]any{"id": len(messages)}, payload))
+	}
+
+	assert.NoError(t, writer.Write(context.Background(), messages))
+	assert.Len(t, writer.tables, 2)
+	for topic, expectedRows := range map[string]uint{"foo.users": 1, "bar.users": 2} {
+		state := writer.tables[topic]
+		assert.Equal(t, "users", state.tableName, topic)
+		assert.Equal(t, expectedRows, state.inMemDB.TableData()["users"].NumberOfRows(), topic)
+	}
+}
+
+func TestWriter_Write_BeforeBackfill(t *testing.T) {
+	newWriter := func() (*Writer, *mockDataWarehouse) {
+		dwh := &mockDataWarehouse{}
+		return &Writer{
+			cfg:            transferCfg.Config{BufferRows: 10, FlushSizeKb: 1024},
+			destination:    dwh,
+			topicConfigs:   map[string]kafkalib.TopicConfig{"public.users": {Topic: "public.users", Database: "db", Schema: "public", CDCKeyFormat: kafkalib.JSONKeyFmt}},
+			tables:         make(map[string]*tableState),
+			beforeBackfill: config.BeforeBackfillTruncateTable,
+		}, dwh
+	}
+
+	payload := &util.SchemaEventPayload{
+		Schema: debezium.Schema{FieldsObject: []debezium.FieldsObject{{
+			FieldLabel: debezium.After,
+			Fields:     []debezium.Field{{FieldName: "id", Type: debezium.Int32}},
+		}}},
+		Payload: util.Payload{After: map[string]any{"id": 1}, Operation: "r", Source: util.Source{Table: "users"}},
+	}
+	messages := []readerKafkaLib.Message{readerKafkaLib.NewMessage("public.users", debezium.FieldsObject{}, map[string]any{"id": 1}, payload)}
+	{
+		// Table is truncated once before the first rows are written
+		writer, dwh := newWriter()
+		assert.NoError(t, writer.Write(context.Background(), messages))
+		assert.NoError(t, writer.Write(context.Background(), messages))
+		assert.Equal(t, []string{`TRUNCATE TABLE IF EXISTS db.public."USERS"`}, dwh.queries)
+	}
+	{
+		// Snapshot resumes from a checkpoint, the rows that have already been written are kept
+		writer, dwh := newWriter()
+		assert.NoError(t, writer.OnResume("public.users"))
+		assert.NoError(t, writer.Write(context.Background(), messages))
+		assert.Empty(t, dwh.queries)
+		assert.True(t, writer.HasPendingWrites())
+	}
+}
+
+func TestWriter_OnComplete(t *testing.T) {
+	dwh := &mockDataWarehouse{}
+	writer := Writer{destination: dwh, tables: make(map[string]*tableState)}
+	for topic, tableName := range map[string]string{"public.orders": "orders", "public.users": "users"} {
+		writer.tables[topic] = &tableState{
+			tc:            kafkalib.TopicConfig{Topic: topic, Database: "db", Schema: "public"},
+			tableName:     tableName,
+			inMemDB:       models.NewMemoryDB(),
+			primaryKeys:   []string{"id"},
+			pendingDedupe: true,
+		}
+	}
+
+	// Only the table that completed is deduped, the other one may still be snapshotted
+	assert.NoError(t, writer.OnComplete(context.Background(), []string{"public.users"}))
+	assert.Equal(t, []string{`db.public."USERS"`}, dwh.dedupedTables)
+	assert.False(t, writer.tables["public.users"].pendingDedupe)
+	assert.True(t, writer.tables["public.orders"].pendingDedupe)
+
+	// Tables are only deduped once
+	assert.NoError(t, writer.OnComplete(context.Background(), []string{"public.users"}))
+	assert.Len(t, dwh.dedupedTables, 1)
+}
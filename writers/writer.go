@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/artie-labs/transfer/lib/typing/columns"
@@ -14,43 +16,144 @@ import (
 )
 
 type DestinationWriter interface {
-	CreateTable(ctx context.Context, tableName string, columns []columns.Column) error
+	CreateTable(ctx context.Context, topicSuffix string, tableName string, columns []columns.Column) error
 	Write(ctx context.Context, rawMsgs []kafkalib.Message) error
-	OnComplete(ctx context.Context) error
+	// OnComplete - Called once an iterator is exhausted with the topic suffixes that it wrote messages to.
+	OnComplete(ctx context.Context, topicSuffixes []string) error
 }
 
+// BufferedDestinationWriter is implemented by destinations that buffer messages across calls to [DestinationWriter.Write].
+// Offsets for streaming iterators are only committed once everything that has been written has also been flushed.
+type BufferedDestinationWriter interface {
+	DestinationWriter
+	HasPendingWrites() bool
+	// FlushIfDue - Flushes the buffer if the flush interval has elapsed, this is called when the source is idle.
+	FlushIfDue(ctx context.Context) error
+	Flush(ctx context.Context) error
+}
+
+// ResumableDestinationWriter is implemented by destinations that prepare a table before it's snapshotted (e.g. truncating it).
+type ResumableDestinationWriter interface {
+	DestinationWriter
+	// OnResume - Called before a table is snapshotted from a checkpoint, the table must be left as is since it already has
+	// the rows that have been written by a previous run.
+	OnResume(topicSuffix string) error
+}
+
+// Writer - Writes iterators to a destination, it is safe for concurrent use so that several tables can be written at the
+// same time. Calls to the destination are serialized since destinations are not safe for concurrent use.
 type Writer struct {
 	destinationWriter DestinationWriter
-	logProgress       bool
+	// mu - Guards [destinationWriter], this is a pointer so that copies of the writer share it.
+	mu          *sync.Mutex
+	logProgress bool
+	// drainTimeout - How long in-flight writes are allowed to run for once [ctx] passed to [Write] has been cancelled.
+	drainTimeout time.Duration
+}
+
+func New(destinationWriter DestinationWriter, logProgress bool, drainTimeout time.Duration) Writer {
+	return Writer{destinationWriter: destinationWriter, mu: &sync.Mutex{}, logProgress: logProgress, drainTimeout: drainTimeout}
+}
+
+// drainContext returns a context that is cancelled [timeout] after [ctx] is done, so in-flight writes can finish when we are shutting down.
+func drainContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	drainCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		time.AfterFunc(timeout, cancel)
+	})
+
+	return drainCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+func (w *Writer) withDestination(fn func() error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return fn()
+}
+
+func (w *Writer) hasPendingWrites(buffered BufferedDestinationWriter) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return buffered.HasPendingWrites()
 }
 
-func New(destinationWriter DestinationWriter, logProgress bool) Writer {
-	return Writer{destinationWriter: destinationWriter, logProgress: logProgress}
+// commitOffset commits the offset of a streaming iterator, unless the destination still has messages that have not been flushed.
+// It returns whether the offset was committed.
+func (w *Writer) commitOffset(iter iterator.Iterator[[]kafkalib.Message]) bool {
+	// Is it a streaming iterator? if so, let's commit the offset.
+	streamingIter, isOk := iter.(iterator.StreamingIterator[[]kafkalib.Message])
+	if !isOk {
+		return false
+	}
+
+	if buffered, isOk := w.destinationWriter.(BufferedDestinationWriter); isOk && w.hasPendingWrites(buffered) {
+		return false
+	}
+
+	if err := streamingIter.CommitOffset(); err != nil {
+		logger.Panic("Failed to commit offset", slog.Any("err", err))
+	}
+
+	return true
 }
 
 // Write writes all the messages from an iterator to the destination.
+// If [ctx] is cancelled, the batch that is in-flight will be written and committed before returning [context.Canceled].
 func (w *Writer) Write(ctx context.Context, iter iterator.Iterator[[]kafkalib.Message]) (int, error) {
+	writeCtx, cancel := drainContext(ctx, w.drainTimeout)
+	defer cancel()
+
+	buffered, isBuffered := w.destinationWriter.(BufferedDestinationWriter)
 	start := time.Now()
 	var count int
+	var topicSuffixes []string
+	// hasUncommittedWrites - Whether messages have been written since the last time the offset was committed.
+	var hasUncommittedWrites bool
 	for iter.HasNext() {
+		if err := ctx.Err(); err != nil {
+			slog.Info("Stopping writer since the context is done", slog.Int("totalSize", count))
+			if hasUncommittedWrites && isBuffered {
+				if flushErr := w.withDestination(func() error { return buffered.Flush(writeCtx) }); flushErr != nil {
+					return count, fmt.Errorf("failed to flush messages before shutting down: %w", flushErr)
+				}
+
+				w.commitOffset(iter)
+			}
+
+			return count, err
+		}
+
 		iterStart := time.Now()
-		msgs, err := iter.Next()
+		msgs, err := iterator.Next(ctx, iter)
 		if err != nil {
 			return 0, fmt.Errorf("failed to iterate over messages: %w", err)
 		} else if len(msgs) > 0 {
-			if err = w.destinationWriter.Write(ctx, msgs); err != nil {
+			if err = w.withDestination(func() error { return w.destinationWriter.Write(writeCtx, msgs) }); err != nil {
 				return 0, fmt.Errorf("failed to write messages: %w", err)
 			}
 
-			// Is it a streaming iterator? if so, let's commit the offset.
-			if streamingIter, isOk := iter.(iterator.StreamingIterator[[]kafkalib.Message]); isOk {
-				if err = streamingIter.CommitOffset(); err != nil {
-					logger.Panic("Failed to commit offset", slog.Any("err", err))
+			hasUncommittedWrites = true
+			count += len(msgs)
+			for _, msg := range msgs {
+				if !slices.Contains(topicSuffixes, msg.TopicSuffix()) {
+					topicSuffixes = append(topicSuffixes, msg.TopicSuffix())
 				}
 			}
+		} else if isBuffered {
+			if err = w.withDestination(func() error { return buffered.FlushIfDue(writeCtx) }); err != nil {
+				return 0, fmt.Errorf("failed to flush messages: %w", err)
+			}
+		}
 
-			count += len(msgs)
+		if hasUncommittedWrites && w.commitOffset(iter) {
+			hasUncommittedWrites = false
 		}
+
 		if w.logProgress {
 			slog.Info("Write progress",
 				slog.Int("totalSize", count),
@@ -63,24 +166,42 @@ func (w *Writer) Write(ctx context.Context, iter iterator.Iterator[[]kafkalib.Me
 
 	// Only run [OnComplete] if we wrote messages out. Otherwise, primary keys may not be loaded.
 	if count > 0 {
-		if err := w.destinationWriter.OnComplete(ctx); err != nil {
+		if err := w.withDestination(func() error { return w.destinationWriter.OnComplete(ctx, topicSuffixes) }); err != nil {
 			return 0, fmt.Errorf("failed running destination OnComplete: %w", err)
 		}
+
+		if hasUncommittedWrites {
+			w.commitOffset(iter)
+		}
 	}
 
 	return count, nil
 }
 
-func (w *Writer) OnComplete(ctx context.Context) error {
-	if err := w.destinationWriter.OnComplete(ctx); err != nil {
+func (w *Writer) OnComplete(ctx context.Context, topicSuffixes []string) error {
+	if err := w.withDestination(func() error { return w.destinationWriter.OnComplete(ctx, topicSuffixes) }); err != nil {
 		return fmt.Errorf("failed running destination OnComplete: %w", err)
 	}
 
 	return nil
 }
 
-func (w *Writer) CreateTable(ctx context.Context, tableName string, columns []columns.Column) error {
-	if err := w.destinationWriter.CreateTable(ctx, tableName, columns); err != nil {
+// OnResume should be called before writing a snapshot that resumes from a checkpoint.
+func (w *Writer) OnResume(topicSuffix string) error {
+	resumable, isOk := w.destinationWriter.(ResumableDestinationWriter)
+	if !isOk {
+		return nil
+	}
+
+	if err := w.withDestination(func() error { return resumable.OnResume(topicSuffix) }); err != nil {
+		return fmt.Errorf("failed running destination OnResume: %w", err)
+	}
+
+	return nil
+}
+
+func (w *Writer) CreateTable(ctx context.Context, topicSuffix string, tableName string, columns []columns.Column) error {
+	if err := w.withDestination(func() error { return w.destinationWriter.CreateTable(ctx, topicSuffix, tableName, columns) }); err != nil {
 		return fmt.Errorf("failed to create table: %w", err)
 	}
 
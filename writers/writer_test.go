@@ -3,7 +3,9 @@ package writers
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/artie-labs/transfer/lib/debezium"
 	"github.com/artie-labs/transfer/lib/typing/columns"
@@ -18,7 +20,7 @@ type mockDestination struct {
 	emitError bool
 }
 
-func (m *mockDestination) CreateTable(_ context.Context, _ string, _ []columns.Column) error {
+func (m *mockDestination) CreateTable(_ context.Context, _ string, _ string, _ []columns.Column) error {
 	return nil
 }
 
@@ -30,7 +32,7 @@ func (m *mockDestination) Write(_ context.Context, msgs []kafkalib.Message) erro
 	return nil
 }
 
-func (m *mockDestination) OnComplete(_ context.Context) error {
+func (m *mockDestination) OnComplete(_ context.Context, _ []string) error {
 	return nil
 }
 
@@ -44,11 +46,113 @@ func (m *errorIterator) Next() ([]kafkalib.Message, error) {
 	return nil, fmt.Errorf("test iteration error")
 }
 
+// bufferedDestination only flushes once it has received [flushSize] messages.
+type bufferedDestination struct {
+	mockDestination
+	flushSize int
+	buffer    []kafkalib.Message
+}
+
+func (b *bufferedDestination) Write(ctx context.Context, msgs []kafkalib.Message) error {
+	b.buffer = append(b.buffer, msgs...)
+	if len(b.buffer) >= b.flushSize {
+		return b.Flush(ctx)
+	}
+	return nil
+}
+
+func (b *bufferedDestination) OnComplete(ctx context.Context, _ []string) error {
+	return b.Flush(ctx)
+}
+
+func (b *bufferedDestination) HasPendingWrites() bool {
+	return len(b.buffer) > 0
+}
+
+func (b *bufferedDestination) FlushIfDue(_ context.Context) error {
+	return nil
+}
+
+func (b *bufferedDestination) Flush(_ context.Context) error {
+	b.messages = append(b.messages, b.buffer...)
+	b.buffer = nil
+	return nil
+}
+
+// resumableDestination records the tables that have been resumed.
+type resumableDestination struct {
+	mockDestination
+	resumed []string
+}
+
+func (r *resumableDestination) OnResume(topicSuffix string) error {
+	r.resumed = append(r.resumed, topicSuffix)
+	return nil
+}
+
+// streamingIterator returns each batch once and records the number of batches that had been read when offsets were committed.
+type streamingIterator struct {
+	batches   [][]kafkalib.Message
+	index     int
+	committed []int
+}
+
+func (s *streamingIterator) HasNext() bool {
+	return s.index < len(s.batches)
+}
+
+func (s *streamingIterator) Next() ([]kafkalib.Message, error) {
+	s.index++
+	return s.batches[s.index-1], nil
+}
+
+func (s *streamingIterator) CommitOffset() error {
+	s.committed = append(s.committed, s.index)
+	return nil
+}
+
+// cancellingIterator cancels the context when the first batch is read, to simulate receiving a shutdown signal mid-batch.
+type cancellingIterator struct {
+	cancel    context.CancelFunc
+	calls     int
+	committed int
+}
+
+func (c *cancellingIterator) HasNext() bool {
+	return true
+}
+
+func (c *cancellingIterator) Next() ([]kafkalib.Message, error) {
+	c.calls++
+	c.cancel()
+	return []kafkalib.Message{kafkalib.NewMessage("a", debezium.FieldsObject{}, nil, nil)}, nil
+}
+
+func (c *cancellingIterator) CommitOffset() error {
+	c.committed++
+	return nil
+}
+
+func TestDrainContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	drainCtx, drainCancel := drainContext(ctx, 50*time.Millisecond)
+	defer drainCancel()
+
+	cancel()
+	assert.NoError(t, drainCtx.Err())
+
+	select {
+	case <-drainCtx.Done():
+	case <-time.After(time.Second):
+		assert.Fail(t, "drain context should have been cancelled after the timeout")
+	}
+}
+
 func TestWriter_Write(t *testing.T) {
 	{
 		// Empty iterator
 		destination := &mockDestination{}
-		writer := New(destination, false)
+		writer := New(destination, false, time.Second)
 		iter := iterator.ForSlice([][]kafkalib.Message{})
 		count, err := writer.Write(context.Background(), iter)
 		assert.NoError(t, err)
@@ -58,7 +162,7 @@ func TestWriter_Write(t *testing.T) {
 	{
 		// Iteration error
 		destination := &mockDestination{}
-		writer := New(destination, false)
+		writer := New(destination, false, time.Second)
 		iter := &errorIterator{}
 		_, err := writer.Write(context.Background(), iter)
 		assert.ErrorContains(t, err, "failed to iterate over messages: test iteration error")
@@ -67,7 +171,7 @@ func TestWriter_Write(t *testing.T) {
 	{
 		// Two empty batches
 		destination := &mockDestination{}
-		writer := New(destination, false)
+		writer := New(destination, false, time.Second)
 		iter := iterator.ForSlice([][]kafkalib.Message{{}, {}})
 		count, err := writer.Write(context.Background(), iter)
 		assert.NoError(t, err)
@@ -77,7 +181,7 @@ func TestWriter_Write(t *testing.T) {
 	{
 		// Three batches, two non-empty
 		destination := &mockDestination{}
-		writer := New(destination, false)
+		writer := New(destination, false, time.Second)
 		iter := iterator.ForSlice([][]kafkalib.Message{
 			{kafkalib.NewMessage("a", debezium.FieldsObject{}, nil, nil)},
 			{},
@@ -94,13 +198,75 @@ func TestWriter_Write(t *testing.T) {
 		assert.Equal(t, destination.messages[1].Topic(""), "b")
 		assert.Equal(t, destination.messages[2].Topic(""), "c")
 	}
+	{
+		// Concurrent writes, the destination is not called concurrently
+		destination := &mockDestination{}
+		writer := New(destination, false, time.Second)
+		var wg sync.WaitGroup
+		for i := range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				iter := iterator.ForSlice([][]kafkalib.Message{
+					{kafkalib.NewMessage(fmt.Sprintf("a%d", i), debezium.FieldsObject{}, nil, nil)},
+					{kafkalib.NewMessage(fmt.Sprintf("b%d", i), debezium.FieldsObject{}, nil, nil)},
+				})
+				count, err := writer.Write(context.Background(), iter)
+				assert.NoError(t, err)
+				assert.Equal(t, 2, count)
+			}()
+		}
+		wg.Wait()
+		assert.Len(t, destination.messages, 8)
+	}
 	{
 		// Destination error
 		destination := &mockDestination{emitError: true}
-		writer := New(destination, false)
+		writer := New(destination, false, time.Second)
 		iter := iterator.Once([]kafkalib.Message{kafkalib.NewMessage("a", debezium.FieldsObject{}, nil, nil)})
 		_, err := writer.Write(context.Background(), iter)
 		assert.ErrorContains(t, err, "failed to write messages: test write-raw-messages error")
 		assert.Empty(t, destination.messages)
 	}
+	{
+		// Context is cancelled while reading a batch, the batch is written and committed before returning
+		destination := &mockDestination{}
+		writer := New(destination, false, time.Second)
+		ctx, cancel := context.WithCancel(context.Background())
+		iter := &cancellingIterator{cancel: cancel}
+		count, err := writer.Write(ctx, iter)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, count)
+		assert.Len(t, destination.messages, 1)
+		assert.Equal(t, 1, iter.calls)
+		assert.Equal(t, 1, iter.committed)
+	}
+	{
+		// Buffered destination, offsets are only committed once the messages have been flushed
+		destination := &bufferedDestination{flushSize: 2}
+		writer := New(destination, false, time.Second)
+		msg := kafkalib.NewMessage("a", debezium.FieldsObject{}, nil, nil)
+		iter := &streamingIterator{batches: [][]kafkalib.Message{{msg}, {msg}, {msg}, {}}}
+		count, err := writer.Write(context.Background(), iter)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+		// The last message is flushed in [OnComplete]
+		assert.Equal(t, []int{2, 4}, iter.committed)
+		assert.Len(t, destination.messages, 3)
+	}
+}
+
+func TestWriter_OnResume(t *testing.T) {
+	{
+		// Destination does not implement [ResumableDestinationWriter]
+		writer := New(&mockDestination{}, false, time.Second)
+		assert.NoError(t, writer.OnResume("public.users"))
+	}
+	{
+		// Destination implements [ResumableDestinationWriter]
+		destination := &resumableDestination{}
+		writer := New(destination, false, time.Second)
+		assert.NoError(t, writer.OnResume("public.users"))
+		assert.Equal(t, []string{"public.users"}, destination.resumed)
+	}
 }